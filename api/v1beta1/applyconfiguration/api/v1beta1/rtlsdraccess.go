@@ -0,0 +1,82 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by controller-gen. DO NOT EDIT.
+
+package v1beta1
+
+import (
+	apiv1beta1 "github.com/frelon/k8s-radio/api/v1beta1"
+)
+
+// RtlSdrAccessApplyConfiguration represents a declarative configuration of the RtlSdrAccess type for use
+// with apply.
+type RtlSdrAccessApplyConfiguration struct {
+	Authentication     *apiv1beta1.RtlSdrAuthentication `json:"authentication,omitempty"`
+	TokenSecretName    *string                          `json:"tokenSecretName,omitempty"`
+	Audiences          []string                         `json:"audiences,omitempty"`
+	ServiceAccountName *string                          `json:"serviceAccountName,omitempty"`
+	Hosts              []string                         `json:"hosts,omitempty"`
+}
+
+// RtlSdrAccessApplyConfiguration constructs a declarative configuration of the RtlSdrAccess type for use with
+// apply.
+func RtlSdrAccess() *RtlSdrAccessApplyConfiguration {
+	return &RtlSdrAccessApplyConfiguration{}
+}
+
+// WithAuthentication sets the Authentication field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Authentication field is set to the value of the last call.
+func (b *RtlSdrAccessApplyConfiguration) WithAuthentication(value apiv1beta1.RtlSdrAuthentication) *RtlSdrAccessApplyConfiguration {
+	b.Authentication = &value
+	return b
+}
+
+// WithTokenSecretName sets the TokenSecretName field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the TokenSecretName field is set to the value of the last call.
+func (b *RtlSdrAccessApplyConfiguration) WithTokenSecretName(value string) *RtlSdrAccessApplyConfiguration {
+	b.TokenSecretName = &value
+	return b
+}
+
+// WithAudiences adds the given value to the Audiences field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the Audiences field.
+func (b *RtlSdrAccessApplyConfiguration) WithAudiences(values ...string) *RtlSdrAccessApplyConfiguration {
+	for i := range values {
+		b.Audiences = append(b.Audiences, values[i])
+	}
+	return b
+}
+
+// WithServiceAccountName sets the ServiceAccountName field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the ServiceAccountName field is set to the value of the last call.
+func (b *RtlSdrAccessApplyConfiguration) WithServiceAccountName(value string) *RtlSdrAccessApplyConfiguration {
+	b.ServiceAccountName = &value
+	return b
+}
+
+// WithHosts adds the given value to the Hosts field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the Hosts field.
+func (b *RtlSdrAccessApplyConfiguration) WithHosts(values ...string) *RtlSdrAccessApplyConfiguration {
+	for i := range values {
+		b.Hosts = append(b.Hosts, values[i])
+	}
+	return b
+}
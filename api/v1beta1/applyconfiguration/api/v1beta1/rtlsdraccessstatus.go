@@ -0,0 +1,60 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by controller-gen. DO NOT EDIT.
+
+package v1beta1
+
+import (
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RtlSdrAccessStatusApplyConfiguration represents a declarative configuration of the RtlSdrAccessStatus type for use
+// with apply.
+type RtlSdrAccessStatusApplyConfiguration struct {
+	CAConfigMapName  *string  `json:"caConfigMapName,omitempty"`
+	ClientSecretName *string  `json:"clientSecretName,omitempty"`
+	NotAfter         *v1.Time `json:"notAfter,omitempty"`
+}
+
+// RtlSdrAccessStatusApplyConfiguration constructs a declarative configuration of the RtlSdrAccessStatus type for use with
+// apply.
+func RtlSdrAccessStatus() *RtlSdrAccessStatusApplyConfiguration {
+	return &RtlSdrAccessStatusApplyConfiguration{}
+}
+
+// WithCAConfigMapName sets the CAConfigMapName field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the CAConfigMapName field is set to the value of the last call.
+func (b *RtlSdrAccessStatusApplyConfiguration) WithCAConfigMapName(value string) *RtlSdrAccessStatusApplyConfiguration {
+	b.CAConfigMapName = &value
+	return b
+}
+
+// WithClientSecretName sets the ClientSecretName field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the ClientSecretName field is set to the value of the last call.
+func (b *RtlSdrAccessStatusApplyConfiguration) WithClientSecretName(value string) *RtlSdrAccessStatusApplyConfiguration {
+	b.ClientSecretName = &value
+	return b
+}
+
+// WithNotAfter sets the NotAfter field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the NotAfter field is set to the value of the last call.
+func (b *RtlSdrAccessStatusApplyConfiguration) WithNotAfter(value v1.Time) *RtlSdrAccessStatusApplyConfiguration {
+	b.NotAfter = &value
+	return b
+}
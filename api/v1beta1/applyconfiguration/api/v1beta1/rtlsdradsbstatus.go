@@ -0,0 +1,69 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by controller-gen. DO NOT EDIT.
+
+package v1beta1
+
+import (
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RtlSdrADSBStatusApplyConfiguration represents a declarative configuration of the RtlSdrADSBStatus type for use
+// with apply.
+type RtlSdrADSBStatusApplyConfiguration struct {
+	Aircraft       *int32   `json:"aircraft,omitempty"`
+	MessageRate    *int32   `json:"messageRate,omitempty"`
+	Messages       *int64   `json:"messages,omitempty"`
+	LastUpdateTime *v1.Time `json:"lastUpdateTime,omitempty"`
+}
+
+// RtlSdrADSBStatusApplyConfiguration constructs a declarative configuration of the RtlSdrADSBStatus type for use with
+// apply.
+func RtlSdrADSBStatus() *RtlSdrADSBStatusApplyConfiguration {
+	return &RtlSdrADSBStatusApplyConfiguration{}
+}
+
+// WithAircraft sets the Aircraft field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Aircraft field is set to the value of the last call.
+func (b *RtlSdrADSBStatusApplyConfiguration) WithAircraft(value int32) *RtlSdrADSBStatusApplyConfiguration {
+	b.Aircraft = &value
+	return b
+}
+
+// WithMessageRate sets the MessageRate field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the MessageRate field is set to the value of the last call.
+func (b *RtlSdrADSBStatusApplyConfiguration) WithMessageRate(value int32) *RtlSdrADSBStatusApplyConfiguration {
+	b.MessageRate = &value
+	return b
+}
+
+// WithMessages sets the Messages field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Messages field is set to the value of the last call.
+func (b *RtlSdrADSBStatusApplyConfiguration) WithMessages(value int64) *RtlSdrADSBStatusApplyConfiguration {
+	b.Messages = &value
+	return b
+}
+
+// WithLastUpdateTime sets the LastUpdateTime field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the LastUpdateTime field is set to the value of the last call.
+func (b *RtlSdrADSBStatusApplyConfiguration) WithLastUpdateTime(value v1.Time) *RtlSdrADSBStatusApplyConfiguration {
+	b.LastUpdateTime = &value
+	return b
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by controller-gen. DO NOT EDIT.
+
+package v1beta1
+
+import (
+	resource "k8s.io/apimachinery/pkg/api/resource"
+)
+
+// RtlSdrChannelSpecApplyConfiguration represents a declarative configuration of the RtlSdrChannelSpec type for use
+// with apply.
+type RtlSdrChannelSpecApplyConfiguration struct {
+	Receiver  *string            `json:"receiver,omitempty"`
+	Offset    *resource.Quantity `json:"offset,omitempty"`
+	Bandwidth *resource.Quantity `json:"bandwidth,omitempty"`
+}
+
+// RtlSdrChannelSpecApplyConfiguration constructs a declarative configuration of the RtlSdrChannelSpec type for use with
+// apply.
+func RtlSdrChannelSpec() *RtlSdrChannelSpecApplyConfiguration {
+	return &RtlSdrChannelSpecApplyConfiguration{}
+}
+
+// WithReceiver sets the Receiver field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Receiver field is set to the value of the last call.
+func (b *RtlSdrChannelSpecApplyConfiguration) WithReceiver(value string) *RtlSdrChannelSpecApplyConfiguration {
+	b.Receiver = &value
+	return b
+}
+
+// WithOffset sets the Offset field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Offset field is set to the value of the last call.
+func (b *RtlSdrChannelSpecApplyConfiguration) WithOffset(value resource.Quantity) *RtlSdrChannelSpecApplyConfiguration {
+	b.Offset = &value
+	return b
+}
+
+// WithBandwidth sets the Bandwidth field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Bandwidth field is set to the value of the last call.
+func (b *RtlSdrChannelSpecApplyConfiguration) WithBandwidth(value resource.Quantity) *RtlSdrChannelSpecApplyConfiguration {
+	b.Bandwidth = &value
+	return b
+}
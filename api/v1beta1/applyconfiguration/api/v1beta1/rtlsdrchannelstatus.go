@@ -0,0 +1,85 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by controller-gen. DO NOT EDIT.
+
+package v1beta1
+
+import (
+	apiv1beta1 "github.com/frelon/k8s-radio/api/v1beta1"
+	resource "k8s.io/apimachinery/pkg/api/resource"
+	v1 "k8s.io/client-go/applyconfigurations/meta/v1"
+)
+
+// RtlSdrChannelStatusApplyConfiguration represents a declarative configuration of the RtlSdrChannelStatus type for use
+// with apply.
+type RtlSdrChannelStatusApplyConfiguration struct {
+	Conditions []v1.ConditionApplyConfiguration `json:"conditions,omitempty"`
+	State      *apiv1beta1.RtlSdrReceiverState  `json:"state,omitempty"`
+	Endpoint   *string                          `json:"endpoint,omitempty"`
+	Frequency  *resource.Quantity               `json:"frequency,omitempty"`
+	SampleRate *resource.Quantity               `json:"sampleRate,omitempty"`
+}
+
+// RtlSdrChannelStatusApplyConfiguration constructs a declarative configuration of the RtlSdrChannelStatus type for use with
+// apply.
+func RtlSdrChannelStatus() *RtlSdrChannelStatusApplyConfiguration {
+	return &RtlSdrChannelStatusApplyConfiguration{}
+}
+
+// WithConditions adds the given value to the Conditions field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the Conditions field.
+func (b *RtlSdrChannelStatusApplyConfiguration) WithConditions(values ...*v1.ConditionApplyConfiguration) *RtlSdrChannelStatusApplyConfiguration {
+	for i := range values {
+		if values[i] == nil {
+			panic("nil value passed to WithConditions")
+		}
+		b.Conditions = append(b.Conditions, *values[i])
+	}
+	return b
+}
+
+// WithState sets the State field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the State field is set to the value of the last call.
+func (b *RtlSdrChannelStatusApplyConfiguration) WithState(value apiv1beta1.RtlSdrReceiverState) *RtlSdrChannelStatusApplyConfiguration {
+	b.State = &value
+	return b
+}
+
+// WithEndpoint sets the Endpoint field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Endpoint field is set to the value of the last call.
+func (b *RtlSdrChannelStatusApplyConfiguration) WithEndpoint(value string) *RtlSdrChannelStatusApplyConfiguration {
+	b.Endpoint = &value
+	return b
+}
+
+// WithFrequency sets the Frequency field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Frequency field is set to the value of the last call.
+func (b *RtlSdrChannelStatusApplyConfiguration) WithFrequency(value resource.Quantity) *RtlSdrChannelStatusApplyConfiguration {
+	b.Frequency = &value
+	return b
+}
+
+// WithSampleRate sets the SampleRate field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the SampleRate field is set to the value of the last call.
+func (b *RtlSdrChannelStatusApplyConfiguration) WithSampleRate(value resource.Quantity) *RtlSdrChannelStatusApplyConfiguration {
+	b.SampleRate = &value
+	return b
+}
@@ -0,0 +1,47 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by controller-gen. DO NOT EDIT.
+
+package v1beta1
+
+// RtlSdrEventsApplyConfiguration represents a declarative configuration of the RtlSdrEvents type for use
+// with apply.
+type RtlSdrEventsApplyConfiguration struct {
+	Sink  *string `json:"sink,omitempty"`
+	Topic *string `json:"topic,omitempty"`
+}
+
+// RtlSdrEventsApplyConfiguration constructs a declarative configuration of the RtlSdrEvents type for use with
+// apply.
+func RtlSdrEvents() *RtlSdrEventsApplyConfiguration {
+	return &RtlSdrEventsApplyConfiguration{}
+}
+
+// WithSink sets the Sink field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Sink field is set to the value of the last call.
+func (b *RtlSdrEventsApplyConfiguration) WithSink(value string) *RtlSdrEventsApplyConfiguration {
+	b.Sink = &value
+	return b
+}
+
+// WithTopic sets the Topic field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Topic field is set to the value of the last call.
+func (b *RtlSdrEventsApplyConfiguration) WithTopic(value string) *RtlSdrEventsApplyConfiguration {
+	b.Topic = &value
+	return b
+}
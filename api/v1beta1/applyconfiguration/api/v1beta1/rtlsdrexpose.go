@@ -0,0 +1,65 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by controller-gen. DO NOT EDIT.
+
+package v1beta1
+
+// RtlSdrExposeApplyConfiguration represents a declarative configuration of the RtlSdrExpose type for use
+// with apply.
+type RtlSdrExposeApplyConfiguration struct {
+	Hostname   *string                                `json:"hostname,omitempty"`
+	PathPrefix *string                                `json:"pathPrefix,omitempty"`
+	Gateway    *RtlSdrGatewayExposeApplyConfiguration `json:"gateway,omitempty"`
+	Ingress    *RtlSdrIngressExposeApplyConfiguration `json:"ingress,omitempty"`
+}
+
+// RtlSdrExposeApplyConfiguration constructs a declarative configuration of the RtlSdrExpose type for use with
+// apply.
+func RtlSdrExpose() *RtlSdrExposeApplyConfiguration {
+	return &RtlSdrExposeApplyConfiguration{}
+}
+
+// WithHostname sets the Hostname field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Hostname field is set to the value of the last call.
+func (b *RtlSdrExposeApplyConfiguration) WithHostname(value string) *RtlSdrExposeApplyConfiguration {
+	b.Hostname = &value
+	return b
+}
+
+// WithPathPrefix sets the PathPrefix field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the PathPrefix field is set to the value of the last call.
+func (b *RtlSdrExposeApplyConfiguration) WithPathPrefix(value string) *RtlSdrExposeApplyConfiguration {
+	b.PathPrefix = &value
+	return b
+}
+
+// WithGateway sets the Gateway field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Gateway field is set to the value of the last call.
+func (b *RtlSdrExposeApplyConfiguration) WithGateway(value *RtlSdrGatewayExposeApplyConfiguration) *RtlSdrExposeApplyConfiguration {
+	b.Gateway = value
+	return b
+}
+
+// WithIngress sets the Ingress field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Ingress field is set to the value of the last call.
+func (b *RtlSdrExposeApplyConfiguration) WithIngress(value *RtlSdrIngressExposeApplyConfiguration) *RtlSdrExposeApplyConfiguration {
+	b.Ingress = value
+	return b
+}
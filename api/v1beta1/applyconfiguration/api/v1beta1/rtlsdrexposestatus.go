@@ -0,0 +1,47 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by controller-gen. DO NOT EDIT.
+
+package v1beta1
+
+// RtlSdrExposeStatusApplyConfiguration represents a declarative configuration of the RtlSdrExposeStatus type for use
+// with apply.
+type RtlSdrExposeStatusApplyConfiguration struct {
+	URL       *string `json:"url,omitempty"`
+	StreamURL *string `json:"streamURL,omitempty"`
+}
+
+// RtlSdrExposeStatusApplyConfiguration constructs a declarative configuration of the RtlSdrExposeStatus type for use with
+// apply.
+func RtlSdrExposeStatus() *RtlSdrExposeStatusApplyConfiguration {
+	return &RtlSdrExposeStatusApplyConfiguration{}
+}
+
+// WithURL sets the URL field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the URL field is set to the value of the last call.
+func (b *RtlSdrExposeStatusApplyConfiguration) WithURL(value string) *RtlSdrExposeStatusApplyConfiguration {
+	b.URL = &value
+	return b
+}
+
+// WithStreamURL sets the StreamURL field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the StreamURL field is set to the value of the last call.
+func (b *RtlSdrExposeStatusApplyConfiguration) WithStreamURL(value string) *RtlSdrExposeStatusApplyConfiguration {
+	b.StreamURL = &value
+	return b
+}
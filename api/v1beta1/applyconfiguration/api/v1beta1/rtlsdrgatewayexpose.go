@@ -0,0 +1,65 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by controller-gen. DO NOT EDIT.
+
+package v1beta1
+
+// RtlSdrGatewayExposeApplyConfiguration represents a declarative configuration of the RtlSdrGatewayExpose type for use
+// with apply.
+type RtlSdrGatewayExposeApplyConfiguration struct {
+	Name           *string `json:"name,omitempty"`
+	Namespace      *string `json:"namespace,omitempty"`
+	SectionName    *string `json:"sectionName,omitempty"`
+	TCPSectionName *string `json:"tcpSectionName,omitempty"`
+}
+
+// RtlSdrGatewayExposeApplyConfiguration constructs a declarative configuration of the RtlSdrGatewayExpose type for use with
+// apply.
+func RtlSdrGatewayExpose() *RtlSdrGatewayExposeApplyConfiguration {
+	return &RtlSdrGatewayExposeApplyConfiguration{}
+}
+
+// WithName sets the Name field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Name field is set to the value of the last call.
+func (b *RtlSdrGatewayExposeApplyConfiguration) WithName(value string) *RtlSdrGatewayExposeApplyConfiguration {
+	b.Name = &value
+	return b
+}
+
+// WithNamespace sets the Namespace field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Namespace field is set to the value of the last call.
+func (b *RtlSdrGatewayExposeApplyConfiguration) WithNamespace(value string) *RtlSdrGatewayExposeApplyConfiguration {
+	b.Namespace = &value
+	return b
+}
+
+// WithSectionName sets the SectionName field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the SectionName field is set to the value of the last call.
+func (b *RtlSdrGatewayExposeApplyConfiguration) WithSectionName(value string) *RtlSdrGatewayExposeApplyConfiguration {
+	b.SectionName = &value
+	return b
+}
+
+// WithTCPSectionName sets the TCPSectionName field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the TCPSectionName field is set to the value of the last call.
+func (b *RtlSdrGatewayExposeApplyConfiguration) WithTCPSectionName(value string) *RtlSdrGatewayExposeApplyConfiguration {
+	b.TCPSectionName = &value
+	return b
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by controller-gen. DO NOT EDIT.
+
+package v1beta1
+
+// RtlSdrIngressExposeApplyConfiguration represents a declarative configuration of the RtlSdrIngressExpose type for use
+// with apply.
+type RtlSdrIngressExposeApplyConfiguration struct {
+	ClassName     *string           `json:"className,omitempty"`
+	Annotations   map[string]string `json:"annotations,omitempty"`
+	TLSSecretName *string           `json:"tlsSecretName,omitempty"`
+}
+
+// RtlSdrIngressExposeApplyConfiguration constructs a declarative configuration of the RtlSdrIngressExpose type for use with
+// apply.
+func RtlSdrIngressExpose() *RtlSdrIngressExposeApplyConfiguration {
+	return &RtlSdrIngressExposeApplyConfiguration{}
+}
+
+// WithClassName sets the ClassName field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the ClassName field is set to the value of the last call.
+func (b *RtlSdrIngressExposeApplyConfiguration) WithClassName(value string) *RtlSdrIngressExposeApplyConfiguration {
+	b.ClassName = &value
+	return b
+}
+
+// WithAnnotations puts the entries into the Annotations field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, the entries provided by each call will be put on the Annotations field,
+// overwriting an existing map entries in Annotations field with the same key.
+func (b *RtlSdrIngressExposeApplyConfiguration) WithAnnotations(entries map[string]string) *RtlSdrIngressExposeApplyConfiguration {
+	if b.Annotations == nil && len(entries) > 0 {
+		b.Annotations = make(map[string]string, len(entries))
+	}
+	for k, v := range entries {
+		b.Annotations[k] = v
+	}
+	return b
+}
+
+// WithTLSSecretName sets the TLSSecretName field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the TLSSecretName field is set to the value of the last call.
+func (b *RtlSdrIngressExposeApplyConfiguration) WithTLSSecretName(value string) *RtlSdrIngressExposeApplyConfiguration {
+	b.TLSSecretName = &value
+	return b
+}
@@ -0,0 +1,42 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by controller-gen. DO NOT EDIT.
+
+package v1beta1
+
+import (
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RtlSdrLiveTuningApplyConfiguration represents a declarative configuration of the RtlSdrLiveTuning type for use
+// with apply.
+type RtlSdrLiveTuningApplyConfiguration struct {
+	Interval *v1.Duration `json:"interval,omitempty"`
+}
+
+// RtlSdrLiveTuningApplyConfiguration constructs a declarative configuration of the RtlSdrLiveTuning type for use with
+// apply.
+func RtlSdrLiveTuning() *RtlSdrLiveTuningApplyConfiguration {
+	return &RtlSdrLiveTuningApplyConfiguration{}
+}
+
+// WithInterval sets the Interval field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Interval field is set to the value of the last call.
+func (b *RtlSdrLiveTuningApplyConfiguration) WithInterval(value v1.Duration) *RtlSdrLiveTuningApplyConfiguration {
+	b.Interval = &value
+	return b
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by controller-gen. DO NOT EDIT.
+
+package v1beta1
+
+import (
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RtlSdrMQTTApplyConfiguration represents a declarative configuration of the RtlSdrMQTT type for use
+// with apply.
+type RtlSdrMQTTApplyConfiguration struct {
+	Broker               *string                  `json:"broker,omitempty"`
+	Topic                *string                  `json:"topic,omitempty"`
+	QoS                  *int32                   `json:"qos,omitempty"`
+	CredentialsSecretRef *v1.LocalObjectReference `json:"credentialsSecretRef,omitempty"`
+	HeartbeatInterval    *metav1.Duration         `json:"heartbeatInterval,omitempty"`
+	Interval             *metav1.Duration         `json:"interval,omitempty"`
+}
+
+// RtlSdrMQTTApplyConfiguration constructs a declarative configuration of the RtlSdrMQTT type for use with
+// apply.
+func RtlSdrMQTT() *RtlSdrMQTTApplyConfiguration {
+	return &RtlSdrMQTTApplyConfiguration{}
+}
+
+// WithBroker sets the Broker field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Broker field is set to the value of the last call.
+func (b *RtlSdrMQTTApplyConfiguration) WithBroker(value string) *RtlSdrMQTTApplyConfiguration {
+	b.Broker = &value
+	return b
+}
+
+// WithTopic sets the Topic field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Topic field is set to the value of the last call.
+func (b *RtlSdrMQTTApplyConfiguration) WithTopic(value string) *RtlSdrMQTTApplyConfiguration {
+	b.Topic = &value
+	return b
+}
+
+// WithQoS sets the QoS field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the QoS field is set to the value of the last call.
+func (b *RtlSdrMQTTApplyConfiguration) WithQoS(value int32) *RtlSdrMQTTApplyConfiguration {
+	b.QoS = &value
+	return b
+}
+
+// WithCredentialsSecretRef sets the CredentialsSecretRef field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the CredentialsSecretRef field is set to the value of the last call.
+func (b *RtlSdrMQTTApplyConfiguration) WithCredentialsSecretRef(value v1.LocalObjectReference) *RtlSdrMQTTApplyConfiguration {
+	b.CredentialsSecretRef = &value
+	return b
+}
+
+// WithHeartbeatInterval sets the HeartbeatInterval field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the HeartbeatInterval field is set to the value of the last call.
+func (b *RtlSdrMQTTApplyConfiguration) WithHeartbeatInterval(value metav1.Duration) *RtlSdrMQTTApplyConfiguration {
+	b.HeartbeatInterval = &value
+	return b
+}
+
+// WithInterval sets the Interval field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Interval field is set to the value of the last call.
+func (b *RtlSdrMQTTApplyConfiguration) WithInterval(value metav1.Duration) *RtlSdrMQTTApplyConfiguration {
+	b.Interval = &value
+	return b
+}
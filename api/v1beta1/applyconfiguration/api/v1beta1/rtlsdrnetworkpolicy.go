@@ -0,0 +1,44 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by controller-gen. DO NOT EDIT.
+
+package v1beta1
+
+import (
+	v1 "k8s.io/api/networking/v1"
+)
+
+// RtlSdrNetworkPolicyApplyConfiguration represents a declarative configuration of the RtlSdrNetworkPolicy type for use
+// with apply.
+type RtlSdrNetworkPolicyApplyConfiguration struct {
+	From []v1.NetworkPolicyPeer `json:"from,omitempty"`
+}
+
+// RtlSdrNetworkPolicyApplyConfiguration constructs a declarative configuration of the RtlSdrNetworkPolicy type for use with
+// apply.
+func RtlSdrNetworkPolicy() *RtlSdrNetworkPolicyApplyConfiguration {
+	return &RtlSdrNetworkPolicyApplyConfiguration{}
+}
+
+// WithFrom adds the given value to the From field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the From field.
+func (b *RtlSdrNetworkPolicyApplyConfiguration) WithFrom(values ...v1.NetworkPolicyPeer) *RtlSdrNetworkPolicyApplyConfiguration {
+	for i := range values {
+		b.From = append(b.From, values[i])
+	}
+	return b
+}
@@ -0,0 +1,65 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by controller-gen. DO NOT EDIT.
+
+package v1beta1
+
+// RtlSdrPoolAdmissionApplyConfiguration represents a declarative configuration of the RtlSdrPoolAdmission type for use
+// with apply.
+type RtlSdrPoolAdmissionApplyConfiguration struct {
+	Namespace *string `json:"namespace,omitempty"`
+	Name      *string `json:"name,omitempty"`
+	Node      *string `json:"node,omitempty"`
+	Serial    *string `json:"serial,omitempty"`
+}
+
+// RtlSdrPoolAdmissionApplyConfiguration constructs a declarative configuration of the RtlSdrPoolAdmission type for use with
+// apply.
+func RtlSdrPoolAdmission() *RtlSdrPoolAdmissionApplyConfiguration {
+	return &RtlSdrPoolAdmissionApplyConfiguration{}
+}
+
+// WithNamespace sets the Namespace field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Namespace field is set to the value of the last call.
+func (b *RtlSdrPoolAdmissionApplyConfiguration) WithNamespace(value string) *RtlSdrPoolAdmissionApplyConfiguration {
+	b.Namespace = &value
+	return b
+}
+
+// WithName sets the Name field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Name field is set to the value of the last call.
+func (b *RtlSdrPoolAdmissionApplyConfiguration) WithName(value string) *RtlSdrPoolAdmissionApplyConfiguration {
+	b.Name = &value
+	return b
+}
+
+// WithNode sets the Node field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Node field is set to the value of the last call.
+func (b *RtlSdrPoolAdmissionApplyConfiguration) WithNode(value string) *RtlSdrPoolAdmissionApplyConfiguration {
+	b.Node = &value
+	return b
+}
+
+// WithSerial sets the Serial field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Serial field is set to the value of the last call.
+func (b *RtlSdrPoolAdmissionApplyConfiguration) WithSerial(value string) *RtlSdrPoolAdmissionApplyConfiguration {
+	b.Serial = &value
+	return b
+}
@@ -0,0 +1,65 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by controller-gen. DO NOT EDIT.
+
+package v1beta1
+
+// RtlSdrPoolQueueEntryApplyConfiguration represents a declarative configuration of the RtlSdrPoolQueueEntry type for use
+// with apply.
+type RtlSdrPoolQueueEntryApplyConfiguration struct {
+	Namespace *string `json:"namespace,omitempty"`
+	Name      *string `json:"name,omitempty"`
+	Priority  *int32  `json:"priority,omitempty"`
+	Reason    *string `json:"reason,omitempty"`
+}
+
+// RtlSdrPoolQueueEntryApplyConfiguration constructs a declarative configuration of the RtlSdrPoolQueueEntry type for use with
+// apply.
+func RtlSdrPoolQueueEntry() *RtlSdrPoolQueueEntryApplyConfiguration {
+	return &RtlSdrPoolQueueEntryApplyConfiguration{}
+}
+
+// WithNamespace sets the Namespace field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Namespace field is set to the value of the last call.
+func (b *RtlSdrPoolQueueEntryApplyConfiguration) WithNamespace(value string) *RtlSdrPoolQueueEntryApplyConfiguration {
+	b.Namespace = &value
+	return b
+}
+
+// WithName sets the Name field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Name field is set to the value of the last call.
+func (b *RtlSdrPoolQueueEntryApplyConfiguration) WithName(value string) *RtlSdrPoolQueueEntryApplyConfiguration {
+	b.Name = &value
+	return b
+}
+
+// WithPriority sets the Priority field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Priority field is set to the value of the last call.
+func (b *RtlSdrPoolQueueEntryApplyConfiguration) WithPriority(value int32) *RtlSdrPoolQueueEntryApplyConfiguration {
+	b.Priority = &value
+	return b
+}
+
+// WithReason sets the Reason field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Reason field is set to the value of the last call.
+func (b *RtlSdrPoolQueueEntryApplyConfiguration) WithReason(value string) *RtlSdrPoolQueueEntryApplyConfiguration {
+	b.Reason = &value
+	return b
+}
@@ -0,0 +1,47 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by controller-gen. DO NOT EDIT.
+
+package v1beta1
+
+// RtlSdrPoolQuotaApplyConfiguration represents a declarative configuration of the RtlSdrPoolQuota type for use
+// with apply.
+type RtlSdrPoolQuotaApplyConfiguration struct {
+	Namespace *string `json:"namespace,omitempty"`
+	Receivers *int32  `json:"receivers,omitempty"`
+}
+
+// RtlSdrPoolQuotaApplyConfiguration constructs a declarative configuration of the RtlSdrPoolQuota type for use with
+// apply.
+func RtlSdrPoolQuota() *RtlSdrPoolQuotaApplyConfiguration {
+	return &RtlSdrPoolQuotaApplyConfiguration{}
+}
+
+// WithNamespace sets the Namespace field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Namespace field is set to the value of the last call.
+func (b *RtlSdrPoolQuotaApplyConfiguration) WithNamespace(value string) *RtlSdrPoolQuotaApplyConfiguration {
+	b.Namespace = &value
+	return b
+}
+
+// WithReceivers sets the Receivers field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Receivers field is set to the value of the last call.
+func (b *RtlSdrPoolQuotaApplyConfiguration) WithReceivers(value int32) *RtlSdrPoolQuotaApplyConfiguration {
+	b.Receivers = &value
+	return b
+}
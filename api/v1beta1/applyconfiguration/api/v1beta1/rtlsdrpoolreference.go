@@ -0,0 +1,47 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by controller-gen. DO NOT EDIT.
+
+package v1beta1
+
+// RtlSdrPoolReferenceApplyConfiguration represents a declarative configuration of the RtlSdrPoolReference type for use
+// with apply.
+type RtlSdrPoolReferenceApplyConfiguration struct {
+	Name     *string `json:"name,omitempty"`
+	Priority *int32  `json:"priority,omitempty"`
+}
+
+// RtlSdrPoolReferenceApplyConfiguration constructs a declarative configuration of the RtlSdrPoolReference type for use with
+// apply.
+func RtlSdrPoolReference() *RtlSdrPoolReferenceApplyConfiguration {
+	return &RtlSdrPoolReferenceApplyConfiguration{}
+}
+
+// WithName sets the Name field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Name field is set to the value of the last call.
+func (b *RtlSdrPoolReferenceApplyConfiguration) WithName(value string) *RtlSdrPoolReferenceApplyConfiguration {
+	b.Name = &value
+	return b
+}
+
+// WithPriority sets the Priority field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Priority field is set to the value of the last call.
+func (b *RtlSdrPoolReferenceApplyConfiguration) WithPriority(value int32) *RtlSdrPoolReferenceApplyConfiguration {
+	b.Priority = &value
+	return b
+}
@@ -0,0 +1,56 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by controller-gen. DO NOT EDIT.
+
+package v1beta1
+
+// RtlSdrPoolStatusApplyConfiguration represents a declarative configuration of the RtlSdrPoolStatus type for use
+// with apply.
+type RtlSdrPoolStatusApplyConfiguration struct {
+	Node     *string `json:"node,omitempty"`
+	Serial   *string `json:"serial,omitempty"`
+	Position *int32  `json:"position,omitempty"`
+}
+
+// RtlSdrPoolStatusApplyConfiguration constructs a declarative configuration of the RtlSdrPoolStatus type for use with
+// apply.
+func RtlSdrPoolStatus() *RtlSdrPoolStatusApplyConfiguration {
+	return &RtlSdrPoolStatusApplyConfiguration{}
+}
+
+// WithNode sets the Node field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Node field is set to the value of the last call.
+func (b *RtlSdrPoolStatusApplyConfiguration) WithNode(value string) *RtlSdrPoolStatusApplyConfiguration {
+	b.Node = &value
+	return b
+}
+
+// WithSerial sets the Serial field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Serial field is set to the value of the last call.
+func (b *RtlSdrPoolStatusApplyConfiguration) WithSerial(value string) *RtlSdrPoolStatusApplyConfiguration {
+	b.Serial = &value
+	return b
+}
+
+// WithPosition sets the Position field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Position field is set to the value of the last call.
+func (b *RtlSdrPoolStatusApplyConfiguration) WithPosition(value int32) *RtlSdrPoolStatusApplyConfiguration {
+	b.Position = &value
+	return b
+}
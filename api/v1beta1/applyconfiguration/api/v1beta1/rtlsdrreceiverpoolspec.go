@@ -0,0 +1,76 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by controller-gen. DO NOT EDIT.
+
+package v1beta1
+
+import (
+	v1 "k8s.io/client-go/applyconfigurations/meta/v1"
+)
+
+// RtlSdrReceiverPoolSpecApplyConfiguration represents a declarative configuration of the RtlSdrReceiverPoolSpec type for use
+// with apply.
+type RtlSdrReceiverPoolSpecApplyConfiguration struct {
+	NodeSelector *v1.LabelSelectorApplyConfiguration `json:"nodeSelector,omitempty"`
+	Serials      []string                            `json:"serials,omitempty"`
+	Quotas       []RtlSdrPoolQuotaApplyConfiguration `json:"quotas,omitempty"`
+	DefaultQuota *int32                              `json:"defaultQuota,omitempty"`
+}
+
+// RtlSdrReceiverPoolSpecApplyConfiguration constructs a declarative configuration of the RtlSdrReceiverPoolSpec type for use with
+// apply.
+func RtlSdrReceiverPoolSpec() *RtlSdrReceiverPoolSpecApplyConfiguration {
+	return &RtlSdrReceiverPoolSpecApplyConfiguration{}
+}
+
+// WithNodeSelector sets the NodeSelector field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the NodeSelector field is set to the value of the last call.
+func (b *RtlSdrReceiverPoolSpecApplyConfiguration) WithNodeSelector(value *v1.LabelSelectorApplyConfiguration) *RtlSdrReceiverPoolSpecApplyConfiguration {
+	b.NodeSelector = value
+	return b
+}
+
+// WithSerials adds the given value to the Serials field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the Serials field.
+func (b *RtlSdrReceiverPoolSpecApplyConfiguration) WithSerials(values ...string) *RtlSdrReceiverPoolSpecApplyConfiguration {
+	for i := range values {
+		b.Serials = append(b.Serials, values[i])
+	}
+	return b
+}
+
+// WithQuotas adds the given value to the Quotas field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the Quotas field.
+func (b *RtlSdrReceiverPoolSpecApplyConfiguration) WithQuotas(values ...*RtlSdrPoolQuotaApplyConfiguration) *RtlSdrReceiverPoolSpecApplyConfiguration {
+	for i := range values {
+		if values[i] == nil {
+			panic("nil value passed to WithQuotas")
+		}
+		b.Quotas = append(b.Quotas, *values[i])
+	}
+	return b
+}
+
+// WithDefaultQuota sets the DefaultQuota field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the DefaultQuota field is set to the value of the last call.
+func (b *RtlSdrReceiverPoolSpecApplyConfiguration) WithDefaultQuota(value int32) *RtlSdrReceiverPoolSpecApplyConfiguration {
+	b.DefaultQuota = &value
+	return b
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by controller-gen. DO NOT EDIT.
+
+package v1beta1
+
+// RtlSdrReceiverPoolStatusApplyConfiguration represents a declarative configuration of the RtlSdrReceiverPoolStatus type for use
+// with apply.
+type RtlSdrReceiverPoolStatusApplyConfiguration struct {
+	Capacity  *int32                                   `json:"capacity,omitempty"`
+	Allocated *int32                                   `json:"allocated,omitempty"`
+	Queued    *int32                                   `json:"queued,omitempty"`
+	Admitted  []RtlSdrPoolAdmissionApplyConfiguration  `json:"admitted,omitempty"`
+	Queue     []RtlSdrPoolQueueEntryApplyConfiguration `json:"queue,omitempty"`
+}
+
+// RtlSdrReceiverPoolStatusApplyConfiguration constructs a declarative configuration of the RtlSdrReceiverPoolStatus type for use with
+// apply.
+func RtlSdrReceiverPoolStatus() *RtlSdrReceiverPoolStatusApplyConfiguration {
+	return &RtlSdrReceiverPoolStatusApplyConfiguration{}
+}
+
+// WithCapacity sets the Capacity field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Capacity field is set to the value of the last call.
+func (b *RtlSdrReceiverPoolStatusApplyConfiguration) WithCapacity(value int32) *RtlSdrReceiverPoolStatusApplyConfiguration {
+	b.Capacity = &value
+	return b
+}
+
+// WithAllocated sets the Allocated field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Allocated field is set to the value of the last call.
+func (b *RtlSdrReceiverPoolStatusApplyConfiguration) WithAllocated(value int32) *RtlSdrReceiverPoolStatusApplyConfiguration {
+	b.Allocated = &value
+	return b
+}
+
+// WithQueued sets the Queued field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Queued field is set to the value of the last call.
+func (b *RtlSdrReceiverPoolStatusApplyConfiguration) WithQueued(value int32) *RtlSdrReceiverPoolStatusApplyConfiguration {
+	b.Queued = &value
+	return b
+}
+
+// WithAdmitted adds the given value to the Admitted field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the Admitted field.
+func (b *RtlSdrReceiverPoolStatusApplyConfiguration) WithAdmitted(values ...*RtlSdrPoolAdmissionApplyConfiguration) *RtlSdrReceiverPoolStatusApplyConfiguration {
+	for i := range values {
+		if values[i] == nil {
+			panic("nil value passed to WithAdmitted")
+		}
+		b.Admitted = append(b.Admitted, *values[i])
+	}
+	return b
+}
+
+// WithQueue adds the given value to the Queue field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the Queue field.
+func (b *RtlSdrReceiverPoolStatusApplyConfiguration) WithQueue(values ...*RtlSdrPoolQueueEntryApplyConfiguration) *RtlSdrReceiverPoolStatusApplyConfiguration {
+	for i := range values {
+		if values[i] == nil {
+			panic("nil value passed to WithQueue")
+		}
+		b.Queue = append(b.Queue, *values[i])
+	}
+	return b
+}
@@ -0,0 +1,242 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by controller-gen. DO NOT EDIT.
+
+package v1beta1
+
+import (
+	apiv1beta1 "github.com/frelon/k8s-radio/api/v1beta1"
+	v1 "k8s.io/api/core/v1"
+	resource "k8s.io/apimachinery/pkg/api/resource"
+)
+
+// RtlSdrReceiverSpecApplyConfiguration represents a declarative configuration of the RtlSdrReceiverSpec type for use
+// with apply.
+type RtlSdrReceiverSpecApplyConfiguration struct {
+	Version                       *apiv1beta1.RtlSdrVersion              `json:"version,omitempty"`
+	Frequency                     *resource.Quantity                     `json:"frequency,omitempty"`
+	SampleRate                    *resource.Quantity                     `json:"sampleRate,omitempty"`
+	Gain                          *resource.Quantity                     `json:"gain,omitempty"`
+	LiveTuning                    *RtlSdrLiveTuningApplyConfiguration    `json:"liveTuning,omitempty"`
+	ContainerPort                 *v1.ContainerPort                      `json:"port,omitempty"`
+	Mode                          *apiv1beta1.RtlSdrMode                 `json:"mode,omitempty"`
+	Workload                      *apiv1beta1.RtlSdrWorkload             `json:"workload,omitempty"`
+	TerminationGracePeriodSeconds *int64                                 `json:"terminationGracePeriodSeconds,omitempty"`
+	Simulation                    *RtlSdrSimulationApplyConfiguration    `json:"simulation,omitempty"`
+	Sharing                       *RtlSdrSharingApplyConfiguration       `json:"sharing,omitempty"`
+	Recording                     *RtlSdrRecordingApplyConfiguration     `json:"recording,omitempty"`
+	Schedule                      *RtlSdrScheduleApplyConfiguration      `json:"schedule,omitempty"`
+	Scan                          *RtlSdrScanApplyConfiguration          `json:"scan,omitempty"`
+	Spectrum                      *RtlSdrSpectrumApplyConfiguration      `json:"spectrum,omitempty"`
+	Events                        *RtlSdrEventsApplyConfiguration        `json:"events,omitempty"`
+	MQTT                          *RtlSdrMQTTApplyConfiguration          `json:"mqtt,omitempty"`
+	Metrics                       *RtlSdrMetricsApplyConfiguration       `json:"metrics,omitempty"`
+	SampleLoss                    *RtlSdrSampleLossApplyConfiguration    `json:"sampleLoss,omitempty"`
+	Access                        *RtlSdrAccessApplyConfiguration        `json:"access,omitempty"`
+	NetworkPolicy                 *RtlSdrNetworkPolicyApplyConfiguration `json:"networkPolicy,omitempty"`
+	Expose                        *RtlSdrExposeApplyConfiguration        `json:"expose,omitempty"`
+	Pool                          *RtlSdrPoolReferenceApplyConfiguration `json:"pool,omitempty"`
+}
+
+// RtlSdrReceiverSpecApplyConfiguration constructs a declarative configuration of the RtlSdrReceiverSpec type for use with
+// apply.
+func RtlSdrReceiverSpec() *RtlSdrReceiverSpecApplyConfiguration {
+	return &RtlSdrReceiverSpecApplyConfiguration{}
+}
+
+// WithVersion sets the Version field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Version field is set to the value of the last call.
+func (b *RtlSdrReceiverSpecApplyConfiguration) WithVersion(value apiv1beta1.RtlSdrVersion) *RtlSdrReceiverSpecApplyConfiguration {
+	b.Version = &value
+	return b
+}
+
+// WithFrequency sets the Frequency field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Frequency field is set to the value of the last call.
+func (b *RtlSdrReceiverSpecApplyConfiguration) WithFrequency(value resource.Quantity) *RtlSdrReceiverSpecApplyConfiguration {
+	b.Frequency = &value
+	return b
+}
+
+// WithSampleRate sets the SampleRate field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the SampleRate field is set to the value of the last call.
+func (b *RtlSdrReceiverSpecApplyConfiguration) WithSampleRate(value resource.Quantity) *RtlSdrReceiverSpecApplyConfiguration {
+	b.SampleRate = &value
+	return b
+}
+
+// WithGain sets the Gain field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Gain field is set to the value of the last call.
+func (b *RtlSdrReceiverSpecApplyConfiguration) WithGain(value resource.Quantity) *RtlSdrReceiverSpecApplyConfiguration {
+	b.Gain = &value
+	return b
+}
+
+// WithLiveTuning sets the LiveTuning field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the LiveTuning field is set to the value of the last call.
+func (b *RtlSdrReceiverSpecApplyConfiguration) WithLiveTuning(value *RtlSdrLiveTuningApplyConfiguration) *RtlSdrReceiverSpecApplyConfiguration {
+	b.LiveTuning = value
+	return b
+}
+
+// WithContainerPort sets the ContainerPort field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the ContainerPort field is set to the value of the last call.
+func (b *RtlSdrReceiverSpecApplyConfiguration) WithContainerPort(value v1.ContainerPort) *RtlSdrReceiverSpecApplyConfiguration {
+	b.ContainerPort = &value
+	return b
+}
+
+// WithMode sets the Mode field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Mode field is set to the value of the last call.
+func (b *RtlSdrReceiverSpecApplyConfiguration) WithMode(value apiv1beta1.RtlSdrMode) *RtlSdrReceiverSpecApplyConfiguration {
+	b.Mode = &value
+	return b
+}
+
+// WithWorkload sets the Workload field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Workload field is set to the value of the last call.
+func (b *RtlSdrReceiverSpecApplyConfiguration) WithWorkload(value apiv1beta1.RtlSdrWorkload) *RtlSdrReceiverSpecApplyConfiguration {
+	b.Workload = &value
+	return b
+}
+
+// WithTerminationGracePeriodSeconds sets the TerminationGracePeriodSeconds field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the TerminationGracePeriodSeconds field is set to the value of the last call.
+func (b *RtlSdrReceiverSpecApplyConfiguration) WithTerminationGracePeriodSeconds(value int64) *RtlSdrReceiverSpecApplyConfiguration {
+	b.TerminationGracePeriodSeconds = &value
+	return b
+}
+
+// WithSimulation sets the Simulation field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Simulation field is set to the value of the last call.
+func (b *RtlSdrReceiverSpecApplyConfiguration) WithSimulation(value *RtlSdrSimulationApplyConfiguration) *RtlSdrReceiverSpecApplyConfiguration {
+	b.Simulation = value
+	return b
+}
+
+// WithSharing sets the Sharing field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Sharing field is set to the value of the last call.
+func (b *RtlSdrReceiverSpecApplyConfiguration) WithSharing(value *RtlSdrSharingApplyConfiguration) *RtlSdrReceiverSpecApplyConfiguration {
+	b.Sharing = value
+	return b
+}
+
+// WithRecording sets the Recording field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Recording field is set to the value of the last call.
+func (b *RtlSdrReceiverSpecApplyConfiguration) WithRecording(value *RtlSdrRecordingApplyConfiguration) *RtlSdrReceiverSpecApplyConfiguration {
+	b.Recording = value
+	return b
+}
+
+// WithSchedule sets the Schedule field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Schedule field is set to the value of the last call.
+func (b *RtlSdrReceiverSpecApplyConfiguration) WithSchedule(value *RtlSdrScheduleApplyConfiguration) *RtlSdrReceiverSpecApplyConfiguration {
+	b.Schedule = value
+	return b
+}
+
+// WithScan sets the Scan field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Scan field is set to the value of the last call.
+func (b *RtlSdrReceiverSpecApplyConfiguration) WithScan(value *RtlSdrScanApplyConfiguration) *RtlSdrReceiverSpecApplyConfiguration {
+	b.Scan = value
+	return b
+}
+
+// WithSpectrum sets the Spectrum field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Spectrum field is set to the value of the last call.
+func (b *RtlSdrReceiverSpecApplyConfiguration) WithSpectrum(value *RtlSdrSpectrumApplyConfiguration) *RtlSdrReceiverSpecApplyConfiguration {
+	b.Spectrum = value
+	return b
+}
+
+// WithEvents sets the Events field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Events field is set to the value of the last call.
+func (b *RtlSdrReceiverSpecApplyConfiguration) WithEvents(value *RtlSdrEventsApplyConfiguration) *RtlSdrReceiverSpecApplyConfiguration {
+	b.Events = value
+	return b
+}
+
+// WithMQTT sets the MQTT field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the MQTT field is set to the value of the last call.
+func (b *RtlSdrReceiverSpecApplyConfiguration) WithMQTT(value *RtlSdrMQTTApplyConfiguration) *RtlSdrReceiverSpecApplyConfiguration {
+	b.MQTT = value
+	return b
+}
+
+// WithMetrics sets the Metrics field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Metrics field is set to the value of the last call.
+func (b *RtlSdrReceiverSpecApplyConfiguration) WithMetrics(value *RtlSdrMetricsApplyConfiguration) *RtlSdrReceiverSpecApplyConfiguration {
+	b.Metrics = value
+	return b
+}
+
+// WithSampleLoss sets the SampleLoss field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the SampleLoss field is set to the value of the last call.
+func (b *RtlSdrReceiverSpecApplyConfiguration) WithSampleLoss(value *RtlSdrSampleLossApplyConfiguration) *RtlSdrReceiverSpecApplyConfiguration {
+	b.SampleLoss = value
+	return b
+}
+
+// WithAccess sets the Access field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Access field is set to the value of the last call.
+func (b *RtlSdrReceiverSpecApplyConfiguration) WithAccess(value *RtlSdrAccessApplyConfiguration) *RtlSdrReceiverSpecApplyConfiguration {
+	b.Access = value
+	return b
+}
+
+// WithNetworkPolicy sets the NetworkPolicy field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the NetworkPolicy field is set to the value of the last call.
+func (b *RtlSdrReceiverSpecApplyConfiguration) WithNetworkPolicy(value *RtlSdrNetworkPolicyApplyConfiguration) *RtlSdrReceiverSpecApplyConfiguration {
+	b.NetworkPolicy = value
+	return b
+}
+
+// WithExpose sets the Expose field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Expose field is set to the value of the last call.
+func (b *RtlSdrReceiverSpecApplyConfiguration) WithExpose(value *RtlSdrExposeApplyConfiguration) *RtlSdrReceiverSpecApplyConfiguration {
+	b.Expose = value
+	return b
+}
+
+// WithPool sets the Pool field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Pool field is set to the value of the last call.
+func (b *RtlSdrReceiverSpecApplyConfiguration) WithPool(value *RtlSdrPoolReferenceApplyConfiguration) *RtlSdrReceiverSpecApplyConfiguration {
+	b.Pool = value
+	return b
+}
@@ -0,0 +1,185 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by controller-gen. DO NOT EDIT.
+
+package v1beta1
+
+import (
+	apiv1beta1 "github.com/frelon/k8s-radio/api/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	resource "k8s.io/apimachinery/pkg/api/resource"
+	v1 "k8s.io/client-go/applyconfigurations/meta/v1"
+)
+
+// RtlSdrReceiverStatusApplyConfiguration represents a declarative configuration of the RtlSdrReceiverStatus type for use
+// with apply.
+type RtlSdrReceiverStatusApplyConfiguration struct {
+	Conditions         []v1.ConditionApplyConfiguration          `json:"conditions,omitempty"`
+	State              *apiv1beta1.RtlSdrReceiverState           `json:"state,omitempty"`
+	Pod                *corev1.ObjectReference                   `json:"pod,omitempty"`
+	Endpoint           *string                                   `json:"endpoint,omitempty"`
+	Deployment         *corev1.ObjectReference                   `json:"deployment,omitempty"`
+	Recording          *RtlSdrRecordingStatusApplyConfiguration  `json:"recording,omitempty"`
+	Schedule           *RtlSdrScheduleStatusApplyConfiguration   `json:"schedule,omitempty"`
+	Scan               *RtlSdrScanStatusApplyConfiguration       `json:"scan,omitempty"`
+	ADSB               *RtlSdrADSBStatusApplyConfiguration       `json:"adsb,omitempty"`
+	SpectrumURL        *string                                   `json:"spectrumURL,omitempty"`
+	SampleLoss         *RtlSdrSampleLossStatusApplyConfiguration `json:"sampleLoss,omitempty"`
+	Access             *RtlSdrAccessStatusApplyConfiguration     `json:"access,omitempty"`
+	Expose             *RtlSdrExposeStatusApplyConfiguration     `json:"expose,omitempty"`
+	ObservedGeneration *int64                                    `json:"observedGeneration,omitempty"`
+	ObservedFrequency  *resource.Quantity                        `json:"observedFrequency,omitempty"`
+	Pool               *RtlSdrPoolStatusApplyConfiguration       `json:"pool,omitempty"`
+}
+
+// RtlSdrReceiverStatusApplyConfiguration constructs a declarative configuration of the RtlSdrReceiverStatus type for use with
+// apply.
+func RtlSdrReceiverStatus() *RtlSdrReceiverStatusApplyConfiguration {
+	return &RtlSdrReceiverStatusApplyConfiguration{}
+}
+
+// WithConditions adds the given value to the Conditions field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the Conditions field.
+func (b *RtlSdrReceiverStatusApplyConfiguration) WithConditions(values ...*v1.ConditionApplyConfiguration) *RtlSdrReceiverStatusApplyConfiguration {
+	for i := range values {
+		if values[i] == nil {
+			panic("nil value passed to WithConditions")
+		}
+		b.Conditions = append(b.Conditions, *values[i])
+	}
+	return b
+}
+
+// WithState sets the State field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the State field is set to the value of the last call.
+func (b *RtlSdrReceiverStatusApplyConfiguration) WithState(value apiv1beta1.RtlSdrReceiverState) *RtlSdrReceiverStatusApplyConfiguration {
+	b.State = &value
+	return b
+}
+
+// WithPod sets the Pod field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Pod field is set to the value of the last call.
+func (b *RtlSdrReceiverStatusApplyConfiguration) WithPod(value corev1.ObjectReference) *RtlSdrReceiverStatusApplyConfiguration {
+	b.Pod = &value
+	return b
+}
+
+// WithEndpoint sets the Endpoint field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Endpoint field is set to the value of the last call.
+func (b *RtlSdrReceiverStatusApplyConfiguration) WithEndpoint(value string) *RtlSdrReceiverStatusApplyConfiguration {
+	b.Endpoint = &value
+	return b
+}
+
+// WithDeployment sets the Deployment field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Deployment field is set to the value of the last call.
+func (b *RtlSdrReceiverStatusApplyConfiguration) WithDeployment(value corev1.ObjectReference) *RtlSdrReceiverStatusApplyConfiguration {
+	b.Deployment = &value
+	return b
+}
+
+// WithRecording sets the Recording field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Recording field is set to the value of the last call.
+func (b *RtlSdrReceiverStatusApplyConfiguration) WithRecording(value *RtlSdrRecordingStatusApplyConfiguration) *RtlSdrReceiverStatusApplyConfiguration {
+	b.Recording = value
+	return b
+}
+
+// WithSchedule sets the Schedule field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Schedule field is set to the value of the last call.
+func (b *RtlSdrReceiverStatusApplyConfiguration) WithSchedule(value *RtlSdrScheduleStatusApplyConfiguration) *RtlSdrReceiverStatusApplyConfiguration {
+	b.Schedule = value
+	return b
+}
+
+// WithScan sets the Scan field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Scan field is set to the value of the last call.
+func (b *RtlSdrReceiverStatusApplyConfiguration) WithScan(value *RtlSdrScanStatusApplyConfiguration) *RtlSdrReceiverStatusApplyConfiguration {
+	b.Scan = value
+	return b
+}
+
+// WithADSB sets the ADSB field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the ADSB field is set to the value of the last call.
+func (b *RtlSdrReceiverStatusApplyConfiguration) WithADSB(value *RtlSdrADSBStatusApplyConfiguration) *RtlSdrReceiverStatusApplyConfiguration {
+	b.ADSB = value
+	return b
+}
+
+// WithSpectrumURL sets the SpectrumURL field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the SpectrumURL field is set to the value of the last call.
+func (b *RtlSdrReceiverStatusApplyConfiguration) WithSpectrumURL(value string) *RtlSdrReceiverStatusApplyConfiguration {
+	b.SpectrumURL = &value
+	return b
+}
+
+// WithSampleLoss sets the SampleLoss field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the SampleLoss field is set to the value of the last call.
+func (b *RtlSdrReceiverStatusApplyConfiguration) WithSampleLoss(value *RtlSdrSampleLossStatusApplyConfiguration) *RtlSdrReceiverStatusApplyConfiguration {
+	b.SampleLoss = value
+	return b
+}
+
+// WithAccess sets the Access field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Access field is set to the value of the last call.
+func (b *RtlSdrReceiverStatusApplyConfiguration) WithAccess(value *RtlSdrAccessStatusApplyConfiguration) *RtlSdrReceiverStatusApplyConfiguration {
+	b.Access = value
+	return b
+}
+
+// WithExpose sets the Expose field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Expose field is set to the value of the last call.
+func (b *RtlSdrReceiverStatusApplyConfiguration) WithExpose(value *RtlSdrExposeStatusApplyConfiguration) *RtlSdrReceiverStatusApplyConfiguration {
+	b.Expose = value
+	return b
+}
+
+// WithObservedGeneration sets the ObservedGeneration field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the ObservedGeneration field is set to the value of the last call.
+func (b *RtlSdrReceiverStatusApplyConfiguration) WithObservedGeneration(value int64) *RtlSdrReceiverStatusApplyConfiguration {
+	b.ObservedGeneration = &value
+	return b
+}
+
+// WithObservedFrequency sets the ObservedFrequency field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the ObservedFrequency field is set to the value of the last call.
+func (b *RtlSdrReceiverStatusApplyConfiguration) WithObservedFrequency(value resource.Quantity) *RtlSdrReceiverStatusApplyConfiguration {
+	b.ObservedFrequency = &value
+	return b
+}
+
+// WithPool sets the Pool field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Pool field is set to the value of the last call.
+func (b *RtlSdrReceiverStatusApplyConfiguration) WithPool(value *RtlSdrPoolStatusApplyConfiguration) *RtlSdrReceiverStatusApplyConfiguration {
+	b.Pool = value
+	return b
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by controller-gen. DO NOT EDIT.
+
+package v1beta1
+
+import (
+	resource "k8s.io/apimachinery/pkg/api/resource"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RtlSdrRecordingApplyConfiguration represents a declarative configuration of the RtlSdrRecording type for use
+// with apply.
+type RtlSdrRecordingApplyConfiguration struct {
+	ClaimName *string                           `json:"claimName,omitempty"`
+	Path      *string                           `json:"path,omitempty"`
+	Duration  *v1.Duration                      `json:"duration,omitempty"`
+	MaxSize   *resource.Quantity                `json:"maxSize,omitempty"`
+	Rotation  *RtlSdrRotationApplyConfiguration `json:"rotation,omitempty"`
+}
+
+// RtlSdrRecordingApplyConfiguration constructs a declarative configuration of the RtlSdrRecording type for use with
+// apply.
+func RtlSdrRecording() *RtlSdrRecordingApplyConfiguration {
+	return &RtlSdrRecordingApplyConfiguration{}
+}
+
+// WithClaimName sets the ClaimName field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the ClaimName field is set to the value of the last call.
+func (b *RtlSdrRecordingApplyConfiguration) WithClaimName(value string) *RtlSdrRecordingApplyConfiguration {
+	b.ClaimName = &value
+	return b
+}
+
+// WithPath sets the Path field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Path field is set to the value of the last call.
+func (b *RtlSdrRecordingApplyConfiguration) WithPath(value string) *RtlSdrRecordingApplyConfiguration {
+	b.Path = &value
+	return b
+}
+
+// WithDuration sets the Duration field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Duration field is set to the value of the last call.
+func (b *RtlSdrRecordingApplyConfiguration) WithDuration(value v1.Duration) *RtlSdrRecordingApplyConfiguration {
+	b.Duration = &value
+	return b
+}
+
+// WithMaxSize sets the MaxSize field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the MaxSize field is set to the value of the last call.
+func (b *RtlSdrRecordingApplyConfiguration) WithMaxSize(value resource.Quantity) *RtlSdrRecordingApplyConfiguration {
+	b.MaxSize = &value
+	return b
+}
+
+// WithRotation sets the Rotation field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Rotation field is set to the value of the last call.
+func (b *RtlSdrRecordingApplyConfiguration) WithRotation(value *RtlSdrRotationApplyConfiguration) *RtlSdrRecordingApplyConfiguration {
+	b.Rotation = value
+	return b
+}
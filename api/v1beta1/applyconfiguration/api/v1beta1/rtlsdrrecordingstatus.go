@@ -0,0 +1,58 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by controller-gen. DO NOT EDIT.
+
+package v1beta1
+
+// RtlSdrRecordingStatusApplyConfiguration represents a declarative configuration of the RtlSdrRecordingStatus type for use
+// with apply.
+type RtlSdrRecordingStatusApplyConfiguration struct {
+	Files    []string `json:"files,omitempty"`
+	Bytes    *int64   `json:"bytes,omitempty"`
+	Finished *bool    `json:"finished,omitempty"`
+}
+
+// RtlSdrRecordingStatusApplyConfiguration constructs a declarative configuration of the RtlSdrRecordingStatus type for use with
+// apply.
+func RtlSdrRecordingStatus() *RtlSdrRecordingStatusApplyConfiguration {
+	return &RtlSdrRecordingStatusApplyConfiguration{}
+}
+
+// WithFiles adds the given value to the Files field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the Files field.
+func (b *RtlSdrRecordingStatusApplyConfiguration) WithFiles(values ...string) *RtlSdrRecordingStatusApplyConfiguration {
+	for i := range values {
+		b.Files = append(b.Files, values[i])
+	}
+	return b
+}
+
+// WithBytes sets the Bytes field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Bytes field is set to the value of the last call.
+func (b *RtlSdrRecordingStatusApplyConfiguration) WithBytes(value int64) *RtlSdrRecordingStatusApplyConfiguration {
+	b.Bytes = &value
+	return b
+}
+
+// WithFinished sets the Finished field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Finished field is set to the value of the last call.
+func (b *RtlSdrRecordingStatusApplyConfiguration) WithFinished(value bool) *RtlSdrRecordingStatusApplyConfiguration {
+	b.Finished = &value
+	return b
+}
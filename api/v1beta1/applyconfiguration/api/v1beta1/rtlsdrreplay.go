@@ -0,0 +1,47 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by controller-gen. DO NOT EDIT.
+
+package v1beta1
+
+// RtlSdrReplayApplyConfiguration represents a declarative configuration of the RtlSdrReplay type for use
+// with apply.
+type RtlSdrReplayApplyConfiguration struct {
+	ClaimName *string `json:"claimName,omitempty"`
+	Path      *string `json:"path,omitempty"`
+}
+
+// RtlSdrReplayApplyConfiguration constructs a declarative configuration of the RtlSdrReplay type for use with
+// apply.
+func RtlSdrReplay() *RtlSdrReplayApplyConfiguration {
+	return &RtlSdrReplayApplyConfiguration{}
+}
+
+// WithClaimName sets the ClaimName field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the ClaimName field is set to the value of the last call.
+func (b *RtlSdrReplayApplyConfiguration) WithClaimName(value string) *RtlSdrReplayApplyConfiguration {
+	b.ClaimName = &value
+	return b
+}
+
+// WithPath sets the Path field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Path field is set to the value of the last call.
+func (b *RtlSdrReplayApplyConfiguration) WithPath(value string) *RtlSdrReplayApplyConfiguration {
+	b.Path = &value
+	return b
+}
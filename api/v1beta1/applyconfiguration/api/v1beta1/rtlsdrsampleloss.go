@@ -0,0 +1,51 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by controller-gen. DO NOT EDIT.
+
+package v1beta1
+
+import (
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RtlSdrSampleLossApplyConfiguration represents a declarative configuration of the RtlSdrSampleLoss type for use
+// with apply.
+type RtlSdrSampleLossApplyConfiguration struct {
+	Threshold *string      `json:"threshold,omitempty"`
+	Window    *v1.Duration `json:"window,omitempty"`
+}
+
+// RtlSdrSampleLossApplyConfiguration constructs a declarative configuration of the RtlSdrSampleLoss type for use with
+// apply.
+func RtlSdrSampleLoss() *RtlSdrSampleLossApplyConfiguration {
+	return &RtlSdrSampleLossApplyConfiguration{}
+}
+
+// WithThreshold sets the Threshold field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Threshold field is set to the value of the last call.
+func (b *RtlSdrSampleLossApplyConfiguration) WithThreshold(value string) *RtlSdrSampleLossApplyConfiguration {
+	b.Threshold = &value
+	return b
+}
+
+// WithWindow sets the Window field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Window field is set to the value of the last call.
+func (b *RtlSdrSampleLossApplyConfiguration) WithWindow(value v1.Duration) *RtlSdrSampleLossApplyConfiguration {
+	b.Window = &value
+	return b
+}
@@ -0,0 +1,56 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by controller-gen. DO NOT EDIT.
+
+package v1beta1
+
+// RtlSdrSampleLossStatusApplyConfiguration represents a declarative configuration of the RtlSdrSampleLossStatus type for use
+// with apply.
+type RtlSdrSampleLossStatusApplyConfiguration struct {
+	DroppedSamples *int64  `json:"droppedSamples,omitempty"`
+	DroppedBuffers *int64  `json:"droppedBuffers,omitempty"`
+	Ratio          *string `json:"ratio,omitempty"`
+}
+
+// RtlSdrSampleLossStatusApplyConfiguration constructs a declarative configuration of the RtlSdrSampleLossStatus type for use with
+// apply.
+func RtlSdrSampleLossStatus() *RtlSdrSampleLossStatusApplyConfiguration {
+	return &RtlSdrSampleLossStatusApplyConfiguration{}
+}
+
+// WithDroppedSamples sets the DroppedSamples field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the DroppedSamples field is set to the value of the last call.
+func (b *RtlSdrSampleLossStatusApplyConfiguration) WithDroppedSamples(value int64) *RtlSdrSampleLossStatusApplyConfiguration {
+	b.DroppedSamples = &value
+	return b
+}
+
+// WithDroppedBuffers sets the DroppedBuffers field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the DroppedBuffers field is set to the value of the last call.
+func (b *RtlSdrSampleLossStatusApplyConfiguration) WithDroppedBuffers(value int64) *RtlSdrSampleLossStatusApplyConfiguration {
+	b.DroppedBuffers = &value
+	return b
+}
+
+// WithRatio sets the Ratio field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Ratio field is set to the value of the last call.
+func (b *RtlSdrSampleLossStatusApplyConfiguration) WithRatio(value string) *RtlSdrSampleLossStatusApplyConfiguration {
+	b.Ratio = &value
+	return b
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by controller-gen. DO NOT EDIT.
+
+package v1beta1
+
+import (
+	resource "k8s.io/apimachinery/pkg/api/resource"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RtlSdrScanApplyConfiguration represents a declarative configuration of the RtlSdrScan type for use
+// with apply.
+type RtlSdrScanApplyConfiguration struct {
+	Frequencies []resource.Quantity                `json:"frequencies,omitempty"`
+	Range       *RtlSdrScanRangeApplyConfiguration `json:"range,omitempty"`
+	Bandwidth   *resource.Quantity                 `json:"bandwidth,omitempty"`
+	Squelch     *int32                             `json:"squelch,omitempty"`
+	Dwell       *v1.Duration                       `json:"dwell,omitempty"`
+	Hold        *v1.Duration                       `json:"hold,omitempty"`
+	MaxHits     *int32                             `json:"maxHits,omitempty"`
+}
+
+// RtlSdrScanApplyConfiguration constructs a declarative configuration of the RtlSdrScan type for use with
+// apply.
+func RtlSdrScan() *RtlSdrScanApplyConfiguration {
+	return &RtlSdrScanApplyConfiguration{}
+}
+
+// WithFrequencies adds the given value to the Frequencies field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the Frequencies field.
+func (b *RtlSdrScanApplyConfiguration) WithFrequencies(values ...resource.Quantity) *RtlSdrScanApplyConfiguration {
+	for i := range values {
+		b.Frequencies = append(b.Frequencies, values[i])
+	}
+	return b
+}
+
+// WithRange sets the Range field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Range field is set to the value of the last call.
+func (b *RtlSdrScanApplyConfiguration) WithRange(value *RtlSdrScanRangeApplyConfiguration) *RtlSdrScanApplyConfiguration {
+	b.Range = value
+	return b
+}
+
+// WithBandwidth sets the Bandwidth field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Bandwidth field is set to the value of the last call.
+func (b *RtlSdrScanApplyConfiguration) WithBandwidth(value resource.Quantity) *RtlSdrScanApplyConfiguration {
+	b.Bandwidth = &value
+	return b
+}
+
+// WithSquelch sets the Squelch field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Squelch field is set to the value of the last call.
+func (b *RtlSdrScanApplyConfiguration) WithSquelch(value int32) *RtlSdrScanApplyConfiguration {
+	b.Squelch = &value
+	return b
+}
+
+// WithDwell sets the Dwell field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Dwell field is set to the value of the last call.
+func (b *RtlSdrScanApplyConfiguration) WithDwell(value v1.Duration) *RtlSdrScanApplyConfiguration {
+	b.Dwell = &value
+	return b
+}
+
+// WithHold sets the Hold field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Hold field is set to the value of the last call.
+func (b *RtlSdrScanApplyConfiguration) WithHold(value v1.Duration) *RtlSdrScanApplyConfiguration {
+	b.Hold = &value
+	return b
+}
+
+// WithMaxHits sets the MaxHits field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the MaxHits field is set to the value of the last call.
+func (b *RtlSdrScanApplyConfiguration) WithMaxHits(value int32) *RtlSdrScanApplyConfiguration {
+	b.MaxHits = &value
+	return b
+}
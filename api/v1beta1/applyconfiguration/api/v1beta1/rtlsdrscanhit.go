@@ -0,0 +1,70 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by controller-gen. DO NOT EDIT.
+
+package v1beta1
+
+import (
+	resource "k8s.io/apimachinery/pkg/api/resource"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RtlSdrScanHitApplyConfiguration represents a declarative configuration of the RtlSdrScanHit type for use
+// with apply.
+type RtlSdrScanHitApplyConfiguration struct {
+	Frequency *resource.Quantity `json:"frequency,omitempty"`
+	Power     *int32             `json:"power,omitempty"`
+	FirstSeen *v1.Time           `json:"firstSeen,omitempty"`
+	LastSeen  *v1.Time           `json:"lastSeen,omitempty"`
+}
+
+// RtlSdrScanHitApplyConfiguration constructs a declarative configuration of the RtlSdrScanHit type for use with
+// apply.
+func RtlSdrScanHit() *RtlSdrScanHitApplyConfiguration {
+	return &RtlSdrScanHitApplyConfiguration{}
+}
+
+// WithFrequency sets the Frequency field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Frequency field is set to the value of the last call.
+func (b *RtlSdrScanHitApplyConfiguration) WithFrequency(value resource.Quantity) *RtlSdrScanHitApplyConfiguration {
+	b.Frequency = &value
+	return b
+}
+
+// WithPower sets the Power field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Power field is set to the value of the last call.
+func (b *RtlSdrScanHitApplyConfiguration) WithPower(value int32) *RtlSdrScanHitApplyConfiguration {
+	b.Power = &value
+	return b
+}
+
+// WithFirstSeen sets the FirstSeen field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the FirstSeen field is set to the value of the last call.
+func (b *RtlSdrScanHitApplyConfiguration) WithFirstSeen(value v1.Time) *RtlSdrScanHitApplyConfiguration {
+	b.FirstSeen = &value
+	return b
+}
+
+// WithLastSeen sets the LastSeen field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the LastSeen field is set to the value of the last call.
+func (b *RtlSdrScanHitApplyConfiguration) WithLastSeen(value v1.Time) *RtlSdrScanHitApplyConfiguration {
+	b.LastSeen = &value
+	return b
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by controller-gen. DO NOT EDIT.
+
+package v1beta1
+
+import (
+	resource "k8s.io/apimachinery/pkg/api/resource"
+)
+
+// RtlSdrScanRangeApplyConfiguration represents a declarative configuration of the RtlSdrScanRange type for use
+// with apply.
+type RtlSdrScanRangeApplyConfiguration struct {
+	Start *resource.Quantity `json:"start,omitempty"`
+	End   *resource.Quantity `json:"end,omitempty"`
+	Step  *resource.Quantity `json:"step,omitempty"`
+}
+
+// RtlSdrScanRangeApplyConfiguration constructs a declarative configuration of the RtlSdrScanRange type for use with
+// apply.
+func RtlSdrScanRange() *RtlSdrScanRangeApplyConfiguration {
+	return &RtlSdrScanRangeApplyConfiguration{}
+}
+
+// WithStart sets the Start field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Start field is set to the value of the last call.
+func (b *RtlSdrScanRangeApplyConfiguration) WithStart(value resource.Quantity) *RtlSdrScanRangeApplyConfiguration {
+	b.Start = &value
+	return b
+}
+
+// WithEnd sets the End field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the End field is set to the value of the last call.
+func (b *RtlSdrScanRangeApplyConfiguration) WithEnd(value resource.Quantity) *RtlSdrScanRangeApplyConfiguration {
+	b.End = &value
+	return b
+}
+
+// WithStep sets the Step field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Step field is set to the value of the last call.
+func (b *RtlSdrScanRangeApplyConfiguration) WithStep(value resource.Quantity) *RtlSdrScanRangeApplyConfiguration {
+	b.Step = &value
+	return b
+}
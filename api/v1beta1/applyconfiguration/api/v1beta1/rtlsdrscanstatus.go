@@ -0,0 +1,56 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by controller-gen. DO NOT EDIT.
+
+package v1beta1
+
+import (
+	resource "k8s.io/apimachinery/pkg/api/resource"
+)
+
+// RtlSdrScanStatusApplyConfiguration represents a declarative configuration of the RtlSdrScanStatus type for use
+// with apply.
+type RtlSdrScanStatusApplyConfiguration struct {
+	Frequency *resource.Quantity                `json:"frequency,omitempty"`
+	Hits      []RtlSdrScanHitApplyConfiguration `json:"hits,omitempty"`
+}
+
+// RtlSdrScanStatusApplyConfiguration constructs a declarative configuration of the RtlSdrScanStatus type for use with
+// apply.
+func RtlSdrScanStatus() *RtlSdrScanStatusApplyConfiguration {
+	return &RtlSdrScanStatusApplyConfiguration{}
+}
+
+// WithFrequency sets the Frequency field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Frequency field is set to the value of the last call.
+func (b *RtlSdrScanStatusApplyConfiguration) WithFrequency(value resource.Quantity) *RtlSdrScanStatusApplyConfiguration {
+	b.Frequency = &value
+	return b
+}
+
+// WithHits adds the given value to the Hits field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the Hits field.
+func (b *RtlSdrScanStatusApplyConfiguration) WithHits(values ...*RtlSdrScanHitApplyConfiguration) *RtlSdrScanStatusApplyConfiguration {
+	for i := range values {
+		if values[i] == nil {
+			panic("nil value passed to WithHits")
+		}
+		b.Hits = append(b.Hits, *values[i])
+	}
+	return b
+}
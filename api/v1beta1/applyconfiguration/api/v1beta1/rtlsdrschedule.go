@@ -0,0 +1,79 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by controller-gen. DO NOT EDIT.
+
+package v1beta1
+
+import (
+	apiv1beta1 "github.com/frelon/k8s-radio/api/v1beta1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RtlSdrScheduleApplyConfiguration represents a declarative configuration of the RtlSdrSchedule type for use
+// with apply.
+type RtlSdrScheduleApplyConfiguration struct {
+	Cron                    *string                              `json:"cron,omitempty"`
+	Duration                *v1.Duration                         `json:"duration,omitempty"`
+	TimeZone                *string                              `json:"timeZone,omitempty"`
+	MissedWindowPolicy      *apiv1beta1.RtlSdrMissedWindowPolicy `json:"missedWindowPolicy,omitempty"`
+	StartingDeadlineSeconds *int64                               `json:"startingDeadlineSeconds,omitempty"`
+}
+
+// RtlSdrScheduleApplyConfiguration constructs a declarative configuration of the RtlSdrSchedule type for use with
+// apply.
+func RtlSdrSchedule() *RtlSdrScheduleApplyConfiguration {
+	return &RtlSdrScheduleApplyConfiguration{}
+}
+
+// WithCron sets the Cron field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Cron field is set to the value of the last call.
+func (b *RtlSdrScheduleApplyConfiguration) WithCron(value string) *RtlSdrScheduleApplyConfiguration {
+	b.Cron = &value
+	return b
+}
+
+// WithDuration sets the Duration field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Duration field is set to the value of the last call.
+func (b *RtlSdrScheduleApplyConfiguration) WithDuration(value v1.Duration) *RtlSdrScheduleApplyConfiguration {
+	b.Duration = &value
+	return b
+}
+
+// WithTimeZone sets the TimeZone field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the TimeZone field is set to the value of the last call.
+func (b *RtlSdrScheduleApplyConfiguration) WithTimeZone(value string) *RtlSdrScheduleApplyConfiguration {
+	b.TimeZone = &value
+	return b
+}
+
+// WithMissedWindowPolicy sets the MissedWindowPolicy field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the MissedWindowPolicy field is set to the value of the last call.
+func (b *RtlSdrScheduleApplyConfiguration) WithMissedWindowPolicy(value apiv1beta1.RtlSdrMissedWindowPolicy) *RtlSdrScheduleApplyConfiguration {
+	b.MissedWindowPolicy = &value
+	return b
+}
+
+// WithStartingDeadlineSeconds sets the StartingDeadlineSeconds field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the StartingDeadlineSeconds field is set to the value of the last call.
+func (b *RtlSdrScheduleApplyConfiguration) WithStartingDeadlineSeconds(value int64) *RtlSdrScheduleApplyConfiguration {
+	b.StartingDeadlineSeconds = &value
+	return b
+}
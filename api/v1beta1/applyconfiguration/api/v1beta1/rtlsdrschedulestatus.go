@@ -0,0 +1,69 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by controller-gen. DO NOT EDIT.
+
+package v1beta1
+
+import (
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RtlSdrScheduleStatusApplyConfiguration represents a declarative configuration of the RtlSdrScheduleStatus type for use
+// with apply.
+type RtlSdrScheduleStatusApplyConfiguration struct {
+	LastRunTime    *v1.Time `json:"lastRunTime,omitempty"`
+	ActiveUntil    *v1.Time `json:"activeUntil,omitempty"`
+	NextRunTime    *v1.Time `json:"nextRunTime,omitempty"`
+	LastMissedTime *v1.Time `json:"lastMissedTime,omitempty"`
+}
+
+// RtlSdrScheduleStatusApplyConfiguration constructs a declarative configuration of the RtlSdrScheduleStatus type for use with
+// apply.
+func RtlSdrScheduleStatus() *RtlSdrScheduleStatusApplyConfiguration {
+	return &RtlSdrScheduleStatusApplyConfiguration{}
+}
+
+// WithLastRunTime sets the LastRunTime field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the LastRunTime field is set to the value of the last call.
+func (b *RtlSdrScheduleStatusApplyConfiguration) WithLastRunTime(value v1.Time) *RtlSdrScheduleStatusApplyConfiguration {
+	b.LastRunTime = &value
+	return b
+}
+
+// WithActiveUntil sets the ActiveUntil field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the ActiveUntil field is set to the value of the last call.
+func (b *RtlSdrScheduleStatusApplyConfiguration) WithActiveUntil(value v1.Time) *RtlSdrScheduleStatusApplyConfiguration {
+	b.ActiveUntil = &value
+	return b
+}
+
+// WithNextRunTime sets the NextRunTime field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the NextRunTime field is set to the value of the last call.
+func (b *RtlSdrScheduleStatusApplyConfiguration) WithNextRunTime(value v1.Time) *RtlSdrScheduleStatusApplyConfiguration {
+	b.NextRunTime = &value
+	return b
+}
+
+// WithLastMissedTime sets the LastMissedTime field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the LastMissedTime field is set to the value of the last call.
+func (b *RtlSdrScheduleStatusApplyConfiguration) WithLastMissedTime(value v1.Time) *RtlSdrScheduleStatusApplyConfiguration {
+	b.LastMissedTime = &value
+	return b
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by controller-gen. DO NOT EDIT.
+
+package v1beta1
+
+import (
+	apiv1beta1 "github.com/frelon/k8s-radio/api/v1beta1"
+)
+
+// RtlSdrSharingApplyConfiguration represents a declarative configuration of the RtlSdrSharing type for use
+// with apply.
+type RtlSdrSharingApplyConfiguration struct {
+	Enabled    *bool                        `json:"enabled,omitempty"`
+	LockPolicy *apiv1beta1.RtlSdrLockPolicy `json:"lockPolicy,omitempty"`
+	MaxClients *int32                       `json:"maxClients,omitempty"`
+}
+
+// RtlSdrSharingApplyConfiguration constructs a declarative configuration of the RtlSdrSharing type for use with
+// apply.
+func RtlSdrSharing() *RtlSdrSharingApplyConfiguration {
+	return &RtlSdrSharingApplyConfiguration{}
+}
+
+// WithEnabled sets the Enabled field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Enabled field is set to the value of the last call.
+func (b *RtlSdrSharingApplyConfiguration) WithEnabled(value bool) *RtlSdrSharingApplyConfiguration {
+	b.Enabled = &value
+	return b
+}
+
+// WithLockPolicy sets the LockPolicy field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the LockPolicy field is set to the value of the last call.
+func (b *RtlSdrSharingApplyConfiguration) WithLockPolicy(value apiv1beta1.RtlSdrLockPolicy) *RtlSdrSharingApplyConfiguration {
+	b.LockPolicy = &value
+	return b
+}
+
+// WithMaxClients sets the MaxClients field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the MaxClients field is set to the value of the last call.
+func (b *RtlSdrSharingApplyConfiguration) WithMaxClients(value int32) *RtlSdrSharingApplyConfiguration {
+	b.MaxClients = &value
+	return b
+}
@@ -0,0 +1,61 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by controller-gen. DO NOT EDIT.
+
+package v1beta1
+
+import (
+	apiv1beta1 "github.com/frelon/k8s-radio/api/v1beta1"
+	resource "k8s.io/apimachinery/pkg/api/resource"
+)
+
+// RtlSdrSimulationApplyConfiguration represents a declarative configuration of the RtlSdrSimulation type for use
+// with apply.
+type RtlSdrSimulationApplyConfiguration struct {
+	Signal          *apiv1beta1.RtlSdrSignal        `json:"signal,omitempty"`
+	SignalFrequency *resource.Quantity              `json:"signalFrequency,omitempty"`
+	Replay          *RtlSdrReplayApplyConfiguration `json:"replay,omitempty"`
+}
+
+// RtlSdrSimulationApplyConfiguration constructs a declarative configuration of the RtlSdrSimulation type for use with
+// apply.
+func RtlSdrSimulation() *RtlSdrSimulationApplyConfiguration {
+	return &RtlSdrSimulationApplyConfiguration{}
+}
+
+// WithSignal sets the Signal field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Signal field is set to the value of the last call.
+func (b *RtlSdrSimulationApplyConfiguration) WithSignal(value apiv1beta1.RtlSdrSignal) *RtlSdrSimulationApplyConfiguration {
+	b.Signal = &value
+	return b
+}
+
+// WithSignalFrequency sets the SignalFrequency field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the SignalFrequency field is set to the value of the last call.
+func (b *RtlSdrSimulationApplyConfiguration) WithSignalFrequency(value resource.Quantity) *RtlSdrSimulationApplyConfiguration {
+	b.SignalFrequency = &value
+	return b
+}
+
+// WithReplay sets the Replay field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Replay field is set to the value of the last call.
+func (b *RtlSdrSimulationApplyConfiguration) WithReplay(value *RtlSdrReplayApplyConfiguration) *RtlSdrSimulationApplyConfiguration {
+	b.Replay = value
+	return b
+}
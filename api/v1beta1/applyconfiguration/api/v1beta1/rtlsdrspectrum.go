@@ -0,0 +1,56 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by controller-gen. DO NOT EDIT.
+
+package v1beta1
+
+// RtlSdrSpectrumApplyConfiguration represents a declarative configuration of the RtlSdrSpectrum type for use
+// with apply.
+type RtlSdrSpectrumApplyConfiguration struct {
+	FFTSize   *int32 `json:"fftSize,omitempty"`
+	Averaging *int32 `json:"averaging,omitempty"`
+	FrameRate *int32 `json:"frameRate,omitempty"`
+}
+
+// RtlSdrSpectrumApplyConfiguration constructs a declarative configuration of the RtlSdrSpectrum type for use with
+// apply.
+func RtlSdrSpectrum() *RtlSdrSpectrumApplyConfiguration {
+	return &RtlSdrSpectrumApplyConfiguration{}
+}
+
+// WithFFTSize sets the FFTSize field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the FFTSize field is set to the value of the last call.
+func (b *RtlSdrSpectrumApplyConfiguration) WithFFTSize(value int32) *RtlSdrSpectrumApplyConfiguration {
+	b.FFTSize = &value
+	return b
+}
+
+// WithAveraging sets the Averaging field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Averaging field is set to the value of the last call.
+func (b *RtlSdrSpectrumApplyConfiguration) WithAveraging(value int32) *RtlSdrSpectrumApplyConfiguration {
+	b.Averaging = &value
+	return b
+}
+
+// WithFrameRate sets the FrameRate field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the FrameRate field is set to the value of the last call.
+func (b *RtlSdrSpectrumApplyConfiguration) WithFrameRate(value int32) *RtlSdrSpectrumApplyConfiguration {
+	b.FrameRate = &value
+	return b
+}
@@ -0,0 +1,56 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by controller-gen. DO NOT EDIT.
+
+package v1beta1
+
+// RtlSdrSurveyOutputApplyConfiguration represents a declarative configuration of the RtlSdrSurveyOutput type for use
+// with apply.
+type RtlSdrSurveyOutputApplyConfiguration struct {
+	ClaimName *string `json:"claimName,omitempty"`
+	Path      *string `json:"path,omitempty"`
+	ConfigMap *string `json:"configMap,omitempty"`
+}
+
+// RtlSdrSurveyOutputApplyConfiguration constructs a declarative configuration of the RtlSdrSurveyOutput type for use with
+// apply.
+func RtlSdrSurveyOutput() *RtlSdrSurveyOutputApplyConfiguration {
+	return &RtlSdrSurveyOutputApplyConfiguration{}
+}
+
+// WithClaimName sets the ClaimName field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the ClaimName field is set to the value of the last call.
+func (b *RtlSdrSurveyOutputApplyConfiguration) WithClaimName(value string) *RtlSdrSurveyOutputApplyConfiguration {
+	b.ClaimName = &value
+	return b
+}
+
+// WithPath sets the Path field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Path field is set to the value of the last call.
+func (b *RtlSdrSurveyOutputApplyConfiguration) WithPath(value string) *RtlSdrSurveyOutputApplyConfiguration {
+	b.Path = &value
+	return b
+}
+
+// WithConfigMap sets the ConfigMap field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the ConfigMap field is set to the value of the last call.
+func (b *RtlSdrSurveyOutputApplyConfiguration) WithConfigMap(value string) *RtlSdrSurveyOutputApplyConfiguration {
+	b.ConfigMap = &value
+	return b
+}
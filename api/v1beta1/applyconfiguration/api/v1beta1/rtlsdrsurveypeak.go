@@ -0,0 +1,51 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by controller-gen. DO NOT EDIT.
+
+package v1beta1
+
+import (
+	resource "k8s.io/apimachinery/pkg/api/resource"
+)
+
+// RtlSdrSurveyPeakApplyConfiguration represents a declarative configuration of the RtlSdrSurveyPeak type for use
+// with apply.
+type RtlSdrSurveyPeakApplyConfiguration struct {
+	Frequency *resource.Quantity `json:"frequency,omitempty"`
+	Power     *int32             `json:"power,omitempty"`
+}
+
+// RtlSdrSurveyPeakApplyConfiguration constructs a declarative configuration of the RtlSdrSurveyPeak type for use with
+// apply.
+func RtlSdrSurveyPeak() *RtlSdrSurveyPeakApplyConfiguration {
+	return &RtlSdrSurveyPeakApplyConfiguration{}
+}
+
+// WithFrequency sets the Frequency field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Frequency field is set to the value of the last call.
+func (b *RtlSdrSurveyPeakApplyConfiguration) WithFrequency(value resource.Quantity) *RtlSdrSurveyPeakApplyConfiguration {
+	b.Frequency = &value
+	return b
+}
+
+// WithPower sets the Power field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Power field is set to the value of the last call.
+func (b *RtlSdrSurveyPeakApplyConfiguration) WithPower(value int32) *RtlSdrSurveyPeakApplyConfiguration {
+	b.Power = &value
+	return b
+}
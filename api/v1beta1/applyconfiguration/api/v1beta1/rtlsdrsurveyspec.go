@@ -0,0 +1,140 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by controller-gen. DO NOT EDIT.
+
+package v1beta1
+
+import (
+	apiv1beta1 "github.com/frelon/k8s-radio/api/v1beta1"
+	resource "k8s.io/apimachinery/pkg/api/resource"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RtlSdrSurveySpecApplyConfiguration represents a declarative configuration of the RtlSdrSurveySpec type for use
+// with apply.
+type RtlSdrSurveySpecApplyConfiguration struct {
+	Version      *apiv1beta1.RtlSdrVersion             `json:"version,omitempty"`
+	Start        *resource.Quantity                    `json:"start,omitempty"`
+	End          *resource.Quantity                    `json:"end,omitempty"`
+	BinSize      *resource.Quantity                    `json:"binSize,omitempty"`
+	Integration  *v1.Duration                          `json:"integration,omitempty"`
+	Duration     *v1.Duration                          `json:"duration,omitempty"`
+	Gain         *resource.Quantity                    `json:"gain,omitempty"`
+	NodeName     *string                               `json:"nodeName,omitempty"`
+	NodeSelector map[string]string                     `json:"nodeSelector,omitempty"`
+	Peaks        *int32                                `json:"peaks,omitempty"`
+	Output       *RtlSdrSurveyOutputApplyConfiguration `json:"output,omitempty"`
+}
+
+// RtlSdrSurveySpecApplyConfiguration constructs a declarative configuration of the RtlSdrSurveySpec type for use with
+// apply.
+func RtlSdrSurveySpec() *RtlSdrSurveySpecApplyConfiguration {
+	return &RtlSdrSurveySpecApplyConfiguration{}
+}
+
+// WithVersion sets the Version field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Version field is set to the value of the last call.
+func (b *RtlSdrSurveySpecApplyConfiguration) WithVersion(value apiv1beta1.RtlSdrVersion) *RtlSdrSurveySpecApplyConfiguration {
+	b.Version = &value
+	return b
+}
+
+// WithStart sets the Start field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Start field is set to the value of the last call.
+func (b *RtlSdrSurveySpecApplyConfiguration) WithStart(value resource.Quantity) *RtlSdrSurveySpecApplyConfiguration {
+	b.Start = &value
+	return b
+}
+
+// WithEnd sets the End field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the End field is set to the value of the last call.
+func (b *RtlSdrSurveySpecApplyConfiguration) WithEnd(value resource.Quantity) *RtlSdrSurveySpecApplyConfiguration {
+	b.End = &value
+	return b
+}
+
+// WithBinSize sets the BinSize field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the BinSize field is set to the value of the last call.
+func (b *RtlSdrSurveySpecApplyConfiguration) WithBinSize(value resource.Quantity) *RtlSdrSurveySpecApplyConfiguration {
+	b.BinSize = &value
+	return b
+}
+
+// WithIntegration sets the Integration field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Integration field is set to the value of the last call.
+func (b *RtlSdrSurveySpecApplyConfiguration) WithIntegration(value v1.Duration) *RtlSdrSurveySpecApplyConfiguration {
+	b.Integration = &value
+	return b
+}
+
+// WithDuration sets the Duration field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Duration field is set to the value of the last call.
+func (b *RtlSdrSurveySpecApplyConfiguration) WithDuration(value v1.Duration) *RtlSdrSurveySpecApplyConfiguration {
+	b.Duration = &value
+	return b
+}
+
+// WithGain sets the Gain field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Gain field is set to the value of the last call.
+func (b *RtlSdrSurveySpecApplyConfiguration) WithGain(value resource.Quantity) *RtlSdrSurveySpecApplyConfiguration {
+	b.Gain = &value
+	return b
+}
+
+// WithNodeName sets the NodeName field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the NodeName field is set to the value of the last call.
+func (b *RtlSdrSurveySpecApplyConfiguration) WithNodeName(value string) *RtlSdrSurveySpecApplyConfiguration {
+	b.NodeName = &value
+	return b
+}
+
+// WithNodeSelector puts the entries into the NodeSelector field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, the entries provided by each call will be put on the NodeSelector field,
+// overwriting an existing map entries in NodeSelector field with the same key.
+func (b *RtlSdrSurveySpecApplyConfiguration) WithNodeSelector(entries map[string]string) *RtlSdrSurveySpecApplyConfiguration {
+	if b.NodeSelector == nil && len(entries) > 0 {
+		b.NodeSelector = make(map[string]string, len(entries))
+	}
+	for k, v := range entries {
+		b.NodeSelector[k] = v
+	}
+	return b
+}
+
+// WithPeaks sets the Peaks field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Peaks field is set to the value of the last call.
+func (b *RtlSdrSurveySpecApplyConfiguration) WithPeaks(value int32) *RtlSdrSurveySpecApplyConfiguration {
+	b.Peaks = &value
+	return b
+}
+
+// WithOutput sets the Output field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Output field is set to the value of the last call.
+func (b *RtlSdrSurveySpecApplyConfiguration) WithOutput(value *RtlSdrSurveyOutputApplyConfiguration) *RtlSdrSurveySpecApplyConfiguration {
+	b.Output = value
+	return b
+}
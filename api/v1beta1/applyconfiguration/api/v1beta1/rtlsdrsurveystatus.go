@@ -0,0 +1,109 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by controller-gen. DO NOT EDIT.
+
+package v1beta1
+
+import (
+	apiv1beta1 "github.com/frelon/k8s-radio/api/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	v1 "k8s.io/client-go/applyconfigurations/meta/v1"
+)
+
+// RtlSdrSurveyStatusApplyConfiguration represents a declarative configuration of the RtlSdrSurveyStatus type for use
+// with apply.
+type RtlSdrSurveyStatusApplyConfiguration struct {
+	Conditions     []v1.ConditionApplyConfiguration     `json:"conditions,omitempty"`
+	State          *apiv1beta1.RtlSdrSurveyState        `json:"state,omitempty"`
+	Job            *corev1.ObjectReference              `json:"job,omitempty"`
+	StartTime      *metav1.Time                         `json:"startTime,omitempty"`
+	CompletionTime *metav1.Time                         `json:"completionTime,omitempty"`
+	Sweeps         *int32                               `json:"sweeps,omitempty"`
+	Peaks          []RtlSdrSurveyPeakApplyConfiguration `json:"peaks,omitempty"`
+}
+
+// RtlSdrSurveyStatusApplyConfiguration constructs a declarative configuration of the RtlSdrSurveyStatus type for use with
+// apply.
+func RtlSdrSurveyStatus() *RtlSdrSurveyStatusApplyConfiguration {
+	return &RtlSdrSurveyStatusApplyConfiguration{}
+}
+
+// WithConditions adds the given value to the Conditions field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the Conditions field.
+func (b *RtlSdrSurveyStatusApplyConfiguration) WithConditions(values ...*v1.ConditionApplyConfiguration) *RtlSdrSurveyStatusApplyConfiguration {
+	for i := range values {
+		if values[i] == nil {
+			panic("nil value passed to WithConditions")
+		}
+		b.Conditions = append(b.Conditions, *values[i])
+	}
+	return b
+}
+
+// WithState sets the State field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the State field is set to the value of the last call.
+func (b *RtlSdrSurveyStatusApplyConfiguration) WithState(value apiv1beta1.RtlSdrSurveyState) *RtlSdrSurveyStatusApplyConfiguration {
+	b.State = &value
+	return b
+}
+
+// WithJob sets the Job field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Job field is set to the value of the last call.
+func (b *RtlSdrSurveyStatusApplyConfiguration) WithJob(value corev1.ObjectReference) *RtlSdrSurveyStatusApplyConfiguration {
+	b.Job = &value
+	return b
+}
+
+// WithStartTime sets the StartTime field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the StartTime field is set to the value of the last call.
+func (b *RtlSdrSurveyStatusApplyConfiguration) WithStartTime(value metav1.Time) *RtlSdrSurveyStatusApplyConfiguration {
+	b.StartTime = &value
+	return b
+}
+
+// WithCompletionTime sets the CompletionTime field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the CompletionTime field is set to the value of the last call.
+func (b *RtlSdrSurveyStatusApplyConfiguration) WithCompletionTime(value metav1.Time) *RtlSdrSurveyStatusApplyConfiguration {
+	b.CompletionTime = &value
+	return b
+}
+
+// WithSweeps sets the Sweeps field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Sweeps field is set to the value of the last call.
+func (b *RtlSdrSurveyStatusApplyConfiguration) WithSweeps(value int32) *RtlSdrSurveyStatusApplyConfiguration {
+	b.Sweeps = &value
+	return b
+}
+
+// WithPeaks adds the given value to the Peaks field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the Peaks field.
+func (b *RtlSdrSurveyStatusApplyConfiguration) WithPeaks(values ...*RtlSdrSurveyPeakApplyConfiguration) *RtlSdrSurveyStatusApplyConfiguration {
+	for i := range values {
+		if values[i] == nil {
+			panic("nil value passed to WithPeaks")
+		}
+		b.Peaks = append(b.Peaks, *values[i])
+	}
+	return b
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Code generated by controller-gen. DO NOT EDIT.
+
+package applyconfiguration
+
+import (
+	v1beta1 "github.com/frelon/k8s-radio/api/v1beta1"
+	apiv1beta1 "github.com/frelon/k8s-radio/api/v1beta1/applyconfiguration/api/v1beta1"
+	internal "github.com/frelon/k8s-radio/api/v1beta1/applyconfiguration/internal"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+	managedfields "k8s.io/apimachinery/pkg/util/managedfields"
+)
+
+// ForKind returns an apply configuration type for the given GroupVersionKind, or nil if no
+// apply configuration type exists for the given GroupVersionKind.
+func ForKind(kind schema.GroupVersionKind) interface{} {
+	switch kind {
+	// Group=radio.frelon.se, Version=v1beta1
+	case v1beta1.SchemeGroupVersion.WithKind("RtlSdrAccess"):
+		return &apiv1beta1.RtlSdrAccessApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("RtlSdrAccessStatus"):
+		return &apiv1beta1.RtlSdrAccessStatusApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("RtlSdrADSBStatus"):
+		return &apiv1beta1.RtlSdrADSBStatusApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("RtlSdrChannel"):
+		return &apiv1beta1.RtlSdrChannelApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("RtlSdrChannelSpec"):
+		return &apiv1beta1.RtlSdrChannelSpecApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("RtlSdrChannelStatus"):
+		return &apiv1beta1.RtlSdrChannelStatusApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("RtlSdrEvents"):
+		return &apiv1beta1.RtlSdrEventsApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("RtlSdrExpose"):
+		return &apiv1beta1.RtlSdrExposeApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("RtlSdrExposeStatus"):
+		return &apiv1beta1.RtlSdrExposeStatusApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("RtlSdrGatewayExpose"):
+		return &apiv1beta1.RtlSdrGatewayExposeApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("RtlSdrIngressExpose"):
+		return &apiv1beta1.RtlSdrIngressExposeApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("RtlSdrLiveTuning"):
+		return &apiv1beta1.RtlSdrLiveTuningApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("RtlSdrMetrics"):
+		return &apiv1beta1.RtlSdrMetricsApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("RtlSdrMQTT"):
+		return &apiv1beta1.RtlSdrMQTTApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("RtlSdrNetworkPolicy"):
+		return &apiv1beta1.RtlSdrNetworkPolicyApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("RtlSdrPoolAdmission"):
+		return &apiv1beta1.RtlSdrPoolAdmissionApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("RtlSdrPoolQueueEntry"):
+		return &apiv1beta1.RtlSdrPoolQueueEntryApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("RtlSdrPoolQuota"):
+		return &apiv1beta1.RtlSdrPoolQuotaApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("RtlSdrPoolReference"):
+		return &apiv1beta1.RtlSdrPoolReferenceApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("RtlSdrPoolStatus"):
+		return &apiv1beta1.RtlSdrPoolStatusApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("RtlSdrReceiver"):
+		return &apiv1beta1.RtlSdrReceiverApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("RtlSdrReceiverPool"):
+		return &apiv1beta1.RtlSdrReceiverPoolApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("RtlSdrReceiverPoolSpec"):
+		return &apiv1beta1.RtlSdrReceiverPoolSpecApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("RtlSdrReceiverPoolStatus"):
+		return &apiv1beta1.RtlSdrReceiverPoolStatusApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("RtlSdrReceiverSpec"):
+		return &apiv1beta1.RtlSdrReceiverSpecApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("RtlSdrReceiverStatus"):
+		return &apiv1beta1.RtlSdrReceiverStatusApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("RtlSdrRecording"):
+		return &apiv1beta1.RtlSdrRecordingApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("RtlSdrRecordingStatus"):
+		return &apiv1beta1.RtlSdrRecordingStatusApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("RtlSdrReplay"):
+		return &apiv1beta1.RtlSdrReplayApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("RtlSdrRotation"):
+		return &apiv1beta1.RtlSdrRotationApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("RtlSdrSampleLoss"):
+		return &apiv1beta1.RtlSdrSampleLossApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("RtlSdrSampleLossStatus"):
+		return &apiv1beta1.RtlSdrSampleLossStatusApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("RtlSdrScan"):
+		return &apiv1beta1.RtlSdrScanApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("RtlSdrScanHit"):
+		return &apiv1beta1.RtlSdrScanHitApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("RtlSdrScanRange"):
+		return &apiv1beta1.RtlSdrScanRangeApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("RtlSdrScanStatus"):
+		return &apiv1beta1.RtlSdrScanStatusApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("RtlSdrSchedule"):
+		return &apiv1beta1.RtlSdrScheduleApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("RtlSdrScheduleStatus"):
+		return &apiv1beta1.RtlSdrScheduleStatusApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("RtlSdrSharing"):
+		return &apiv1beta1.RtlSdrSharingApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("RtlSdrSimulation"):
+		return &apiv1beta1.RtlSdrSimulationApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("RtlSdrSpectrum"):
+		return &apiv1beta1.RtlSdrSpectrumApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("RtlSdrSurvey"):
+		return &apiv1beta1.RtlSdrSurveyApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("RtlSdrSurveyOutput"):
+		return &apiv1beta1.RtlSdrSurveyOutputApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("RtlSdrSurveyPeak"):
+		return &apiv1beta1.RtlSdrSurveyPeakApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("RtlSdrSurveySpec"):
+		return &apiv1beta1.RtlSdrSurveySpecApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("RtlSdrSurveyStatus"):
+		return &apiv1beta1.RtlSdrSurveyStatusApplyConfiguration{}
+
+	}
+	return nil
+}
+
+func NewTypeConverter(scheme *runtime.Scheme) managedfields.TypeConverter {
+	return managedfields.NewSchemeTypeConverter(scheme, internal.Parser())
+}
@@ -17,6 +17,7 @@ limitations under the License.
 // Package v1beta1 contains API Schema definitions for the radio v1beta1 API group
 
 // +kubebuilder:object:generate=true
+// +kubebuilder:ac:generate=true
 // +groupName=radio.frelon.se
 package v1beta1
 
@@ -30,6 +31,10 @@ var (
 	// GroupVersion is group version used to register these objects
 	GroupVersion = schema.GroupVersion{Group: "radio.frelon.se", Version: "v1beta1"}
 
+	// SchemeGroupVersion is an alias of GroupVersion used by the generated
+	// apply configurations.
+	SchemeGroupVersion = GroupVersion
+
 	// SchemeBuilder is used to add go types to the GroupVersionKind scheme
 	SchemeBuilder = runtime.NewSchemeBuilder(addKnownTypes)
 
@@ -41,6 +46,12 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 	scheme.AddKnownTypes(GroupVersion,
 		&RtlSdrReceiver{},
 		&RtlSdrReceiverList{},
+		&RtlSdrChannel{},
+		&RtlSdrChannelList{},
+		&RtlSdrSurvey{},
+		&RtlSdrSurveyList{},
+		&RtlSdrReceiverPool{},
+		&RtlSdrReceiverPoolList{},
 	)
 	metav1.AddToGroupVersion(scheme, GroupVersion)
 	return nil
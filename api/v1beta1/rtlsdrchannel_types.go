@@ -0,0 +1,96 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	ReceiverNotFoundReason = "ReceiverNotFound"
+	OutsidePassbandReason  = "OutsidePassband"
+)
+
+// RtlSdrChannelSpec defines the desired state of RtlSdrChannel
+type RtlSdrChannelSpec struct {
+	// Receiver is the name of the RtlSdrReceiver in the same namespace the
+	// channel is split from. It must be in IQ mode.
+	// +kubebuilder:validation:MinLength=1
+	Receiver string `json:"receiver"`
+
+	// Offset is the center of the channel relative to the frequency of the
+	// receiver.
+	// +kubebuilder:example="-200k"
+	Offset resource.Quantity `json:"offset"`
+
+	// Bandwidth is the width of the channel. The channel is sampled at the
+	// receiver sample rate divided by a whole number, at least twice the
+	// bandwidth.
+	// +kubebuilder:example="25k"
+	Bandwidth resource.Quantity `json:"bandwidth"`
+}
+
+// RtlSdrChannelStatus defines the observed state of RtlSdrChannel
+type RtlSdrChannelStatus struct {
+	// Conditions describe the state of the channel.
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+
+	// State describes the current state of the channel.
+	// +optional
+	State RtlSdrReceiverState `json:"state,omitempty"`
+
+	// Endpoint is the in-cluster URL of the channel I/Q stream, which speaks
+	// the rtl_tcp protocol.
+	// +optional
+	Endpoint string `json:"endpoint,omitempty"`
+
+	// Frequency is the center frequency of the channel.
+	// +optional
+	Frequency *resource.Quantity `json:"frequency,omitempty"`
+
+	// SampleRate is the rate the channel is sampled at.
+	// +optional
+	SampleRate *resource.Quantity `json:"sampleRate,omitempty"`
+}
+
+// RtlSdrChannel is a narrowband virtual receiver split from the I/Q stream
+// of an RtlSdrReceiver.
+// +kubebuilder:ac:generate=true
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Receiver",type=string,JSONPath=`.spec.receiver`
+// +kubebuilder:printcolumn:name="Frequency",type=string,JSONPath=`.status.frequency`
+// +kubebuilder:printcolumn:name="State",type=string,JSONPath=`.status.state`
+type RtlSdrChannel struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   RtlSdrChannelSpec   `json:"spec,omitempty"`
+	Status RtlSdrChannelStatus `json:"status,omitempty"`
+}
+
+// RtlSdrChannelList contains a list of RtlSdrChannel
+// +kubebuilder:object:root=true
+type RtlSdrChannelList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []RtlSdrChannel `json:"items"`
+}
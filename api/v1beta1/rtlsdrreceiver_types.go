@@ -18,16 +18,42 @@ package v1beta1
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 const (
-	PodFailedReason = "PodFailed"
-	ReadyCondition  = "Ready"
+	PodFailedReason        = "PodFailed"
+	DeploymentFailedReason = "DeploymentFailed"
+	TerminatingReason      = "Terminating"
+	TeardownTimeoutReason  = "TeardownTimeout"
+	InvalidScheduleReason  = "InvalidSchedule"
+	InvalidScanReason      = "InvalidScan"
+	SamplesDroppedReason   = "SamplesDropped"
+	NoSampleLossReason     = "NoSampleLoss"
+	RetunedReason          = "Retuned"
+	QueuedReason           = "Queued"
+	ReadyCondition         = "Ready"
+	SampleLossCondition    = "SampleLoss"
 )
 
 // RtlSdrReceiverSpec defines the desired state of RtlSdrReceiver
+// +kubebuilder:validation:XValidation:rule="!has(self.simulation) || !has(self.mode) || self.mode == 'IQ'",message="simulation is only supported in IQ mode"
+// +kubebuilder:validation:XValidation:rule="!has(self.sharing) || !self.sharing.enabled || !has(self.mode) || self.mode == 'IQ'",message="sharing is only supported in IQ mode"
+// +kubebuilder:validation:XValidation:rule="!has(self.recording) || !has(self.mode) || self.mode == 'IQ'",message="recording is only supported in IQ mode"
+// +kubebuilder:validation:XValidation:rule="!has(self.scan) || !has(self.mode) || self.mode == 'IQ'",message="scanning is only supported in IQ mode"
+// +kubebuilder:validation:XValidation:rule="!has(self.spectrum) || !has(self.mode) || self.mode == 'IQ'",message="spectrum is only supported in IQ mode"
+// +kubebuilder:validation:XValidation:rule="!has(self.events) || (has(self.mode) && self.mode in ['AIS', 'ISM'])",message="events are only supported in AIS and ISM mode"
+// +kubebuilder:validation:XValidation:rule="!has(self.metrics) || !has(self.mode) || self.mode == 'IQ'",message="metrics are only supported in IQ mode"
+// +kubebuilder:validation:XValidation:rule="!has(self.sampleLoss) || !has(self.mode) || self.mode == 'IQ'",message="sample loss detection is only supported in IQ mode"
+// +kubebuilder:validation:XValidation:rule="!has(self.access) || !has(self.mode) || self.mode in ['IQ', 'FM']",message="access is only supported in IQ and FM mode"
+// +kubebuilder:validation:XValidation:rule="!has(self.gain) || !has(self.mode) || self.mode != 'FM'",message="gain is not supported in FM mode"
+// +kubebuilder:validation:XValidation:rule="!has(self.access) || !has(self.spectrum)",message="the spectrum of receivers with access cannot be served"
+// +kubebuilder:validation:XValidation:rule="!has(self.expose) || !has(self.access) || !has(self.mode) || self.mode != 'FM'",message="the audio of FM receivers with access cannot be exposed"
+// +kubebuilder:validation:XValidation:rule="!has(self.liveTuning) || !has(self.mode) || self.mode == 'IQ'",message="live tuning is only supported in IQ mode"
+// +kubebuilder:validation:XValidation:rule="!has(self.liveTuning) || !has(self.scan)",message="live tuning is not supported while scanning"
+// +kubebuilder:validation:XValidation:rule="!has(self.pool) || !has(self.simulation)",message="simulated receivers cannot use a pool"
 type RtlSdrReceiverSpec struct {
 	// +kubebuilder:validation:Default=v4
 	Version RtlSdrVersion `json:"version"`
@@ -37,11 +63,643 @@ type RtlSdrReceiverSpec struct {
 	// +optional
 	Frequency *resource.Quantity `json:"frequency"`
 
+	// SampleRate is the rate the dongle is sampled at, which is also the
+	// bandwidth of the I/Q stream. Defaults to 2.048M like rtl_tcp.
+	// +kubebuilder:example="2.4M"
+	// +optional
+	SampleRate *resource.Quantity `json:"sampleRate,omitempty"`
+
+	// Gain is the tuner gain in dB, automatic when unset. Not supported in
+	// FM mode, where fm-streamer controls the gain.
+	// +kubebuilder:example="40.2"
+	// +optional
+	Gain *resource.Quantity `json:"gain,omitempty"`
+
+	// LiveTuning retunes the running receiver over the rtl_tcp command
+	// channel when the frequency, sample rate or gain change, instead of
+	// restarting its pod and disconnecting its clients. rtl_tcp runs behind
+	// the proxy, which confirms the tuning in observedFrequency and
+	// observedGeneration. Only supported in IQ mode and not while scanning.
+	// +optional
+	LiveTuning *RtlSdrLiveTuning `json:"liveTuning,omitempty"`
+
 	// ContainerPort contains the port settings for the Pod.
 	// +optional
 	ContainerPort *corev1.ContainerPort `json:"port"`
+
+	// Mode selects what the receiver produces. IQ runs rtl_tcp and exposes
+	// the raw I/Q stream, FM demodulates a broadcast station and exposes the
+	// audio over HTTP, ADSB decodes aircraft transponders on 1090 MHz and
+	// exposes them as SBS-1, Beast and JSON. AIS decodes vessel positions
+	// with AIS-catcher and ISM decodes sensors in the 433/868 MHz bands with
+	// rtl_433, publishing them as events.
+	// +kubebuilder:default=IQ
+	// +optional
+	Mode RtlSdrMode `json:"mode,omitempty"`
+
+	// Workload selects the kind of object that runs the receiver. A Deployment
+	// survives node drains and works with PodDisruptionBudgets.
+	// +kubebuilder:default=Pod
+	// +optional
+	Workload RtlSdrWorkload `json:"workload,omitempty"`
+
+	// TerminationGracePeriodSeconds is the time given to the receiver to
+	// flush recordings and release the device before it is killed.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	TerminationGracePeriodSeconds *int64 `json:"terminationGracePeriodSeconds,omitempty"`
+
+	// Simulation runs a fake rtl_tcp server instead of claiming a dongle,
+	// for clusters without hardware.
+	// +optional
+	Simulation *RtlSdrSimulation `json:"simulation,omitempty"`
+
+	// Sharing lets several clients connect to the I/Q stream at once.
+	// +optional
+	Sharing *RtlSdrSharing `json:"sharing,omitempty"`
+
+	// Recording writes the I/Q stream to a PersistentVolumeClaim as SigMF
+	// datasets.
+	// +optional
+	Recording *RtlSdrRecording `json:"recording,omitempty"`
+
+	// Schedule only runs the receiver during recurring windows, releasing
+	// the dongle in between.
+	// +optional
+	Schedule *RtlSdrSchedule `json:"schedule,omitempty"`
+
+	// Scan tunes the receiver through a list of channels looking for
+	// activity instead of staying on Frequency.
+	// +optional
+	Scan *RtlSdrScan `json:"scan,omitempty"`
+
+	// Spectrum serves a live spectrum and waterfall of the I/Q stream over
+	// HTTP and WebSocket. Not supported with Access, as the spectrum is
+	// served in plaintext to anyone.
+	// +optional
+	Spectrum *RtlSdrSpectrum `json:"spectrum,omitempty"`
+
+	// Events configures where the messages decoded in AIS and ISM mode are
+	// published.
+	// +optional
+	Events *RtlSdrEvents `json:"events,omitempty"`
+
+	// MQTT publishes the status, signal level and decoded messages of the
+	// receiver to an MQTT broker.
+	// +optional
+	MQTT *RtlSdrMQTT `json:"mqtt,omitempty"`
+
+	// Metrics exports the signal level, noise floor, SNR, clipping and
+	// sample counters of the receiver to Prometheus.
+	// +optional
+	Metrics *RtlSdrMetrics `json:"metrics,omitempty"`
+
+	// SampleLoss enables the detection of samples dropped by rtl_tcp when
+	// the USB bus or the network can't keep up. Only supported in IQ mode.
+	// +optional
+	SampleLoss *RtlSdrSampleLoss `json:"sampleLoss,omitempty"`
+
+	// Access serves the stream over TLS and only to authenticated clients,
+	// instead of in plaintext to anyone who can reach it. Only supported in
+	// IQ and FM mode.
+	// +optional
+	Access *RtlSdrAccess `json:"access,omitempty"`
+
+	// NetworkPolicy restricts who can reach the receiver with a
+	// NetworkPolicy owned by the controller.
+	// +optional
+	NetworkPolicy *RtlSdrNetworkPolicy `json:"networkPolicy,omitempty"`
+
+	// Expose routes the HTTP endpoints of the receiver from outside the
+	// cluster through a Gateway or an Ingress: the audio in FM mode, the
+	// aircraft in ADSB mode, the recent events in AIS and ISM mode and the
+	// live spectrum in IQ mode. Through a Gateway the raw stream of IQ
+	// receivers is routed as well, where TCPRoutes are supported.
+	// +optional
+	Expose *RtlSdrExpose `json:"expose,omitempty"`
+
+	// Pool runs the receiver on a dongle of an RtlSdrReceiverPool, waiting
+	// in the Queued state until the pool admits it.
+	// +optional
+	Pool *RtlSdrPoolReference `json:"pool,omitempty"`
+}
+
+// RtlSdrPoolReference names the pool a receiver takes its dongle from.
+type RtlSdrPoolReference struct {
+	// Name is the name of the RtlSdrReceiverPool.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Priority orders the queue of the pool, receivers with a higher
+	// priority are admitted first. Admitted receivers are never preempted.
+	// +optional
+	Priority int32 `json:"priority,omitempty"`
+}
+
+// RtlSdrExpose configures how the endpoints of a receiver are reached from
+// outside the cluster.
+// +kubebuilder:validation:XValidation:rule="has(self.gateway) != has(self.ingress)",message="exactly one of gateway and ingress is required"
+type RtlSdrExpose struct {
+	// Hostname is the host the HTTP endpoints are served on. Requests for
+	// any host are routed when empty.
+	// +optional
+	Hostname string `json:"hostname,omitempty"`
+
+	// PathPrefix is the path the HTTP endpoints are served under. Through
+	// a Gateway the prefix is stripped before requests reach the receiver;
+	// an Ingress controller has to be told to do so, e.g. by annotations.
+	// +kubebuilder:validation:Pattern=`^/`
+	// +kubebuilder:default="/"
+	// +optional
+	PathPrefix string `json:"pathPrefix,omitempty"`
+
+	// Gateway routes the endpoints with an HTTPRoute and a TCPRoute
+	// attached to a Gateway.
+	// +optional
+	Gateway *RtlSdrGatewayExpose `json:"gateway,omitempty"`
+
+	// Ingress routes the HTTP endpoints with an Ingress.
+	// +optional
+	Ingress *RtlSdrIngressExpose `json:"ingress,omitempty"`
+}
+
+// RtlSdrGatewayExpose names the Gateway the routes of a receiver attach to.
+type RtlSdrGatewayExpose struct {
+	// Name of the Gateway.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Namespace of the Gateway, defaults to the namespace of the receiver.
+	// The Gateway has to allow routes from the namespace of the receiver.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// SectionName is the listener of the Gateway the HTTPRoute attaches
+	// to, all HTTP listeners when empty.
+	// +optional
+	SectionName string `json:"sectionName,omitempty"`
+
+	// TCPSectionName is the listener of the Gateway the TCPRoute of the
+	// raw stream attaches to, all TCP listeners when empty.
+	// +optional
+	TCPSectionName string `json:"tcpSectionName,omitempty"`
+}
+
+// RtlSdrIngressExpose configures the Ingress of a receiver.
+type RtlSdrIngressExpose struct {
+	// ClassName is the IngressClass of the Ingress, the default class when
+	// empty.
+	// +optional
+	ClassName *string `json:"className,omitempty"`
+
+	// Annotations are added to the Ingress, to configure the Ingress
+	// controller.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// TLSSecretName names a Secret holding the certificate of the hostname,
+	// to serve the endpoints over HTTPS.
+	// +optional
+	TLSSecretName string `json:"tlsSecretName,omitempty"`
 }
 
+// RtlSdrNetworkPolicy lists the clients allowed to reach a receiver. The
+// controller is always allowed to fetch the status of the receiver, and pods
+// in namespaces labelled metrics: enabled to scrape its metrics.
+type RtlSdrNetworkPolicy struct {
+	// From are the pods, namespaces and CIDRs allowed to connect to the
+	// stream, spectrum and channels of the receiver. No clients are allowed
+	// when empty.
+	// +listType=atomic
+	// +optional
+	From []networkingv1.NetworkPolicyPeer `json:"from,omitempty"`
+}
+
+// RtlSdrAccess configures the proxy guarding the stream of a receiver. The
+// controller issues the certificate the stream is served with from a CA of
+// the receiver, and a client certificate for ClientCertificate
+// authentication.
+// +kubebuilder:validation:XValidation:rule="self.authentication != 'Token' || has(self.tokenSecretName)",message="tokenSecretName is required for Token authentication"
+// +kubebuilder:validation:XValidation:rule="self.authentication != 'TokenReview' || has(self.serviceAccountName)",message="serviceAccountName is required for TokenReview authentication"
+type RtlSdrAccess struct {
+	// Authentication selects how clients authenticate. ClientCertificate
+	// requires a certificate signed by the CA of the receiver, Token a
+	// bearer token stored in a Secret, and TokenReview a bearer token the
+	// API server authenticates, such as the token of a service account.
+	// +kubebuilder:default=ClientCertificate
+	// +optional
+	Authentication RtlSdrAuthentication `json:"authentication,omitempty"`
+
+	// TokenSecretName names a Secret in the namespace of the receiver
+	// holding the bearer tokens accepted for Token authentication, each
+	// key naming the user of its token.
+	// +optional
+	TokenSecretName string `json:"tokenSecretName,omitempty"`
+
+	// Audiences are the audiences tokens must be issued for with
+	// TokenReview authentication. Defaults to the DNS name of the receiver
+	// Service, e.g. name.namespace.svc.
+	// +optional
+	Audiences []string `json:"audiences,omitempty"`
+
+	// ServiceAccountName is the service account the receiver pod runs as
+	// with TokenReview authentication. It has to be allowed to create
+	// TokenReviews, e.g. by binding it to the system:auth-delegator
+	// ClusterRole.
+	// +optional
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+
+	// Hosts are additional DNS names and IP addresses the certificate of
+	// the stream is valid for, such as the addresses of the nodes the stream
+	// is reached on through a host port.
+	// +optional
+	Hosts []string `json:"hosts,omitempty"`
+}
+
+// RtlSdrAuthentication is how clients of a receiver authenticate.
+// +kubebuilder:validation:Enum=ClientCertificate;Token;TokenReview
+type RtlSdrAuthentication string
+
+const (
+	AuthenticationClientCertificate RtlSdrAuthentication = "ClientCertificate"
+	AuthenticationToken             RtlSdrAuthentication = "Token"
+	AuthenticationTokenReview       RtlSdrAuthentication = "TokenReview"
+)
+
+// RtlSdrLiveTuning configures the live tuning of a receiver. The controller
+// sets the tuning of the receiver whenever the receiver changes or its pod
+// restarts, and clients allowed to tune may still retune it in between.
+type RtlSdrLiveTuning struct {
+	// Interval is the time between checks of the tuning of the running
+	// receiver, which notice restarts and clients retuning it. Defaults to
+	// 30s.
+	// +optional
+	Interval *metav1.Duration `json:"interval,omitempty"`
+}
+
+// RtlSdrSampleLoss configures the detection of dropped samples. The proxy
+// compares the samples it receives with the sample rate, and the
+// SampleLoss condition is set, and a Warning Event emitted, once the
+// samples dropped over a window exceed the threshold.
+type RtlSdrSampleLoss struct {
+	// Threshold is the percentage of the samples dropped over a window
+	// above which the receiver is losing samples.
+	// +kubebuilder:default="1%"
+	// +kubebuilder:validation:Pattern=`^(100|[0-9]{1,2}(\.[0-9]+)?)%$`
+	// +optional
+	Threshold string `json:"threshold,omitempty"`
+
+	// Window is the time the dropped samples are measured over, and between
+	// checks. Defaults to 10s.
+	// +optional
+	Window *metav1.Duration `json:"window,omitempty"`
+}
+
+// RtlSdrMetrics configures the sidecar exporting the signal of a receiver as
+// Prometheus metrics on the port named metrics. A PodMonitor scraping them
+// is created when the Prometheus Operator is installed.
+type RtlSdrMetrics struct {
+	// Interval is the time each measurement is taken over, and between
+	// scrapes of the PodMonitor. Defaults to 5s.
+	// +optional
+	Interval *metav1.Duration `json:"interval,omitempty"`
+
+	// FFTSize is the number of frequency bins the noise floor and SNR are
+	// estimated from.
+	// +kubebuilder:default=1024
+	// +kubebuilder:validation:Enum=64;128;256;512;1024;2048;4096;8192;16384
+	// +optional
+	FFTSize *int32 `json:"fftSize,omitempty"`
+}
+
+// RtlSdrMQTT configures the sidecar publishing a receiver to an MQTT broker.
+// Heartbeats are published retained to <topic>/status in every mode, and
+// <topic>/status says the receiver is offline once it stops. The signal level
+// is published to <topic>/signal in IQ mode, the aircraft to
+// <topic>/aircraft in ADSB mode, and the decoded messages to <topic>/events
+// in AIS and ISM mode unless events.sink names another sink.
+type RtlSdrMQTT struct {
+	// Broker is the URL of the broker.
+	// +kubebuilder:validation:Pattern=`^mqtts?://.+`
+	// +kubebuilder:example="mqtt://mosquitto.home.svc:1883"
+	Broker string `json:"broker"`
+
+	// Topic is the topic the topics of the receiver are below, in which
+	// {namespace} and {name} are replaced by those of the receiver.
+	// +kubebuilder:default="radio/{namespace}/{name}"
+	// +kubebuilder:validation:Pattern=`^[^#+]+$`
+	// +optional
+	Topic string `json:"topic,omitempty"`
+
+	// QoS is the quality of service the messages are published with.
+	// +kubebuilder:default=1
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=2
+	// +optional
+	QoS *int32 `json:"qos,omitempty"`
+
+	// CredentialsSecretRef names a Secret in the namespace of the receiver
+	// holding the username and, optionally, the password to authenticate
+	// with under the keys username and password.
+	// +optional
+	CredentialsSecretRef *corev1.LocalObjectReference `json:"credentialsSecretRef,omitempty"`
+
+	// HeartbeatInterval is the time between heartbeats. Defaults to 30s.
+	// +optional
+	HeartbeatInterval *metav1.Duration `json:"heartbeatInterval,omitempty"`
+
+	// Interval is the time each signal level is averaged over, and between
+	// publications of the aircraft. Defaults to 5s.
+	// +optional
+	Interval *metav1.Duration `json:"interval,omitempty"`
+}
+
+// RtlSdrEvents configures where decoded messages are published. Every
+// message is published as a JSON event with its time, source, type, the id
+// of its sender and the message as decoded.
+type RtlSdrEvents struct {
+	// Sink is where the events are published: stdout writes them to the
+	// container log, an http or https URL posts each one to a webhook, and
+	// an mqtt or mqtts URL publishes them to a broker.
+	// +kubebuilder:default=stdout
+	// +kubebuilder:validation:Pattern=`^(stdout|(https?|mqtts?)://.+)$`
+	// +kubebuilder:example="mqtt://mosquitto.home.svc:1883"
+	// +optional
+	Sink string `json:"sink,omitempty"`
+
+	// Topic is the MQTT topic the events are published to, defaults to
+	// radio/<namespace>/<name>/events.
+	// +optional
+	Topic string `json:"topic,omitempty"`
+}
+
+// RtlSdrSpectrum configures the live spectrum of a receiver. Each frame is
+// the average of several FFTs of the I/Q stream, spanning the sample rate
+// around the receiver frequency.
+type RtlSdrSpectrum struct {
+	// FFTSize is the number of frequency bins of each frame.
+	// +kubebuilder:default=1024
+	// +kubebuilder:validation:Enum=64;128;256;512;1024;2048;4096;8192;16384
+	// +optional
+	FFTSize *int32 `json:"fftSize,omitempty"`
+
+	// Averaging is the number of FFTs averaged into each frame, smoothing
+	// the noise at the cost of CPU.
+	// +kubebuilder:default=4
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	Averaging *int32 `json:"averaging,omitempty"`
+
+	// FrameRate is the number of frames per second.
+	// +kubebuilder:default=10
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=60
+	// +optional
+	FrameRate *int32 `json:"frameRate,omitempty"`
+}
+
+// RtlSdrScan configures the scanner of a receiver. The scanner measures the
+// power of each channel in turn, and stays on a channel while it is active.
+// +kubebuilder:validation:XValidation:rule="has(self.frequencies) != has(self.range)",message="exactly one of frequencies and range is required"
+type RtlSdrScan struct {
+	// Frequencies are the centre frequencies of the channels to scan.
+	// +kubebuilder:validation:MaxItems=1000
+	// +listType=atomic
+	// +optional
+	Frequencies []resource.Quantity `json:"frequencies,omitempty"`
+
+	// Range scans the channels from Start to End, Step apart.
+	// +optional
+	Range *RtlSdrScanRange `json:"range,omitempty"`
+
+	// Bandwidth is the bandwidth of the channels, defaults to the step of
+	// the range or 25k.
+	// +kubebuilder:example="12.5k"
+	// +optional
+	Bandwidth *resource.Quantity `json:"bandwidth,omitempty"`
+
+	// Squelch is the power in dBFS a channel must reach to count as active.
+	// +kubebuilder:default=-40
+	// +kubebuilder:validation:Maximum=0
+	// +optional
+	Squelch *int32 `json:"squelch,omitempty"`
+
+	// Dwell is the time spent measuring each channel. Defaults to 100ms.
+	// +optional
+	Dwell *metav1.Duration `json:"dwell,omitempty"`
+
+	// Hold is the time the scanner stays on a channel after its activity
+	// ends. Defaults to 2s.
+	// +optional
+	Hold *metav1.Duration `json:"hold,omitempty"`
+
+	// MaxHits is the number of recent hits published in the status.
+	// +kubebuilder:default=10
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	MaxHits *int32 `json:"maxHits,omitempty"`
+}
+
+// RtlSdrScanRange is a range of evenly spaced channels.
+type RtlSdrScanRange struct {
+	// Start is the frequency of the first channel.
+	// +kubebuilder:example="144M"
+	Start resource.Quantity `json:"start"`
+
+	// End is the frequency of the last channel.
+	// +kubebuilder:example="146M"
+	End resource.Quantity `json:"end"`
+
+	// Step is the spacing of the channels.
+	// +kubebuilder:example="12.5k"
+	Step resource.Quantity `json:"step"`
+}
+
+// RtlSdrSchedule describes the recurring windows a receiver runs in.
+type RtlSdrSchedule struct {
+	// Cron is the start of every window in cron format, e.g. "0 21 * * 5"
+	// for every Friday at 21:00.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:example="0 21 * * 5"
+	Cron string `json:"cron"`
+
+	// Duration is the length of every window, at most 720h.
+	// +kubebuilder:validation:XValidation:rule="duration(self) > duration('0s') && duration(self) <= duration('720h')",message="duration must be positive and at most 720h"
+	Duration metav1.Duration `json:"duration"`
+
+	// TimeZone is the IANA name of the time zone Cron is in, e.g.
+	// "Europe/Stockholm". Defaults to UTC.
+	// +optional
+	TimeZone *string `json:"timeZone,omitempty"`
+
+	// MissedWindowPolicy decides whether a window is still run when it is
+	// started later than StartingDeadlineSeconds, e.g. because the
+	// controller was down or the receiver was created mid-window.
+	// +kubebuilder:default=RunRemaining
+	// +optional
+	MissedWindowPolicy RtlSdrMissedWindowPolicy `json:"missedWindowPolicy,omitempty"`
+
+	// StartingDeadlineSeconds is how late a window may start before it
+	// counts as missed.
+	// +kubebuilder:default=60
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	StartingDeadlineSeconds *int64 `json:"startingDeadlineSeconds,omitempty"`
+}
+
+// RtlSdrMissedWindowPolicy decides what happens to a missed window.
+// RunRemaining runs the rest of the window, Skip waits for the next one.
+// +kubebuilder:validation:Enum=RunRemaining;Skip
+type RtlSdrMissedWindowPolicy string
+
+const (
+	MissedWindowRunRemaining RtlSdrMissedWindowPolicy = "RunRemaining"
+	MissedWindowSkip         RtlSdrMissedWindowPolicy = "Skip"
+)
+
+// RtlSdrRecording configures the recording of the I/Q stream of a receiver.
+// Each dataset is a .sigmf-data file of unsigned 8-bit I/Q samples and a
+// .sigmf-meta file describing how they were captured.
+type RtlSdrRecording struct {
+	// ClaimName is the name of the PersistentVolumeClaim the datasets are
+	// written to. The volume must be writable by the receiver pod.
+	// +kubebuilder:validation:MinLength=1
+	ClaimName string `json:"claimName"`
+
+	// Path is the directory within the volume the datasets are written to,
+	// defaults to the name of the receiver.
+	// +optional
+	Path string `json:"path,omitempty"`
+
+	// Duration stops the recording after this long. The recording runs
+	// until the receiver is deleted when unset.
+	// +optional
+	Duration *metav1.Duration `json:"duration,omitempty"`
+
+	// MaxSize stops the recording after this many bytes of samples.
+	// +kubebuilder:example="10Gi"
+	// +optional
+	MaxSize *resource.Quantity `json:"maxSize,omitempty"`
+
+	// Rotation splits the recording into several datasets.
+	// +optional
+	Rotation *RtlSdrRotation `json:"rotation,omitempty"`
+}
+
+// RtlSdrRotation decides when a recording starts a new dataset. A new
+// dataset is also started when the sample rate changes.
+type RtlSdrRotation struct {
+	// Interval starts a new dataset after this long.
+	// +optional
+	Interval *metav1.Duration `json:"interval,omitempty"`
+
+	// MaxFileSize starts a new dataset once the data file has this many
+	// bytes.
+	// +kubebuilder:example="1Gi"
+	// +optional
+	MaxFileSize *resource.Quantity `json:"maxFileSize,omitempty"`
+
+	// MaxFiles is the number of datasets kept on the volume. The oldest
+	// are deleted when a new one is started.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxFiles *int32 `json:"maxFiles,omitempty"`
+}
+
+// RtlSdrSharing configures the proxy sharing the I/Q stream of a receiver
+// among several clients.
+type RtlSdrSharing struct {
+	// Enabled puts a multiplexing proxy in front of rtl_tcp.
+	Enabled bool `json:"enabled"`
+
+	// LockPolicy decides which clients may send tuning commands.
+	// +kubebuilder:default=FirstClient
+	// +optional
+	LockPolicy RtlSdrLockPolicy `json:"lockPolicy,omitempty"`
+
+	// MaxClients limits the number of connected clients.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxClients *int32 `json:"maxClients,omitempty"`
+}
+
+// RtlSdrLockPolicy decides which clients of a shared receiver may tune it.
+// FirstClient lets the longest connected client tune while the others are
+// read-only, Open lets every client tune and ReadOnly ignores all tuning
+// commands.
+// +kubebuilder:validation:Enum=FirstClient;Open;ReadOnly
+type RtlSdrLockPolicy string
+
+const (
+	LockPolicyFirstClient RtlSdrLockPolicy = "FirstClient"
+	LockPolicyOpen        RtlSdrLockPolicy = "Open"
+	LockPolicyReadOnly    RtlSdrLockPolicy = "ReadOnly"
+)
+
+// RtlSdrSimulation configures the fake rtl_tcp server of a simulated
+// receiver.
+type RtlSdrSimulation struct {
+	// Signal is the synthetic signal to stream. Ignored when Replay is set.
+	// +kubebuilder:default=FM
+	// +optional
+	Signal RtlSdrSignal `json:"signal,omitempty"`
+
+	// SignalFrequency is the frequency the synthetic signal is placed at,
+	// defaults to the frequency of the receiver.
+	// +kubebuilder:example="101.9M"
+	// +optional
+	SignalFrequency *resource.Quantity `json:"signalFrequency,omitempty"`
+
+	// Replay streams a recording of unsigned 8-bit I/Q samples in a loop.
+	// +optional
+	Replay *RtlSdrReplay `json:"replay,omitempty"`
+}
+
+// RtlSdrSignal is a synthetic signal streamed by a simulated receiver.
+// +kubebuilder:validation:Enum=FM;Tone;Noise
+type RtlSdrSignal string
+
+const (
+	SignalFM    RtlSdrSignal = "FM"
+	SignalTone  RtlSdrSignal = "Tone"
+	SignalNoise RtlSdrSignal = "Noise"
+)
+
+// RtlSdrReplay points at a recording stored on a PersistentVolumeClaim.
+type RtlSdrReplay struct {
+	// ClaimName is the name of the PersistentVolumeClaim holding the
+	// recording.
+	// +kubebuilder:validation:MinLength=1
+	ClaimName string `json:"claimName"`
+
+	// Path is the path of the recording within the volume.
+	// +kubebuilder:validation:MinLength=1
+	Path string `json:"path"`
+}
+
+// RtlSdrMode is what a receiver produces.
+// +kubebuilder:validation:Enum=IQ;FM;ADSB;AIS;ISM
+type RtlSdrMode string
+
+const (
+	ModeIQ   RtlSdrMode = "IQ"
+	ModeFM   RtlSdrMode = "FM"
+	ModeADSB RtlSdrMode = "ADSB"
+	ModeAIS  RtlSdrMode = "AIS"
+	ModeISM  RtlSdrMode = "ISM"
+)
+
+// RtlSdrWorkload is the kind of object backing a receiver.
+// +kubebuilder:validation:Enum=Pod;Deployment
+type RtlSdrWorkload string
+
+const (
+	WorkloadPod        RtlSdrWorkload = "Pod"
+	WorkloadDeployment RtlSdrWorkload = "Deployment"
+)
+
 // RtlSdrVersion is the major version of the rtl-sdr receiver.
 // +kubebuilder:validation:Enum=v3;v4
 type RtlSdrVersion string
@@ -54,6 +712,8 @@ const (
 // RtlSdrReceiverStatus defines the observed state of RtlSdrReceiver
 type RtlSdrReceiverStatus struct {
 	// Conditions describe the state of the receiver.
+	// +listType=map
+	// +listMapKey=type
 	// +optional
 	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
 
@@ -64,19 +724,234 @@ type RtlSdrReceiverStatus struct {
 	// Pod is a reference to the underlying pod.
 	// +optional
 	Pod *corev1.ObjectReference `json:"pod,omitempty"`
+
+	// Endpoint is the in-cluster URL of the receiver stream, e.g.
+	// tcp://name.namespace.svc:1234 for IQ or an HTTP URL for FM audio. It
+	// is a tls:// or https:// URL when access is guarded.
+	// +optional
+	Endpoint string `json:"endpoint,omitempty"`
+
+	// Deployment is a reference to the underlying deployment when the
+	// receiver uses the Deployment workload.
+	// +optional
+	Deployment *corev1.ObjectReference `json:"deployment,omitempty"`
+
+	// Recording is the progress of the recording, if enabled.
+	// +optional
+	Recording *RtlSdrRecordingStatus `json:"recording,omitempty"`
+
+	// Schedule tracks the windows of a scheduled receiver.
+	// +optional
+	Schedule *RtlSdrScheduleStatus `json:"schedule,omitempty"`
+
+	// Scan is what the scanner is doing and what it has found, if enabled.
+	// +optional
+	Scan *RtlSdrScanStatus `json:"scan,omitempty"`
+
+	// ADSB is the traffic heard by a receiver in ADSB mode.
+	// +optional
+	ADSB *RtlSdrADSBStatus `json:"adsb,omitempty"`
+
+	// SpectrumURL is the in-cluster URL of the live spectrum and waterfall,
+	// if enabled, e.g. http://name.namespace.svc:8090/.
+	// +optional
+	SpectrumURL string `json:"spectrumURL,omitempty"`
+
+	// SampleLoss counts the samples dropped by rtl_tcp, if detected.
+	// +optional
+	SampleLoss *RtlSdrSampleLossStatus `json:"sampleLoss,omitempty"`
+
+	// Access names what clients connect to the stream with, if it is
+	// guarded.
+	// +optional
+	Access *RtlSdrAccessStatus `json:"access,omitempty"`
+
+	// Expose lists the URLs the receiver is reached on from outside the
+	// cluster, if exposed.
+	// +optional
+	Expose *RtlSdrExposeStatus `json:"expose,omitempty"`
+
+	// ObservedGeneration is the generation of the receiver the running
+	// receiver was last tuned from, if tuned live. The receiver is retuned
+	// once it equals metadata.generation.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// ObservedFrequency is the frequency the running receiver is tuned to,
+	// if tuned live. It differs from the frequency of the spec when a client
+	// retuned the receiver.
+	// +optional
+	ObservedFrequency *resource.Quantity `json:"observedFrequency,omitempty"`
+
+	// Pool is where the receiver stands in its pool, if it uses one.
+	// +optional
+	Pool *RtlSdrPoolStatus `json:"pool,omitempty"`
+}
+
+// RtlSdrPoolStatus is where a receiver stands in its pool.
+type RtlSdrPoolStatus struct {
+	// Node is the node the pool assigned the receiver to, once admitted.
+	// +optional
+	Node string `json:"node,omitempty"`
+
+	// Serial is the dongle the pool assigned the receiver, once admitted to
+	// a pool listing serials.
+	// +optional
+	Serial string `json:"serial,omitempty"`
+
+	// Position is the place of the receiver in the queue of the pool,
+	// starting at 1, while queued.
+	// +optional
+	Position int32 `json:"position,omitempty"`
+}
+
+// RtlSdrExposeStatus lists the external URLs of a receiver. They are only
+// known once the hostname is set or the Gateway or Ingress has an address.
+type RtlSdrExposeStatus struct {
+	// URL is the external URL of the HTTP endpoint, e.g.
+	// https://radio.example.com/fm/audio.wav.
+	// +optional
+	URL string `json:"url,omitempty"`
+
+	// StreamURL is the external URL of the raw stream of an IQ receiver
+	// routed through a TCP listener of the Gateway, e.g.
+	// tcp://192.0.2.1:1234.
+	// +optional
+	StreamURL string `json:"streamURL,omitempty"`
+}
+
+// RtlSdrAccessStatus names what the clients of a guarded receiver connect
+// with.
+type RtlSdrAccessStatus struct {
+	// CAConfigMapName names the ConfigMap holding the ca.crt clients
+	// verify the stream with.
+	CAConfigMapName string `json:"caConfigMapName"`
+
+	// ClientSecretName names the Secret holding the client certificate
+	// and key for ClientCertificate authentication.
+	// +optional
+	ClientSecretName string `json:"clientSecretName,omitempty"`
+
+	// NotAfter is when the certificate of the stream expires. It is renewed
+	// well before.
+	// +optional
+	NotAfter *metav1.Time `json:"notAfter,omitempty"`
+}
+
+// RtlSdrSampleLossStatus counts the samples dropped by rtl_tcp since the
+// receiver pod started.
+type RtlSdrSampleLossStatus struct {
+	// DroppedSamples is the number of samples missing from the stream at
+	// its sample rate.
+	DroppedSamples int64 `json:"droppedSamples"`
+
+	// DroppedBuffers is the number of rtl_tcp buffers the dropped samples
+	// make up.
+	DroppedBuffers int64 `json:"droppedBuffers"`
+
+	// Ratio is the percentage of the samples dropped over the last window,
+	// e.g. 0.4%.
+	Ratio string `json:"ratio"`
+}
+
+// RtlSdrADSBStatus describes the traffic heard by the Mode-S decoder of a
+// receiver.
+type RtlSdrADSBStatus struct {
+	// Aircraft is the number of aircraft heard within the last minute.
+	Aircraft int32 `json:"aircraft"`
+
+	// MessageRate is the number of messages decoded per second.
+	MessageRate int32 `json:"messageRate"`
+
+	// Messages is the number of messages decoded since the decoder started.
+	Messages int64 `json:"messages"`
+
+	// LastUpdateTime is when the decoder last reported the aircraft.
+	// +optional
+	LastUpdateTime *metav1.Time `json:"lastUpdateTime,omitempty"`
+}
+
+// RtlSdrScanStatus describes the scanner of a receiver.
+type RtlSdrScanStatus struct {
+	// Frequency is the channel the scanner is on.
+	// +optional
+	Frequency *resource.Quantity `json:"frequency,omitempty"`
+
+	// Hits are the most recent periods of activity, newest first.
+	// +listType=atomic
+	// +optional
+	Hits []RtlSdrScanHit `json:"hits,omitempty"`
+}
+
+// RtlSdrScanHit is a period of activity found by the scanner.
+type RtlSdrScanHit struct {
+	// Frequency is the channel the activity was on.
+	Frequency resource.Quantity `json:"frequency"`
+
+	// Power is the peak power of the channel in dBFS.
+	Power int32 `json:"power"`
+
+	// FirstSeen is when the activity was detected.
+	FirstSeen metav1.Time `json:"firstSeen"`
+
+	// LastSeen is when the activity was last detected.
+	LastSeen metav1.Time `json:"lastSeen"`
+}
+
+// RtlSdrScheduleStatus tracks the windows of a scheduled receiver.
+type RtlSdrScheduleStatus struct {
+	// LastRunTime is the scheduled start of the last window that ran.
+	// +optional
+	LastRunTime *metav1.Time `json:"lastRunTime,omitempty"`
+
+	// ActiveUntil is when the current window ends.
+	// +optional
+	ActiveUntil *metav1.Time `json:"activeUntil,omitempty"`
+
+	// NextRunTime is the start of the next window.
+	// +optional
+	NextRunTime *metav1.Time `json:"nextRunTime,omitempty"`
+
+	// LastMissedTime is the scheduled start of the last window skipped
+	// because it was missed.
+	// +optional
+	LastMissedTime *metav1.Time `json:"lastMissedTime,omitempty"`
+}
+
+// RtlSdrRecordingStatus describes the datasets recorded by a receiver.
+type RtlSdrRecordingStatus struct {
+	// Files are the names of the most recent datasets on the volume, oldest
+	// first and without suffix.
+	// +listType=atomic
+	// +optional
+	Files []string `json:"files,omitempty"`
+
+	// Bytes is the size of the samples of all datasets on the volume.
+	// +optional
+	Bytes int64 `json:"bytes,omitempty"`
+
+	// Finished is set once the duration or size limit has been reached.
+	// +optional
+	Finished bool `json:"finished,omitempty"`
 }
 
 // RtlSdrReceiverState state of the rtl-sdr receiver.
-// +kubebuilder:validation:Enum=Waiting;Running;Failed
+// Scheduled receivers are Scheduled between their windows, receivers of a
+// pool are Queued until the pool admits them.
+// +kubebuilder:validation:Enum=Waiting;Running;Failed;Terminating;Scheduled;Queued
 type RtlSdrReceiverState string
 
 const (
-	StateWaiting RtlSdrReceiverState = "Waiting"
-	StateRunning RtlSdrReceiverState = "Running"
-	StateFailed  RtlSdrReceiverState = "Failed"
+	StateWaiting     RtlSdrReceiverState = "Waiting"
+	StateRunning     RtlSdrReceiverState = "Running"
+	StateFailed      RtlSdrReceiverState = "Failed"
+	StateTerminating RtlSdrReceiverState = "Terminating"
+	StateScheduled   RtlSdrReceiverState = "Scheduled"
+	StateQueued      RtlSdrReceiverState = "Queued"
 )
 
 // RtlSdrReceiver is the Schema for the rtlsdrreceivers API
+// +kubebuilder:ac:generate=true
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
 type RtlSdrReceiver struct {
@@ -0,0 +1,155 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// PoolExhaustedReason queues a receiver while every dongle of the pool
+	// is in use.
+	PoolExhaustedReason = "PoolExhausted"
+	// QuotaExceededReason queues a receiver while its namespace uses all
+	// the dongles its quota allows.
+	QuotaExceededReason = "QuotaExceeded"
+)
+
+// RtlSdrReceiverPoolSpec defines the desired state of RtlSdrReceiverPool
+type RtlSdrReceiverPoolSpec struct {
+	// NodeSelector selects the nodes whose dongles are in the pool, all
+	// nodes when unset.
+	// +optional
+	NodeSelector *metav1.LabelSelector `json:"nodeSelector,omitempty"`
+
+	// Serials limits the pool to the dongles with these serials, as listed
+	// by the device plugin, all the dongles of the nodes when unset. Each
+	// receiver is then allocated exactly the dongle it was admitted to.
+	// +listType=set
+	// +optional
+	Serials []string `json:"serials,omitempty"`
+
+	// Quotas cap the dongles the receivers of a namespace use at once.
+	// +listType=map
+	// +listMapKey=namespace
+	// +optional
+	Quotas []RtlSdrPoolQuota `json:"quotas,omitempty"`
+
+	// DefaultQuota caps the dongles used at once by the receivers of the
+	// namespaces without a quota, unlimited when unset.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	DefaultQuota *int32 `json:"defaultQuota,omitempty"`
+}
+
+// RtlSdrPoolQuota caps the dongles of a pool used by a namespace.
+type RtlSdrPoolQuota struct {
+	// Namespace is the namespace of the receivers.
+	Namespace string `json:"namespace"`
+
+	// Receivers is the number of receivers of the namespace admitted at
+	// once.
+	// +kubebuilder:validation:Minimum=0
+	Receivers int32 `json:"receivers"`
+}
+
+// RtlSdrReceiverPoolStatus defines the observed state of RtlSdrReceiverPool
+type RtlSdrReceiverPoolStatus struct {
+	// Capacity is the number of dongles in the pool.
+	// +optional
+	Capacity int32 `json:"capacity,omitempty"`
+
+	// Allocated is the number of receivers admitted to the pool.
+	// +optional
+	Allocated int32 `json:"allocated,omitempty"`
+
+	// Queued is the number of receivers waiting for a dongle.
+	// +optional
+	Queued int32 `json:"queued,omitempty"`
+
+	// Admitted are the receivers assigned a dongle of the pool and the node
+	// it is plugged into.
+	// +listType=atomic
+	// +optional
+	Admitted []RtlSdrPoolAdmission `json:"admitted,omitempty"`
+
+	// Queue are the receivers waiting for a dongle, in the order they are
+	// admitted.
+	// +listType=atomic
+	// +optional
+	Queue []RtlSdrPoolQueueEntry `json:"queue,omitempty"`
+}
+
+// RtlSdrPoolAdmission is a receiver assigned a dongle of a pool.
+type RtlSdrPoolAdmission struct {
+	// Namespace is the namespace of the receiver.
+	Namespace string `json:"namespace"`
+
+	// Name is the name of the receiver.
+	Name string `json:"name"`
+
+	// Node is the node the receiver runs on.
+	Node string `json:"node"`
+
+	// Serial is the dongle the receiver is allocated, when the pool lists
+	// serials.
+	// +optional
+	Serial string `json:"serial,omitempty"`
+}
+
+// RtlSdrPoolQueueEntry is a receiver waiting for a dongle of a pool.
+type RtlSdrPoolQueueEntry struct {
+	// Namespace is the namespace of the receiver.
+	Namespace string `json:"namespace"`
+
+	// Name is the name of the receiver.
+	Name string `json:"name"`
+
+	// Priority is the priority of the receiver in the pool.
+	// +optional
+	Priority int32 `json:"priority,omitempty"`
+
+	// Reason is why the receiver waits, PoolExhausted or QuotaExceeded.
+	Reason string `json:"reason"`
+}
+
+// RtlSdrReceiverPool is a set of dongles shared by the receivers referencing
+// it. The controller assigns each receiver a node with a free dongle, and
+// queues the receivers by priority while the pool or their quota is used up.
+// +kubebuilder:ac:generate=true
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:name="Capacity",type=integer,JSONPath=`.status.capacity`
+// +kubebuilder:printcolumn:name="Allocated",type=integer,JSONPath=`.status.allocated`
+// +kubebuilder:printcolumn:name="Queued",type=integer,JSONPath=`.status.queued`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+type RtlSdrReceiverPool struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   RtlSdrReceiverPoolSpec   `json:"spec,omitempty"`
+	Status RtlSdrReceiverPoolStatus `json:"status,omitempty"`
+}
+
+// RtlSdrReceiverPoolList contains a list of RtlSdrReceiverPool
+// +kubebuilder:object:root=true
+type RtlSdrReceiverPoolList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []RtlSdrReceiverPool `json:"items"`
+}
@@ -0,0 +1,186 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	InvalidSurveyReason = "InvalidSurvey"
+	JobFailedReason     = "JobFailed"
+	CompleteCondition   = "Complete"
+)
+
+// RtlSdrSurveySpec defines the desired state of RtlSdrSurvey
+// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="spec is immutable, create a new survey instead"
+type RtlSdrSurveySpec struct {
+	// +kubebuilder:default=v4
+	// +optional
+	Version RtlSdrVersion `json:"version,omitempty"`
+
+	// Start is the lowest frequency of the surveyed range.
+	// +kubebuilder:example="88M"
+	Start resource.Quantity `json:"start"`
+
+	// End is the highest frequency of the surveyed range.
+	// +kubebuilder:example="108M"
+	End resource.Quantity `json:"end"`
+
+	// BinSize is the width of the frequency bins the power is measured in.
+	// +kubebuilder:example="10k"
+	BinSize resource.Quantity `json:"binSize"`
+
+	// Integration is the time the power of every bin is averaged over.
+	// Defaults to 10s like rtl_power.
+	// +optional
+	Integration *metav1.Duration `json:"integration,omitempty"`
+
+	// Duration repeats the sweep for this long, giving one heatmap row per
+	// sweep. A single sweep is measured when unset.
+	// +optional
+	Duration *metav1.Duration `json:"duration,omitempty"`
+
+	// Gain is the tuner gain in dB, automatic when unset.
+	// +kubebuilder:example="29.7"
+	// +optional
+	Gain *resource.Quantity `json:"gain,omitempty"`
+
+	// NodeName runs the survey on the dongle of this node, e.g. the site an
+	// antenna is planned for. Any node with a free dongle is used when
+	// neither NodeName nor NodeSelector is set.
+	// +optional
+	NodeName string `json:"nodeName,omitempty"`
+
+	// NodeSelector runs the survey on a node with these labels.
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// Peaks is the number of peak frequencies summarised in the status.
+	// +kubebuilder:default=10
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=20
+	// +optional
+	Peaks *int32 `json:"peaks,omitempty"`
+
+	// Output stores the CSV written by rtl_power, a heatmap of the sweeps
+	// and the summary. Only the summary in the status is kept when unset.
+	// +optional
+	Output *RtlSdrSurveyOutput `json:"output,omitempty"`
+}
+
+// RtlSdrSurveyOutput is where the results of a survey are stored: survey.csv
+// in the rtl_power format, heatmap.png and summary.json.
+// +kubebuilder:validation:XValidation:rule="has(self.claimName) != has(self.configMap)",message="exactly one of claimName and configMap is required"
+type RtlSdrSurveyOutput struct {
+	// ClaimName is the name of the PersistentVolumeClaim the results are
+	// written to. The volume must be writable by the survey pod.
+	// +optional
+	ClaimName string `json:"claimName,omitempty"`
+
+	// Path is the directory within the volume the results are written to,
+	// defaults to the name of the survey.
+	// +optional
+	Path string `json:"path,omitempty"`
+
+	// ConfigMap is the name of a ConfigMap the controller creates to hold
+	// the results. A ConfigMap holds at most 1MiB, which fits short sweeps
+	// of narrow ranges.
+	// +optional
+	ConfigMap string `json:"configMap,omitempty"`
+}
+
+// RtlSdrSurveyState is the state of a survey.
+// +kubebuilder:validation:Enum=Pending;Running;Succeeded;Failed
+type RtlSdrSurveyState string
+
+const (
+	SurveyPending   RtlSdrSurveyState = "Pending"
+	SurveyRunning   RtlSdrSurveyState = "Running"
+	SurveySucceeded RtlSdrSurveyState = "Succeeded"
+	SurveyFailed    RtlSdrSurveyState = "Failed"
+)
+
+// RtlSdrSurveyStatus defines the observed state of RtlSdrSurvey
+type RtlSdrSurveyStatus struct {
+	// Conditions describe the state of the survey.
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+
+	// State describes the current state of the survey.
+	// +optional
+	State RtlSdrSurveyState `json:"state,omitempty"`
+
+	// Job is a reference to the Job running the survey.
+	// +optional
+	Job *corev1.ObjectReference `json:"job,omitempty"`
+
+	// StartTime is when the survey started.
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// CompletionTime is when the survey finished.
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// Sweeps is the number of sweeps measured.
+	// +optional
+	Sweeps int32 `json:"sweeps,omitempty"`
+
+	// Peaks are the strongest peaks of the surveyed range, strongest first.
+	// +listType=atomic
+	// +optional
+	Peaks []RtlSdrSurveyPeak `json:"peaks,omitempty"`
+}
+
+// RtlSdrSurveyPeak is a frequency standing out of the noise floor.
+type RtlSdrSurveyPeak struct {
+	// Frequency is the centre of the bin of the peak.
+	Frequency resource.Quantity `json:"frequency"`
+
+	// Power is the highest power of the bin over all sweeps in dB.
+	Power int32 `json:"power"`
+}
+
+// RtlSdrSurvey is a one-shot sweep of a frequency range measuring the power
+// of every bin, like rtl_power.
+// +kubebuilder:ac:generate=true
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Start",type=string,JSONPath=`.spec.start`
+// +kubebuilder:printcolumn:name="End",type=string,JSONPath=`.spec.end`
+// +kubebuilder:printcolumn:name="State",type=string,JSONPath=`.status.state`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+type RtlSdrSurvey struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   RtlSdrSurveySpec   `json:"spec,omitempty"`
+	Status RtlSdrSurveyStatus `json:"status,omitempty"`
+}
+
+// RtlSdrSurveyList contains a list of RtlSdrSurvey
+// +kubebuilder:object:root=true
+type RtlSdrSurveyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []RtlSdrSurvey `json:"items"`
+}
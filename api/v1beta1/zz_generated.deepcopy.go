@@ -21,11 +21,458 @@ limitations under the License.
 package v1beta1
 
 import (
-	"k8s.io/api/core/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RtlSdrADSBStatus) DeepCopyInto(out *RtlSdrADSBStatus) {
+	*out = *in
+	if in.LastUpdateTime != nil {
+		in, out := &in.LastUpdateTime, &out.LastUpdateTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RtlSdrADSBStatus.
+func (in *RtlSdrADSBStatus) DeepCopy() *RtlSdrADSBStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RtlSdrADSBStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RtlSdrAccess) DeepCopyInto(out *RtlSdrAccess) {
+	*out = *in
+	if in.Audiences != nil {
+		in, out := &in.Audiences, &out.Audiences
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Hosts != nil {
+		in, out := &in.Hosts, &out.Hosts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RtlSdrAccess.
+func (in *RtlSdrAccess) DeepCopy() *RtlSdrAccess {
+	if in == nil {
+		return nil
+	}
+	out := new(RtlSdrAccess)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RtlSdrAccessStatus) DeepCopyInto(out *RtlSdrAccessStatus) {
+	*out = *in
+	if in.NotAfter != nil {
+		in, out := &in.NotAfter, &out.NotAfter
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RtlSdrAccessStatus.
+func (in *RtlSdrAccessStatus) DeepCopy() *RtlSdrAccessStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RtlSdrAccessStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RtlSdrChannel) DeepCopyInto(out *RtlSdrChannel) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RtlSdrChannel.
+func (in *RtlSdrChannel) DeepCopy() *RtlSdrChannel {
+	if in == nil {
+		return nil
+	}
+	out := new(RtlSdrChannel)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RtlSdrChannel) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RtlSdrChannelList) DeepCopyInto(out *RtlSdrChannelList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]RtlSdrChannel, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RtlSdrChannelList.
+func (in *RtlSdrChannelList) DeepCopy() *RtlSdrChannelList {
+	if in == nil {
+		return nil
+	}
+	out := new(RtlSdrChannelList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RtlSdrChannelList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RtlSdrChannelSpec) DeepCopyInto(out *RtlSdrChannelSpec) {
+	*out = *in
+	out.Offset = in.Offset.DeepCopy()
+	out.Bandwidth = in.Bandwidth.DeepCopy()
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RtlSdrChannelSpec.
+func (in *RtlSdrChannelSpec) DeepCopy() *RtlSdrChannelSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RtlSdrChannelSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RtlSdrChannelStatus) DeepCopyInto(out *RtlSdrChannelStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Frequency != nil {
+		in, out := &in.Frequency, &out.Frequency
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.SampleRate != nil {
+		in, out := &in.SampleRate, &out.SampleRate
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RtlSdrChannelStatus.
+func (in *RtlSdrChannelStatus) DeepCopy() *RtlSdrChannelStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RtlSdrChannelStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RtlSdrEvents) DeepCopyInto(out *RtlSdrEvents) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RtlSdrEvents.
+func (in *RtlSdrEvents) DeepCopy() *RtlSdrEvents {
+	if in == nil {
+		return nil
+	}
+	out := new(RtlSdrEvents)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RtlSdrExpose) DeepCopyInto(out *RtlSdrExpose) {
+	*out = *in
+	if in.Gateway != nil {
+		in, out := &in.Gateway, &out.Gateway
+		*out = new(RtlSdrGatewayExpose)
+		**out = **in
+	}
+	if in.Ingress != nil {
+		in, out := &in.Ingress, &out.Ingress
+		*out = new(RtlSdrIngressExpose)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RtlSdrExpose.
+func (in *RtlSdrExpose) DeepCopy() *RtlSdrExpose {
+	if in == nil {
+		return nil
+	}
+	out := new(RtlSdrExpose)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RtlSdrExposeStatus) DeepCopyInto(out *RtlSdrExposeStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RtlSdrExposeStatus.
+func (in *RtlSdrExposeStatus) DeepCopy() *RtlSdrExposeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RtlSdrExposeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RtlSdrGatewayExpose) DeepCopyInto(out *RtlSdrGatewayExpose) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RtlSdrGatewayExpose.
+func (in *RtlSdrGatewayExpose) DeepCopy() *RtlSdrGatewayExpose {
+	if in == nil {
+		return nil
+	}
+	out := new(RtlSdrGatewayExpose)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RtlSdrIngressExpose) DeepCopyInto(out *RtlSdrIngressExpose) {
+	*out = *in
+	if in.ClassName != nil {
+		in, out := &in.ClassName, &out.ClassName
+		*out = new(string)
+		**out = **in
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RtlSdrIngressExpose.
+func (in *RtlSdrIngressExpose) DeepCopy() *RtlSdrIngressExpose {
+	if in == nil {
+		return nil
+	}
+	out := new(RtlSdrIngressExpose)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RtlSdrLiveTuning) DeepCopyInto(out *RtlSdrLiveTuning) {
+	*out = *in
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RtlSdrLiveTuning.
+func (in *RtlSdrLiveTuning) DeepCopy() *RtlSdrLiveTuning {
+	if in == nil {
+		return nil
+	}
+	out := new(RtlSdrLiveTuning)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RtlSdrMQTT) DeepCopyInto(out *RtlSdrMQTT) {
+	*out = *in
+	if in.QoS != nil {
+		in, out := &in.QoS, &out.QoS
+		*out = new(int32)
+		**out = **in
+	}
+	if in.CredentialsSecretRef != nil {
+		in, out := &in.CredentialsSecretRef, &out.CredentialsSecretRef
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
+	if in.HeartbeatInterval != nil {
+		in, out := &in.HeartbeatInterval, &out.HeartbeatInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RtlSdrMQTT.
+func (in *RtlSdrMQTT) DeepCopy() *RtlSdrMQTT {
+	if in == nil {
+		return nil
+	}
+	out := new(RtlSdrMQTT)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RtlSdrMetrics) DeepCopyInto(out *RtlSdrMetrics) {
+	*out = *in
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.FFTSize != nil {
+		in, out := &in.FFTSize, &out.FFTSize
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RtlSdrMetrics.
+func (in *RtlSdrMetrics) DeepCopy() *RtlSdrMetrics {
+	if in == nil {
+		return nil
+	}
+	out := new(RtlSdrMetrics)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RtlSdrNetworkPolicy) DeepCopyInto(out *RtlSdrNetworkPolicy) {
+	*out = *in
+	if in.From != nil {
+		in, out := &in.From, &out.From
+		*out = make([]networkingv1.NetworkPolicyPeer, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RtlSdrNetworkPolicy.
+func (in *RtlSdrNetworkPolicy) DeepCopy() *RtlSdrNetworkPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(RtlSdrNetworkPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RtlSdrPoolAdmission) DeepCopyInto(out *RtlSdrPoolAdmission) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RtlSdrPoolAdmission.
+func (in *RtlSdrPoolAdmission) DeepCopy() *RtlSdrPoolAdmission {
+	if in == nil {
+		return nil
+	}
+	out := new(RtlSdrPoolAdmission)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RtlSdrPoolQueueEntry) DeepCopyInto(out *RtlSdrPoolQueueEntry) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RtlSdrPoolQueueEntry.
+func (in *RtlSdrPoolQueueEntry) DeepCopy() *RtlSdrPoolQueueEntry {
+	if in == nil {
+		return nil
+	}
+	out := new(RtlSdrPoolQueueEntry)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RtlSdrPoolQuota) DeepCopyInto(out *RtlSdrPoolQuota) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RtlSdrPoolQuota.
+func (in *RtlSdrPoolQuota) DeepCopy() *RtlSdrPoolQuota {
+	if in == nil {
+		return nil
+	}
+	out := new(RtlSdrPoolQuota)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RtlSdrPoolReference) DeepCopyInto(out *RtlSdrPoolReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RtlSdrPoolReference.
+func (in *RtlSdrPoolReference) DeepCopy() *RtlSdrPoolReference {
+	if in == nil {
+		return nil
+	}
+	out := new(RtlSdrPoolReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RtlSdrPoolStatus) DeepCopyInto(out *RtlSdrPoolStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RtlSdrPoolStatus.
+func (in *RtlSdrPoolStatus) DeepCopy() *RtlSdrPoolStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RtlSdrPoolStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RtlSdrReceiver) DeepCopyInto(out *RtlSdrReceiver) {
 	*out = *in
@@ -35,18 +482,765 @@ func (in *RtlSdrReceiver) DeepCopyInto(out *RtlSdrReceiver) {
 	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RtlSdrReceiver.
-func (in *RtlSdrReceiver) DeepCopy() *RtlSdrReceiver {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RtlSdrReceiver.
+func (in *RtlSdrReceiver) DeepCopy() *RtlSdrReceiver {
+	if in == nil {
+		return nil
+	}
+	out := new(RtlSdrReceiver)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RtlSdrReceiver) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RtlSdrReceiverList) DeepCopyInto(out *RtlSdrReceiverList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]RtlSdrReceiver, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RtlSdrReceiverList.
+func (in *RtlSdrReceiverList) DeepCopy() *RtlSdrReceiverList {
+	if in == nil {
+		return nil
+	}
+	out := new(RtlSdrReceiverList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RtlSdrReceiverList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RtlSdrReceiverPool) DeepCopyInto(out *RtlSdrReceiverPool) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RtlSdrReceiverPool.
+func (in *RtlSdrReceiverPool) DeepCopy() *RtlSdrReceiverPool {
+	if in == nil {
+		return nil
+	}
+	out := new(RtlSdrReceiverPool)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RtlSdrReceiverPool) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RtlSdrReceiverPoolList) DeepCopyInto(out *RtlSdrReceiverPoolList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]RtlSdrReceiverPool, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RtlSdrReceiverPoolList.
+func (in *RtlSdrReceiverPoolList) DeepCopy() *RtlSdrReceiverPoolList {
+	if in == nil {
+		return nil
+	}
+	out := new(RtlSdrReceiverPoolList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RtlSdrReceiverPoolList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RtlSdrReceiverPoolSpec) DeepCopyInto(out *RtlSdrReceiverPoolSpec) {
+	*out = *in
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Serials != nil {
+		in, out := &in.Serials, &out.Serials
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Quotas != nil {
+		in, out := &in.Quotas, &out.Quotas
+		*out = make([]RtlSdrPoolQuota, len(*in))
+		copy(*out, *in)
+	}
+	if in.DefaultQuota != nil {
+		in, out := &in.DefaultQuota, &out.DefaultQuota
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RtlSdrReceiverPoolSpec.
+func (in *RtlSdrReceiverPoolSpec) DeepCopy() *RtlSdrReceiverPoolSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RtlSdrReceiverPoolSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RtlSdrReceiverPoolStatus) DeepCopyInto(out *RtlSdrReceiverPoolStatus) {
+	*out = *in
+	if in.Admitted != nil {
+		in, out := &in.Admitted, &out.Admitted
+		*out = make([]RtlSdrPoolAdmission, len(*in))
+		copy(*out, *in)
+	}
+	if in.Queue != nil {
+		in, out := &in.Queue, &out.Queue
+		*out = make([]RtlSdrPoolQueueEntry, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RtlSdrReceiverPoolStatus.
+func (in *RtlSdrReceiverPoolStatus) DeepCopy() *RtlSdrReceiverPoolStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RtlSdrReceiverPoolStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RtlSdrReceiverSpec) DeepCopyInto(out *RtlSdrReceiverSpec) {
+	*out = *in
+	if in.Frequency != nil {
+		in, out := &in.Frequency, &out.Frequency
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.SampleRate != nil {
+		in, out := &in.SampleRate, &out.SampleRate
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.Gain != nil {
+		in, out := &in.Gain, &out.Gain
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.LiveTuning != nil {
+		in, out := &in.LiveTuning, &out.LiveTuning
+		*out = new(RtlSdrLiveTuning)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ContainerPort != nil {
+		in, out := &in.ContainerPort, &out.ContainerPort
+		*out = new(corev1.ContainerPort)
+		**out = **in
+	}
+	if in.TerminationGracePeriodSeconds != nil {
+		in, out := &in.TerminationGracePeriodSeconds, &out.TerminationGracePeriodSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Simulation != nil {
+		in, out := &in.Simulation, &out.Simulation
+		*out = new(RtlSdrSimulation)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Sharing != nil {
+		in, out := &in.Sharing, &out.Sharing
+		*out = new(RtlSdrSharing)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Recording != nil {
+		in, out := &in.Recording, &out.Recording
+		*out = new(RtlSdrRecording)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Schedule != nil {
+		in, out := &in.Schedule, &out.Schedule
+		*out = new(RtlSdrSchedule)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Scan != nil {
+		in, out := &in.Scan, &out.Scan
+		*out = new(RtlSdrScan)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Spectrum != nil {
+		in, out := &in.Spectrum, &out.Spectrum
+		*out = new(RtlSdrSpectrum)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Events != nil {
+		in, out := &in.Events, &out.Events
+		*out = new(RtlSdrEvents)
+		**out = **in
+	}
+	if in.MQTT != nil {
+		in, out := &in.MQTT, &out.MQTT
+		*out = new(RtlSdrMQTT)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Metrics != nil {
+		in, out := &in.Metrics, &out.Metrics
+		*out = new(RtlSdrMetrics)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SampleLoss != nil {
+		in, out := &in.SampleLoss, &out.SampleLoss
+		*out = new(RtlSdrSampleLoss)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Access != nil {
+		in, out := &in.Access, &out.Access
+		*out = new(RtlSdrAccess)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NetworkPolicy != nil {
+		in, out := &in.NetworkPolicy, &out.NetworkPolicy
+		*out = new(RtlSdrNetworkPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Expose != nil {
+		in, out := &in.Expose, &out.Expose
+		*out = new(RtlSdrExpose)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Pool != nil {
+		in, out := &in.Pool, &out.Pool
+		*out = new(RtlSdrPoolReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RtlSdrReceiverSpec.
+func (in *RtlSdrReceiverSpec) DeepCopy() *RtlSdrReceiverSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RtlSdrReceiverSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RtlSdrReceiverStatus) DeepCopyInto(out *RtlSdrReceiverStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Pod != nil {
+		in, out := &in.Pod, &out.Pod
+		*out = new(corev1.ObjectReference)
+		**out = **in
+	}
+	if in.Deployment != nil {
+		in, out := &in.Deployment, &out.Deployment
+		*out = new(corev1.ObjectReference)
+		**out = **in
+	}
+	if in.Recording != nil {
+		in, out := &in.Recording, &out.Recording
+		*out = new(RtlSdrRecordingStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Schedule != nil {
+		in, out := &in.Schedule, &out.Schedule
+		*out = new(RtlSdrScheduleStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Scan != nil {
+		in, out := &in.Scan, &out.Scan
+		*out = new(RtlSdrScanStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ADSB != nil {
+		in, out := &in.ADSB, &out.ADSB
+		*out = new(RtlSdrADSBStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SampleLoss != nil {
+		in, out := &in.SampleLoss, &out.SampleLoss
+		*out = new(RtlSdrSampleLossStatus)
+		**out = **in
+	}
+	if in.Access != nil {
+		in, out := &in.Access, &out.Access
+		*out = new(RtlSdrAccessStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Expose != nil {
+		in, out := &in.Expose, &out.Expose
+		*out = new(RtlSdrExposeStatus)
+		**out = **in
+	}
+	if in.ObservedFrequency != nil {
+		in, out := &in.ObservedFrequency, &out.ObservedFrequency
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.Pool != nil {
+		in, out := &in.Pool, &out.Pool
+		*out = new(RtlSdrPoolStatus)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RtlSdrReceiverStatus.
+func (in *RtlSdrReceiverStatus) DeepCopy() *RtlSdrReceiverStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RtlSdrReceiverStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RtlSdrRecording) DeepCopyInto(out *RtlSdrRecording) {
+	*out = *in
+	if in.Duration != nil {
+		in, out := &in.Duration, &out.Duration
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.MaxSize != nil {
+		in, out := &in.MaxSize, &out.MaxSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.Rotation != nil {
+		in, out := &in.Rotation, &out.Rotation
+		*out = new(RtlSdrRotation)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RtlSdrRecording.
+func (in *RtlSdrRecording) DeepCopy() *RtlSdrRecording {
+	if in == nil {
+		return nil
+	}
+	out := new(RtlSdrRecording)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RtlSdrRecordingStatus) DeepCopyInto(out *RtlSdrRecordingStatus) {
+	*out = *in
+	if in.Files != nil {
+		in, out := &in.Files, &out.Files
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RtlSdrRecordingStatus.
+func (in *RtlSdrRecordingStatus) DeepCopy() *RtlSdrRecordingStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RtlSdrRecordingStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RtlSdrReplay) DeepCopyInto(out *RtlSdrReplay) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RtlSdrReplay.
+func (in *RtlSdrReplay) DeepCopy() *RtlSdrReplay {
+	if in == nil {
+		return nil
+	}
+	out := new(RtlSdrReplay)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RtlSdrRotation) DeepCopyInto(out *RtlSdrRotation) {
+	*out = *in
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.MaxFileSize != nil {
+		in, out := &in.MaxFileSize, &out.MaxFileSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.MaxFiles != nil {
+		in, out := &in.MaxFiles, &out.MaxFiles
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RtlSdrRotation.
+func (in *RtlSdrRotation) DeepCopy() *RtlSdrRotation {
+	if in == nil {
+		return nil
+	}
+	out := new(RtlSdrRotation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RtlSdrSampleLoss) DeepCopyInto(out *RtlSdrSampleLoss) {
+	*out = *in
+	if in.Window != nil {
+		in, out := &in.Window, &out.Window
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RtlSdrSampleLoss.
+func (in *RtlSdrSampleLoss) DeepCopy() *RtlSdrSampleLoss {
+	if in == nil {
+		return nil
+	}
+	out := new(RtlSdrSampleLoss)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RtlSdrSampleLossStatus) DeepCopyInto(out *RtlSdrSampleLossStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RtlSdrSampleLossStatus.
+func (in *RtlSdrSampleLossStatus) DeepCopy() *RtlSdrSampleLossStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RtlSdrSampleLossStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RtlSdrScan) DeepCopyInto(out *RtlSdrScan) {
+	*out = *in
+	if in.Frequencies != nil {
+		in, out := &in.Frequencies, &out.Frequencies
+		*out = make([]resource.Quantity, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Range != nil {
+		in, out := &in.Range, &out.Range
+		*out = new(RtlSdrScanRange)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Bandwidth != nil {
+		in, out := &in.Bandwidth, &out.Bandwidth
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.Squelch != nil {
+		in, out := &in.Squelch, &out.Squelch
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Dwell != nil {
+		in, out := &in.Dwell, &out.Dwell
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Hold != nil {
+		in, out := &in.Hold, &out.Hold
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.MaxHits != nil {
+		in, out := &in.MaxHits, &out.MaxHits
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RtlSdrScan.
+func (in *RtlSdrScan) DeepCopy() *RtlSdrScan {
+	if in == nil {
+		return nil
+	}
+	out := new(RtlSdrScan)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RtlSdrScanHit) DeepCopyInto(out *RtlSdrScanHit) {
+	*out = *in
+	out.Frequency = in.Frequency.DeepCopy()
+	in.FirstSeen.DeepCopyInto(&out.FirstSeen)
+	in.LastSeen.DeepCopyInto(&out.LastSeen)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RtlSdrScanHit.
+func (in *RtlSdrScanHit) DeepCopy() *RtlSdrScanHit {
+	if in == nil {
+		return nil
+	}
+	out := new(RtlSdrScanHit)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RtlSdrScanRange) DeepCopyInto(out *RtlSdrScanRange) {
+	*out = *in
+	out.Start = in.Start.DeepCopy()
+	out.End = in.End.DeepCopy()
+	out.Step = in.Step.DeepCopy()
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RtlSdrScanRange.
+func (in *RtlSdrScanRange) DeepCopy() *RtlSdrScanRange {
+	if in == nil {
+		return nil
+	}
+	out := new(RtlSdrScanRange)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RtlSdrScanStatus) DeepCopyInto(out *RtlSdrScanStatus) {
+	*out = *in
+	if in.Frequency != nil {
+		in, out := &in.Frequency, &out.Frequency
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.Hits != nil {
+		in, out := &in.Hits, &out.Hits
+		*out = make([]RtlSdrScanHit, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RtlSdrScanStatus.
+func (in *RtlSdrScanStatus) DeepCopy() *RtlSdrScanStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RtlSdrScanStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RtlSdrSchedule) DeepCopyInto(out *RtlSdrSchedule) {
+	*out = *in
+	out.Duration = in.Duration
+	if in.TimeZone != nil {
+		in, out := &in.TimeZone, &out.TimeZone
+		*out = new(string)
+		**out = **in
+	}
+	if in.StartingDeadlineSeconds != nil {
+		in, out := &in.StartingDeadlineSeconds, &out.StartingDeadlineSeconds
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RtlSdrSchedule.
+func (in *RtlSdrSchedule) DeepCopy() *RtlSdrSchedule {
+	if in == nil {
+		return nil
+	}
+	out := new(RtlSdrSchedule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RtlSdrScheduleStatus) DeepCopyInto(out *RtlSdrScheduleStatus) {
+	*out = *in
+	if in.LastRunTime != nil {
+		in, out := &in.LastRunTime, &out.LastRunTime
+		*out = (*in).DeepCopy()
+	}
+	if in.ActiveUntil != nil {
+		in, out := &in.ActiveUntil, &out.ActiveUntil
+		*out = (*in).DeepCopy()
+	}
+	if in.NextRunTime != nil {
+		in, out := &in.NextRunTime, &out.NextRunTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastMissedTime != nil {
+		in, out := &in.LastMissedTime, &out.LastMissedTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RtlSdrScheduleStatus.
+func (in *RtlSdrScheduleStatus) DeepCopy() *RtlSdrScheduleStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RtlSdrScheduleStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RtlSdrSharing) DeepCopyInto(out *RtlSdrSharing) {
+	*out = *in
+	if in.MaxClients != nil {
+		in, out := &in.MaxClients, &out.MaxClients
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RtlSdrSharing.
+func (in *RtlSdrSharing) DeepCopy() *RtlSdrSharing {
+	if in == nil {
+		return nil
+	}
+	out := new(RtlSdrSharing)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RtlSdrSimulation) DeepCopyInto(out *RtlSdrSimulation) {
+	*out = *in
+	if in.SignalFrequency != nil {
+		in, out := &in.SignalFrequency, &out.SignalFrequency
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.Replay != nil {
+		in, out := &in.Replay, &out.Replay
+		*out = new(RtlSdrReplay)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RtlSdrSimulation.
+func (in *RtlSdrSimulation) DeepCopy() *RtlSdrSimulation {
+	if in == nil {
+		return nil
+	}
+	out := new(RtlSdrSimulation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RtlSdrSpectrum) DeepCopyInto(out *RtlSdrSpectrum) {
+	*out = *in
+	if in.FFTSize != nil {
+		in, out := &in.FFTSize, &out.FFTSize
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Averaging != nil {
+		in, out := &in.Averaging, &out.Averaging
+		*out = new(int32)
+		**out = **in
+	}
+	if in.FrameRate != nil {
+		in, out := &in.FrameRate, &out.FrameRate
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RtlSdrSpectrum.
+func (in *RtlSdrSpectrum) DeepCopy() *RtlSdrSpectrum {
+	if in == nil {
+		return nil
+	}
+	out := new(RtlSdrSpectrum)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RtlSdrSurvey) DeepCopyInto(out *RtlSdrSurvey) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RtlSdrSurvey.
+func (in *RtlSdrSurvey) DeepCopy() *RtlSdrSurvey {
 	if in == nil {
 		return nil
 	}
-	out := new(RtlSdrReceiver)
+	out := new(RtlSdrSurvey)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *RtlSdrReceiver) DeepCopyObject() runtime.Object {
+func (in *RtlSdrSurvey) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -54,31 +1248,31 @@ func (in *RtlSdrReceiver) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RtlSdrReceiverList) DeepCopyInto(out *RtlSdrReceiverList) {
+func (in *RtlSdrSurveyList) DeepCopyInto(out *RtlSdrSurveyList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]RtlSdrReceiver, len(*in))
+		*out = make([]RtlSdrSurvey, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RtlSdrReceiverList.
-func (in *RtlSdrReceiverList) DeepCopy() *RtlSdrReceiverList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RtlSdrSurveyList.
+func (in *RtlSdrSurveyList) DeepCopy() *RtlSdrSurveyList {
 	if in == nil {
 		return nil
 	}
-	out := new(RtlSdrReceiverList)
+	out := new(RtlSdrSurveyList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *RtlSdrReceiverList) DeepCopyObject() runtime.Object {
+func (in *RtlSdrSurveyList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -86,53 +1280,124 @@ func (in *RtlSdrReceiverList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RtlSdrReceiverSpec) DeepCopyInto(out *RtlSdrReceiverSpec) {
+func (in *RtlSdrSurveyOutput) DeepCopyInto(out *RtlSdrSurveyOutput) {
 	*out = *in
-	if in.Frequency != nil {
-		in, out := &in.Frequency, &out.Frequency
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RtlSdrSurveyOutput.
+func (in *RtlSdrSurveyOutput) DeepCopy() *RtlSdrSurveyOutput {
+	if in == nil {
+		return nil
+	}
+	out := new(RtlSdrSurveyOutput)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RtlSdrSurveyPeak) DeepCopyInto(out *RtlSdrSurveyPeak) {
+	*out = *in
+	out.Frequency = in.Frequency.DeepCopy()
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RtlSdrSurveyPeak.
+func (in *RtlSdrSurveyPeak) DeepCopy() *RtlSdrSurveyPeak {
+	if in == nil {
+		return nil
+	}
+	out := new(RtlSdrSurveyPeak)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RtlSdrSurveySpec) DeepCopyInto(out *RtlSdrSurveySpec) {
+	*out = *in
+	out.Start = in.Start.DeepCopy()
+	out.End = in.End.DeepCopy()
+	out.BinSize = in.BinSize.DeepCopy()
+	if in.Integration != nil {
+		in, out := &in.Integration, &out.Integration
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Duration != nil {
+		in, out := &in.Duration, &out.Duration
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Gain != nil {
+		in, out := &in.Gain, &out.Gain
 		x := (*in).DeepCopy()
 		*out = &x
 	}
-	if in.ContainerPort != nil {
-		in, out := &in.ContainerPort, &out.ContainerPort
-		*out = new(v1.ContainerPort)
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Peaks != nil {
+		in, out := &in.Peaks, &out.Peaks
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Output != nil {
+		in, out := &in.Output, &out.Output
+		*out = new(RtlSdrSurveyOutput)
 		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RtlSdrReceiverSpec.
-func (in *RtlSdrReceiverSpec) DeepCopy() *RtlSdrReceiverSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RtlSdrSurveySpec.
+func (in *RtlSdrSurveySpec) DeepCopy() *RtlSdrSurveySpec {
 	if in == nil {
 		return nil
 	}
-	out := new(RtlSdrReceiverSpec)
+	out := new(RtlSdrSurveySpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RtlSdrReceiverStatus) DeepCopyInto(out *RtlSdrReceiverStatus) {
+func (in *RtlSdrSurveyStatus) DeepCopyInto(out *RtlSdrSurveyStatus) {
 	*out = *in
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
-		*out = make([]metav1.Condition, len(*in))
+		*out = make([]v1.Condition, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	if in.Pod != nil {
-		in, out := &in.Pod, &out.Pod
-		*out = new(v1.ObjectReference)
+	if in.Job != nil {
+		in, out := &in.Job, &out.Job
+		*out = new(corev1.ObjectReference)
 		**out = **in
 	}
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Peaks != nil {
+		in, out := &in.Peaks, &out.Peaks
+		*out = make([]RtlSdrSurveyPeak, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RtlSdrReceiverStatus.
-func (in *RtlSdrReceiverStatus) DeepCopy() *RtlSdrReceiverStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RtlSdrSurveyStatus.
+func (in *RtlSdrSurveyStatus) DeepCopy() *RtlSdrSurveyStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(RtlSdrReceiverStatus)
+	out := new(RtlSdrSurveyStatus)
 	in.DeepCopyInto(out)
 	return out
 }
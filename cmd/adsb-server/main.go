@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/exec"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/frelon/k8s-radio/pkg/adsb"
+)
+
+func main() {
+	var listenAddr string
+	tracker := &adsb.Tracker{}
+	flag.StringVar(&listenAddr, "listen", ":8080", "The address the aircraft and stats are served on.")
+	flag.StringVar(&tracker.Dir, "json-dir", "/run/adsb", "The directory the decoder writes aircraft.json to.")
+	flag.DurationVar(&tracker.Interval, "interval", adsb.DefaultInterval, "The time between reads of the aircraft.")
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		slog.Error("Usage: adsb-server [flags] -- decoder [args...]")
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := os.MkdirAll(tracker.Dir, 0o755); err != nil {
+		slog.Error("Failed to create the JSON directory", slog.Any("error", err))
+		os.Exit(1)
+	}
+
+	cmd := exec.CommandContext(ctx, flag.Arg(0), flag.Args()[1:]...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	server := &http.Server{
+		Addr:              listenAddr,
+		Handler:           tracker.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		slog.Info("Serving aircraft", slog.String("address", listenAddr))
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			slog.Error("Failed to serve aircraft", slog.Any("error", err))
+			stop()
+		}
+	}()
+
+	go func() {
+		if err := tracker.Run(ctx); err != nil && ctx.Err() == nil {
+			slog.Error("Failed reading aircraft", slog.Any("error", err))
+		}
+	}()
+
+	slog.Info("Starting decoder", slog.String("command", cmd.String()))
+	if err := cmd.Start(); err != nil {
+		slog.Error("Failed to start decoder", slog.Any("error", err))
+		os.Exit(1)
+	}
+
+	err := cmd.Wait()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_ = server.Shutdown(shutdownCtx)
+
+	if err != nil && ctx.Err() == nil {
+		slog.Error("Decoder exited", slog.Any("error", err))
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/exec"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/frelon/k8s-radio/pkg/audio"
+)
+
+func main() {
+	var listenAddr, path string
+	var sampleRate uint
+	flag.StringVar(&listenAddr, "listen", ":8000", "The address the audio endpoint binds to.")
+	flag.StringVar(&path, "path", "/audio.wav", "The HTTP path the audio is served on.")
+	flag.UintVar(&sampleRate, "sample-rate", 16000, "The sample rate of the 16-bit mono PCM produced by the command.")
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		slog.Error("Usage: audio-server [flags] -- command [args...]")
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	cmd := exec.CommandContext(ctx, flag.Arg(0), flag.Args()[1:]...)
+	cmd.Stderr = os.Stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		slog.Error("Failed to create pipe", slog.Any("error", err))
+		os.Exit(1)
+	}
+
+	broadcaster := audio.NewBroadcaster(audio.Format{
+		SampleRate:    uint32(sampleRate),
+		Channels:      1,
+		BitsPerSample: 16,
+	})
+
+	mux := http.NewServeMux()
+	mux.Handle(path, broadcaster)
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	server := &http.Server{
+		Addr:              listenAddr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		slog.Info("Serving audio", slog.String("address", listenAddr), slog.String("path", path))
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			slog.Error("Failed to serve audio", slog.Any("error", err))
+			stop()
+		}
+	}()
+
+	slog.Info("Starting command", slog.String("command", cmd.String()))
+	if err := cmd.Start(); err != nil {
+		slog.Error("Failed to start command", slog.Any("error", err))
+		os.Exit(1)
+	}
+
+	if err := broadcaster.Run(stdout); err != nil {
+		slog.Error("Failed reading audio", slog.Any("error", err))
+	}
+
+	err = cmd.Wait()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_ = server.Shutdown(shutdownCtx)
+
+	if err != nil && ctx.Err() == nil {
+		slog.Error("Command exited", slog.Any("error", err))
+		os.Exit(1)
+	}
+}
@@ -1,23 +1,63 @@
 package main
 
 import (
+	"context"
 	"flag"
+	"fmt"
+	"io"
+	"io/fs"
 	"log/slog"
 	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/kubevirt/device-plugin-manager/pkg/dpm"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	podresourcesv1 "k8s.io/kubelet/pkg/apis/podresources/v1"
 
 	rtlsdr "github.com/frelon/k8s-radio/device-plugin/rtl-sdr"
 )
 
 type RadioDeviceLister struct {
 	ResUpdateChan chan dpm.PluginNameList
-	Heartbeat     chan bool
+
+	// Simulate is the number of virtual devices to serve instead of
+	// discovering dongles.
+	Simulate int
+	// Backend is the host path of the fake rtl_tcp replacing rtl_tcp in
+	// containers allocated a virtual device.
+	Backend string
+	// PodResources is the pod resources API of the kubelet, which keeps a
+	// dongle from being allocated both as the resource of any dongle and
+	// as its own.
+	PodResources podresourcesv1.PodResourcesListerClient
+
+	mu         sync.Mutex
+	heartbeats []chan bool
 }
 
 func (l *RadioDeviceLister) GetResourceNamespace() string {
-	return "frelon.se"
+	return rtlsdr.ResourceNamespace
+}
+
+// Beat makes all plugins update their devices, skipping those still busy
+// with the previous beat.
+func (l *RadioDeviceLister) Beat() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, heartbeat := range l.heartbeats {
+		select {
+		case heartbeat <- true:
+		default:
+		}
+	}
 }
 
 func (l *RadioDeviceLister) Discover(pluginListCh chan dpm.PluginNameList) {
@@ -32,22 +72,184 @@ func (l *RadioDeviceLister) Discover(pluginListCh chan dpm.PluginNameList) {
 }
 
 func (l *RadioDeviceLister) NewPlugin(resourceLastName string) dpm.PluginInterface {
-	if resourceLastName == rtlsdr.ResourceName {
-		return rtlsdr.NewPlugin(l.Heartbeat, os.DirFS("/"))
+	serial, pinned := rtlsdr.ResourceSerial(resourceLastName)
+	if resourceLastName != rtlsdr.ResourceName && !pinned {
+		slog.Error("Unknown resource", "name", resourceLastName)
+		return nil
 	}
 
-	slog.Error("Unknown resource", "name", resourceLastName)
-	return nil
+	heartbeat := make(chan bool, 1)
+	l.mu.Lock()
+	l.heartbeats = append(l.heartbeats, heartbeat)
+	l.mu.Unlock()
+
+	var p *rtlsdr.Plugin
+	if l.Simulate > 0 {
+		p = rtlsdr.NewSimulatedPlugin(heartbeat, l.Simulate, l.Backend)
+	} else {
+		p = rtlsdr.NewPlugin(heartbeat, os.DirFS("/"))
+	}
+	if pinned {
+		p.WithSerial(serial)
+	}
+
+	return p.WithPodResources(l.PodResources)
+}
+
+// resourceNames returns the last names of the resources to serve: the one of
+// any dongle, and the own one of each dongle ever seen when the pod resources
+// API keeps them exclusive. Resources of unplugged dongles are kept, so
+// their allocations remain known to the kubelet.
+func (l *RadioDeviceLister) resourceNames(fsys fs.FS, previous dpm.PluginNameList) dpm.PluginNameList {
+	names := dpm.PluginNameList{rtlsdr.ResourceName}
+	if l.PodResources == nil {
+		return names
+	}
+
+	for _, name := range previous {
+		if !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+
+	devs, err := rtlsdr.ListUsbDevices(fsys)
+	if err != nil {
+		slog.Error("Failed listing devices", slog.Any("error", err))
+	}
+	for _, dev := range devs {
+		name, ok := rtlsdr.SerialResourceName(dev.Serial)
+		if !ok {
+			slog.Info("Not serving dongle as its own resource", "serial", dev.Serial)
+			continue
+		}
+		name = strings.TrimPrefix(name, rtlsdr.ResourceNamespace+"/")
+		if !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
+
+// installBackend copies the fake rtl_tcp binary at src to the host path dst,
+// so the kubelet can mount it into receiver containers.
+func installBackend(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("failed opening '%s': %w", src, err)
+	}
+	defer in.Close()
+
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return fmt.Errorf("failed creating '%s': %w", filepath.Dir(dst), err)
+	}
+
+	// Write to a temporary file and rename it, so a running receiver
+	// never sees a partially written binary.
+	tmp, err := os.CreateTemp(filepath.Dir(dst), ".fake-rtltcp-*")
+	if err != nil {
+		return fmt.Errorf("failed creating temporary file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, in); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed copying '%s': %w", src, err)
+	}
+
+	if err := tmp.Chmod(0o755); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed setting permissions: %w", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed closing '%s': %w", tmp.Name(), err)
+	}
+
+	return os.Rename(tmp.Name(), dst)
+}
+
+// publishDevices annotates the node with its dongles and the pods they are
+// allocated to every interval, for clients listing the dongles of the
+// cluster.
+func publishDevices(ctx context.Context, fsys fs.FS, nodeName string, podResources podresourcesv1.PodResourcesListerClient, interval time.Duration) error {
+	config, err := rest.InClusterConfig()
+	if err != nil {
+		return fmt.Errorf("failed loading the in-cluster config: %w", err)
+	}
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return fmt.Errorf("failed creating the client: %w", err)
+	}
+
+	inv := rtlsdr.Inventory{FS: fsys, PodResources: podResources}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		devices, err := inv.List(ctx)
+		if err == nil {
+			err = rtlsdr.PublishDevices(ctx, clientset.CoreV1().Nodes(), nodeName, devices)
+		}
+		if err != nil {
+			slog.Error("Failed publishing devices", slog.Any("error", err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
 }
 
 func main() {
+	var simulate int
+	var backend, backendSource, nodeName, podResourcesSocket string
+	var publishInterval time.Duration
+	flag.IntVar(&simulate, "simulate", 0, "The number of virtual devices to serve instead of discovering dongles.")
+	flag.StringVar(&backend, "simulate-backend", "/var/lib/k8s-radio/fake-rtltcp",
+		"The host path the fake rtl_tcp is installed to and mounted from for virtual devices, empty to keep rtl_tcp.")
+	flag.StringVar(&backendSource, "simulate-backend-source", "/fake-rtltcp", "The fake rtl_tcp binary to install.")
+	flag.StringVar(&nodeName, "node-name", os.Getenv("NODE_NAME"), "The node to annotate with its dongles, empty to not publish them.")
+	flag.StringVar(&podResourcesSocket, "pod-resources-socket", "/var/lib/kubelet/pod-resources/kubelet.sock",
+		"The socket of the kubelet pod resources API, telling which pod was allocated which dongle, empty to neither publish allocations nor serve dongles as their own resource.")
+	flag.DurationVar(&publishInterval, "publish-interval", 30*time.Second, "How often the dongles of the node are published.")
 	flag.Parse()
 
 	slog.Info("Starting radio device plugin")
 
 	l := RadioDeviceLister{
 		ResUpdateChan: make(chan dpm.PluginNameList),
-		Heartbeat:     make(chan bool),
+		Simulate:      simulate,
+	}
+
+	var fsys fs.FS = os.DirFS("/")
+	if simulate > 0 {
+		fsys = rtlsdr.SimulatedDevices(simulate)
+	}
+
+	if simulate > 0 {
+		slog.Info("Simulating devices", slog.Int("devices", simulate), slog.String("backend", backend))
+
+		if backend != "" {
+			if err := installBackend(backendSource, backend); err != nil {
+				slog.Error("Failed installing simulation backend", slog.Any("error", err))
+				os.Exit(1)
+			}
+
+			l.Backend = backend
+		}
+	}
+
+	if podResourcesSocket != "" {
+		conn, err := grpc.NewClient("unix://"+podResourcesSocket, grpc.WithTransportCredentials(insecure.NewCredentials()))
+		if err != nil {
+			slog.Error("Failed connecting to the pod resources API", "socket", podResourcesSocket, slog.Any("error", err))
+			os.Exit(1)
+		}
+		defer conn.Close()
+		l.PodResources = podresourcesv1.NewPodResourcesListerClient(conn)
 	}
 
 	pulse := 2
@@ -56,14 +258,30 @@ func main() {
 
 		for {
 			time.Sleep(time.Second * time.Duration(pulse))
-			l.Heartbeat <- true
+			l.Beat()
 		}
 	}()
 
+	if nodeName != "" {
+		go func() {
+			if err := publishDevices(context.Background(), fsys, nodeName, l.PodResources, publishInterval); err != nil {
+				slog.Error("Not publishing devices", slog.Any("error", err))
+			}
+		}()
+	}
+
 	manager := dpm.NewManager(&l)
 
+	// Dongles plugged in later are served as their own resource as well.
 	go func() {
-		l.ResUpdateChan <- []string{rtlsdr.ResourceName}
+		var names dpm.PluginNameList
+		for {
+			if next := l.resourceNames(fsys, names); !slices.Equal(next, names) {
+				names = next
+				l.ResUpdateChan <- names
+			}
+			time.Sleep(publishInterval)
+		}
 	}()
 
 	manager.Run()
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log/slog"
+	"net"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/frelon/k8s-radio/pkg/fakesdr"
+)
+
+func main() {
+	// The address, port, frequency, sample rate and gain flags match rtl_tcp,
+	// so the fake can be swapped in without changing the arguments.
+	var address, port, frequency, sampleRate, signalFrequency, signalType, replay string
+	var gain float64
+	var noise float64
+	flag.StringVar(&address, "a", "127.0.0.1", "The address to listen on.")
+	flag.StringVar(&port, "p", "1234", "The port to listen on.")
+	flag.StringVar(&frequency, "f", "100M", "The initial frequency to tune to.")
+	flag.StringVar(&sampleRate, "s", "2048k", "The initial sample rate.")
+	flag.Float64Var(&gain, "g", 0, "The initial gain in dB, 0 for automatic gain.")
+	flag.StringVar(&signalType, "signal", "fm", "The synthetic signal to stream: fm, tone or noise.")
+	flag.StringVar(&signalFrequency, "signal-frequency", "", "The frequency of the synthetic signal, defaults to the initial frequency.")
+	flag.Float64Var(&noise, "noise", 0.02, "The standard deviation of the noise relative to full scale.")
+	flag.StringVar(&replay, "replay", "", "A file of unsigned 8-bit I/Q samples to replay instead of a synthetic signal.")
+	flag.Parse()
+
+	tuning := fakesdr.Tuning{
+		Frequency:  uint32(mustParseHz(frequency)),
+		SampleRate: uint32(mustParseHz(sampleRate)),
+	}
+	if gain != 0 {
+		tuning.ManualGain = true
+		tuning.Gain = int32(gain * 10)
+	}
+
+	var source fakesdr.Source
+	if replay != "" {
+		f, err := os.Open(replay)
+		if err != nil {
+			slog.Error("Failed to open replay file", slog.Any("error", err))
+			os.Exit(1)
+		}
+		defer f.Close()
+
+		source = fakesdr.NewReplay(f)
+	} else {
+		carrierFrequency := float64(tuning.Frequency)
+		if signalFrequency != "" {
+			carrierFrequency = float64(mustParseHz(signalFrequency))
+		}
+
+		switch signalType {
+		case "fm":
+			source = fakesdr.NewGenerator(noise, &fakesdr.Carrier{
+				Frequency:      carrierFrequency,
+				Amplitude:      0.5,
+				Modulation:     fakesdr.FM,
+				AudioFrequency: 1000,
+				Deviation:      75000,
+			})
+		case "tone":
+			source = fakesdr.NewGenerator(noise, &fakesdr.Carrier{
+				Frequency: carrierFrequency,
+				Amplitude: 0.5,
+			})
+		case "noise":
+			source = fakesdr.NewGenerator(noise)
+		default:
+			slog.Error("Unknown signal", slog.String("signal", signalType))
+			os.Exit(2)
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	l, err := net.Listen("tcp", net.JoinHostPort(address, port))
+	if err != nil {
+		slog.Error("Failed to listen", slog.Any("error", err))
+		os.Exit(1)
+	}
+
+	slog.Info("Listening", slog.String("address", l.Addr().String()), slog.Any("tuning", tuning))
+
+	if err := fakesdr.NewServer(source, tuning).Serve(ctx, l); err != nil {
+		slog.Error("Failed to serve", slog.Any("error", err))
+		os.Exit(1)
+	}
+}
+
+// mustParseHz parses a frequency like rtl_tcp does, accepting k, M and G
+// suffixes.
+func mustParseHz(s string) int64 {
+	if v, err := strconv.ParseFloat(s, 64); err == nil {
+		return int64(v)
+	}
+
+	q, err := resource.ParseQuantity(s)
+	if err != nil {
+		slog.Error("Invalid frequency", slog.String("value", s), slog.Any("error", err))
+		os.Exit(2)
+	}
+
+	return q.Value()
+}
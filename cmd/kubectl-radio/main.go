@@ -0,0 +1,252 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	radiov1beta1 "github.com/frelon/k8s-radio/api/v1beta1"
+	"github.com/frelon/k8s-radio/pkg/radioctl"
+)
+
+const usage = `kubectl radio operates the RTL-SDR receivers of a cluster.
+
+Usage:
+  kubectl radio list [-A]                      List the receivers with their node, dongle, frequency and state
+  kubectl radio devices                        List the dongles of each node and the pods using them
+  kubectl radio tune <receiver> <frequency>    Tune a receiver, e.g. to 145.8M
+  kubectl radio gain <receiver> <dB|auto>      Set the tuner gain of a receiver
+  kubectl radio listen <receiver>              Play the audio of an FM receiver with a local player
+  kubectl radio record <receiver> <name>       Record the I/Q stream of a receiver to a SigMF dataset
+
+Run kubectl radio <command> -h for the flags of a command.
+`
+
+// command parses the flags of a command and connects to the cluster.
+type command struct {
+	flags *flag.FlagSet
+
+	kubeconfig, context, namespace string
+}
+
+func newCommand(name string) *command {
+	c := &command{flags: flag.NewFlagSet(name, flag.ExitOnError)}
+	c.flags.StringVar(&c.kubeconfig, "kubeconfig", "", "The kubeconfig file to use.")
+	c.flags.StringVar(&c.context, "context", "", "The kubeconfig context to use.")
+	c.flags.StringVar(&c.namespace, "namespace", "", "The namespace of the receivers.")
+	c.flags.StringVar(&c.namespace, "n", "", "Shorthand for --namespace.")
+
+	return c
+}
+
+// parse parses the flags of the command and requires n arguments.
+func (c *command) parse(args []string, n int, synopsis string) []string {
+	c.flags.Usage = func() {
+		fmt.Fprintf(c.flags.Output(), "Usage: kubectl radio %s %s\n", c.flags.Name(), synopsis)
+		c.flags.PrintDefaults()
+	}
+	_ = c.flags.Parse(args)
+	if c.flags.NArg() != n {
+		c.flags.Usage()
+		os.Exit(2)
+	}
+
+	return c.flags.Args()
+}
+
+// radio connects to the cluster the flags select.
+func (c *command) radio() (*radioctl.Radio, error) {
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	rules.ExplicitPath = c.kubeconfig
+	overrides := &clientcmd.ConfigOverrides{CurrentContext: c.context}
+	overrides.Context.Namespace = c.namespace
+	clientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, overrides)
+
+	config, err := clientConfig.ClientConfig()
+	if err != nil {
+		return nil, err
+	}
+	namespace, _, err := clientConfig.Namespace()
+	if err != nil {
+		return nil, err
+	}
+
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		return nil, err
+	}
+	if err := radiov1beta1.AddToScheme(scheme); err != nil {
+		return nil, err
+	}
+	k8sClient, err := client.New(config, client.Options{Scheme: scheme})
+	if err != nil {
+		return nil, err
+	}
+
+	return &radioctl.Radio{
+		Client:    k8sClient,
+		Namespace: namespace,
+		Forwarder: radioctl.PortForwarder{Config: config},
+		Out:       os.Stdout,
+	}, nil
+}
+
+// run runs the command named by the first argument.
+func run(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	c := newCommand(args[0])
+	switch args[0] {
+	case "list":
+		var all bool
+		c.flags.BoolVar(&all, "all-namespaces", false, "List the receivers of all namespaces.")
+		c.flags.BoolVar(&all, "A", false, "Shorthand for --all-namespaces.")
+		c.parse(args[1:], 0, "[-A]")
+		radio, err := c.radio()
+		if err != nil {
+			return err
+		}
+		return radio.List(ctx, all)
+
+	case "devices":
+		c.parse(args[1:], 0, "")
+		radio, err := c.radio()
+		if err != nil {
+			return err
+		}
+		return radio.Devices(ctx)
+
+	case "tune":
+		args := c.parse(args[1:], 2, "<receiver> <frequency>")
+		frequency, err := resource.ParseQuantity(args[1])
+		if err != nil {
+			return fmt.Errorf("invalid frequency %q: %w", args[1], err)
+		}
+		radio, err := c.radio()
+		if err != nil {
+			return err
+		}
+		return radio.Tune(ctx, args[0], frequency)
+
+	case "gain":
+		args := c.parse(args[1:], 2, "<receiver> <dB|auto>")
+		var gain *resource.Quantity
+		if args[1] != "auto" {
+			q, err := resource.ParseQuantity(args[1])
+			if err != nil {
+				return fmt.Errorf("invalid gain %q: %w", args[1], err)
+			}
+			gain = &q
+		}
+		radio, err := c.radio()
+		if err != nil {
+			return err
+		}
+		return radio.Gain(ctx, args[0], gain)
+
+	case "listen":
+		var player, output string
+		c.flags.StringVar(&player, "player", "ffplay -nodisp -autoexit -loglevel error -", "The command playing the WAV audio read from its stdin.")
+		c.flags.StringVar(&output, "output", "", "Write the audio to this file instead of playing it, - for stdout.")
+		c.flags.StringVar(&output, "o", "", "Shorthand for --output.")
+		args := c.parse(args[1:], 1, "<receiver>")
+		radio, err := c.radio()
+		if err != nil {
+			return err
+		}
+		return listen(ctx, radio, args[0], player, output)
+
+	case "record":
+		var duration time.Duration
+		c.flags.DurationVar(&duration, "duration", 0, "Stop recording after this long, 0 to record until interrupted.")
+		args := c.parse(args[1:], 2, "<receiver> <name>")
+		radio, err := c.radio()
+		if err != nil {
+			return err
+		}
+		samples, err := radio.Record(ctx, args[0], args[1], duration)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Recorded %d samples to %s.sigmf-data\n", samples, args[1])
+		return nil
+
+	case "help", "-h", "--help":
+		fmt.Fprint(os.Stdout, usage)
+		return nil
+	}
+
+	return fmt.Errorf("unknown command %q, see kubectl radio help", args[0])
+}
+
+// listen plays the audio of the receiver with player, or writes it to output.
+func listen(ctx context.Context, radio *radioctl.Radio, name, player, output string) error {
+	switch output {
+	case "":
+	case "-":
+		return radio.Listen(ctx, name, os.Stdout)
+	default:
+		f, err := os.Create(output)
+		if err != nil {
+			return err
+		}
+		if err := radio.Listen(ctx, name, f); err != nil {
+			_ = f.Close()
+			return err
+		}
+		return f.Close()
+	}
+
+	fields := strings.Fields(player)
+	if len(fields) == 0 {
+		return errors.New("no player given")
+	}
+	cmd := exec.CommandContext(ctx, fields[0], fields[1:]...)
+	cmd.Stdout, cmd.Stderr = os.Stderr, os.Stderr
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed starting the player: %w", err)
+	}
+
+	err = radio.Listen(ctx, name, stdin)
+	_ = stdin.Close()
+	if waitErr := cmd.Wait(); ctx.Err() == nil && err == nil {
+		err = waitErr
+	}
+	// The player closing its stdin ends the stream.
+	if errors.Is(err, io.ErrClosedPipe) || errors.Is(err, syscall.EPIPE) {
+		return nil
+	}
+
+	return err
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := run(ctx, os.Args[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
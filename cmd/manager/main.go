@@ -5,6 +5,7 @@ import (
 	"crypto/tls"
 	"flag"
 	"os"
+	"time"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
@@ -46,6 +47,7 @@ func main() {
 	var probeAddr string
 	var secureMetrics bool
 	var enableHTTP2 bool
+	var deletionTimeout time.Duration
 	var tlsOpts []func(*tls.Config)
 	flag.StringVar(&metricsAddr, "metrics-bind-address", "0", "The address the metrics endpoint binds to. "+
 		"Use :8443 for HTTPS or :8080 for HTTP, or leave as 0 to disable the metrics service.")
@@ -64,6 +66,8 @@ func main() {
 	flag.StringVar(&metricsCertKey, "metrics-cert-key", "tls.key", "The name of the metrics server key file.")
 	flag.BoolVar(&enableHTTP2, "enable-http2", false,
 		"If set, HTTP/2 will be enabled for the metrics and webhook servers")
+	flag.DurationVar(&deletionTimeout, "receiver-deletion-timeout", controller.DefaultDeletionTimeout,
+		"How long to wait for a deleted receiver to shut down before its workload is force deleted.")
 	opts := zap.Options{
 		Development: true,
 	}
@@ -164,12 +168,47 @@ func main() {
 	}
 
 	if err := (&controller.RtlSdrReceiverReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
+		Client:              mgr.GetClient(),
+		Scheme:              mgr.GetScheme(),
+		Image:               envOrDefault("RTLSDR_IMG", controller.RtlSdrDefaultImage),
+		FMImage:             envOrDefault("FM_IMG", controller.FMDefaultImage),
+		ADSBImage:           envOrDefault("ADSB_IMG", controller.ADSBDefaultImage),
+		DecoderImage:        envOrDefault("DECODER_IMG", controller.DecoderDefaultImage),
+		MQTTImage:           envOrDefault("MQTT_IMG", controller.MQTTDefaultImage),
+		ExporterImage:       envOrDefault("EXPORTER_IMG", controller.ExporterDefaultImage),
+		AccessImage:         envOrDefault("ACCESS_IMG", controller.AccessDefaultImage),
+		SimulatorImage:      envOrDefault("SIM_IMG", controller.SimulatorDefaultImage),
+		MuxImage:            envOrDefault("MUX_IMG", controller.MuxDefaultImage),
+		DeletionTimeout:     deletionTimeout,
+		ControllerNamespace: os.Getenv("POD_NAMESPACE"),
+		Recorder:            mgr.GetEventRecorder("rtlsdrreceiver-controller"),
 	}).SetupWithManager(context.Background(), mgr); err != nil {
 		setupLog.Error(err, "Failed to create controller", "controller", "rtlsdrreceiver")
 		os.Exit(1)
 	}
+	if err := (&controller.RtlSdrChannelReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "Failed to create controller", "controller", "rtlsdrchannel")
+		os.Exit(1)
+	}
+	if err := (&controller.RtlSdrSurveyReconciler{
+		Client:      mgr.GetClient(),
+		Scheme:      mgr.GetScheme(),
+		Image:       envOrDefault("RTLSDR_IMG", controller.RtlSdrDefaultImage),
+		SurveyImage: envOrDefault("SURVEY_IMG", controller.SurveyDefaultImage),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "Failed to create controller", "controller", "rtlsdrsurvey")
+		os.Exit(1)
+	}
+	if err := (&controller.RtlSdrReceiverPoolReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "Failed to create controller", "controller", "rtlsdrreceiverpool")
+		os.Exit(1)
+	}
 	// +kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
@@ -187,3 +226,13 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// envOrDefault returns the value of the environment variable key, or def if
+// it is not set.
+func envOrDefault(key, def string) string {
+	if v, ok := os.LookupEnv(key); ok && v != "" {
+		return v
+	}
+
+	return def
+}
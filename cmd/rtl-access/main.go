@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"flag"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	"github.com/frelon/k8s-radio/pkg/access"
+)
+
+func main() {
+	var listenAddr, tokensDir, audiences string
+	var tokenReview bool
+	p := &access.Proxy{}
+	certs := &access.Certificates{}
+	flag.StringVar(&listenAddr, "listen", ":1234", "The address clients connect to over TLS.")
+	flag.StringVar(&p.Upstream, "upstream", "127.0.0.1:9183", "The address of the guarded stream.")
+	flag.BoolVar(&p.HTTP, "http", false, "Proxy HTTP requests instead of a raw TCP stream.")
+	flag.StringVar(&certs.CertFile, "tls-cert", "/tls/tls.crt", "The certificate served to clients.")
+	flag.StringVar(&certs.KeyFile, "tls-key", "/tls/tls.key", "The private key of the certificate.")
+	flag.StringVar(&certs.ClientCAFile, "client-ca", "", "The CA client certificates are verified with, empty to authenticate clients by tokens.")
+	flag.StringVar(&tokensDir, "tokens", "", "The directory holding the accepted bearer tokens, one file per user.")
+	flag.BoolVar(&tokenReview, "token-review", false, "Authenticate bearer tokens with TokenReviews.")
+	flag.StringVar(&audiences, "audiences", "", "The comma separated audiences reviewed tokens must be issued for, empty for the audiences of the API server.")
+	flag.Parse()
+
+	switch {
+	case tokensDir != "":
+		p.Authenticator = access.Tokens{Dir: tokensDir}
+	case tokenReview:
+		config, err := rest.InClusterConfig()
+		if err != nil {
+			slog.Error("Failed to load the in-cluster config", slog.Any("error", err))
+			os.Exit(1)
+		}
+		clientset, err := kubernetes.NewForConfig(config)
+		if err != nil {
+			slog.Error("Failed to create the client", slog.Any("error", err))
+			os.Exit(1)
+		}
+		reviewer := access.TokenReviewer{Reviews: clientset.AuthenticationV1().TokenReviews()}
+		if audiences != "" {
+			reviewer.Audiences = strings.Split(audiences, ",")
+		}
+		p.Authenticator = reviewer
+	case certs.ClientCAFile == "":
+		slog.Error("Either --client-ca, --tokens or --token-review is required to authenticate clients")
+		os.Exit(2)
+	}
+
+	config, err := certs.TLSConfig()
+	if err != nil {
+		slog.Error("Failed to load the certificates", slog.Any("error", err))
+		os.Exit(1)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	l, err := tls.Listen("tcp", listenAddr, config)
+	if err != nil {
+		slog.Error("Failed to listen", slog.String("address", listenAddr), slog.Any("error", err))
+		os.Exit(1)
+	}
+
+	slog.Info("Guarding stream", slog.String("address", listenAddr), slog.String("upstream", p.Upstream))
+	if err := p.Serve(ctx, l); err != nil {
+		slog.Error("Failed to serve", slog.Any("error", err))
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/exec"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/frelon/k8s-radio/pkg/events"
+)
+
+func main() {
+	var listenAddr, sourceName, sinkURL, topic, receiver string
+	recent := &events.RecentSink{}
+	flag.StringVar(&listenAddr, "listen", ":8080", "The address the recent events are served on.")
+	flag.IntVar(&recent.Max, "recent", events.DefaultRecent, "The number of recent events served.")
+	flag.StringVar(&sourceName, "source", "", "The decoder writing the messages: ais or ism.")
+	flag.StringVar(&sinkURL, "sink", events.StdoutSink, "Where to publish the events: stdout, a webhook URL or an MQTT broker URL.")
+	flag.StringVar(&topic, "topic", "", "The MQTT topic to publish the events to.")
+	flag.StringVar(&receiver, "receiver", "", "The namespace and name of the receiver recorded in the events.")
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		slog.Error("Usage: rtl-events [flags] -- decoder [args...]")
+		os.Exit(2)
+	}
+
+	source, err := events.ParseSource(sourceName)
+	if err != nil {
+		slog.Error("Invalid source", slog.Any("error", err))
+		os.Exit(2)
+	}
+
+	hostname, _ := os.Hostname()
+	published, err := events.NewSink(sinkURL, topic, "rtl-events-"+hostname)
+	if err != nil {
+		slog.Error("Invalid sink", slog.Any("error", err))
+		os.Exit(2)
+	}
+	sink := events.MultiSink{recent, published}
+	defer func() { _ = sink.Close() }()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	mux := http.NewServeMux()
+	mux.Handle("GET /events", recent)
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	server := &http.Server{Addr: listenAddr, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+
+	go func() {
+		slog.Info("Serving events", slog.String("address", listenAddr))
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			slog.Error("Failed to serve events", slog.Any("error", err))
+			stop()
+		}
+	}()
+
+	cmd := exec.CommandContext(ctx, flag.Arg(0), flag.Args()[1:]...)
+	// Decoders log to stderr and write their messages to stdout.
+	cmd.Stderr = os.Stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		slog.Error("Failed to create pipe", slog.Any("error", err))
+		os.Exit(1)
+	}
+
+	slog.Info("Starting decoder", slog.String("command", cmd.String()), slog.String("source", string(source)))
+	if err := cmd.Start(); err != nil {
+		slog.Error("Failed to start decoder", slog.Any("error", err))
+		os.Exit(1)
+	}
+
+	if err := events.Publish(ctx, stdout, source, receiver, sink); err != nil && ctx.Err() == nil {
+		slog.Error("Failed reading decoder output", slog.Any("error", err))
+	}
+
+	err = cmd.Wait()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_ = server.Shutdown(shutdownCtx)
+
+	if err != nil && ctx.Err() == nil {
+		slog.Error("Decoder exited", slog.Any("error", err))
+		_ = sink.Close()
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/frelon/k8s-radio/pkg/exporter"
+	"github.com/frelon/k8s-radio/pkg/rtltcp"
+)
+
+const (
+	// dialInterval is the time between attempts to connect to the tap.
+	dialInterval = time.Second
+	// chunkSize is the number of bytes of I/Q samples read at a time.
+	chunkSize = 64 * 1024
+	// chunkDepth is the number of chunks buffered between the tap and the
+	// exporter.
+	chunkDepth = 16
+)
+
+func main() {
+	var listenAddr, upstreamAddr, tuningURL string
+	var frequency, sampleRate uint
+	var pollInterval time.Duration
+	e := &exporter.Exporter{}
+	flag.StringVar(&listenAddr, "listen", ":9190", "The address the metrics are served on.")
+	flag.StringVar(&upstreamAddr, "upstream", "127.0.0.1:1240", "The address of the rtl_tcp stream to measure.")
+	flag.StringVar(&tuningURL, "tuning-url", "", "The URL the tuning of the receiver is polled from, empty to use --frequency and --sample-rate.")
+	flag.DurationVar(&pollInterval, "poll-interval", 5*time.Second, "The time between polls of the tuning.")
+	flag.UintVar(&frequency, "frequency", 0, "The frequency of the receiver in Hz, until the tuning is polled.")
+	flag.UintVar(&sampleRate, "sample-rate", 2_048_000, "The sample rate of the receiver in Hz, until the tuning is polled.")
+	flag.StringVar(&e.Receiver, "receiver", "", "The namespace and name of the receiver.")
+	flag.StringVar(&e.Serial, "serial", os.Getenv(exporter.SerialEnv), "The serial number of the dongle, defaults to $RTLSDR_SERIAL of this or another process of the pod.")
+	flag.DurationVar(&e.Interval, "interval", exporter.DefaultInterval, "The time between measurements.")
+	flag.IntVar(&e.FFTSize, "fft-size", exporter.DefaultFFTSize, "The number of bins the noise floor is estimated from.")
+	flag.Parse()
+
+	if e.FFTSize <= 0 || e.FFTSize&(e.FFTSize-1) != 0 {
+		slog.Error("The FFT size must be a power of two", slog.Int("fft-size", e.FFTSize))
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var tuning atomic.Pointer[rtltcp.Tuning]
+	tuning.Store(&rtltcp.Tuning{Frequency: uint32(frequency), SampleRate: uint32(sampleRate)})
+	if tuningURL != "" {
+		go pollTuning(ctx, tuningURL, pollInterval, &tuning)
+	}
+
+	c, err := dial(ctx, upstreamAddr)
+	if err != nil {
+		return
+	}
+
+	// rtl_tcp is up once the stream is, so its environment can be searched
+	// for the serial before the metrics are served.
+	if e.Serial == "" {
+		e.Serial = exporter.FindSerial(os.DirFS("/proc"))
+	}
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(e)
+
+	mux := http.NewServeMux()
+	mux.Handle("GET /metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	server := &http.Server{Addr: listenAddr, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+
+	go func() {
+		slog.Info("Serving metrics", slog.String("address", listenAddr))
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			slog.Error("Failed to serve metrics", slog.Any("error", err))
+			stop()
+		}
+	}()
+
+	// Measure the stream, reconnecting whenever it ends. The samples missed
+	// while reconnecting are counted as dropped.
+	for {
+		slog.Info("Measuring", slog.String("upstream", upstreamAddr), slog.String("receiver", e.Receiver))
+		err = e.Run(ctx, c.Stream(ctx, chunkSize, chunkDepth), func() rtltcp.Tuning { return *tuning.Load() })
+		if ctx.Err() == nil {
+			slog.Error("Lost the stream", slog.Any("error", errors.Join(err, c.Err())))
+		}
+		_ = c.Close()
+
+		if c, err = dial(ctx, upstreamAddr); err != nil {
+			break
+		}
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_ = server.Shutdown(shutdownCtx)
+}
+
+// dial connects to the tap, retrying until it is up or ctx is done.
+func dial(ctx context.Context, addr string) (*rtltcp.Client, error) {
+	for {
+		c, err := rtltcp.Dial(ctx, addr)
+		if err == nil {
+			return c, nil
+		}
+
+		slog.Info("Waiting for the stream", slog.String("upstream", addr), slog.Any("error", err))
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(dialInterval):
+		}
+	}
+}
+
+// pollTuning stores the tuning served as JSON at url every interval until
+// ctx is done.
+func pollTuning(ctx context.Context, url string, interval time.Duration, tuning *atomic.Pointer[rtltcp.Tuning]) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if t, err := fetchTuning(ctx, url); err != nil {
+			slog.Info("Failed polling the tuning", slog.String("url", url), slog.Any("error", err))
+		} else if t.SampleRate > 0 {
+			tuning.Store(&t)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func fetchTuning(ctx context.Context, url string) (rtltcp.Tuning, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return rtltcp.Tuning{}, err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return rtltcp.Tuning{}, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return rtltcp.Tuning{}, errors.New(resp.Status)
+	}
+
+	var t rtltcp.Tuning
+	err = json.NewDecoder(resp.Body).Decode(&t)
+
+	return t, err
+}
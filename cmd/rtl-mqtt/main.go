@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/frelon/k8s-radio/pkg/mqttpub"
+)
+
+// polls is the list of topic=url flags.
+type polls map[string]string
+
+func (p polls) String() string {
+	var s []string
+	for topic, u := range p {
+		s = append(s, topic+"="+u)
+	}
+
+	return strings.Join(s, ",")
+}
+
+func (p polls) Set(v string) error {
+	topic, u, ok := strings.Cut(v, "=")
+	if !ok || topic == "" || u == "" {
+		return fmt.Errorf("expected topic=url, got %q", v)
+	}
+	p[topic] = u
+
+	return nil
+}
+
+func main() {
+	var listenAddr, broker, clientID string
+	var qos uint
+	var pollInterval time.Duration
+	poll := polls{}
+	relay := &mqttpub.Relay{}
+	flag.StringVar(&listenAddr, "listen", "127.0.0.1:9181", "The address containers of the pod post JSON to publish on.")
+	flag.StringVar(&broker, "broker", "", "The URL of the MQTT broker.")
+	flag.StringVar(&clientID, "client-id", "", "The MQTT client id, defaults to rtl-mqtt-<hostname>.")
+	flag.UintVar(&qos, "qos", 1, "The QoS the messages are published with.")
+	flag.StringVar(&relay.Prefix, "topic", "", "The topic the topics of the receiver are below.")
+	flag.StringVar(&relay.Status.Receiver, "receiver", "", "The namespace and name of the receiver.")
+	flag.StringVar(&relay.Status.Mode, "mode", "", "The mode of the receiver.")
+	flag.Int64Var(&relay.Status.Frequency, "frequency", 0, "The frequency of the receiver in Hz.")
+	flag.DurationVar(&relay.HeartbeatInterval, "heartbeat-interval", mqttpub.DefaultHeartbeatInterval, "The time between heartbeats.")
+	flag.Var(poll, "poll", "A topic=url to poll JSON from and publish, may be repeated.")
+	flag.DurationVar(&pollInterval, "poll-interval", 5*time.Second, "The time between polls.")
+	flag.Parse()
+
+	u, err := url.Parse(broker)
+	if err != nil || broker == "" || relay.Prefix == "" {
+		slog.Error("Usage: rtl-mqtt --broker URL --topic PREFIX [flags]", slog.Any("error", err))
+		os.Exit(2)
+	}
+
+	if clientID == "" {
+		hostname, _ := os.Hostname()
+		clientID = "rtl-mqtt-" + hostname
+	}
+
+	relay.Publisher, err = mqttpub.New(mqttpub.Config{
+		Broker:   u,
+		ClientID: clientID,
+		Username: os.Getenv("MQTT_USERNAME"),
+		Password: os.Getenv("MQTT_PASSWORD"),
+		QoS:      byte(qos),
+		Will:     relay.Will(),
+	})
+	if err != nil {
+		slog.Error("Invalid broker", slog.Any("error", err))
+		os.Exit(2)
+	}
+	defer func() { _ = relay.Publisher.Close() }()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	slog.Info("Publishing", slog.String("broker", u.Redacted()), slog.String("topic", relay.Prefix))
+
+	var wg sync.WaitGroup
+	wg.Go(func() { relay.Heartbeat(ctx) })
+	for topic, u := range poll {
+		wg.Go(func() { relay.Poll(ctx, topic, u, pollInterval) })
+	}
+
+	server := &http.Server{Addr: listenAddr, Handler: relay.Handler(), ReadHeaderTimeout: 10 * time.Second}
+	wg.Go(func() {
+		<-ctx.Done()
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	})
+
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		slog.Error("Failed to serve", slog.Any("error", err))
+		stop()
+	}
+
+	wg.Wait()
+}
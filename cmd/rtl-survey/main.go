@@ -0,0 +1,168 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1ac "k8s.io/client-go/applyconfigurations/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	"github.com/frelon/k8s-radio/pkg/survey"
+)
+
+const (
+	// CSVFile, HeatmapFile and SummaryFile are the names of the results in
+	// the output directory and ConfigMap.
+	CSVFile     = "survey.csv"
+	HeatmapFile = "heatmap.png"
+	SummaryFile = "summary.json"
+
+	// maxConfigMapSize is the size limit of a ConfigMap, less some room for
+	// its metadata.
+	maxConfigMapSize = 1000 * 1024
+
+	// fieldManager is the owner of the results written to the ConfigMap.
+	fieldManager = "rtl-survey"
+)
+
+func main() {
+	var input, outputDir, configMap, namespace, terminationLog string
+	var peaks int
+	var threshold float64
+	flag.StringVar(&input, "input", "/survey/survey.csv", "The CSV written by rtl_power.")
+	flag.StringVar(&outputDir, "output-dir", "", "The directory to write the results to, empty to not write files.")
+	flag.StringVar(&configMap, "configmap", "", "The ConfigMap to write the results to, empty to not write one.")
+	flag.StringVar(&namespace, "namespace", os.Getenv("POD_NAMESPACE"), "The namespace of the ConfigMap.")
+	flag.IntVar(&peaks, "peaks", 10, "The number of peaks to summarise.")
+	flag.Float64Var(&threshold, "peak-threshold", 10, "How many dB above the median power a peak must stand.")
+	flag.StringVar(&terminationLog, "termination-log", "/dev/termination-log", "The file the summary is written to, empty to not write it.")
+	flag.Parse()
+
+	results, summary, err := process(input, peaks, threshold)
+	if err != nil {
+		slog.Error("Failed processing the survey", slog.Any("error", err))
+		os.Exit(1)
+	}
+
+	slog.Info("Processed survey", slog.Int("sweeps", summary.Sweeps), slog.Int("peaks", len(summary.Peaks)))
+
+	if outputDir != "" {
+		if err := writeFiles(outputDir, results); err != nil {
+			slog.Error("Failed writing the results", slog.Any("error", err))
+			os.Exit(1)
+		}
+	}
+
+	if configMap != "" {
+		if err := writeConfigMap(context.Background(), namespace, configMap, results); err != nil {
+			slog.Error("Failed writing the results to the ConfigMap", slog.Any("error", err))
+			os.Exit(1)
+		}
+	}
+
+	// The controller reads the summary from the termination message.
+	if terminationLog != "" {
+		if err := os.WriteFile(terminationLog, results[SummaryFile], 0o644); err != nil {
+			slog.Error("Failed writing the termination message", slog.Any("error", err))
+			os.Exit(1)
+		}
+	}
+}
+
+// process reads the CSV and returns the contents of the result files by name,
+// and the summary.
+func process(input string, peaks int, threshold float64) (map[string][]byte, *survey.Summary, error) {
+	csv, err := os.ReadFile(input)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rows, err := survey.ReadCSV(bytes.NewReader(csv))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sweeps := survey.Sweeps(rows)
+	if len(sweeps) == 0 {
+		return nil, nil, errors.New("rtl_power measured nothing")
+	}
+
+	summary := &survey.Summary{
+		Sweeps: len(sweeps),
+		Peaks:  survey.Peaks(survey.MaxHold(sweeps), peaks, threshold),
+	}
+	encoded, err := json.Marshal(summary)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var heatmap bytes.Buffer
+	if err := survey.WriteHeatmap(&heatmap, sweeps); err != nil {
+		return nil, nil, err
+	}
+
+	return map[string][]byte{
+		CSVFile:     csv,
+		HeatmapFile: heatmap.Bytes(),
+		SummaryFile: encoded,
+	}, summary, nil
+}
+
+// writeFiles writes the results to the directory.
+func writeFiles(dir string, results map[string][]byte) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	for name, data := range results {
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0o644); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeConfigMap applies the results to the ConfigMap, which the controller
+// created and allowed this pod to patch.
+func writeConfigMap(ctx context.Context, namespace, name string, results map[string][]byte) error {
+	size := 0
+	for _, data := range results {
+		size += len(data)
+	}
+	if size > maxConfigMapSize {
+		return fmt.Errorf("the results are %d bytes, too large for a ConfigMap, write them to a volume instead", size)
+	}
+
+	config, err := rest.InClusterConfig()
+	if err != nil {
+		return err
+	}
+
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+
+	ac := corev1ac.ConfigMap(name, namespace).
+		WithData(map[string]string{
+			CSVFile:     string(results[CSVFile]),
+			SummaryFile: string(results[SummaryFile]),
+		}).
+		WithBinaryData(map[string][]byte{
+			HeatmapFile: results[HeatmapFile],
+		})
+
+	_, err = clientset.CoreV1().ConfigMaps(namespace).Apply(ctx, ac, metav1.ApplyOptions{FieldManager: fieldManager, Force: true})
+
+	return err
+}
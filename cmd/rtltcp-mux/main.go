@@ -0,0 +1,426 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/frelon/k8s-radio/pkg/level"
+	"github.com/frelon/k8s-radio/pkg/loss"
+	"github.com/frelon/k8s-radio/pkg/rtlmux"
+	"github.com/frelon/k8s-radio/pkg/rtltcp"
+	"github.com/frelon/k8s-radio/pkg/scanner"
+	"github.com/frelon/k8s-radio/pkg/sigmf"
+	"github.com/frelon/k8s-radio/pkg/spectrum"
+)
+
+const (
+	// dialInterval is the time between attempts to reach rtl_tcp, which
+	// starts alongside the mux and may not be listening yet.
+	dialInterval = time.Second
+	// reloadInterval is the time between checks of the channels file,
+	// which the kubelet updates in place when the ConfigMap changes.
+	reloadInterval = 5 * time.Second
+	// shutdownTimeout bounds how long the HTTP servers may take to stop.
+	shutdownTimeout = 5 * time.Second
+)
+
+func main() {
+	var listenAddr, upstreamAddr, lockPolicy, channelsPath, statusAddr, scanFrequencies, spectrumAddr, tapAddr, controlTokenFile string
+	var maxClients int
+	var control bool
+	var frequency, sampleRate uint
+	rec := &sigmf.Recorder{}
+	scan := &scanner.Scanner{}
+	analyzer := &spectrum.Analyzer{}
+	meter := &level.Meter{}
+	counter := &loss.Counter{}
+	flag.StringVar(&listenAddr, "listen", ":1234", "The address clients connect to.")
+	flag.StringVar(&tapAddr, "tap-listen", "", "The address monitors read the stream from without counting as clients, empty to not serve them.")
+	flag.StringVar(&upstreamAddr, "upstream", "127.0.0.1:1235", "The address of the rtl_tcp server to share.")
+	flag.StringVar(&lockPolicy, "lock-policy", string(rtlmux.PolicyFirstClient),
+		"Which clients may tune: FirstClient, Open or ReadOnly.")
+	flag.IntVar(&maxClients, "max-clients", 0, "The maximum number of connected clients, 0 for no limit.")
+	flag.StringVar(&channelsPath, "channels", "", "A JSON file listing narrowband channels to serve, reloaded when it changes.")
+	flag.UintVar(&frequency, "frequency", 0, "The frequency rtl_tcp was started with in Hz, recorded until a client retunes.")
+	flag.UintVar(&sampleRate, "sample-rate", 2_048_000, "The sample rate rtl_tcp was started with in Hz.")
+	flag.StringVar(&rec.Dir, "record-dir", "", "The directory to record the stream to as SigMF datasets, empty to not record.")
+	flag.StringVar(&rec.Name, "record-name", "recording", "The prefix of the names of the recorded datasets.")
+	flag.DurationVar(&rec.Interval, "record-interval", 0, "Start a new dataset after this long, 0 for no limit.")
+	flag.Int64Var(&rec.MaxFileSize, "record-file-size", 0, "Start a new dataset after this many bytes, 0 for no limit.")
+	flag.IntVar(&rec.MaxFiles, "record-max-files", 0, "The number of datasets to keep, 0 to keep all.")
+	flag.DurationVar(&rec.Duration, "record-duration", 0, "Stop recording after this long, 0 for no limit.")
+	flag.Int64Var(&rec.MaxSize, "record-max-size", 0, "Stop recording after this many bytes, 0 for no limit.")
+	flag.StringVar(&rec.Global.Hardware, "hardware", "RTL-SDR", "The hardware recorded in the dataset metadata.")
+	flag.StringVar(&rec.Global.Serial, "serial", os.Getenv("RTLSDR_SERIAL"), "The serial number of the dongle recorded in the dataset metadata.")
+	flag.StringVar(&rec.Global.Node, "node", os.Getenv("NODE_NAME"), "The node recorded in the dataset metadata.")
+	flag.StringVar(&rec.Global.Receiver, "receiver", "", "The receiver recorded in the dataset metadata.")
+	flag.StringVar(&scanFrequencies, "scan-frequencies", "", "A comma separated list of frequencies in Hz to scan, empty to not scan.")
+	flag.Float64Var(&scan.Bandwidth, "scan-bandwidth", 25_000, "The bandwidth of the scanned channels in Hz.")
+	flag.Float64Var(&scan.Squelch, "scan-squelch", -40, "The power in dBFS above which a scanned channel is active.")
+	flag.DurationVar(&scan.Dwell, "scan-dwell", scanner.DefaultDwell, "The time spent measuring each scanned channel.")
+	flag.DurationVar(&scan.Hold, "scan-hold", scanner.DefaultHold, "The time to stay on a channel after its activity ends.")
+	flag.IntVar(&scan.MaxHits, "scan-max-hits", scanner.DefaultMaxHits, "The number of recent hits to keep.")
+	flag.DurationVar(&meter.Interval, "level-interval", 0, "The time the signal level is averaged over, 0 to not measure it.")
+	flag.DurationVar(&counter.Window, "loss-window", 0, "The time the ratio of samples dropped by rtl_tcp is measured over, 0 to not count them.")
+	flag.BoolVar(&control, "control", false, "Serve the API retuning rtl_tcp on behalf of the controller on the status address.")
+	flag.StringVar(&controlTokenFile, "control-token-file", "", "The file holding the bearer token the controller calls the control API with.")
+	flag.StringVar(&statusAddr, "status-listen", ":9180", "The address the recording and scanner status, the signal level, the sample loss and the control API are served on.")
+	flag.StringVar(&spectrumAddr, "spectrum-listen", "", "The address the live spectrum and waterfall are served on, empty to not serve them.")
+	flag.IntVar(&analyzer.FFTSize, "spectrum-fft-size", spectrum.DefaultFFTSize, "The number of bins of the spectrum, a power of two.")
+	flag.IntVar(&analyzer.Averaging, "spectrum-averaging", spectrum.DefaultAveraging, "The number of FFTs averaged into each spectrum frame.")
+	flag.Float64Var(&analyzer.FrameRate, "spectrum-frame-rate", spectrum.DefaultFrameRate, "The number of spectrum frames per second.")
+	flag.Parse()
+
+	policy, err := rtlmux.ParsePolicy(lockPolicy)
+	if err != nil {
+		slog.Error("Invalid lock policy", slog.Any("error", err))
+		os.Exit(2)
+	}
+
+	if scan.Frequencies, err = parseFrequencies(scanFrequencies); err != nil {
+		slog.Error("Invalid scan frequencies", slog.Any("error", err))
+		os.Exit(2)
+	}
+
+	if err := analyzer.Validate(); err != nil {
+		slog.Error("Invalid spectrum settings", slog.Any("error", err))
+		os.Exit(2)
+	}
+
+	if control && controlTokenFile == "" {
+		slog.Error("The control API requires a token file")
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	upstream, err := dial(ctx, upstreamAddr)
+	if err != nil {
+		if ctx.Err() != nil {
+			return
+		}
+		slog.Error("Failed to connect to rtl_tcp", slog.Any("error", err))
+		os.Exit(1)
+	}
+
+	slog.Info("Connected to rtl_tcp",
+		slog.String("upstream", upstreamAddr),
+		slog.String("tuner", upstream.Info().Tuner.String()))
+
+	l, err := net.Listen("tcp", listenAddr)
+	if err != nil {
+		slog.Error("Failed to listen", slog.Any("error", err))
+		os.Exit(1)
+	}
+
+	m := rtlmux.New(upstream, policy)
+	m.MaxClients = maxClients
+	m.SetTuning(rtltcp.Tuning{Frequency: uint32(frequency), SampleRate: uint32(sampleRate)})
+
+	if channelsPath != "" {
+		go watchChannels(ctx, rtlmux.NewChannels(m), channelsPath)
+	}
+
+	// The recorder closes its dataset before the mux exits.
+	var recording sync.WaitGroup
+	defer recording.Wait()
+
+	status := http.NewServeMux()
+	status.HandleFunc("GET /tuning", jsonHandler(func() any { return m.Tuning() }))
+	if rec.Dir != "" {
+		rec.Global.Hardware = fmt.Sprintf("%s, %s tuner", rec.Global.Hardware, upstream.Info().Tuner)
+		rec.Global.Recorder = "k8s-radio"
+
+		recording.Go(func() { record(ctx, m, rec) })
+		status.HandleFunc("GET /recording", jsonHandler(func() any { return rec.Status() }))
+	}
+
+	if len(scan.Frequencies) > 0 {
+		go runScanner(ctx, m, scan)
+		status.HandleFunc("GET /scan", jsonHandler(func() any { return scan.Status() }))
+	}
+
+	if meter.Interval > 0 {
+		go runMeter(ctx, m, meter)
+		status.HandleFunc("GET /level", func(w http.ResponseWriter, r *http.Request) {
+			sample, ok := meter.Latest()
+			if !ok {
+				http.Error(w, "no signal level yet", http.StatusServiceUnavailable)
+				return
+			}
+			jsonHandler(func() any { return sample })(w, r)
+		})
+	}
+
+	if counter.Window > 0 {
+		m.Loss = counter
+		status.HandleFunc("GET /loss", jsonHandler(func() any { return counter.Stats(time.Now()) }))
+	}
+
+	if control {
+		c := rtlmux.NewControl(m)
+		c.TokenFile = controlTokenFile
+		status.Handle("/control", c)
+	}
+
+	if rec.Dir != "" || len(scan.Frequencies) > 0 || meter.Interval > 0 || counter.Window > 0 || tapAddr != "" || control {
+		go func() {
+			if err := serve(ctx, statusAddr, status); err != nil {
+				slog.Error("Failed to serve status", slog.Any("error", err))
+			}
+		}()
+	}
+
+	if spectrumAddr != "" {
+		go runSpectrum(ctx, m, analyzer)
+		go func() {
+			slog.Info("Serving spectrum", slog.String("address", spectrumAddr))
+			if err := serve(ctx, spectrumAddr, analyzer.Handler()); err != nil {
+				slog.Error("Failed to serve spectrum", slog.Any("error", err))
+			}
+		}()
+	}
+
+	if tapAddr != "" {
+		tl, err := net.Listen("tcp", tapAddr)
+		if err != nil {
+			slog.Error("Failed to listen for taps", slog.Any("error", err))
+			os.Exit(1)
+		}
+
+		go func() {
+			slog.Info("Serving taps", slog.String("address", tapAddr))
+			if err := m.ServeTap(ctx, tl); err != nil {
+				slog.Error("Failed to serve taps", slog.Any("error", err))
+			}
+		}()
+	}
+
+	go func() {
+		slog.Info("Serving clients", slog.String("address", listenAddr), slog.String("policy", string(policy)))
+		if err := m.Serve(ctx, l); err != nil {
+			slog.Error("Failed to serve clients", slog.Any("error", err))
+			stop()
+		}
+	}()
+
+	if err := m.Run(ctx); err != nil && ctx.Err() == nil {
+		slog.Error("Lost connection to rtl_tcp", slog.Any("error", err))
+		os.Exit(1)
+	}
+}
+
+// dial connects to rtl_tcp, retrying until it is up or ctx is done.
+func dial(ctx context.Context, addr string) (*rtltcp.Client, error) {
+	for {
+		c, err := rtltcp.Dial(ctx, addr)
+		if err == nil {
+			return c, nil
+		}
+
+		slog.Info("Waiting for rtl_tcp", slog.String("upstream", addr), slog.Any("error", err))
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(dialInterval):
+		}
+	}
+}
+
+// watchChannels serves the channels listed in the file at path until ctx is
+// done, updating them whenever the file changes.
+func watchChannels(ctx context.Context, channels *rtlmux.Channels, path string) {
+	var last []byte
+	for {
+		data, err := os.ReadFile(path)
+		switch {
+		case err != nil:
+			slog.Error("Failed reading channels", slog.String("path", path), slog.Any("error", err))
+		case !bytes.Equal(data, last):
+			var config rtlmux.Config
+			if err := json.Unmarshal(data, &config); err != nil {
+				slog.Error("Failed parsing channels", slog.String("path", path), slog.Any("error", err))
+				break
+			}
+
+			channels.Update(ctx, config)
+			last = data
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(reloadInterval):
+		}
+	}
+}
+
+// record records the stream until ctx is done or the recording has
+// finished, resuming with a new dataset when the recorder falls behind.
+func record(ctx context.Context, m *rtlmux.Mux, rec *sigmf.Recorder) {
+	slog.Info("Recording", slog.String("dir", rec.Dir))
+
+	for {
+		samples, unsubscribe, err := m.Subscribe("recorder")
+		if err != nil {
+			return
+		}
+
+		err = rec.Record(ctx, samples, m.Tuning)
+		unsubscribe()
+		if err == nil || ctx.Err() != nil {
+			return
+		}
+
+		slog.Error("Recording interrupted", slog.Any("error", err))
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(dialInterval):
+		}
+	}
+}
+
+// runScanner scans the channels until ctx is done, resuming when the scanner
+// falls behind.
+func runScanner(ctx context.Context, m *rtlmux.Mux, scan *scanner.Scanner) {
+	slog.Info("Scanning", slog.Int("channels", len(scan.Frequencies)))
+
+	tune := func(hz uint32) error {
+		return m.SendCommand(rtltcp.SetFrequency, hz)
+	}
+	sampleRate := func() uint32 {
+		return m.Tuning().SampleRate
+	}
+
+	for {
+		samples, unsubscribe, err := m.Subscribe("scanner")
+		if err != nil {
+			return
+		}
+
+		err = scan.Run(ctx, samples, tune, sampleRate)
+		unsubscribe()
+		if ctx.Err() != nil {
+			return
+		}
+
+		slog.Error("Scanning interrupted", slog.Any("error", err))
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(dialInterval):
+		}
+	}
+}
+
+// runSpectrum computes the spectrum until ctx is done, resuming when the
+// analyzer falls behind.
+func runSpectrum(ctx context.Context, m *rtlmux.Mux, analyzer *spectrum.Analyzer) {
+	for {
+		samples, unsubscribe, err := m.Subscribe("spectrum")
+		if err != nil {
+			return
+		}
+
+		err = analyzer.Run(ctx, samples, m.Tuning)
+		unsubscribe()
+		if ctx.Err() != nil {
+			return
+		}
+
+		slog.Error("Spectrum interrupted", slog.Any("error", err))
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(dialInterval):
+		}
+	}
+}
+
+// runMeter measures the signal level until ctx is done, resuming when the
+// meter falls behind.
+func runMeter(ctx context.Context, m *rtlmux.Mux, meter *level.Meter) {
+	for {
+		samples, unsubscribe, err := m.Subscribe("level")
+		if err != nil {
+			return
+		}
+
+		err = meter.Run(ctx, samples, m.Tuning)
+		unsubscribe()
+		if ctx.Err() != nil {
+			return
+		}
+
+		slog.Error("Signal level interrupted", slog.Any("error", err))
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(dialInterval):
+		}
+	}
+}
+
+// parseFrequencies parses a comma separated list of frequencies in Hz.
+func parseFrequencies(s string) ([]uint32, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	var frequencies []uint32
+	for f := range strings.SplitSeq(s, ",") {
+		hz, err := strconv.ParseUint(strings.TrimSpace(f), 10, 32)
+		if err != nil {
+			return nil, err
+		}
+		frequencies = append(frequencies, uint32(hz))
+	}
+
+	return frequencies, nil
+}
+
+// jsonHandler serves the value returned by status as JSON.
+func jsonHandler(status func() any) http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(status())
+	}
+}
+
+// serve serves the handler on addr until ctx is done.
+func serve(ctx context.Context, addr string, handler http.Handler) error {
+	server := &http.Server{Addr: addr, Handler: handler, ReadHeaderTimeout: 5 * time.Second}
+	stop := context.AfterFunc(ctx, func() {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	})
+	defer stop()
+
+	if err := server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,158 @@
+package rtlsdr
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"slices"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	podresourcesv1 "k8s.io/kubelet/pkg/apis/podresources/v1"
+)
+
+const (
+	// DevicesAnnotation is the node annotation listing the dongles plugged
+	// into the node as JSON, see DeviceInfo.
+	DevicesAnnotation = "radio.frelon.se/rtl-sdr-devices"
+
+	// ResourceNamespace is the namespace of the extended resources the
+	// dongles are allocated as.
+	ResourceNamespace = "frelon.se"
+
+	// ResourceFullName is the extended resource the dongles are allocated
+	// as.
+	ResourceFullName = ResourceNamespace + "/" + ResourceName
+)
+
+// DeviceInfo describes a dongle plugged into a node.
+type DeviceInfo struct {
+	Serial string `json:"serial"`
+	// Product is the USB vendor and product ID, e.g. 0bda:2838.
+	Product string `json:"product"`
+	// Pod is the namespace/name of the pod allocated the dongle, empty
+	// while it is free.
+	Pod string `json:"pod,omitempty"`
+}
+
+// Inventory lists the dongles of a node and the pods they are allocated to.
+type Inventory struct {
+	FS fs.FS
+	// PodResources is the pod resources API of the kubelet, telling which
+	// pod was allocated which dongle. Allocations are not listed when nil.
+	PodResources podresourcesv1.PodResourcesListerClient
+}
+
+// List returns the dongles plugged into the node, sorted by serial.
+func (inv Inventory) List(ctx context.Context) ([]DeviceInfo, error) {
+	devs, err := ListUsbDevices(inv.FS)
+	if err != nil {
+		return nil, err
+	}
+
+	allocations := map[string]allocation{}
+	if inv.PodResources != nil {
+		if allocations, err = listAllocations(ctx, inv.PodResources); err != nil {
+			return nil, err
+		}
+	}
+
+	infos := make([]DeviceInfo, 0, len(devs))
+	for _, dev := range devs {
+		infos = append(infos, DeviceInfo{
+			Serial:  dev.Serial,
+			Product: dev.VendorID + ":" + dev.ProductID,
+			Pod:     allocations[dev.Serial].pod,
+		})
+	}
+	slices.SortFunc(infos, func(a, b DeviceInfo) int {
+		return strings.Compare(a.Serial, b.Serial)
+	})
+
+	return infos, nil
+}
+
+// allocation is a dongle allocated to a pod.
+type allocation struct {
+	// pod is the namespace/name of the pod.
+	pod string
+	// resource is the extended resource the dongle was allocated as.
+	resource string
+}
+
+// listAllocations returns the dongles allocated to pods by serial, whichever
+// resource they were allocated as.
+func listAllocations(ctx context.Context, podResources podresourcesv1.PodResourcesListerClient) (map[string]allocation, error) {
+	resp, err := podResources.List(ctx, &podresourcesv1.ListPodResourcesRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("failed listing pod resources: %w", err)
+	}
+
+	allocations := map[string]allocation{}
+	for _, pod := range resp.GetPodResources() {
+		for _, container := range pod.GetContainers() {
+			for _, devices := range container.GetDevices() {
+				name := devices.GetResourceName()
+				if name != ResourceFullName && !strings.HasPrefix(name, ResourceFullName+"-") {
+					continue
+				}
+				for _, id := range devices.GetDeviceIds() {
+					allocations[id] = allocation{pod: pod.GetNamespace() + "/" + pod.GetName(), resource: name}
+				}
+			}
+		}
+	}
+
+	return allocations, nil
+}
+
+// ParseDevices returns the dongles listed in the annotations of a node, or
+// nil if the device plugin has not published any.
+func ParseDevices(annotations map[string]string) ([]DeviceInfo, error) {
+	value, ok := annotations[DevicesAnnotation]
+	if !ok {
+		return nil, nil
+	}
+
+	var infos []DeviceInfo
+	if err := json.Unmarshal([]byte(value), &infos); err != nil {
+		return nil, fmt.Errorf("failed parsing annotation %s: %w", DevicesAnnotation, err)
+	}
+
+	return infos, nil
+}
+
+// PublishDevices sets the devices annotation of the node, unless it already
+// lists devices.
+func PublishDevices(ctx context.Context, nodes typedcorev1.NodeInterface, name string, devices []DeviceInfo) error {
+	node, err := nodes.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed getting node %s: %w", name, err)
+	}
+
+	value, err := json.Marshal(devices)
+	if err != nil {
+		return err
+	}
+	if node.Annotations[DevicesAnnotation] == string(value) {
+		return nil
+	}
+
+	patch, err := json.Marshal(map[string]any{
+		"metadata": map[string]any{
+			"annotations": map[string]string{DevicesAnnotation: string(value)},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	if _, err := nodes.Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+		return fmt.Errorf("failed annotating node %s: %w", name, err)
+	}
+
+	return nil
+}
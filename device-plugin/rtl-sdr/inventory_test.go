@@ -0,0 +1,77 @@
+package rtlsdr
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"google.golang.org/grpc"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	podresourcesv1 "k8s.io/kubelet/pkg/apis/podresources/v1"
+)
+
+// podResources answers List with the pods it holds.
+type podResources struct {
+	podresourcesv1.PodResourcesListerClient
+
+	pods []*podresourcesv1.PodResources
+}
+
+func (p podResources) List(context.Context, *podresourcesv1.ListPodResourcesRequest, ...grpc.CallOption) (*podresourcesv1.ListPodResourcesResponse, error) {
+	return &podresourcesv1.ListPodResourcesResponse{PodResources: p.pods}, nil
+}
+
+var _ = Describe("Inventory", func() {
+	It("lists the dongles and the pods allocated them", func(ctx SpecContext) {
+		inv := Inventory{
+			FS: SimulatedDevices(3),
+			PodResources: podResources{pods: []*podresourcesv1.PodResources{
+				{
+					Name:      "fm",
+					Namespace: "radio",
+					Containers: []*podresourcesv1.ContainerResources{{
+						Name: "receiver",
+						Devices: []*podresourcesv1.ContainerDevices{
+							{ResourceName: "example.com/gpu", DeviceIds: []string{"SIM00001"}},
+							{ResourceName: "frelon.se/rtl-sdr", DeviceIds: []string{"SIM00002"}},
+							{ResourceName: "frelon.se/rtl-sdr-SIM00003", DeviceIds: []string{"SIM00003"}},
+						},
+					}},
+				},
+			}},
+		}
+
+		devices, err := inv.List(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(devices).To(Equal([]DeviceInfo{
+			{Serial: "SIM00001", Product: "0bda:2838"},
+			{Serial: "SIM00002", Product: "0bda:2838", Pod: "radio/fm"},
+			{Serial: "SIM00003", Product: "0bda:2838", Pod: "radio/fm"},
+		}))
+	})
+
+	It("publishes the dongles in an annotation of the node", func(ctx SpecContext) {
+		clientset := fake.NewClientset(&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "pi"}})
+		nodes := clientset.CoreV1().Nodes()
+		devices := []DeviceInfo{{Serial: "SIM00001", Product: "0bda:2838", Pod: "radio/fm"}}
+
+		Expect(PublishDevices(ctx, nodes, "pi", devices)).To(Succeed())
+
+		node, err := nodes.Get(ctx, "pi", metav1.GetOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(node.Annotations).To(HaveKeyWithValue(DevicesAnnotation,
+			`[{"serial":"SIM00001","product":"0bda:2838","pod":"radio/fm"}]`))
+
+		parsed, err := ParseDevices(node.Annotations)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(parsed).To(Equal(devices))
+
+		By("not patching the node while the dongles stay the same")
+		clientset.ClearActions()
+		Expect(PublishDevices(ctx, nodes, "pi", devices)).To(Succeed())
+		Expect(clientset.Actions()).To(HaveLen(1))
+		Expect(clientset.Actions()[0].GetVerb()).To(Equal("get"))
+	})
+})
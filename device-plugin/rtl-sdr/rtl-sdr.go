@@ -2,23 +2,74 @@ package rtlsdr
 
 import (
 	"context"
+	"fmt"
 	"io/fs"
 	"log/slog"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/kubevirt/device-plugin-manager/pkg/dpm"
+	"k8s.io/apimachinery/pkg/util/validation"
 	pluginapi "k8s.io/kubelet/pkg/apis/deviceplugin/v1beta1"
+	podresourcesv1 "k8s.io/kubelet/pkg/apis/podresources/v1"
 )
 
 const (
 	ResourceName = "rtl-sdr"
+
+	// SerialEnv is the environment variable holding the serial number of
+	// the dongle allocated to a container.
+	SerialEnv = "RTLSDR_SERIAL"
+
+	// podResourcesTimeout bounds asking the kubelet which dongles are
+	// allocated.
+	podResourcesTimeout = 5 * time.Second
 )
 
+// SerialResourceName returns the extended resource the dongle with the
+// serial is also allocated as, for pods that need exactly that dongle, and
+// false if the serial cannot be part of a resource name.
+func SerialResourceName(serial string) (string, bool) {
+	name := ResourceFullName + "-" + serial
+	if len(validation.IsQualifiedName(name)) > 0 {
+		return "", false
+	}
+
+	return name, true
+}
+
+// ResourceSerial returns the serial of the dongle served as the resource
+// with the last name, and false for the resource of any dongle.
+func ResourceSerial(resourceLastName string) (string, bool) {
+	serial, ok := strings.CutPrefix(resourceLastName, ResourceName+"-")
+	if !ok || serial == "" {
+		return "", false
+	}
+
+	return serial, true
+}
+
 type Plugin struct {
 	pluginapi.UnimplementedDevicePluginServer
 
 	devices   map[string]*UsbDevice
 	heartbeat chan bool
 	fsys      fs.FS
+
+	// simulated is set when the devices are virtual.
+	simulated bool
+	// backend is the host path of the binary replacing rtl_tcp in
+	// containers allocated a virtual device.
+	backend string
+
+	// serial is the dongle served as its own resource, empty when any
+	// dongle is served.
+	serial string
+	// podResources tells which dongles are allocated as another resource,
+	// as the same dongle is served both as the resource of any dongle and
+	// as its own. Those are not allocated again.
+	podResources podresourcesv1.PodResourcesListerClient
 }
 
 var _ dpm.PluginInterface = (*Plugin)(nil)
@@ -31,8 +82,59 @@ func NewPlugin(heartbeat chan bool, fsys fs.FS) *Plugin {
 	}
 }
 
+// WithSerial makes the plugin serve only the dongle with the serial, as the
+// resource SerialResourceName returns.
+func (p *Plugin) WithSerial(serial string) *Plugin {
+	p.serial = serial
+	return p
+}
+
+// WithPodResources makes the plugin keep the dongles allocated as another
+// resource from being allocated again, as told by the pod resources API of
+// the kubelet.
+func (p *Plugin) WithPodResources(podResources podresourcesv1.PodResourcesListerClient) *Plugin {
+	p.podResources = podResources
+	return p
+}
+
+// resource returns the extended resource the plugin serves.
+func (p *Plugin) resource() string {
+	if p.serial == "" {
+		return ResourceFullName
+	}
+
+	name, _ := SerialResourceName(p.serial)
+	return name
+}
+
+// allocatedElsewhere returns the serials of the dongles allocated as another
+// resource than the one the plugin serves.
+func (p *Plugin) allocatedElsewhere(ctx context.Context) (map[string]bool, error) {
+	elsewhere := map[string]bool{}
+	if p.podResources == nil {
+		return elsewhere, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, podResourcesTimeout)
+	defer cancel()
+
+	allocations, err := listAllocations(ctx, p.podResources)
+	if err != nil {
+		return nil, err
+	}
+	for serial, a := range allocations {
+		if a.resource != p.resource() {
+			elsewhere[serial] = true
+		}
+	}
+
+	return elsewhere, nil
+}
+
 func (p *Plugin) GetDevicePluginOptions(ctx context.Context, e *pluginapi.Empty) (*pluginapi.DevicePluginOptions, error) {
-	return &pluginapi.DevicePluginOptions{}, nil
+	return &pluginapi.DevicePluginOptions{
+		GetPreferredAllocationAvailable: p.podResources != nil,
+	}, nil
 }
 
 func (p *Plugin) PreStartContainer(ctx context.Context, r *pluginapi.PreStartContainerRequest) (*pluginapi.PreStartContainerResponse, error) {
@@ -48,8 +150,22 @@ func (p *Plugin) UpdateDevices() ([]*pluginapi.Device, error) {
 
 	slog.Info("Found devices", "len", len(connectedDevs))
 
+	// The dongle of a serial is unhealthy while allocated as any dongle,
+	// so pods needing it wait for it to be freed. The resource of any
+	// dongle keeps counting all of them, and prefers the free ones.
+	elsewhere := map[string]bool{}
+	if p.serial != "" {
+		if elsewhere, err = p.allocatedElsewhere(context.Background()); err != nil {
+			slog.Info("Error listing allocations", slog.Any("error", err))
+			return nil, err
+		}
+	}
+
 	connectedDevsBySerial := map[string]*UsbDevice{}
 	for i := range connectedDevs {
+		if p.serial != "" && connectedDevs[i].Serial != p.serial {
+			continue
+		}
 		connectedDevsBySerial[connectedDevs[i].Serial] = connectedDevs[i]
 		p.devices[connectedDevs[i].Serial] = connectedDevs[i]
 	}
@@ -62,7 +178,7 @@ func (p *Plugin) UpdateDevices() ([]*pluginapi.Device, error) {
 			Health: pluginapi.Unhealthy,
 		}
 
-		if _, ok := connectedDevsBySerial[rtl.Serial]; ok {
+		if _, ok := connectedDevsBySerial[rtl.Serial]; ok && !elsewhere[rtl.Serial] {
 			pdevs[i].Health = pluginapi.Healthy
 		}
 
@@ -104,8 +220,30 @@ func (p *Plugin) ListAndWatch(e *pluginapi.Empty, s pluginapi.DevicePlugin_ListA
 	return nil
 }
 
-func (p *Plugin) GetPreferredAllocation(context.Context, *pluginapi.PreferredAllocationRequest) (*pluginapi.PreferredAllocationResponse, error) {
-	return &pluginapi.PreferredAllocationResponse{}, nil
+// GetPreferredAllocation prefers the dongles not allocated as another
+// resource, which the kubelet counts as free.
+func (p *Plugin) GetPreferredAllocation(ctx context.Context, r *pluginapi.PreferredAllocationRequest) (*pluginapi.PreferredAllocationResponse, error) {
+	elsewhere, err := p.allocatedElsewhere(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var response pluginapi.PreferredAllocationResponse
+	for _, req := range r.ContainerRequests {
+		ids := slices.Clone(req.MustIncludeDeviceIDs)
+		for _, id := range req.AvailableDeviceIDs {
+			if len(ids) >= int(req.AllocationSize) {
+				break
+			}
+			if !elsewhere[id] && !slices.Contains(ids, id) {
+				ids = append(ids, id)
+			}
+		}
+
+		response.ContainerResponses = append(response.ContainerResponses, &pluginapi.ContainerPreferredAllocationResponse{DeviceIDs: ids})
+	}
+
+	return &response, nil
 }
 
 func (p *Plugin) Allocate(ctx context.Context, r *pluginapi.AllocateRequest) (*pluginapi.AllocateResponse, error) {
@@ -113,6 +251,13 @@ func (p *Plugin) Allocate(ctx context.Context, r *pluginapi.AllocateRequest) (*p
 	var car pluginapi.ContainerAllocateResponse
 	var dev *pluginapi.DeviceSpec
 
+	// The kubelet does not know of the allocations as another resource,
+	// so refuse those dongles rather than hand them out twice.
+	elsewhere, err := p.allocatedElsewhere(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	for _, req := range r.ContainerRequests {
 		car = pluginapi.ContainerAllocateResponse{}
 
@@ -121,10 +266,27 @@ func (p *Plugin) Allocate(ctx context.Context, r *pluginapi.AllocateRequest) (*p
 		car.Devices = append(car.Devices, dev)
 
 		for _, id := range req.DevicesIds {
-			slog.Info("Allocating device", slog.String("ID", id))
+			if elsewhere[id] {
+				return nil, fmt.Errorf("device %s is allocated as another resource than %s", id, p.resource())
+			}
+
+			slog.Info("Allocating device", slog.String("ID", id), slog.Bool("simulated", p.simulated))
 
 			dev.HostPath = p.devices[id].DevicePath()
 			dev.ContainerPath = p.devices[id].DevicePath()
+			car.Envs = map[string]string{SerialEnv: id}
+
+			if p.simulated {
+				dev.HostPath = placeholderDevice
+			}
+		}
+
+		if p.simulated && p.backend != "" {
+			car.Mounts = append(car.Mounts, &pluginapi.Mount{
+				ContainerPath: rtlTCPPath,
+				HostPath:      p.backend,
+				ReadOnly:      true,
+			})
 		}
 
 		response.ContainerResponses = append(response.ContainerResponses, &car)
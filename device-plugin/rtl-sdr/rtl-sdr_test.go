@@ -0,0 +1,104 @@
+package rtlsdr
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	pluginapi "k8s.io/kubelet/pkg/apis/deviceplugin/v1beta1"
+	podresourcesv1 "k8s.io/kubelet/pkg/apis/podresources/v1"
+)
+
+var _ = Describe("Plugin", func() {
+	allocated := func(resource, serial string) podResources {
+		return podResources{pods: []*podresourcesv1.PodResources{{
+			Name:      "fm",
+			Namespace: "radio",
+			Containers: []*podresourcesv1.ContainerResources{{
+				Name:    "receiver",
+				Devices: []*podresourcesv1.ContainerDevices{{ResourceName: resource, DeviceIds: []string{serial}}},
+			}},
+		}}}
+	}
+
+	It("names the resource of each dongle after its serial", func() {
+		name, ok := SerialResourceName("SIM00001")
+		Expect(ok).To(BeTrue())
+		Expect(name).To(Equal("frelon.se/rtl-sdr-SIM00001"))
+
+		serial, ok := ResourceSerial("rtl-sdr-SIM00001")
+		Expect(ok).To(BeTrue())
+		Expect(serial).To(Equal("SIM00001"))
+
+		_, ok = ResourceSerial(ResourceName)
+		Expect(ok).To(BeFalse())
+
+		_, ok = SerialResourceName("not a/serial")
+		Expect(ok).To(BeFalse())
+	})
+
+	It("serves only its dongle as the resource of the serial", func() {
+		p := NewSimulatedPlugin(nil, 2, "").WithSerial("SIM00002")
+
+		devs, err := p.UpdateDevices()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(devs).To(HaveLen(1))
+		Expect(devs[0].ID).To(Equal("SIM00002"))
+		Expect(devs[0].Health).To(Equal(pluginapi.Healthy))
+	})
+
+	It("keeps a dongle allocated as its own resource from being allocated as any", func(ctx SpecContext) {
+		p := NewSimulatedPlugin(nil, 2, "").WithPodResources(allocated("frelon.se/rtl-sdr-SIM00002", "SIM00002"))
+
+		By("still counting the dongle")
+		devs, err := p.UpdateDevices()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(devs).To(ConsistOf(
+			&pluginapi.Device{ID: "SIM00001", Health: pluginapi.Healthy},
+			&pluginapi.Device{ID: "SIM00002", Health: pluginapi.Healthy},
+		))
+
+		By("preferring the free dongle")
+		options, err := p.GetDevicePluginOptions(ctx, &pluginapi.Empty{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(options.GetPreferredAllocationAvailable).To(BeTrue())
+
+		preferred, err := p.GetPreferredAllocation(ctx, &pluginapi.PreferredAllocationRequest{
+			ContainerRequests: []*pluginapi.ContainerPreferredAllocationRequest{{
+				AvailableDeviceIDs: []string{"SIM00002", "SIM00001"},
+				AllocationSize:     1,
+			}},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(preferred.ContainerResponses).To(HaveLen(1))
+		Expect(preferred.ContainerResponses[0].DeviceIDs).To(Equal([]string{"SIM00001"}))
+
+		By("refusing the dongle in use")
+		_, err = p.Allocate(ctx, &pluginapi.AllocateRequest{
+			ContainerRequests: []*pluginapi.ContainerAllocateRequest{{DevicesIds: []string{"SIM00002"}}},
+		})
+		Expect(err).To(MatchError(ContainSubstring("SIM00002")))
+	})
+
+	It("keeps a dongle allocated as any from being allocated as its own resource", func(ctx SpecContext) {
+		p := NewSimulatedPlugin(nil, 2, "").WithSerial("SIM00002").WithPodResources(allocated("frelon.se/rtl-sdr", "SIM00002"))
+
+		devs, err := p.UpdateDevices()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(devs).To(ConsistOf(&pluginapi.Device{ID: "SIM00002", Health: pluginapi.Unhealthy}))
+
+		_, err = p.Allocate(ctx, &pluginapi.AllocateRequest{
+			ContainerRequests: []*pluginapi.ContainerAllocateRequest{{DevicesIds: []string{"SIM00002"}}},
+		})
+		Expect(err).To(MatchError(ContainSubstring("SIM00002")))
+
+		By("allocating it as its own resource once freed")
+		p = NewSimulatedPlugin(nil, 2, "").WithSerial("SIM00002").WithPodResources(podResources{})
+		devs, err = p.UpdateDevices()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(devs).To(ConsistOf(&pluginapi.Device{ID: "SIM00002", Health: pluginapi.Healthy}))
+
+		_, err = p.Allocate(ctx, &pluginapi.AllocateRequest{
+			ContainerRequests: []*pluginapi.ContainerAllocateRequest{{DevicesIds: []string{"SIM00002"}}},
+		})
+		Expect(err).ToNot(HaveOccurred())
+	})
+})
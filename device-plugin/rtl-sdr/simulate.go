@@ -0,0 +1,44 @@
+package rtlsdr
+
+import (
+	"fmt"
+	"path"
+	"testing/fstest"
+)
+
+const (
+	// simulatedBus is the USB bus virtual devices are placed on.
+	simulatedBus = 1
+	// placeholderDevice is the host device node handed to containers in
+	// place of a virtual device.
+	placeholderDevice = "/dev/null"
+	// rtlTCPPath is the path of rtl_tcp in the receiver image.
+	rtlTCPPath = "/bin/rtl_tcp"
+)
+
+// SimulatedDevices returns a sysfs tree with n virtual RTL2838 dongles.
+func SimulatedDevices(n int) fstest.MapFS {
+	fsys := fstest.MapFS{}
+
+	for i := range n {
+		dir := path.Join("sys/bus/usb/devices", fmt.Sprintf("%d-%d", simulatedBus, i+1))
+		fsys[path.Join(dir, "idVendor")] = &fstest.MapFile{Data: []byte("0bda\n")}
+		fsys[path.Join(dir, "idProduct")] = &fstest.MapFile{Data: []byte("2838\n")}
+		fsys[path.Join(dir, "busnum")] = &fstest.MapFile{Data: fmt.Appendf(nil, "%d\n", simulatedBus)}
+		fsys[path.Join(dir, "devnum")] = &fstest.MapFile{Data: fmt.Appendf(nil, "%d\n", i+2)}
+		fsys[path.Join(dir, "serial")] = &fstest.MapFile{Data: fmt.Appendf(nil, "SIM%05d\n", i+1)}
+	}
+
+	return fsys
+}
+
+// NewSimulatedPlugin returns a plugin serving n virtual devices. Containers
+// allocated a virtual device get a placeholder device node, and rtl_tcp is
+// replaced by the binary at the host path backend unless it is empty.
+func NewSimulatedPlugin(heartbeat chan bool, n int, backend string) *Plugin {
+	p := NewPlugin(heartbeat, SimulatedDevices(n))
+	p.simulated = true
+	p.backend = backend
+
+	return p
+}
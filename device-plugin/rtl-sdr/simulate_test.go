@@ -0,0 +1,44 @@
+package rtlsdr
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	pluginapi "k8s.io/kubelet/pkg/apis/deviceplugin/v1beta1"
+)
+
+var _ = Describe("Simulation", func() {
+	It("serves virtual devices", func(ctx SpecContext) {
+		b, err := ListUsbDevices(SimulatedDevices(2))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(b).To(HaveLen(2))
+		Expect(b[0].Serial).To(Equal("SIM00001"))
+		Expect(b[1].Serial).To(Equal("SIM00002"))
+		Expect(b[0].DevicePath()).ToNot(Equal(b[1].DevicePath()))
+	})
+
+	It("allocates a placeholder device and the fake backend", func(ctx SpecContext) {
+		p := NewSimulatedPlugin(nil, 1, "/var/lib/k8s-radio/fake-rtltcp")
+
+		devs, err := p.UpdateDevices()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(devs).To(HaveLen(1))
+		Expect(devs[0].Health).To(Equal(pluginapi.Healthy))
+
+		resp, err := p.Allocate(ctx, &pluginapi.AllocateRequest{
+			ContainerRequests: []*pluginapi.ContainerAllocateRequest{{
+				DevicesIds: []string{devs[0].ID},
+			}},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resp.ContainerResponses).To(HaveLen(1))
+
+		car := resp.ContainerResponses[0]
+		Expect(car.Devices).To(HaveLen(1))
+		Expect(car.Devices[0].HostPath).To(Equal("/dev/null"))
+		Expect(car.Devices[0].ContainerPath).To(Equal("/dev/bus/usb/001/002"))
+		Expect(car.Envs).To(HaveKeyWithValue("RTLSDR_SERIAL", "SIM00001"))
+		Expect(car.Mounts).To(HaveLen(1))
+		Expect(car.Mounts[0].HostPath).To(Equal("/var/lib/k8s-radio/fake-rtltcp"))
+		Expect(car.Mounts[0].ContainerPath).To(Equal("/bin/rtl_tcp"))
+	})
+})
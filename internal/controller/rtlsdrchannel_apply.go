@@ -0,0 +1,201 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	corev1ac "k8s.io/client-go/applyconfigurations/core/v1"
+	metav1ac "k8s.io/client-go/applyconfigurations/meta/v1"
+
+	radiov1beta1 "github.com/frelon/k8s-radio/api/v1beta1"
+	radiov1beta1ac "github.com/frelon/k8s-radio/api/v1beta1/applyconfiguration/api/v1beta1"
+	"github.com/frelon/k8s-radio/pkg/rtlmux"
+)
+
+const (
+	// DefaultSampleRate is the sample rate of receivers that don't set one,
+	// the default of rtl_tcp.
+	DefaultSampleRate = 2_048_000
+
+	// channelBasePort is the port of the first channel served by the proxy.
+	channelBasePort = 20000
+
+	channelsVolume    = "channels"
+	channelsMountPath = "/etc/k8s-radio/channels"
+	channelsFile      = "channels.json"
+)
+
+// sampleRate returns the sample rate of the receiver in Hz.
+func sampleRate(receiver *radiov1beta1.RtlSdrReceiver) int64 {
+	if receiver.Spec.SampleRate != nil {
+		return receiver.Spec.SampleRate.Value()
+	}
+
+	return DefaultSampleRate
+}
+
+// planChannel returns the configuration of the proxy serving the channel
+// split from the receiver on port, or an error if the channel does not fit
+// inside the passband of the receiver.
+func planChannel(receiver *radiov1beta1.RtlSdrReceiver, channel *radiov1beta1.RtlSdrChannel, port int32) (rtlmux.ChannelConfig, error) {
+	if receiver.Spec.Mode != "" && receiver.Spec.Mode != radiov1beta1.ModeIQ {
+		return rtlmux.ChannelConfig{}, fmt.Errorf("receiver %s is not in IQ mode", receiver.Name)
+	}
+
+	rate := sampleRate(receiver)
+	offset := channel.Spec.Offset.Value()
+	bandwidth := channel.Spec.Bandwidth.Value()
+
+	if bandwidth <= 0 {
+		return rtlmux.ChannelConfig{}, fmt.Errorf("bandwidth %s must be positive", channel.Spec.Bandwidth.String())
+	}
+
+	if low, high := offset-bandwidth/2, offset+bandwidth/2; low < -rate/2 || high > rate/2 {
+		return rtlmux.ChannelConfig{}, fmt.Errorf("channel from %d Hz to %d Hz is outside the passband of receiver %s, -%d Hz to %d Hz",
+			low, high, receiver.Name, rate/2, rate/2)
+	}
+
+	return rtlmux.ChannelConfig{
+		Name:       channel.Name,
+		Port:       port,
+		Offset:     float64(offset),
+		Bandwidth:  float64(bandwidth),
+		Decimation: int(max(rate/(2*bandwidth), 1)),
+	}, nil
+}
+
+// channelPorts assigns the proxy ports of the channels of a receiver. Ports
+// are assigned in order of creation, so a channel keeps its port as long as
+// no older channel is deleted.
+func channelPorts(channels []radiov1beta1.RtlSdrChannel) map[string]int32 {
+	sorted := slices.Clone(channels)
+	slices.SortFunc(sorted, func(a, b radiov1beta1.RtlSdrChannel) int {
+		if c := a.CreationTimestamp.Compare(b.CreationTimestamp.Time); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	ports := make(map[string]int32, len(sorted))
+	for i, channel := range sorted {
+		ports[channel.Name] = channelBasePort + int32(i)
+	}
+
+	return ports
+}
+
+// channelsConfigMapName returns the name of the ConfigMap listing the
+// channels of a receiver.
+func channelsConfigMapName(receiver *radiov1beta1.RtlSdrReceiver) string {
+	return receiver.Name + "-channels"
+}
+
+// channelsConfigMap returns the ConfigMap listing the channels the proxy of
+// the receiver serves.
+func channelsConfigMap(receiver *radiov1beta1.RtlSdrReceiver, channels []rtlmux.ChannelConfig) (*corev1ac.ConfigMapApplyConfiguration, error) {
+	data, err := json.Marshal(rtlmux.Config{
+		SampleRate: float64(sampleRate(receiver)),
+		Channels:   channels,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return corev1ac.ConfigMap(channelsConfigMapName(receiver), receiver.Namespace).
+		WithLabels(receiverLabels(receiver)).
+		WithOwnerReferences(ownerReference(receiver)).
+		WithData(map[string]string{channelsFile: string(data)}), nil
+}
+
+// channelEndpoint returns the in-cluster URL of the channel stream.
+func channelEndpoint(channel *radiov1beta1.RtlSdrChannel) string {
+	return fmt.Sprintf("tcp://%s.%s.svc:%d", channel.Name, channel.Namespace, defaultListenPort)
+}
+
+// channelService returns the Service exposing the channel, served by the
+// proxy of the receiver on port.
+func channelService(receiver *radiov1beta1.RtlSdrReceiver, channel *radiov1beta1.RtlSdrChannel, port int32) *corev1ac.ServiceApplyConfiguration {
+	return corev1ac.Service(channel.Name, channel.Namespace).
+		WithLabels(map[string]string{
+			"app.kubernetes.io/name":       "rtlsdrchannel",
+			"app.kubernetes.io/instance":   channel.Name,
+			"app.kubernetes.io/managed-by": "k8s-radio",
+		}).
+		WithOwnerReferences(metav1ac.OwnerReference().
+			WithAPIVersion(radiov1beta1.GroupVersion.String()).
+			WithKind("RtlSdrChannel").
+			WithName(channel.Name).
+			WithUID(channel.UID).
+			WithController(true).
+			WithBlockOwnerDeletion(true)).
+		WithSpec(corev1ac.ServiceSpec().
+			WithSelector(receiverLabels(receiver)).
+			WithPorts(corev1ac.ServicePort().
+				WithName("rtl-tcp").
+				WithProtocol(corev1.ProtocolTCP).
+				WithPort(defaultListenPort).
+				WithTargetPort(intstr.FromInt32(port))))
+}
+
+// channelStatusApplyConfiguration converts the status of the channel into an
+// apply configuration.
+func channelStatusApplyConfiguration(channel *radiov1beta1.RtlSdrChannel) *radiov1beta1ac.RtlSdrChannelApplyConfiguration {
+	status := radiov1beta1ac.RtlSdrChannelStatus()
+
+	if channel.Status.State != "" {
+		status.WithState(channel.Status.State)
+	}
+	if channel.Status.Endpoint != "" {
+		status.WithEndpoint(channel.Status.Endpoint)
+	}
+	if channel.Status.Frequency != nil {
+		status.WithFrequency(*channel.Status.Frequency)
+	}
+	if channel.Status.SampleRate != nil {
+		status.WithSampleRate(*channel.Status.SampleRate)
+	}
+
+	for _, c := range channel.Status.Conditions {
+		status.WithConditions(metav1ac.Condition().
+			WithType(c.Type).
+			WithStatus(c.Status).
+			WithObservedGeneration(c.ObservedGeneration).
+			WithLastTransitionTime(c.LastTransitionTime).
+			WithReason(c.Reason).
+			WithMessage(c.Message))
+	}
+
+	return radiov1beta1ac.RtlSdrChannel(channel.Name, channel.Namespace).
+		WithStatus(status)
+}
+
+// channelFrequency returns the center frequency of the channel, or nil if
+// the receiver has no frequency set.
+func channelFrequency(receiver *radiov1beta1.RtlSdrReceiver, channel *radiov1beta1.RtlSdrChannel) *resource.Quantity {
+	if receiver.Spec.Frequency == nil {
+		return nil
+	}
+
+	return resource.NewQuantity(receiver.Spec.Frequency.Value()+channel.Spec.Offset.Value(), resource.DecimalSI)
+}
@@ -0,0 +1,204 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	radiov1beta1 "github.com/frelon/k8s-radio/api/v1beta1"
+)
+
+// RtlSdrChannelReconciler reconciles a RtlSdrChannel object
+type RtlSdrChannelReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+// +kubebuilder:rbac:groups=radio.frelon.se,resources=rtlsdrchannels,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=radio.frelon.se,resources=rtlsdrchannels/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=radio.frelon.se,resources=rtlsdrchannels/finalizers,verbs=update
+// +kubebuilder:rbac:groups="",resources=services,verbs=get;list;watch;create;update;patch;delete
+
+// Reconcile validates that the channel fits inside the passband of its
+// receiver and exposes the channel stream served by the receiver proxy. The
+// proxy itself is configured by the receiver controller.
+func (r *RtlSdrChannelReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx).WithValues("name", req.String())
+	logger.Info("Reconciling RtlSdrChannel")
+
+	channel := &radiov1beta1.RtlSdrChannel{}
+	if err := r.Get(ctx, req.NamespacedName, channel); err != nil {
+		if apierrors.IsNotFound(err) {
+			logger.Info("Object was not found, not an error")
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, fmt.Errorf("failed to get channel object: %w", err)
+	}
+
+	receiver := &radiov1beta1.RtlSdrReceiver{}
+	err := r.Get(ctx, types.NamespacedName{Name: channel.Spec.Receiver, Namespace: channel.Namespace}, receiver)
+	switch {
+	case apierrors.IsNotFound(err):
+		channel.Status.State = radiov1beta1.StateWaiting
+		channel.Status.Endpoint = ""
+		meta.SetStatusCondition(&channel.Status.Conditions, metav1.Condition{
+			Type:    radiov1beta1.ReadyCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  radiov1beta1.ReceiverNotFoundReason,
+			Message: fmt.Sprintf("Receiver %s does not exist", channel.Spec.Receiver),
+		})
+		return reconcile.Result{}, r.applyStatus(ctx, channel)
+	case err != nil:
+		return reconcile.Result{}, err
+	}
+
+	channels, err := receiverChannels(ctx, r.Client, receiver)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	port := channelPorts(channels)[channel.Name]
+	config, err := planChannel(receiver, channel, port)
+	if err != nil {
+		logger.Info("Channel does not fit the receiver", "reason", err.Error())
+		channel.Status.State = radiov1beta1.StateFailed
+		channel.Status.Endpoint = ""
+		meta.SetStatusCondition(&channel.Status.Conditions, metav1.Condition{
+			Type:    radiov1beta1.ReadyCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  radiov1beta1.OutsidePassbandReason,
+			Message: err.Error(),
+		})
+		return reconcile.Result{}, r.applyStatus(ctx, channel)
+	}
+
+	if err := r.Apply(ctx, channelService(receiver, channel, port), client.FieldOwner(FieldManager), client.ForceOwnership); err != nil {
+		logger.Error(err, "Error applying service")
+		return reconcile.Result{}, err
+	}
+
+	channel.Status.State = receiver.Status.State
+	if channel.Status.State == "" {
+		channel.Status.State = radiov1beta1.StateWaiting
+	}
+	channel.Status.Endpoint = channelEndpoint(channel)
+	channel.Status.Frequency = channelFrequency(receiver, channel)
+	channel.Status.SampleRate = resource.NewQuantity(sampleRate(receiver)/int64(config.Decimation), resource.DecimalSI)
+
+	ready := metav1.Condition{
+		Type:    radiov1beta1.ReadyCondition,
+		Status:  metav1.ConditionTrue,
+		Reason:  string(radiov1beta1.StateRunning),
+		Message: "Channel is streaming",
+	}
+	if channel.Status.State != radiov1beta1.StateRunning {
+		ready.Status = metav1.ConditionFalse
+		ready.Reason = string(channel.Status.State)
+		ready.Message = fmt.Sprintf("Receiver %s is %s", receiver.Name, channel.Status.State)
+	}
+	meta.SetStatusCondition(&channel.Status.Conditions, ready)
+
+	logger.Info("Updating status")
+	if err := r.applyStatus(ctx, channel); err != nil {
+		logger.Error(err, "Error updating RtlSdrChannel status")
+		return reconcile.Result{}, err
+	}
+
+	return reconcile.Result{}, nil
+}
+
+// applyStatus applies the status of the channel.
+func (r *RtlSdrChannelReconciler) applyStatus(ctx context.Context, channel *radiov1beta1.RtlSdrChannel) error {
+	return r.Status().Apply(ctx, channelStatusApplyConfiguration(channel), client.FieldOwner(FieldManager), client.ForceOwnership)
+}
+
+// receiverChannels returns the channels split from the receiver.
+func receiverChannels(ctx context.Context, c client.Reader, receiver *radiov1beta1.RtlSdrReceiver) ([]radiov1beta1.RtlSdrChannel, error) {
+	list := &radiov1beta1.RtlSdrChannelList{}
+	if err := c.List(ctx, list, client.InNamespace(receiver.Namespace)); err != nil {
+		return nil, err
+	}
+
+	var channels []radiov1beta1.RtlSdrChannel
+	for _, channel := range list.Items {
+		if channel.Spec.Receiver == receiver.Name && channel.DeletionTimestamp.IsZero() {
+			channels = append(channels, channel)
+		}
+	}
+
+	return channels, nil
+}
+
+// channelReceiver maps a channel to the receiver it is split from.
+func channelReceiver(_ context.Context, obj client.Object) []reconcile.Request {
+	channel, ok := obj.(*radiov1beta1.RtlSdrChannel)
+	if !ok {
+		return nil
+	}
+
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{
+		Name:      channel.Spec.Receiver,
+		Namespace: channel.Namespace,
+	}}}
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *RtlSdrChannelReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	// A channel depends on its receiver, and on the other channels of the
+	// receiver for its port, so reconcile all of them when one changes.
+	receiverChannelRequests := func(ctx context.Context, receiver *radiov1beta1.RtlSdrReceiver) []reconcile.Request {
+		channels, err := receiverChannels(ctx, mgr.GetClient(), receiver)
+		if err != nil {
+			log.FromContext(ctx).Error(err, "Failed listing channels")
+			return nil
+		}
+
+		requests := make([]reconcile.Request, 0, len(channels))
+		for _, channel := range channels {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&channel)})
+		}
+		return requests
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&radiov1beta1.RtlSdrChannel{}).
+		Owns(&corev1.Service{}).
+		Watches(&radiov1beta1.RtlSdrReceiver{}, handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, obj client.Object) []reconcile.Request {
+			return receiverChannelRequests(ctx, obj.(*radiov1beta1.RtlSdrReceiver))
+		})).
+		Watches(&radiov1beta1.RtlSdrChannel{}, handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, obj client.Object) []reconcile.Request {
+			channel := obj.(*radiov1beta1.RtlSdrChannel)
+			return receiverChannelRequests(ctx, &radiov1beta1.RtlSdrReceiver{
+				ObjectMeta: metav1.ObjectMeta{Name: channel.Spec.Receiver, Namespace: channel.Namespace},
+			})
+		})).
+		Complete(r)
+}
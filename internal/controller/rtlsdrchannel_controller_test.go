@@ -0,0 +1,134 @@
+package controller
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	radiov1 "github.com/frelon/k8s-radio/api/v1beta1"
+	"github.com/frelon/k8s-radio/pkg/rtlmux"
+)
+
+var _ = Describe("RtlSdrChannel controller", func() {
+	const namespace = "default"
+
+	It("Should split channels that fit inside the passband", func(ctx SpecContext) {
+		By("By creating a receiver with two channels")
+
+		freq := resource.MustParse("100M")
+		rate := resource.MustParse("2.4M")
+		recv := &radiov1.RtlSdrReceiver{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-channelized-receiver",
+				Namespace: namespace,
+			},
+			Spec: radiov1.RtlSdrReceiverSpec{
+				Version:    radiov1.V4,
+				Frequency:  &freq,
+				SampleRate: &rate,
+			},
+		}
+		Expect(k8sClient.Create(ctx, recv)).Should(Succeed())
+
+		inside := &radiov1.RtlSdrChannel{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-channel-inside",
+				Namespace: namespace,
+			},
+			Spec: radiov1.RtlSdrChannelSpec{
+				Receiver:  recv.Name,
+				Offset:    resource.MustParse("-200k"),
+				Bandwidth: resource.MustParse("100k"),
+			},
+		}
+		Expect(k8sClient.Create(ctx, inside)).Should(Succeed())
+
+		outside := &radiov1.RtlSdrChannel{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-channel-outside",
+				Namespace: namespace,
+			},
+			Spec: radiov1.RtlSdrChannelSpec{
+				Receiver:  recv.Name,
+				Offset:    resource.MustParse("1.2M"),
+				Bandwidth: resource.MustParse("100k"),
+			},
+		}
+		Expect(k8sClient.Create(ctx, outside)).Should(Succeed())
+
+		By("By running the receiver reconciler")
+		receiverReconciler := RtlSdrReceiverReconciler{
+			Client:   k8sClient,
+			Scheme:   scheme.Scheme,
+			Image:    "test-image",
+			MuxImage: "test-mux-image",
+		}
+		receiverKey := types.NamespacedName{Name: recv.Name, Namespace: namespace}
+		_, err := receiverReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: receiverKey})
+		Expect(err).To(Succeed())
+
+		By("By checking the proxy serves only the channel inside the passband")
+		pod := &corev1.Pod{}
+		Expect(k8sClient.Get(ctx, receiverKey, pod)).To(Succeed())
+		Expect(pod.Spec.Containers).To(HaveLen(2))
+		Expect(pod.Spec.Containers[0].Args).To(ContainElements("-a", "127.0.0.1", "-s", "2400000"))
+		Expect(pod.Spec.Containers[1].Args).To(ContainElements(
+			"--lock-policy", "ReadOnly",
+			"--max-clients", "1",
+			"--channels", "/etc/k8s-radio/channels/channels.json",
+		))
+
+		configMap := &corev1.ConfigMap{}
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Name: recv.Name + "-channels", Namespace: namespace}, configMap)).To(Succeed())
+
+		var config rtlmux.Config
+		Expect(json.Unmarshal([]byte(configMap.Data["channels.json"]), &config)).To(Succeed())
+		Expect(config.SampleRate).To(BeNumerically("==", 2_400_000))
+		Expect(config.Channels).To(Equal([]rtlmux.ChannelConfig{{
+			Name:       inside.Name,
+			Port:       20000,
+			Offset:     -200_000,
+			Bandwidth:  100_000,
+			Decimation: 12,
+		}}))
+
+		By("By running the channel reconciler")
+		channelReconciler := RtlSdrChannelReconciler{
+			Client: k8sClient,
+			Scheme: scheme.Scheme,
+		}
+
+		insideKey := types.NamespacedName{Name: inside.Name, Namespace: namespace}
+		_, err = channelReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: insideKey})
+		Expect(err).To(Succeed())
+
+		service := &corev1.Service{}
+		Expect(k8sClient.Get(ctx, insideKey, service)).To(Succeed())
+		Expect(service.Spec.Selector).To(Equal(receiverLabels(recv)))
+		Expect(service.Spec.Ports[0].TargetPort.IntVal).To(Equal(int32(20000)))
+
+		Expect(k8sClient.Get(ctx, insideKey, inside)).To(Succeed())
+		Expect(inside.Status.Endpoint).To(Equal("tcp://test-channel-inside.default.svc:1234"))
+		Expect(inside.Status.Frequency.String()).To(Equal("99800k"))
+		Expect(inside.Status.SampleRate.String()).To(Equal("200k"))
+
+		By("By checking the channel outside the passband failed")
+		outsideKey := types.NamespacedName{Name: outside.Name, Namespace: namespace}
+		_, err = channelReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: outsideKey})
+		Expect(err).To(Succeed())
+
+		Expect(k8sClient.Get(ctx, outsideKey, outside)).To(Succeed())
+		Expect(outside.Status.State).To(Equal(radiov1.StateFailed))
+		ready := meta.FindStatusCondition(outside.Status.Conditions, radiov1.ReadyCondition)
+		Expect(ready).ToNot(BeNil())
+		Expect(ready.Reason).To(Equal(radiov1.OutsidePassbandReason))
+	})
+})
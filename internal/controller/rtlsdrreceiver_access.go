@@ -0,0 +1,335 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1ac "k8s.io/client-go/applyconfigurations/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	radiov1beta1 "github.com/frelon/k8s-radio/api/v1beta1"
+	"github.com/frelon/k8s-radio/pkg/pki"
+)
+
+const (
+	// accessUpstreamPort is the port the stream is served on behind the
+	// access proxy. It only listens on the loopback interface.
+	accessUpstreamPort = 9183
+
+	// CACertKey is the key of the CA certificate in the Secrets and
+	// ConfigMap of a guarded receiver.
+	CACertKey = "ca.crt"
+
+	accessVolume    = "access-tls"
+	accessMountPath = "/tls"
+	tokensVolume    = "access-tokens"
+	tokensMountPath = "/tokens"
+)
+
+// accessEnabled reports whether the stream of the receiver is guarded by
+// the access proxy.
+func accessEnabled(receiver *radiov1beta1.RtlSdrReceiver) bool {
+	return receiver.Spec.Access != nil
+}
+
+// accessAuthentication returns how the clients of a guarded receiver
+// authenticate.
+func accessAuthentication(receiver *radiov1beta1.RtlSdrReceiver) radiov1beta1.RtlSdrAuthentication {
+	if auth := receiver.Spec.Access.Authentication; auth != "" {
+		return auth
+	}
+
+	return radiov1beta1.AuthenticationClientCertificate
+}
+
+// accessCASecretName returns the name of the Secret holding the CA of the
+// receiver and its private key, which only the controller uses.
+func accessCASecretName(receiver *radiov1beta1.RtlSdrReceiver) string {
+	return receiver.Name + "-access-ca-key"
+}
+
+// accessCAConfigMapName returns the name of the ConfigMap publishing the CA
+// certificate of the receiver to its clients.
+func accessCAConfigMapName(receiver *radiov1beta1.RtlSdrReceiver) string {
+	return receiver.Name + "-access-ca"
+}
+
+// accessServerSecretName returns the name of the Secret holding the
+// certificate the stream is served with.
+func accessServerSecretName(receiver *radiov1beta1.RtlSdrReceiver) string {
+	return receiver.Name + "-access-tls"
+}
+
+// accessClientSecretName returns the name of the Secret holding the client
+// certificate of the receiver.
+func accessClientSecretName(receiver *radiov1beta1.RtlSdrReceiver) string {
+	return receiver.Name + "-access-client"
+}
+
+// accessHosts returns the names the certificate of the stream is valid for:
+// those of the receiver Service, and any additional hosts.
+func accessHosts(receiver *radiov1beta1.RtlSdrReceiver) []string {
+	service := receiver.Name + "." + receiver.Namespace
+
+	return append([]string{
+		receiver.Name,
+		service,
+		service + ".svc",
+		service + ".svc.cluster.local",
+	}, receiver.Spec.Access.Hosts...)
+}
+
+// accessAudiences returns the audiences reviewed tokens must be issued for.
+func accessAudiences(receiver *radiov1beta1.RtlSdrReceiver) []string {
+	if audiences := receiver.Spec.Access.Audiences; len(audiences) > 0 {
+		return audiences
+	}
+
+	return []string{receiver.Name + "." + receiver.Namespace + ".svc"}
+}
+
+// streamAddress returns the host and port the container serving the stream
+// listens on. Behind the access proxy it only listens on the loopback
+// interface.
+func streamAddress(receiver *radiov1beta1.RtlSdrReceiver) (string, int32) {
+	if accessEnabled(receiver) {
+		return "127.0.0.1", accessUpstreamPort
+	}
+
+	return "", listenPort(receiver)
+}
+
+// streamListenAddress returns the address the container serving the stream
+// listens on.
+func streamListenAddress(receiver *radiov1beta1.RtlSdrReceiver) string {
+	host, port := streamAddress(receiver)
+
+	return fmt.Sprintf("%s:%d", host, port)
+}
+
+// accessContainer returns the sidecar serving the stream over TLS to the
+// clients it authenticates.
+func (r *RtlSdrReceiverReconciler) accessContainer(receiver *radiov1beta1.RtlSdrReceiver) *corev1ac.ContainerApplyConfiguration {
+	args := []string{
+		"--listen", fmt.Sprintf(":%d", listenPort(receiver)),
+		"--upstream", streamListenAddress(receiver),
+		"--tls-cert", path.Join(accessMountPath, corev1.TLSCertKey),
+		"--tls-key", path.Join(accessMountPath, corev1.TLSPrivateKeyKey),
+	}
+	if receiver.Spec.Mode == radiov1beta1.ModeFM {
+		args = append(args, "--http")
+	}
+
+	container := corev1ac.Container().
+		WithName("access").
+		WithImage(r.AccessImage).
+		WithCommand("/rtl-access").
+		WithVolumeMounts(corev1ac.VolumeMount().
+			WithName(accessVolume).
+			WithMountPath(accessMountPath).
+			WithReadOnly(true))
+
+	switch accessAuthentication(receiver) {
+	case radiov1beta1.AuthenticationToken:
+		args = append(args, "--tokens", tokensMountPath)
+		container.WithVolumeMounts(corev1ac.VolumeMount().
+			WithName(tokensVolume).
+			WithMountPath(tokensMountPath).
+			WithReadOnly(true))
+	case radiov1beta1.AuthenticationTokenReview:
+		args = append(args, "--token-review", "--audiences", strings.Join(accessAudiences(receiver), ","))
+	default:
+		args = append(args, "--client-ca", path.Join(accessMountPath, CACertKey))
+	}
+
+	return container.WithArgs(args...)
+}
+
+// withAccess adds the volumes of the access proxy to the pod spec, and runs
+// it as the service account allowed to review tokens.
+func withAccess(receiver *radiov1beta1.RtlSdrReceiver, spec *corev1ac.PodSpecApplyConfiguration) {
+	spec.WithVolumes(corev1ac.Volume().
+		WithName(accessVolume).
+		WithSecret(corev1ac.SecretVolumeSource().
+			WithSecretName(accessServerSecretName(receiver))))
+
+	switch accessAuthentication(receiver) {
+	case radiov1beta1.AuthenticationToken:
+		spec.WithVolumes(corev1ac.Volume().
+			WithName(tokensVolume).
+			WithSecret(corev1ac.SecretVolumeSource().
+				WithSecretName(receiver.Spec.Access.TokenSecretName)))
+	case radiov1beta1.AuthenticationTokenReview:
+		spec.WithServiceAccountName(receiver.Spec.Access.ServiceAccountName)
+	}
+}
+
+// reconcileAccess issues the certificates guarding the stream of the
+// receiver and returns when they have to be renewed, or deletes them when
+// the stream is not guarded. Certificates are only issued again once they
+// are due for renewal, or no longer match the CA or the receiver.
+func (r *RtlSdrReceiverReconciler) reconcileAccess(ctx context.Context, receiver *radiov1beta1.RtlSdrReceiver) (time.Duration, error) {
+	if !accessEnabled(receiver) {
+		receiver.Status.Access = nil
+		for _, obj := range []struct {
+			name string
+			obj  client.Object
+		}{
+			{accessCASecretName(receiver), &corev1.Secret{}},
+			{accessCAConfigMapName(receiver), &corev1.ConfigMap{}},
+			{accessServerSecretName(receiver), &corev1.Secret{}},
+			{accessClientSecretName(receiver), &corev1.Secret{}},
+		} {
+			if err := r.deleteOwnedNamed(ctx, receiver, obj.name, obj.obj); err != nil {
+				return 0, err
+			}
+		}
+		return 0, nil
+	}
+
+	now := r.now()
+	ca, err := r.reconcileAccessCA(ctx, receiver, now)
+	if err != nil {
+		return 0, err
+	}
+
+	server, err := r.reconcileCertificate(ctx, receiver, ca, accessServerSecretName(receiver), pki.Request{
+		CommonName: receiver.Name + "." + receiver.Namespace + ".svc",
+		Hosts:      accessHosts(receiver),
+	}, now)
+	if err != nil {
+		return 0, err
+	}
+
+	renewal := minTime(pki.RenewalTime(ca.Cert), pki.RenewalTime(server.Cert))
+	status := &radiov1beta1.RtlSdrAccessStatus{
+		CAConfigMapName: accessCAConfigMapName(receiver),
+		NotAfter:        &metav1.Time{Time: server.Cert.NotAfter},
+	}
+
+	if accessAuthentication(receiver) == radiov1beta1.AuthenticationClientCertificate {
+		client, err := r.reconcileCertificate(ctx, receiver, ca, accessClientSecretName(receiver), pki.Request{
+			CommonName: receiver.Namespace + "/" + receiver.Name,
+			Client:     true,
+		}, now)
+		if err != nil {
+			return 0, err
+		}
+		renewal = minTime(renewal, pki.RenewalTime(client.Cert))
+		status.ClientSecretName = accessClientSecretName(receiver)
+	} else if err := r.deleteOwnedNamed(ctx, receiver, accessClientSecretName(receiver), &corev1.Secret{}); err != nil {
+		return 0, err
+	}
+
+	configMap := corev1ac.ConfigMap(accessCAConfigMapName(receiver), receiver.Namespace).
+		WithLabels(receiverLabels(receiver)).
+		WithOwnerReferences(ownerReference(receiver)).
+		WithData(map[string]string{CACertKey: string(ca.CertPEM)})
+	if err := r.Apply(ctx, configMap, client.FieldOwner(FieldManager), client.ForceOwnership); err != nil {
+		return 0, err
+	}
+
+	receiver.Status.Access = status
+
+	return max(renewal.Sub(now), time.Second), nil
+}
+
+// reconcileAccessCA returns the CA of the receiver, creating a new one when
+// there is none or it is due for renewal.
+func (r *RtlSdrReceiverReconciler) reconcileAccessCA(ctx context.Context, receiver *radiov1beta1.RtlSdrReceiver, now time.Time) (*pki.KeyPair, error) {
+	name := accessCASecretName(receiver)
+	secret := &corev1.Secret{}
+	err := r.Get(ctx, client.ObjectKey{Namespace: receiver.Namespace, Name: name}, secret)
+	switch {
+	case err == nil:
+		ca, err := pki.Parse(secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey])
+		if err == nil && ca.Cert.IsCA && pki.Valid(ca.Cert, ca, nil, now) {
+			return ca, nil
+		}
+	case !apierrors.IsNotFound(err):
+		return nil, err
+	}
+
+	ca, err := pki.NewCA(receiver.Namespace+"/"+receiver.Name, now, pki.CAValidity)
+	if err != nil {
+		return nil, err
+	}
+
+	return ca, r.applyTLSSecret(ctx, receiver, name, ca, nil)
+}
+
+// reconcileCertificate returns the certificate stored in the named Secret,
+// issuing a new one from the CA when there is none or it no longer matches
+// req.
+func (r *RtlSdrReceiverReconciler) reconcileCertificate(ctx context.Context, receiver *radiov1beta1.RtlSdrReceiver, ca *pki.KeyPair, name string, req pki.Request, now time.Time) (*pki.KeyPair, error) {
+	secret := &corev1.Secret{}
+	err := r.Get(ctx, client.ObjectKey{Namespace: receiver.Namespace, Name: name}, secret)
+	switch {
+	case err == nil:
+		cert, err := pki.Parse(secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey])
+		if err == nil && bytes.Equal(secret.Data[CACertKey], ca.CertPEM) && pki.Valid(cert.Cert, ca, req.Hosts, now) {
+			return cert, nil
+		}
+	case !apierrors.IsNotFound(err):
+		return nil, err
+	}
+
+	cert, err := ca.Issue(req, now, pki.CertificateValidity)
+	if err != nil {
+		return nil, err
+	}
+
+	return cert, r.applyTLSSecret(ctx, receiver, name, cert, ca)
+}
+
+// applyTLSSecret stores a certificate and its key in the named Secret,
+// together with the certificate of the CA that issued it, if any.
+func (r *RtlSdrReceiverReconciler) applyTLSSecret(ctx context.Context, receiver *radiov1beta1.RtlSdrReceiver, name string, cert, ca *pki.KeyPair) error {
+	data := map[string][]byte{
+		corev1.TLSCertKey:       cert.CertPEM,
+		corev1.TLSPrivateKeyKey: cert.KeyPEM,
+	}
+	if ca != nil {
+		data[CACertKey] = ca.CertPEM
+	}
+
+	secret := corev1ac.Secret(name, receiver.Namespace).
+		WithLabels(receiverLabels(receiver)).
+		WithOwnerReferences(ownerReference(receiver)).
+		WithType(corev1.SecretTypeTLS).
+		WithData(data)
+
+	return r.Apply(ctx, secret, client.FieldOwner(FieldManager), client.ForceOwnership)
+}
+
+// minTime returns the earlier of a and b.
+func minTime(a, b time.Time) time.Time {
+	if b.Before(a) {
+		return b
+	}
+
+	return a
+}
@@ -0,0 +1,188 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	corev1ac "k8s.io/client-go/applyconfigurations/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	radiov1beta1 "github.com/frelon/k8s-radio/api/v1beta1"
+	"github.com/frelon/k8s-radio/pkg/adsb"
+)
+
+const (
+	// ADSBPort is the default port the aircraft of receivers in ADSB mode
+	// are served on as JSON.
+	ADSBPort = 8080
+	// SBSPort and BeastPort are the ports the decoded messages are served
+	// on in the SBS-1 (BaseStation) and Beast formats.
+	SBSPort   = 30003
+	BeastPort = 30005
+
+	// DefaultADSBFrequency is the frequency of Mode-S transponders.
+	DefaultADSBFrequency = 1_090_000_000
+
+	// ADSBPath is the HTTP path the aircraft are served on.
+	ADSBPath = "/data/" + adsb.AircraftFile
+
+	adsbVolume    = "adsb"
+	adsbMountPath = "/run/adsb"
+
+	// adsbPollInterval is the time between updates of the ADS-B status.
+	adsbPollInterval = 30 * time.Second
+)
+
+// adsbFrequency returns the frequency the decoder listens on in Hz.
+func adsbFrequency(receiver *radiov1beta1.RtlSdrReceiver) int64 {
+	if receiver.Spec.Frequency != nil {
+		return receiver.Spec.Frequency.Value()
+	}
+
+	return DefaultADSBFrequency
+}
+
+// adsbContainer returns the container decoding Mode-S messages with readsb,
+// wrapped by the server reporting the aircraft it tracks.
+func (r *RtlSdrReceiverReconciler) adsbContainer(receiver *radiov1beta1.RtlSdrReceiver) *corev1ac.ContainerApplyConfiguration {
+	args := []string{
+		"--listen", fmt.Sprintf(":%d", listenPort(receiver)),
+		"--json-dir", adsbMountPath,
+		"--", "readsb",
+		"--device-type", "rtlsdr",
+		"--freq", strconv.FormatInt(adsbFrequency(receiver), 10),
+		"--net",
+		"--net-sbs-port", strconv.Itoa(SBSPort),
+		"--net-bo-port", strconv.Itoa(BeastPort),
+		"--write-json", adsbMountPath,
+		"--write-json-every", "1",
+		"--quiet",
+	}
+	if receiver.Spec.Gain != nil {
+		args = append(args, "--gain", gainDB(receiver))
+	}
+
+	return corev1ac.Container().
+		WithName("receiver").
+		WithImage(r.ADSBImage).
+		WithCommand("/usr/local/bin/adsb-server").
+		WithArgs(args...).
+		WithPorts(
+			corev1ac.ContainerPort().
+				WithName("sbs").
+				WithContainerPort(SBSPort).
+				WithProtocol(corev1.ProtocolTCP),
+			corev1ac.ContainerPort().
+				WithName("beast").
+				WithContainerPort(BeastPort).
+				WithProtocol(corev1.ProtocolTCP)).
+		WithVolumeMounts(corev1ac.VolumeMount().
+			WithName(adsbVolume).
+			WithMountPath(adsbMountPath))
+}
+
+// adsbVolumeSource returns the scratch volume the decoder writes the
+// aircraft to.
+func adsbVolumeSource() *corev1ac.VolumeApplyConfiguration {
+	return corev1ac.Volume().
+		WithName(adsbVolume).
+		WithEmptyDir(corev1ac.EmptyDirVolumeSource().
+			WithMedium(corev1.StorageMediumMemory))
+}
+
+// adsbServicePorts returns the Service ports of the decoded messages.
+func adsbServicePorts() []*corev1ac.ServicePortApplyConfiguration {
+	return []*corev1ac.ServicePortApplyConfiguration{
+		corev1ac.ServicePort().
+			WithName("sbs").
+			WithProtocol(corev1.ProtocolTCP).
+			WithPort(SBSPort).
+			WithTargetPort(intstr.FromString("sbs")),
+		corev1ac.ServicePort().
+			WithName("beast").
+			WithProtocol(corev1.ProtocolTCP).
+			WithPort(BeastPort).
+			WithTargetPort(intstr.FromString("beast")),
+	}
+}
+
+// reconcileADSB updates the ADS-B status of a running receiver from its pod
+// and returns when to check it again, or zero if there is nothing to follow.
+func (r *RtlSdrReceiverReconciler) reconcileADSB(ctx context.Context, receiver *radiov1beta1.RtlSdrReceiver, pod *corev1.Pod) time.Duration {
+	if receiver.Spec.Mode != radiov1beta1.ModeADSB {
+		receiver.Status.ADSB = nil
+		return 0
+	}
+
+	if receiver.Status.State != radiov1beta1.StateRunning {
+		return 0
+	}
+
+	if pod == nil {
+		return adsbPollInterval
+	}
+
+	fetch := r.ADSBStatus
+	if fetch == nil {
+		fetch = func(ctx context.Context, pod *corev1.Pod) (*radiov1beta1.RtlSdrADSBStatus, error) {
+			return fetchADSBStatus(ctx, pod, listenPort(receiver))
+		}
+	}
+
+	status, err := fetch(ctx, pod)
+	if err != nil {
+		log.FromContext(ctx).Info("Failed fetching ADS-B status", "pod", pod.Name, "error", err)
+		return adsbPollInterval
+	}
+
+	receiver.Status.ADSB = status
+
+	return adsbPollInterval
+}
+
+// fetchADSBStatus asks the server running in the pod for the traffic heard
+// by the decoder.
+func fetchADSBStatus(ctx context.Context, pod *corev1.Pod, port int32) (*radiov1beta1.RtlSdrADSBStatus, error) {
+	stats := &adsb.Stats{}
+	if err := fetchPodStatus(ctx, pod, port, "/stats", stats); err != nil {
+		return nil, err
+	}
+
+	return adsbStatus(stats), nil
+}
+
+// adsbStatus converts the stats of the decoder to the receiver status.
+func adsbStatus(stats *adsb.Stats) *radiov1beta1.RtlSdrADSBStatus {
+	status := &radiov1beta1.RtlSdrADSBStatus{
+		Aircraft:    int32(stats.Aircraft),
+		MessageRate: int32(math.Round(stats.MessageRate)),
+		Messages:    stats.Messages,
+	}
+	if !stats.Time.IsZero() {
+		status.LastUpdateTime = &metav1.Time{Time: stats.Time}
+	}
+
+	return status
+}
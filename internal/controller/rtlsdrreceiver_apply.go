@@ -0,0 +1,671 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	appsv1ac "k8s.io/client-go/applyconfigurations/apps/v1"
+	corev1ac "k8s.io/client-go/applyconfigurations/core/v1"
+	metav1ac "k8s.io/client-go/applyconfigurations/meta/v1"
+	"k8s.io/utils/ptr"
+
+	radiov1beta1 "github.com/frelon/k8s-radio/api/v1beta1"
+	radiov1beta1ac "github.com/frelon/k8s-radio/api/v1beta1/applyconfiguration/api/v1beta1"
+)
+
+const (
+	defaultListenPort = 1234
+	// defaultUpstreamPort is the port rtl_tcp listens on behind the sharing
+	// proxy.
+	defaultUpstreamPort = 1235
+
+	// AudioPort is the default port the audio of FM receivers is served on.
+	AudioPort = 8000
+	// AudioPath is the HTTP path the audio of FM receivers is served on.
+	AudioPath = "/audio.wav"
+
+	replayVolume    = "replay"
+	replayMountPath = "/replay"
+)
+
+// receiverLabels returns the labels identifying the workload of a receiver.
+func receiverLabels(receiver *radiov1beta1.RtlSdrReceiver) map[string]string {
+	return map[string]string{
+		"app.kubernetes.io/name":       "rtlsdrreceiver",
+		"app.kubernetes.io/instance":   receiver.Name,
+		"app.kubernetes.io/managed-by": "k8s-radio",
+	}
+}
+
+// ownerReference returns the controller reference pointing at the receiver.
+func ownerReference(receiver *radiov1beta1.RtlSdrReceiver) *metav1ac.OwnerReferenceApplyConfiguration {
+	return metav1ac.OwnerReference().
+		WithAPIVersion(radiov1beta1.GroupVersion.String()).
+		WithKind("RtlSdrReceiver").
+		WithName(receiver.Name).
+		WithUID(receiver.UID).
+		WithController(true).
+		WithBlockOwnerDeletion(true)
+}
+
+// ownedUnstructured returns an object of a kind the controller has no types
+// for, such as those of optional operators, named after and controlled by the
+// receiver.
+func ownedUnstructured(receiver *radiov1beta1.RtlSdrReceiver, gvk schema.GroupVersionKind, spec map[string]any) *unstructured.Unstructured {
+	labels := map[string]any{}
+	for k, v := range receiverLabels(receiver) {
+		labels[k] = v
+	}
+
+	owner := ownerReference(receiver)
+	u := &unstructured.Unstructured{Object: map[string]any{
+		"metadata": map[string]any{
+			"name":      receiver.Name,
+			"namespace": receiver.Namespace,
+			"labels":    labels,
+			"ownerReferences": []any{map[string]any{
+				"apiVersion":         *owner.APIVersion,
+				"kind":               *owner.Kind,
+				"name":               *owner.Name,
+				"uid":                string(*owner.UID),
+				"controller":         true,
+				"blockOwnerDeletion": true,
+			}},
+		},
+		"spec": spec,
+	}}
+	u.SetGroupVersionKind(gvk)
+
+	return u
+}
+
+// listenPort returns the port the receiver stream is served on.
+func listenPort(receiver *radiov1beta1.RtlSdrReceiver) int32 {
+	if receiver.Spec.ContainerPort != nil {
+		return receiver.Spec.ContainerPort.ContainerPort
+	}
+
+	switch receiver.Spec.Mode {
+	case radiov1beta1.ModeFM:
+		return AudioPort
+	case radiov1beta1.ModeADSB:
+		return ADSBPort
+	case radiov1beta1.ModeAIS, radiov1beta1.ModeISM:
+		return EventsPort
+	}
+
+	return defaultListenPort
+}
+
+// portName returns the name of the Service port for the receiver stream.
+func portName(receiver *radiov1beta1.RtlSdrReceiver) string {
+	switch receiver.Spec.Mode {
+	case radiov1beta1.ModeFM:
+		return "audio"
+	case radiov1beta1.ModeADSB, radiov1beta1.ModeAIS, radiov1beta1.ModeISM:
+		return "http"
+	}
+
+	return "rtl-tcp"
+}
+
+// endpoint returns the in-cluster URL of the receiver stream.
+func endpoint(receiver *radiov1beta1.RtlSdrReceiver) string {
+	host := fmt.Sprintf("%s.%s.svc:%d", receiver.Name, receiver.Namespace, listenPort(receiver))
+
+	switch receiver.Spec.Mode {
+	case radiov1beta1.ModeFM:
+		if accessEnabled(receiver) {
+			return "https://" + host + AudioPath
+		}
+		return "http://" + host + AudioPath
+	case radiov1beta1.ModeADSB:
+		return "http://" + host + ADSBPath
+	case radiov1beta1.ModeAIS, radiov1beta1.ModeISM:
+		return "http://" + host + EventsPath
+	}
+
+	if accessEnabled(receiver) {
+		return "tls://" + host
+	}
+
+	return "tcp://" + host
+}
+
+// containerPort converts the port settings of the receiver spec.
+func containerPort(port *corev1.ContainerPort) *corev1ac.ContainerPortApplyConfiguration {
+	ac := corev1ac.ContainerPort().WithContainerPort(port.ContainerPort)
+	if port.Name != "" {
+		ac.WithName(port.Name)
+	}
+	if port.HostPort != 0 {
+		ac.WithHostPort(port.HostPort)
+	}
+	if port.HostIP != "" {
+		ac.WithHostIP(port.HostIP)
+	}
+	if port.Protocol != "" {
+		ac.WithProtocol(port.Protocol)
+	}
+
+	return ac
+}
+
+// sharingEnabled reports whether the receiver stream is shared through the
+// multiplexing proxy.
+func sharingEnabled(receiver *radiov1beta1.RtlSdrReceiver) bool {
+	return receiver.Spec.Sharing != nil && receiver.Spec.Sharing.Enabled
+}
+
+// proxyEnabled reports whether rtl_tcp is put behind the multiplexing
+// proxy, which is needed to share the stream, split channels from it,
+// record it, scan with it or serve its spectrum.
+func proxyEnabled(receiver *radiov1beta1.RtlSdrReceiver, channels bool) bool {
+	return sharingEnabled(receiver) || channels || proxyStatusEnabled(receiver) || spectrumEnabled(receiver)
+}
+
+// upstreamPort returns the port rtl_tcp listens on behind the sharing proxy.
+func upstreamPort(receiver *radiov1beta1.RtlSdrReceiver) int32 {
+	if listenPort(receiver) == defaultUpstreamPort {
+		return defaultUpstreamPort + 1
+	}
+
+	return defaultUpstreamPort
+}
+
+// rtlTCPArgs returns the rtl_tcp arguments of the receiver. When proxied
+// rtl_tcp is only reachable by the proxy, and when guarded only by the
+// access proxy. Receivers tuned live are started with the defaults of
+// rtl_tcp and tuned once running, so that retuning them does not change
+// their pod.
+func rtlTCPArgs(receiver *radiov1beta1.RtlSdrReceiver, proxied bool) []string {
+	address, port := streamAddress(receiver)
+	if address == "" {
+		address = "0.0.0.0"
+	}
+	if proxied {
+		address, port = "127.0.0.1", upstreamPort(receiver)
+	}
+
+	args := []string{"-a", address}
+	if liveTuningEnabled(receiver) {
+		return append(args, "-p", strconv.Itoa(int(port)))
+	}
+	if receiver.Spec.Frequency != nil {
+		args = append(args, "-f", receiver.Spec.Frequency.String())
+	}
+	if receiver.Spec.SampleRate != nil {
+		args = append(args, "-s", strconv.FormatInt(receiver.Spec.SampleRate.Value(), 10))
+	}
+	if receiver.Spec.Gain != nil {
+		args = append(args, "-g", gainDB(receiver))
+	}
+
+	return append(args, "-p", strconv.Itoa(int(port)))
+}
+
+// gainDB returns the tuner gain of the receiver in dB.
+func gainDB(receiver *radiov1beta1.RtlSdrReceiver) string {
+	return strconv.FormatFloat(receiver.Spec.Gain.AsApproximateFloat64(), 'f', -1, 64)
+}
+
+// receiverContainer returns the container running rtl_tcp.
+func (r *RtlSdrReceiverReconciler) receiverContainer(receiver *radiov1beta1.RtlSdrReceiver, proxied bool) *corev1ac.ContainerApplyConfiguration {
+	return corev1ac.Container().
+		WithName("receiver").
+		WithImage(r.Image).
+		WithCommand("/bin/rtl_tcp").
+		WithArgs(rtlTCPArgs(receiver, proxied)...)
+}
+
+// simulatorContainer returns the container running the fake rtl_tcp server
+// of a simulated receiver. It takes the same arguments as rtl_tcp.
+func (r *RtlSdrReceiverReconciler) simulatorContainer(receiver *radiov1beta1.RtlSdrReceiver, proxied bool) *corev1ac.ContainerApplyConfiguration {
+	container := corev1ac.Container().
+		WithName("receiver").
+		WithImage(r.SimulatorImage).
+		WithCommand("/fake-rtltcp").
+		WithArgs(rtlTCPArgs(receiver, proxied)...)
+
+	simulation := receiver.Spec.Simulation
+	if simulation.Replay != nil {
+		return container.
+			WithArgs("--replay", path.Join(replayMountPath, simulation.Replay.Path)).
+			WithVolumeMounts(corev1ac.VolumeMount().
+				WithName(replayVolume).
+				WithMountPath(replayMountPath).
+				WithReadOnly(true))
+	}
+
+	if simulation.Signal != "" {
+		container.WithArgs("--signal", strings.ToLower(string(simulation.Signal)))
+	}
+	if simulation.SignalFrequency != nil {
+		container.WithArgs("--signal-frequency", simulation.SignalFrequency.String())
+	}
+
+	return container
+}
+
+// muxContainer returns the sidecar sharing the rtl_tcp stream among several
+// clients, splitting channels from it, recording it, scanning with it and
+// serving its spectrum.
+// Channels are tuned relative to the receiver frequency and the scanner
+// retunes the receiver itself, so clients may not retune a receiver with
+// channels or a scan. Without sharing only one client is allowed, like
+// rtl_tcp.
+func (r *RtlSdrReceiverReconciler) muxContainer(receiver *radiov1beta1.RtlSdrReceiver, channels bool) *corev1ac.ContainerApplyConfiguration {
+	args := []string{
+		"--listen", streamListenAddress(receiver),
+		"--upstream", fmt.Sprintf("127.0.0.1:%d", upstreamPort(receiver)),
+	}
+
+	sharing := receiver.Spec.Sharing
+	if !sharingEnabled(receiver) {
+		sharing = &radiov1beta1.RtlSdrSharing{MaxClients: ptr.To[int32](1)}
+	}
+
+	switch {
+	case channels || receiver.Spec.Scan != nil:
+		args = append(args, "--lock-policy", string(radiov1beta1.LockPolicyReadOnly))
+	case sharing.LockPolicy != "":
+		args = append(args, "--lock-policy", string(sharing.LockPolicy))
+	}
+	if sharing.MaxClients != nil {
+		args = append(args, "--max-clients", strconv.Itoa(int(*sharing.MaxClients)))
+	}
+
+	container := corev1ac.Container().
+		WithName("mux").
+		WithImage(r.MuxImage).
+		WithCommand("/rtltcp-mux")
+
+	if channels {
+		args = append(args, "--channels", path.Join(channelsMountPath, channelsFile))
+		container.WithVolumeMounts(corev1ac.VolumeMount().
+			WithName(channelsVolume).
+			WithMountPath(channelsMountPath).
+			WithReadOnly(true))
+	}
+
+	if proxyStatusEnabled(receiver) {
+		args = append(args, proxyStatusArgs(receiver)...)
+		withProxyStatus(container)
+	}
+	if liveTuningEnabled(receiver) {
+		withControl(container)
+	}
+	if proxyStatusEnabled(receiver) || spectrumEnabled(receiver) {
+		args = append(args, proxyTuningArgs(receiver)...)
+	}
+	if receiver.Spec.Recording != nil {
+		args = append(args, recorderArgs(receiver)...)
+		withRecorder(container)
+	}
+	if receiver.Spec.Scan != nil {
+		args = append(args, scanArgs(receiver)...)
+	}
+	if spectrumEnabled(receiver) {
+		args = append(args, spectrumArgs(receiver)...)
+		withSpectrum(container)
+	}
+	if levelEnabled(receiver) {
+		args = append(args, levelArgs(receiver)...)
+	}
+	if metricsEnabled(receiver) {
+		args = append(args, metricsTapArgs()...)
+	}
+
+	return container.WithArgs(args...)
+}
+
+// fmContainer returns the container demodulating a broadcast station with
+// fm-streamer and serving the audio over HTTP.
+func (r *RtlSdrReceiverReconciler) fmContainer(receiver *radiov1beta1.RtlSdrReceiver) *corev1ac.ContainerApplyConfiguration {
+	args := []string{
+		"--listen", streamListenAddress(receiver),
+		"--path", AudioPath,
+		"--", "fm-streamer",
+	}
+	if receiver.Spec.Frequency != nil {
+		args = append(args, "--frequency", receiver.Spec.Frequency.String())
+	}
+
+	return corev1ac.Container().
+		WithName("receiver").
+		WithImage(r.FMImage).
+		WithCommand("/usr/local/bin/audio-server").
+		WithArgs(args...)
+}
+
+// podSpec returns the spec of the pod running the receiver. channels is set
+// when channels are split from the receiver stream.
+func (r *RtlSdrReceiverReconciler) podSpec(receiver *radiov1beta1.RtlSdrReceiver, channels bool) *corev1ac.PodSpecApplyConfiguration {
+	proxied := proxyEnabled(receiver, channels)
+
+	var container *corev1ac.ContainerApplyConfiguration
+	switch {
+	case receiver.Spec.Simulation != nil:
+		container = r.simulatorContainer(receiver, proxied)
+	case receiver.Spec.Mode == radiov1beta1.ModeFM:
+		container = r.fmContainer(receiver)
+	case receiver.Spec.Mode == radiov1beta1.ModeADSB:
+		container = r.adsbContainer(receiver)
+	case decoderMode(receiver):
+		container = r.decoderContainer(receiver)
+	default:
+		container = r.receiverContainer(receiver, proxied)
+	}
+
+	// Simulated receivers don't need a dongle and can run on any node.
+	if receiver.Spec.Simulation == nil {
+		container.WithResources(corev1ac.ResourceRequirements().
+			WithLimits(corev1.ResourceList{
+				poolResource(receiver): *resource.NewQuantity(1, resource.DecimalSI),
+			}))
+	}
+
+	container.WithSecurityContext(restrictedSecurityContext())
+
+	containers := []*corev1ac.ContainerApplyConfiguration{container}
+
+	// The stream is served by the proxy when rtl_tcp is proxied.
+	exposed := container
+	if proxied {
+		exposed = r.muxContainer(receiver, channels).
+			WithSecurityContext(restrictedSecurityContext())
+		containers = append(containers, exposed)
+	}
+	if accessEnabled(receiver) {
+		exposed = r.accessContainer(receiver).
+			WithSecurityContext(restrictedSecurityContext())
+		containers = append(containers, exposed)
+	}
+	if receiver.Spec.MQTT != nil {
+		containers = append(containers, r.mqttContainer(receiver).
+			WithSecurityContext(restrictedSecurityContext()))
+	}
+	if metricsEnabled(receiver) {
+		containers = append(containers, r.exporterContainer(receiver).
+			WithSecurityContext(restrictedSecurityContext()))
+	}
+
+	switch {
+	case receiver.Spec.ContainerPort != nil:
+		exposed.WithPorts(containerPort(receiver.Spec.ContainerPort))
+	case receiver.Spec.Mode == radiov1beta1.ModeFM, receiver.Spec.Mode == radiov1beta1.ModeADSB, decoderMode(receiver):
+		exposed.WithPorts(corev1ac.ContainerPort().
+			WithName(portName(receiver)).
+			WithContainerPort(listenPort(receiver)).
+			WithProtocol(corev1.ProtocolTCP))
+	}
+
+	spec := corev1ac.PodSpec().WithContainers(containers...)
+	if metricsEnabled(receiver) {
+		// Let the exporter find the serial of the dongle in the
+		// environment of rtl_tcp.
+		spec.WithShareProcessNamespace(true)
+	}
+	if accessEnabled(receiver) {
+		withAccess(receiver, spec)
+	}
+	if liveTuningEnabled(receiver) {
+		spec.WithVolumes(controlVolumeSource(receiver))
+	}
+	if sim := receiver.Spec.Simulation; sim != nil && sim.Replay != nil {
+		spec.WithVolumes(corev1ac.Volume().
+			WithName(replayVolume).
+			WithPersistentVolumeClaim(corev1ac.PersistentVolumeClaimVolumeSource().
+				WithClaimName(sim.Replay.ClaimName).
+				WithReadOnly(true)))
+	}
+	if receiver.Spec.Mode == radiov1beta1.ModeADSB {
+		spec.WithVolumes(adsbVolumeSource())
+	}
+	if channels {
+		spec.WithVolumes(corev1ac.Volume().
+			WithName(channelsVolume).
+			WithConfigMap(corev1ac.ConfigMapVolumeSource().
+				WithName(channelsConfigMapName(receiver))))
+	}
+	if recording := receiver.Spec.Recording; recording != nil {
+		spec.WithVolumes(corev1ac.Volume().
+			WithName(recordingsVolume).
+			WithPersistentVolumeClaim(corev1ac.PersistentVolumeClaimVolumeSource().
+				WithClaimName(recording.ClaimName))).
+			// Let the non-root proxy write to the volume.
+			WithSecurityContext(corev1ac.PodSecurityContext().
+				WithFSGroup(65532))
+	}
+	if receiver.Spec.TerminationGracePeriodSeconds != nil {
+		spec.WithTerminationGracePeriodSeconds(*receiver.Spec.TerminationGracePeriodSeconds)
+	}
+	if affinity := poolAffinity(receiver); affinity != nil {
+		spec.WithAffinity(affinity)
+	}
+
+	return spec
+}
+
+// restrictedSecurityContext returns the security context of all receiver
+// containers.
+func restrictedSecurityContext() *corev1ac.SecurityContextApplyConfiguration {
+	return corev1ac.SecurityContext().
+		WithRunAsNonRoot(true).
+		WithReadOnlyRootFilesystem(true).
+		WithRunAsUser(65532)
+}
+
+// pod returns the bare Pod running the receiver.
+func (r *RtlSdrReceiverReconciler) pod(receiver *radiov1beta1.RtlSdrReceiver, channels bool) *corev1ac.PodApplyConfiguration {
+	return corev1ac.Pod(receiver.Name, receiver.Namespace).
+		WithLabels(receiverLabels(receiver)).
+		WithOwnerReferences(ownerReference(receiver)).
+		WithSpec(r.podSpec(receiver, channels))
+}
+
+// deployment returns the single-replica Deployment running the receiver.
+func (r *RtlSdrReceiverReconciler) deployment(receiver *radiov1beta1.RtlSdrReceiver, channels bool) *appsv1ac.DeploymentApplyConfiguration {
+	labels := receiverLabels(receiver)
+
+	return appsv1ac.Deployment(receiver.Name, receiver.Namespace).
+		WithLabels(labels).
+		WithOwnerReferences(ownerReference(receiver)).
+		WithSpec(appsv1ac.DeploymentSpec().
+			WithReplicas(1).
+			WithSelector(metav1ac.LabelSelector().WithMatchLabels(labels)).
+			// The dongle can only be claimed by one pod at a time, so the
+			// old pod has to be gone before a new one can start.
+			WithStrategy(appsv1ac.DeploymentStrategy().
+				WithType(appsv1.RecreateDeploymentStrategyType)).
+			WithTemplate(corev1ac.PodTemplateSpec().
+				WithLabels(labels).
+				WithSpec(r.podSpec(receiver, channels))))
+}
+
+// service returns the Service exposing the receiver stream inside the cluster.
+func (r *RtlSdrReceiverReconciler) service(receiver *radiov1beta1.RtlSdrReceiver) *corev1ac.ServiceApplyConfiguration {
+	port := listenPort(receiver)
+
+	spec := corev1ac.ServiceSpec().
+		WithSelector(receiverLabels(receiver)).
+		WithPorts(corev1ac.ServicePort().
+			WithName(portName(receiver)).
+			WithProtocol(corev1.ProtocolTCP).
+			WithPort(port).
+			WithTargetPort(intstr.FromInt32(port)))
+	if receiver.Spec.Mode == radiov1beta1.ModeADSB {
+		spec.WithPorts(adsbServicePorts()...)
+	}
+	if spectrumEnabled(receiver) {
+		spec.WithPorts(spectrumServicePort())
+	}
+
+	return corev1ac.Service(receiver.Name, receiver.Namespace).
+		WithLabels(receiverLabels(receiver)).
+		WithOwnerReferences(ownerReference(receiver)).
+		WithSpec(spec)
+}
+
+// statusApplyConfiguration converts the status of the receiver into an apply
+// configuration.
+func statusApplyConfiguration(receiver *radiov1beta1.RtlSdrReceiver) *radiov1beta1ac.RtlSdrReceiverApplyConfiguration {
+	status := radiov1beta1ac.RtlSdrReceiverStatus()
+
+	if receiver.Status.State != "" {
+		status.WithState(receiver.Status.State)
+	}
+	if receiver.Status.Endpoint != "" {
+		status.WithEndpoint(receiver.Status.Endpoint)
+	}
+	if receiver.Status.SpectrumURL != "" {
+		status.WithSpectrumURL(receiver.Status.SpectrumURL)
+	}
+	if receiver.Status.Pod != nil {
+		status.WithPod(*receiver.Status.Pod)
+	}
+	if receiver.Status.Deployment != nil {
+		status.WithDeployment(*receiver.Status.Deployment)
+	}
+	if pool := receiver.Status.Pool; pool != nil {
+		ac := radiov1beta1ac.RtlSdrPoolStatus()
+		if pool.Node != "" {
+			ac.WithNode(pool.Node)
+		}
+		if pool.Serial != "" {
+			ac.WithSerial(pool.Serial)
+		}
+		if pool.Position != 0 {
+			ac.WithPosition(pool.Position)
+		}
+		status.WithPool(ac)
+	}
+	if schedule := receiver.Status.Schedule; schedule != nil {
+		ac := radiov1beta1ac.RtlSdrScheduleStatus()
+		if schedule.LastRunTime != nil {
+			ac.WithLastRunTime(*schedule.LastRunTime)
+		}
+		if schedule.ActiveUntil != nil {
+			ac.WithActiveUntil(*schedule.ActiveUntil)
+		}
+		if schedule.NextRunTime != nil {
+			ac.WithNextRunTime(*schedule.NextRunTime)
+		}
+		if schedule.LastMissedTime != nil {
+			ac.WithLastMissedTime(*schedule.LastMissedTime)
+		}
+		status.WithSchedule(ac)
+	}
+	if recording := receiver.Status.Recording; recording != nil {
+		status.WithRecording(radiov1beta1ac.RtlSdrRecordingStatus().
+			WithFiles(recording.Files...).
+			WithBytes(recording.Bytes).
+			WithFinished(recording.Finished))
+	}
+	if adsb := receiver.Status.ADSB; adsb != nil {
+		ac := radiov1beta1ac.RtlSdrADSBStatus().
+			WithAircraft(adsb.Aircraft).
+			WithMessageRate(adsb.MessageRate).
+			WithMessages(adsb.Messages)
+		if adsb.LastUpdateTime != nil {
+			ac.WithLastUpdateTime(*adsb.LastUpdateTime)
+		}
+		status.WithADSB(ac)
+	}
+	if scan := receiver.Status.Scan; scan != nil {
+		ac := radiov1beta1ac.RtlSdrScanStatus()
+		if scan.Frequency != nil {
+			ac.WithFrequency(*scan.Frequency)
+		}
+		for _, hit := range scan.Hits {
+			ac.WithHits(radiov1beta1ac.RtlSdrScanHit().
+				WithFrequency(hit.Frequency).
+				WithPower(hit.Power).
+				WithFirstSeen(hit.FirstSeen).
+				WithLastSeen(hit.LastSeen))
+		}
+		status.WithScan(ac)
+	}
+	if access := receiver.Status.Access; access != nil {
+		ac := radiov1beta1ac.RtlSdrAccessStatus().
+			WithCAConfigMapName(access.CAConfigMapName)
+		if access.ClientSecretName != "" {
+			ac.WithClientSecretName(access.ClientSecretName)
+		}
+		if access.NotAfter != nil {
+			ac.WithNotAfter(*access.NotAfter)
+		}
+		status.WithAccess(ac)
+	}
+	if e := receiver.Status.Expose; e != nil {
+		ac := radiov1beta1ac.RtlSdrExposeStatus()
+		if e.URL != "" {
+			ac.WithURL(e.URL)
+		}
+		if e.StreamURL != "" {
+			ac.WithStreamURL(e.StreamURL)
+		}
+		status.WithExpose(ac)
+	}
+	if l := receiver.Status.SampleLoss; l != nil {
+		status.WithSampleLoss(radiov1beta1ac.RtlSdrSampleLossStatus().
+			WithDroppedSamples(l.DroppedSamples).
+			WithDroppedBuffers(l.DroppedBuffers).
+			WithRatio(l.Ratio))
+	}
+	if receiver.Status.ObservedGeneration != 0 {
+		status.WithObservedGeneration(receiver.Status.ObservedGeneration)
+	}
+	if receiver.Status.ObservedFrequency != nil {
+		status.WithObservedFrequency(*receiver.Status.ObservedFrequency)
+	}
+
+	for _, c := range receiver.Status.Conditions {
+		status.WithConditions(metav1ac.Condition().
+			WithType(c.Type).
+			WithStatus(c.Status).
+			WithObservedGeneration(c.ObservedGeneration).
+			WithLastTransitionTime(c.LastTransitionTime).
+			WithReason(c.Reason).
+			WithMessage(c.Message))
+	}
+
+	return radiov1beta1ac.RtlSdrReceiver(receiver.Name, receiver.Namespace).
+		WithStatus(status)
+}
+
+// objectReference returns a reference to an object returned by an apply.
+func objectReference(kind, apiVersion *string, obj *metav1ac.ObjectMetaApplyConfiguration) *corev1.ObjectReference {
+	return &corev1.ObjectReference{
+		Kind:            ptr.Deref(kind, ""),
+		APIVersion:      ptr.Deref(apiVersion, ""),
+		Name:            ptr.Deref(obj.Name, ""),
+		Namespace:       ptr.Deref(obj.Namespace, ""),
+		UID:             ptr.Deref(obj.UID, ""),
+		ResourceVersion: ptr.Deref(obj.ResourceVersion, ""),
+	}
+}
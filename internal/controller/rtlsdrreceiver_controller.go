@@ -19,27 +19,58 @@ package controller
 import (
 	"context"
 	"fmt"
-	"strconv"
+	"slices"
+	"strings"
+	"time"
 
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
-	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
-	ref "k8s.io/client-go/tools/reference"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/utils/clock"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	radiov1beta1 "github.com/frelon/k8s-radio/api/v1beta1"
+	radiov1beta1ac "github.com/frelon/k8s-radio/api/v1beta1/applyconfiguration/api/v1beta1"
+	"github.com/frelon/k8s-radio/pkg/loss"
+	"github.com/frelon/k8s-radio/pkg/rtlmux"
 )
 
 const (
-	RtlSdrResourceName = "frelon.se/rtl-sdr"
-	RtlSdrDefaultImage = "rtl-sdr:dev"
+	RtlSdrResourceName    = "frelon.se/rtl-sdr"
+	RtlSdrDefaultImage    = "rtl-sdr:dev"
+	FMDefaultImage        = "fm-streamer:dev"
+	SimulatorDefaultImage = "fake-rtltcp:dev"
+	MuxDefaultImage       = "rtltcp-mux:dev"
+	ADSBDefaultImage      = "adsb:dev"
+	DecoderDefaultImage   = "decoders:dev"
+	MQTTDefaultImage      = "rtl-mqtt:dev"
+	ExporterDefaultImage  = "rtl-exporter:dev"
+	AccessDefaultImage    = "rtl-access:dev"
+
+	// FieldManager is the field manager used for all server-side applies
+	// made by the controller.
+	FieldManager = "k8s-radio"
+
+	// ReceiverFinalizer blocks deletion of a receiver until its workload
+	// has shut down and released the dongle.
+	ReceiverFinalizer = "radio.frelon.se/teardown"
+
+	// DefaultDeletionTimeout is how long the teardown may take before the
+	// workload is force deleted and the finalizer removed.
+	DefaultDeletionTimeout = 5 * time.Minute
+
+	teardownPollInterval = 2 * time.Second
 )
 
 // RtlSdrReceiverReconciler reconciles a RtlSdrReceiver object
@@ -48,13 +79,94 @@ type RtlSdrReceiverReconciler struct {
 	Scheme *runtime.Scheme
 
 	Image string
+
+	// FMImage is the image running fm-streamer for receivers in FM mode.
+	FMImage string
+
+	// ADSBImage is the image running readsb for receivers in ADSB mode.
+	ADSBImage string
+
+	// DecoderImage is the image running AIS-catcher and rtl_433 for
+	// receivers in AIS and ISM mode.
+	DecoderImage string
+
+	// MQTTImage is the image running the sidecar publishing receivers to
+	// MQTT.
+	MQTTImage string
+
+	// ExporterImage is the image running the sidecar exporting the signal
+	// of receivers to Prometheus.
+	ExporterImage string
+
+	// AccessImage is the image running the sidecar guarding the stream of
+	// receivers behind TLS and authentication.
+	AccessImage string
+
+	// SimulatorImage is the image running the fake rtl_tcp server for
+	// simulated receivers.
+	SimulatorImage string
+
+	// MuxImage is the image running the proxy that shares the I/Q stream
+	// of receivers with sharing enabled.
+	MuxImage string
+
+	// DeletionTimeout overrides DefaultDeletionTimeout when set.
+	DeletionTimeout time.Duration
+
+	// RecordingStatus fetches the status of the recorder running in a pod,
+	// defaults to asking the proxy over HTTP.
+	RecordingStatus func(ctx context.Context, pod *corev1.Pod) (*radiov1beta1.RtlSdrRecordingStatus, error)
+
+	// ScanStatus fetches the status of the scanner running in a pod,
+	// defaults to asking the proxy over HTTP.
+	ScanStatus func(ctx context.Context, pod *corev1.Pod) (*radiov1beta1.RtlSdrScanStatus, error)
+
+	// ADSBStatus fetches the traffic heard by the decoder running in a pod,
+	// defaults to asking it over HTTP.
+	ADSBStatus func(ctx context.Context, pod *corev1.Pod) (*radiov1beta1.RtlSdrADSBStatus, error)
+
+	// SampleLossStatus fetches the samples dropped by rtl_tcp running in a
+	// pod, defaults to asking the proxy over HTTP.
+	SampleLossStatus func(ctx context.Context, pod *corev1.Pod) (*loss.Stats, error)
+
+	// TuningStatus fetches the tuning of a pod of a receiver tuned live,
+	// authenticated by token, defaults to asking the proxy over HTTP.
+	TuningStatus func(ctx context.Context, pod *corev1.Pod, token string) (*rtlmux.Setting, error)
+
+	// Retune tunes a pod of a receiver tuned live, authenticated by token,
+	// and returns its resulting tuning, defaults to asking the proxy over
+	// HTTP.
+	Retune func(ctx context.Context, pod *corev1.Pod, token string, setting rtlmux.Setting) (*rtlmux.Setting, error)
+
+	// Recorder emits the Events of receivers, none are emitted when nil.
+	Recorder events.EventRecorder
+
+	// ControllerNamespace is the namespace the controller runs in, which the
+	// NetworkPolicies of receivers let fetch their status. Controller pods
+	// in any namespace are let through when empty.
+	ControllerNamespace string
+
+	// Clock is used to follow schedules, defaults to the real clock.
+	Clock clock.PassiveClock
 }
 
-// Reconcile reconsiles the resources.
 // +kubebuilder:rbac:groups=radio.frelon.se,resources=rtlsdrreceivers,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=radio.frelon.se,resources=rtlsdrreceivers/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=radio.frelon.se,resources=rtlsdrreceivers/finalizers,verbs=update
+// +kubebuilder:rbac:groups=radio.frelon.se,resources=rtlsdrreceiverpools,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=services,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=networking.k8s.io,resources=networkpolicies,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=networking.k8s.io,resources=ingresses,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=httproutes;tcproutes,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=gateways,verbs=get;list;watch
+// +kubebuilder:rbac:groups=monitoring.coreos.com,resources=podmonitors,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=events.k8s.io,resources=events,verbs=create;patch
+
+// Reconcile reconsiles the resources.
 func (r *RtlSdrReceiverReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	logger := log.FromContext(ctx).WithValues("name", req.String())
 	logger.Info("Reconciling RtlSdrReceiver")
@@ -68,103 +180,443 @@ func (r *RtlSdrReceiverReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 		return reconcile.Result{}, fmt.Errorf("failed to get seedimage object: %w", err)
 	}
 
-	pod := &corev1.Pod{}
-	if err := r.Get(ctx, req.NamespacedName, pod); err != nil {
-		if !apierrors.IsNotFound(err) {
-			logger.Error(err, "Error getting pod (Not not found), returning.")
+	if !receiver.DeletionTimestamp.IsZero() {
+		return r.finalize(ctx, receiver)
+	}
+
+	if !controllerutil.ContainsFinalizer(receiver, ReceiverFinalizer) {
+		logger.Info("Adding finalizer")
+		if err := r.applyFinalizers(ctx, receiver, ReceiverFinalizer); err != nil {
 			return reconcile.Result{}, err
 		}
+	}
 
-		receiver.Status.State = radiov1beta1.StateWaiting
+	channels, err := r.reconcileChannels(ctx, receiver)
+	if err != nil {
+		logger.Error(err, "Error reconciling channels")
+		return reconcile.Result{}, err
+	}
 
-		logger.Info("Pod not found, creating it...")
+	renew, err := r.reconcileAccess(ctx, receiver)
+	if err != nil {
+		logger.Error(err, "Error reconciling access")
+		return reconcile.Result{}, err
+	}
 
-		err = r.createPod(ctx, receiver)
-		if err != nil {
-			return reconcile.Result{}, err
-		}
-	} else {
-		// Already running, update state based on pod Phase
-		switch pod.Status.Phase {
-		case corev1.PodRunning:
-			receiver.Status.State = radiov1beta1.StateRunning
-		case corev1.PodFailed:
-			receiver.Status.State = radiov1beta1.StateFailed
-		}
+	if err := r.reconcileControlToken(ctx, receiver); err != nil {
+		logger.Error(err, "Error reconciling control token")
+		return reconcile.Result{}, err
+	}
+
+	active, wake := r.reconcileSchedule(ctx, receiver)
+	if !r.validateScan(ctx, receiver) {
+		active = false
+	}
+	if active, err = r.reconcilePool(ctx, receiver, active); err != nil {
+		logger.Error(err, "Error reconciling pool")
+		return reconcile.Result{}, err
 	}
 
-	podRef, err := ref.GetReference(r.Scheme, pod)
+	switch {
+	case !active:
+		err = r.release(ctx, receiver)
+	case receiver.Spec.Workload == radiov1beta1.WorkloadDeployment:
+		err = r.reconcileDeployment(ctx, receiver, channels)
+	default:
+		err = r.reconcilePod(ctx, receiver, channels)
+	}
 	if err != nil {
 		return reconcile.Result{}, err
 	}
 
-	receiver.Status.Pod = podRef
+	if err := r.Apply(ctx, r.service(receiver), client.FieldOwner(FieldManager), client.ForceOwnership); err != nil {
+		logger.Error(err, "Error applying service")
+		return reconcile.Result{}, err
+	}
+
+	if err := r.reconcileNetworkPolicy(ctx, receiver); err != nil {
+		logger.Error(err, "Error reconciling NetworkPolicy")
+		return reconcile.Result{}, err
+	}
+
+	if err := r.reconcilePodMonitor(ctx, receiver); err != nil {
+		logger.Error(err, "Error reconciling PodMonitor")
+		return reconcile.Result{}, err
+	}
+
+	gatewayPoll, err := r.reconcileExpose(ctx, receiver)
+	if err != nil {
+		logger.Error(err, "Error reconciling exposure")
+		return reconcile.Result{}, err
+	}
+
+	receiver.Status.Endpoint = endpoint(receiver)
+	receiver.Status.SpectrumURL = spectrumURL(receiver)
+	requeue := minRequeue(wake, renew, gatewayPoll, r.reconcilePodStatus(ctx, receiver))
 
 	logger.Info("Updating status")
-	if err := r.Status().Update(ctx, receiver); err != nil {
+	if err := r.applyStatus(ctx, receiver); err != nil {
 		logger.Error(err, "Error updating RtlSdrReceiver status")
 		return ctrl.Result{}, err
 	}
 
 	logger.Info("Reconcile successful.")
+	return reconcile.Result{RequeueAfter: requeue}, nil
+}
+
+// applyFinalizers sets the finalizers owned by the controller. Finalizers
+// added by other actors are left untouched.
+func (r *RtlSdrReceiverReconciler) applyFinalizers(ctx context.Context, receiver *radiov1beta1.RtlSdrReceiver, finalizers ...string) error {
+	ac := radiov1beta1ac.RtlSdrReceiver(receiver.Name, receiver.Namespace).
+		WithFinalizers(finalizers...)
+
+	return r.Apply(ctx, ac, client.FieldOwner(FieldManager), client.ForceOwnership)
+}
+
+// applyStatus applies the status of the receiver. Only the fields set in the
+// status are owned by the controller, so a concurrent change to the receiver
+// does not cause a conflict.
+func (r *RtlSdrReceiverReconciler) applyStatus(ctx context.Context, receiver *radiov1beta1.RtlSdrReceiver) error {
+	return r.Status().Apply(ctx, statusApplyConfiguration(receiver), client.FieldOwner(FieldManager), client.ForceOwnership)
+}
+
+// finalize tears down the receiver in order: the Deployment and Pod are
+// deleted with the receiver's grace period, and the finalizer is removed once
+// they are gone. If teardown does not finish within the deletion timeout the
+// pods of the receiver are force deleted and the finalizer removed anyway so
+// the deletion does not get stuck.
+func (r *RtlSdrReceiverReconciler) finalize(ctx context.Context, receiver *radiov1beta1.RtlSdrReceiver) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	if !controllerutil.ContainsFinalizer(receiver, ReceiverFinalizer) {
+		return reconcile.Result{}, nil
+	}
+
+	timeout := r.DeletionTimeout
+	if timeout == 0 {
+		timeout = DefaultDeletionTimeout
+	}
+	force := r.now().Sub(receiver.DeletionTimestamp.Time) > timeout
+
+	if receiver.Status.State != radiov1beta1.StateTerminating {
+		receiver.Status.State = radiov1beta1.StateTerminating
+		meta.SetStatusCondition(&receiver.Status.Conditions, metav1.Condition{
+			Type:    radiov1beta1.ReadyCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  radiov1beta1.TerminatingReason,
+			Message: "Receiver is shutting down",
+		})
+
+		logger.Info("Updating status")
+		if err := r.applyStatus(ctx, receiver); err != nil {
+			logger.Error(err, "Error updating RtlSdrReceiver status")
+			return reconcile.Result{}, err
+		}
+	}
+
+	done, err := r.teardown(ctx, receiver, force)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	if !done {
+		if !force {
+			logger.Info("Waiting for teardown to finish")
+			return reconcile.Result{RequeueAfter: teardownPollInterval}, nil
+		}
+
+		logger.Info("Teardown timed out, removing finalizer", "timeout", timeout.String())
+		meta.SetStatusCondition(&receiver.Status.Conditions, metav1.Condition{
+			Type:    radiov1beta1.ReadyCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  radiov1beta1.TeardownTimeoutReason,
+			Message: fmt.Sprintf("Teardown did not finish within %s", timeout),
+		})
+		if err := r.applyStatus(ctx, receiver); err != nil {
+			logger.Error(err, "Error updating RtlSdrReceiver status")
+			return reconcile.Result{}, err
+		}
+	}
+
+	logger.Info("Removing finalizer")
+	if err := r.applyFinalizers(ctx, receiver); err != nil {
+		return reconcile.Result{}, client.IgnoreNotFound(err)
+	}
+
 	return reconcile.Result{}, nil
 }
 
-func (r *RtlSdrReceiverReconciler) createPod(ctx context.Context, receiver *radiov1beta1.RtlSdrReceiver) error {
-	pod := &corev1.Pod{}
-
-	args := []string{"-a", "0.0.0.0"}
-	if receiver.Spec.Frequency != nil {
-		args = append(args, "-f", receiver.Spec.Frequency.String())
-	}
-
-	listenPort := 1234
-	ports := []corev1.ContainerPort{}
-	if receiver.Spec.ContainerPort != nil {
-		ports = append(ports, *receiver.Spec.ContainerPort)
-		listenPort = int(receiver.Spec.ContainerPort.ContainerPort)
-	}
-
-	args = append(args, "-p", strconv.Itoa(listenPort))
-
-	t := true
-	userID := int64(65532)
-
-	pod.Name = receiver.Name
-	pod.Namespace = receiver.Namespace
-	pod.Spec = corev1.PodSpec{
-		Containers: []corev1.Container{
-			{
-				Name:    "receiver",
-				Image:   r.Image,
-				Command: []string{"/bin/rtl_tcp"},
-				Args:    args,
-				Ports:   ports,
-				Resources: corev1.ResourceRequirements{
-					Limits: corev1.ResourceList{
-						RtlSdrResourceName: *resource.NewQuantity(1, resource.DecimalSI),
-					},
-				},
-				SecurityContext: &corev1.SecurityContext{
-					RunAsNonRoot:           &t,
-					ReadOnlyRootFilesystem: &t,
-					RunAsUser:              &userID,
-				},
-			},
+// teardown runs the teardown steps of a receiver and reports whether all of
+// them have completed. When force is set, the workload and all pods of the
+// receiver are deleted without a grace period.
+func (r *RtlSdrReceiverReconciler) teardown(ctx context.Context, receiver *radiov1beta1.RtlSdrReceiver, force bool) (bool, error) {
+	var deleteOpts []client.DeleteOption
+	if force {
+		// A grace period only applies to the object it is sent with, so
+		// the pods of a Deployment are killed directly.
+		if err := r.killPods(ctx, receiver); err != nil {
+			return false, err
+		}
+		deleteOpts = append(deleteOpts, client.GracePeriodSeconds(0))
+	} else if receiver.Spec.TerminationGracePeriodSeconds != nil {
+		deleteOpts = append(deleteOpts, client.GracePeriodSeconds(*receiver.Spec.TerminationGracePeriodSeconds))
+	}
+
+	steps := []func() (bool, error){
+		func() (bool, error) {
+			// Delete the pods before the Deployment, so it is only gone
+			// once the dongle has been released.
+			opts := append([]client.DeleteOption{client.PropagationPolicy(metav1.DeletePropagationForeground)}, deleteOpts...)
+			return r.deleteAndWait(ctx, receiver, &appsv1.Deployment{}, force, opts...)
 		},
+		func() (bool, error) {
+			return r.deleteAndWait(ctx, receiver, &corev1.Pod{}, force, deleteOpts...)
+		},
+	}
+
+	for _, step := range steps {
+		done, err := step()
+		if err != nil || !done {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+// killPods deletes all pods running the receiver without a grace period,
+// including those owned by the ReplicaSet of its Deployment.
+func (r *RtlSdrReceiverReconciler) killPods(ctx context.Context, receiver *radiov1beta1.RtlSdrReceiver) error {
+	pods := &corev1.PodList{}
+	if err := r.List(ctx, pods, client.InNamespace(receiver.Namespace), client.MatchingLabels(receiverLabels(receiver))); err != nil {
+		return err
 	}
 
-	if err := controllerutil.SetControllerReference(receiver, pod, r.Scheme); err != nil {
+	for i := range pods.Items {
+		if err := r.Delete(ctx, &pods.Items[i], client.GracePeriodSeconds(0)); client.IgnoreNotFound(err) != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// deleteAndWait deletes the object named after the receiver if it is
+// controlled by the receiver and reports whether it is gone. An object that is
+// already being deleted is only deleted again when forced.
+func (r *RtlSdrReceiverReconciler) deleteAndWait(ctx context.Context, receiver *radiov1beta1.RtlSdrReceiver, obj client.Object, force bool, opts ...client.DeleteOption) (bool, error) {
+	if err := r.Get(ctx, client.ObjectKeyFromObject(receiver), obj); err != nil {
+		if apierrors.IsNotFound(err) {
+			return true, nil
+		}
+		return false, err
+	}
+
+	if !metav1.IsControlledBy(obj, receiver) {
+		return true, nil
+	}
+
+	if !obj.GetDeletionTimestamp().IsZero() && !force {
+		return false, nil
+	}
+
+	if err := r.Delete(ctx, obj, opts...); err != nil {
+		return apierrors.IsNotFound(err), client.IgnoreNotFound(err)
+	}
+
+	return false, nil
+}
+
+// reconcileChannels publishes the channels split from the receiver stream to
+// its proxy and reports whether there are any. Channels that don't fit inside
+// the passband of the receiver are left out.
+func (r *RtlSdrReceiverReconciler) reconcileChannels(ctx context.Context, receiver *radiov1beta1.RtlSdrReceiver) (bool, error) {
+	channels, err := receiverChannels(ctx, r.Client, receiver)
+	if err != nil {
+		return false, err
+	}
+
+	ports := channelPorts(channels)
+
+	var configs []rtlmux.ChannelConfig
+	for i := range channels {
+		config, err := planChannel(receiver, &channels[i], ports[channels[i].Name])
+		if err != nil {
+			continue
+		}
+		configs = append(configs, config)
+	}
+
+	if len(configs) == 0 {
+		return false, r.deleteOwnedNamed(ctx, receiver, channelsConfigMapName(receiver), &corev1.ConfigMap{})
+	}
+
+	slices.SortFunc(configs, func(a, b rtlmux.ChannelConfig) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	configMap, err := channelsConfigMap(receiver, configs)
+	if err != nil {
+		return false, err
+	}
+
+	return true, r.Apply(ctx, configMap, client.FieldOwner(FieldManager), client.ForceOwnership)
+}
+
+// release deletes the workload of a receiver outside its scheduled windows
+// or with an invalid spec, freeing the dongle for other workloads.
+func (r *RtlSdrReceiverReconciler) release(ctx context.Context, receiver *radiov1beta1.RtlSdrReceiver) error {
+	if err := r.deleteOwned(ctx, receiver, &corev1.Pod{}); err != nil {
+		return err
+	}
+	if err := r.deleteOwned(ctx, receiver, &appsv1.Deployment{}); err != nil {
+		return err
+	}
+
+	receiver.Status.Pod = nil
+	receiver.Status.Deployment = nil
+
+	return nil
+}
+
+// reconcilePod runs the receiver as a bare Pod named after the receiver.
+func (r *RtlSdrReceiverReconciler) reconcilePod(ctx context.Context, receiver *radiov1beta1.RtlSdrReceiver, channels bool) error {
+	logger := log.FromContext(ctx)
+
+	// The dongle is exclusive, so make sure a Deployment left over from a
+	// previous workload setting is gone before starting the Pod.
+	if err := r.deleteOwned(ctx, receiver, &appsv1.Deployment{}); err != nil {
+		return err
+	}
+
+	pod := r.pod(receiver, channels)
+	if err := r.Apply(ctx, pod, client.FieldOwner(FieldManager), client.ForceOwnership); err != nil {
+		if !apierrors.IsInvalid(err) {
+			logger.Error(err, "Error applying pod")
+			return err
+		}
+
+		// Most of the pod spec is immutable, so a changed receiver spec
+		// means the pod has to be recreated.
+		logger.Info("Pod spec changed, recreating it...")
+		receiver.Status.State = radiov1beta1.StateWaiting
+		receiver.Status.Pod = nil
+		return r.deleteOwned(ctx, receiver, &corev1.Pod{})
+	}
+
+	switch ptr.Deref(pod.Status.Phase, "") {
+	case corev1.PodRunning:
+		receiver.Status.State = radiov1beta1.StateRunning
+	case corev1.PodFailed:
+		receiver.Status.State = radiov1beta1.StateFailed
 		meta.SetStatusCondition(&receiver.Status.Conditions, metav1.Condition{
 			Type:    radiov1beta1.ReadyCondition,
 			Status:  metav1.ConditionFalse,
 			Reason:  radiov1beta1.PodFailedReason,
-			Message: err.Error(),
+			Message: ptr.Deref(pod.Status.Message, ""),
 		})
+	default:
+		receiver.Status.State = radiov1beta1.StateWaiting
+	}
+
+	receiver.Status.Pod = objectReference(pod.GetKind(), pod.GetAPIVersion(), pod.ObjectMetaApplyConfiguration)
+	receiver.Status.Deployment = nil
+
+	return nil
+}
+
+// reconcileDeployment runs the receiver as a single-replica Deployment and
+// derives the receiver state from the Deployment status.
+func (r *RtlSdrReceiverReconciler) reconcileDeployment(ctx context.Context, receiver *radiov1beta1.RtlSdrReceiver, channels bool) error {
+	logger := log.FromContext(ctx)
+
+	if err := r.deleteOwned(ctx, receiver, &corev1.Pod{}); err != nil {
 		return err
 	}
 
-	return r.Create(ctx, pod)
+	deployment := r.deployment(receiver, channels)
+	if err := r.Apply(ctx, deployment, client.FieldOwner(FieldManager), client.ForceOwnership); err != nil {
+		logger.Error(err, "Error applying deployment")
+		return err
+	}
+
+	status := appsv1.DeploymentStatus{}
+	if deployment.Status != nil {
+		status.AvailableReplicas = ptr.Deref(deployment.Status.AvailableReplicas, 0)
+		for _, c := range deployment.Status.Conditions {
+			status.Conditions = append(status.Conditions, appsv1.DeploymentCondition{
+				Type:    ptr.Deref(c.Type, ""),
+				Status:  ptr.Deref(c.Status, ""),
+				Reason:  ptr.Deref(c.Reason, ""),
+				Message: ptr.Deref(c.Message, ""),
+			})
+		}
+	}
+
+	receiver.Status.State = deploymentState(status)
+	if receiver.Status.State == radiov1beta1.StateFailed {
+		meta.SetStatusCondition(&receiver.Status.Conditions, metav1.Condition{
+			Type:    radiov1beta1.ReadyCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  radiov1beta1.DeploymentFailedReason,
+			Message: deploymentFailureMessage(status),
+		})
+	}
+
+	receiver.Status.Deployment = objectReference(deployment.GetKind(), deployment.GetAPIVersion(), deployment.ObjectMetaApplyConfiguration)
+	receiver.Status.Pod = nil
+
+	return nil
+}
+
+// deleteOwned deletes the object named after the receiver if it is
+// controlled by the receiver.
+func (r *RtlSdrReceiverReconciler) deleteOwned(ctx context.Context, receiver *radiov1beta1.RtlSdrReceiver, obj client.Object) error {
+	return r.deleteOwnedNamed(ctx, receiver, receiver.Name, obj)
+}
+
+// deleteOwnedNamed deletes the object with the given name in the namespace
+// of the receiver if it is controlled by the receiver.
+func (r *RtlSdrReceiverReconciler) deleteOwnedNamed(ctx context.Context, receiver *radiov1beta1.RtlSdrReceiver, name string, obj client.Object) error {
+	if err := r.Get(ctx, client.ObjectKey{Namespace: receiver.Namespace, Name: name}, obj); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+
+	if !metav1.IsControlledBy(obj, receiver) {
+		return nil
+	}
+
+	log.FromContext(ctx).Info("Deleting owned object", "kind", fmt.Sprintf("%T", obj))
+
+	return client.IgnoreNotFound(r.Delete(ctx, obj))
+}
+
+// deploymentState maps the Deployment status onto a receiver state.
+func deploymentState(status appsv1.DeploymentStatus) radiov1beta1.RtlSdrReceiverState {
+	if deploymentFailureMessage(status) != "" {
+		return radiov1beta1.StateFailed
+	}
+
+	if status.AvailableReplicas > 0 {
+		return radiov1beta1.StateRunning
+	}
+
+	return radiov1beta1.StateWaiting
+}
+
+// deploymentFailureMessage returns the message of the condition marking the
+// Deployment as failed, or an empty string if it has not failed.
+func deploymentFailureMessage(status appsv1.DeploymentStatus) string {
+	for _, c := range status.Conditions {
+		switch {
+		case c.Type == appsv1.DeploymentProgressing && c.Status == corev1.ConditionFalse && c.Reason == "ProgressDeadlineExceeded":
+			return c.Message
+		case c.Type == appsv1.DeploymentReplicaFailure && c.Status == corev1.ConditionTrue:
+			return c.Message
+		}
+	}
+
+	return ""
 }
 
 var (
@@ -193,8 +645,55 @@ func (r *RtlSdrReceiverReconciler) SetupWithManager(ctx context.Context, mgr ctr
 		return err
 	}
 
+	// Receivers of a pool run once the pool admits them, so reconcile all
+	// of them when its status changes.
+	poolReceivers := func(ctx context.Context, obj client.Object) []reconcile.Request {
+		receivers := &radiov1beta1.RtlSdrReceiverList{}
+		if err := mgr.GetClient().List(ctx, receivers); err != nil {
+			log.FromContext(ctx).Error(err, "Failed listing receivers")
+			return nil
+		}
+
+		var requests []reconcile.Request
+		for _, receiver := range receivers.Items {
+			if receiver.Spec.Pool != nil && receiver.Spec.Pool.Name == obj.GetName() {
+				requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&receiver)})
+			}
+		}
+
+		return requests
+	}
+
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&radiov1beta1.RtlSdrReceiver{}).
 		Owns(&corev1.Pod{}).
+		Owns(&appsv1.Deployment{}).
+		Owns(&corev1.Service{}).
+		Owns(&corev1.ConfigMap{}).
+		Owns(&corev1.Secret{}).
+		Owns(&networkingv1.NetworkPolicy{}).
+		Owns(&networkingv1.Ingress{}).
+		Watches(&radiov1beta1.RtlSdrChannel{}, handler.EnqueueRequestsFromMapFunc(channelReceiver)).
+		Watches(&radiov1beta1.RtlSdrReceiverPool{}, handler.EnqueueRequestsFromMapFunc(poolReceivers)).
 		Complete(r)
 }
+
+// markInvalid fails the receiver because of an invalid part of its spec,
+// explained by reason and err.
+func markInvalid(receiver *radiov1beta1.RtlSdrReceiver, reason string, err error) {
+	receiver.Status.State = radiov1beta1.StateFailed
+	meta.SetStatusCondition(&receiver.Status.Conditions, metav1.Condition{
+		Type:    radiov1beta1.ReadyCondition,
+		Status:  metav1.ConditionFalse,
+		Reason:  reason,
+		Message: err.Error(),
+	})
+}
+
+// clearInvalid removes the condition set by markInvalid for reason once the
+// spec has been fixed.
+func clearInvalid(receiver *radiov1beta1.RtlSdrReceiver, reason string) {
+	if c := meta.FindStatusCondition(receiver.Status.Conditions, radiov1beta1.ReadyCondition); c != nil && c.Reason == reason {
+		meta.RemoveStatusCondition(&receiver.Status.Conditions, radiov1beta1.ReadyCondition)
+	}
+}
@@ -1,20 +1,38 @@
 package controller
 
 import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"strconv"
 	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/robfig/cron/v3"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/events"
+	clocktesting "k8s.io/utils/clock/testing"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	radiov1 "github.com/frelon/k8s-radio/api/v1beta1"
+	"github.com/frelon/k8s-radio/pkg/loss"
+	"github.com/frelon/k8s-radio/pkg/rtlmux"
+	"github.com/frelon/k8s-radio/pkg/rtltcp"
 )
 
 var _ = Describe("RtlSdrReceiver controller", func() {
@@ -105,4 +123,2742 @@ var _ = Describe("RtlSdrReceiver controller", func() {
 			// }, timeout, interval).Should(Equal(ReceiverName))
 		})
 	})
+
+	Context("When using the Deployment workload", func() {
+		It("Should run the receiver in a single-replica Deployment", func(ctx SpecContext) {
+			By("By creating a new RtlSdrReceiver")
+
+			recv := &radiov1.RtlSdrReceiver{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-deployment-receiver",
+					Namespace: ReceiverNamespace,
+				},
+				Spec: radiov1.RtlSdrReceiverSpec{
+					Version:  radiov1.V4,
+					Workload: radiov1.WorkloadDeployment,
+				},
+			}
+
+			Expect(k8sClient.Create(ctx, recv)).Should(Succeed())
+
+			By("By running reconciler")
+			reconciler := RtlSdrReceiverReconciler{
+				Client: k8sClient,
+				Scheme: scheme,
+				Image:  "test-image",
+			}
+			receiverLookupKey := types.NamespacedName{Name: recv.Name, Namespace: ReceiverNamespace}
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: receiverLookupKey})
+			Expect(err).To(Succeed())
+
+			By("By checking the Deployment")
+			deployment := &appsv1.Deployment{}
+			Expect(k8sClient.Get(ctx, receiverLookupKey, deployment)).To(Succeed())
+			Expect(*deployment.Spec.Replicas).To(Equal(int32(1)))
+			Expect(deployment.Spec.Strategy.Type).To(Equal(appsv1.RecreateDeploymentStrategyType))
+			Expect(deployment.Spec.Template.Spec.Containers).To(HaveLen(1))
+			Expect(deployment.Spec.Template.Spec.Containers[0].Image).To(Equal("test-image"))
+
+			By("By checking the RtlSdrReceiver status")
+			updated := &radiov1.RtlSdrReceiver{}
+			Expect(k8sClient.Get(ctx, receiverLookupKey, updated)).To(Succeed())
+			Expect(updated.Status.State).To(Equal(radiov1.StateWaiting))
+			Expect(updated.Status.Pod).To(BeNil())
+			Expect(updated.Status.Deployment).ToNot(BeNil())
+			Expect(updated.Status.Deployment.Name).To(Equal(recv.Name))
+		})
+	})
+
+	Context("When using the FM mode", func() {
+		It("Should run fm-streamer and expose the audio over HTTP", func(ctx SpecContext) {
+			By("By creating a new RtlSdrReceiver")
+
+			freq := resource.MustParse("101.9M")
+			recv := &radiov1.RtlSdrReceiver{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-fm-receiver",
+					Namespace: ReceiverNamespace,
+				},
+				Spec: radiov1.RtlSdrReceiverSpec{
+					Version:   radiov1.V4,
+					Mode:      radiov1.ModeFM,
+					Frequency: &freq,
+				},
+			}
+
+			Expect(k8sClient.Create(ctx, recv)).Should(Succeed())
+
+			By("By running reconciler")
+			reconciler := RtlSdrReceiverReconciler{
+				Client:  k8sClient,
+				Scheme:  scheme,
+				Image:   "test-image",
+				FMImage: "test-fm-image",
+			}
+			receiverLookupKey := types.NamespacedName{Name: recv.Name, Namespace: ReceiverNamespace}
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: receiverLookupKey})
+			Expect(err).To(Succeed())
+
+			By("By checking the Pod runs fm-streamer")
+			pod := &corev1.Pod{}
+			Expect(k8sClient.Get(ctx, receiverLookupKey, pod)).To(Succeed())
+			Expect(pod.Spec.Containers).To(HaveLen(1))
+			container := pod.Spec.Containers[0]
+			Expect(container.Image).To(Equal("test-fm-image"))
+			Expect(container.Command).To(Equal([]string{"/usr/local/bin/audio-server"}))
+			Expect(container.Args).To(ContainElements("fm-streamer", "--frequency", "101900k"))
+			Expect(container.Ports).To(HaveLen(1))
+			Expect(container.Ports[0].ContainerPort).To(Equal(int32(AudioPort)))
+
+			By("By checking the Service and endpoint")
+			service := &corev1.Service{}
+			Expect(k8sClient.Get(ctx, receiverLookupKey, service)).To(Succeed())
+			Expect(service.Spec.Ports).To(HaveLen(1))
+			Expect(service.Spec.Ports[0].Name).To(Equal("audio"))
+			Expect(service.Spec.Ports[0].Port).To(Equal(int32(AudioPort)))
+
+			updated := &radiov1.RtlSdrReceiver{}
+			Expect(k8sClient.Get(ctx, receiverLookupKey, updated)).To(Succeed())
+			Expect(updated.Status.Endpoint).To(Equal("http://test-fm-receiver.default.svc:8000/audio.wav"))
+		})
+	})
+
+	Context("When using the ADSB mode", func() {
+		It("Should run readsb, expose the aircraft and report the traffic", func(ctx SpecContext) {
+			By("By creating a new RtlSdrReceiver")
+
+			recv := &radiov1.RtlSdrReceiver{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-adsb-receiver",
+					Namespace: ReceiverNamespace,
+				},
+				Spec: radiov1.RtlSdrReceiverSpec{
+					Version: radiov1.V4,
+					Mode:    radiov1.ModeADSB,
+				},
+			}
+
+			Expect(k8sClient.Create(ctx, recv)).Should(Succeed())
+
+			By("By running reconciler")
+			heard := metav1.NewTime(time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC))
+			reconciler := RtlSdrReceiverReconciler{
+				Client:    k8sClient,
+				Scheme:    scheme,
+				Image:     "test-image",
+				ADSBImage: "test-adsb-image",
+				ADSBStatus: func(_ context.Context, pod *corev1.Pod) (*radiov1.RtlSdrADSBStatus, error) {
+					Expect(pod.Name).To(Equal(recv.Name))
+					return &radiov1.RtlSdrADSBStatus{Aircraft: 12, MessageRate: 250, Messages: 90000, LastUpdateTime: &heard}, nil
+				},
+			}
+			receiverLookupKey := types.NamespacedName{Name: recv.Name, Namespace: ReceiverNamespace}
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: receiverLookupKey})
+			Expect(err).To(Succeed())
+
+			By("By checking the Pod runs readsb on 1090 MHz")
+			pod := &corev1.Pod{}
+			Expect(k8sClient.Get(ctx, receiverLookupKey, pod)).To(Succeed())
+			Expect(pod.Spec.Containers).To(HaveLen(1))
+			container := pod.Spec.Containers[0]
+			Expect(container.Image).To(Equal("test-adsb-image"))
+			Expect(container.Command).To(Equal([]string{"/usr/local/bin/adsb-server"}))
+			Expect(container.Args).To(Equal([]string{
+				"--listen", ":8080",
+				"--json-dir", "/run/adsb",
+				"--", "readsb",
+				"--device-type", "rtlsdr",
+				"--freq", "1090000000",
+				"--net",
+				"--net-sbs-port", "30003",
+				"--net-bo-port", "30005",
+				"--write-json", "/run/adsb",
+				"--write-json-every", "1",
+				"--quiet",
+			}))
+			Expect(container.Resources.Limits).To(HaveKey(corev1.ResourceName(RtlSdrResourceName)))
+			Expect(container.Ports).To(ConsistOf(
+				HaveField("ContainerPort", int32(SBSPort)),
+				HaveField("ContainerPort", int32(BeastPort)),
+				HaveField("ContainerPort", int32(ADSBPort)),
+			))
+			Expect(pod.Spec.Volumes).To(ContainElement(HaveField("Name", "adsb")))
+
+			By("By checking the Service and endpoint")
+			service := &corev1.Service{}
+			Expect(k8sClient.Get(ctx, receiverLookupKey, service)).To(Succeed())
+			Expect(service.Spec.Ports).To(HaveLen(3))
+			Expect(service.Spec.Ports[0].Name).To(Equal("http"))
+			Expect(service.Spec.Ports[1].Name).To(Equal("sbs"))
+			Expect(service.Spec.Ports[1].Port).To(Equal(int32(SBSPort)))
+			Expect(service.Spec.Ports[2].Name).To(Equal("beast"))
+			Expect(service.Spec.Ports[2].Port).To(Equal(int32(BeastPort)))
+
+			updated := &radiov1.RtlSdrReceiver{}
+			Expect(k8sClient.Get(ctx, receiverLookupKey, updated)).To(Succeed())
+			Expect(updated.Status.Endpoint).To(Equal("http://test-adsb-receiver.default.svc:8080/data/aircraft.json"))
+			Expect(updated.Status.ADSB).To(BeNil())
+
+			By("By reporting the traffic once the pod is running")
+			pod.Status.Phase = corev1.PodRunning
+			pod.Status.PodIP = "10.0.0.12"
+			Expect(k8sClient.Status().Update(ctx, pod)).To(Succeed())
+
+			result, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: receiverLookupKey})
+			Expect(err).To(Succeed())
+			Expect(result.RequeueAfter).ToNot(BeZero())
+
+			Expect(k8sClient.Get(ctx, receiverLookupKey, updated)).To(Succeed())
+			Expect(updated.Status.ADSB).ToNot(BeNil())
+			Expect(updated.Status.ADSB.Aircraft).To(Equal(int32(12)))
+			Expect(updated.Status.ADSB.MessageRate).To(Equal(int32(250)))
+			Expect(updated.Status.ADSB.Messages).To(Equal(int64(90000)))
+			Expect(updated.Status.ADSB.LastUpdateTime.Time).To(BeTemporally("==", heard.Time))
+		})
+
+		It("Should reject sharing a receiver in ADSB mode", func(ctx SpecContext) {
+			recv := &radiov1.RtlSdrReceiver{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-adsb-shared-receiver",
+					Namespace: ReceiverNamespace,
+				},
+				Spec: radiov1.RtlSdrReceiverSpec{
+					Version: radiov1.V4,
+					Mode:    radiov1.ModeADSB,
+					Sharing: &radiov1.RtlSdrSharing{Enabled: true},
+				},
+			}
+
+			err := k8sClient.Create(ctx, recv)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue(), "expected invalid, got %v", err)
+		})
+	})
+
+	Context("When using the AIS and ISM modes", func() {
+		It("Should run rtl_433 and publish its events to the sink", func(ctx SpecContext) {
+			By("By creating a new RtlSdrReceiver")
+
+			freq := resource.MustParse("868.3M")
+			recv := &radiov1.RtlSdrReceiver{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-ism-receiver",
+					Namespace: ReceiverNamespace,
+				},
+				Spec: radiov1.RtlSdrReceiverSpec{
+					Version:   radiov1.V4,
+					Mode:      radiov1.ModeISM,
+					Frequency: &freq,
+					Gain:      ptr.To(resource.MustParse("32.8")),
+					Events:    &radiov1.RtlSdrEvents{Sink: "mqtt://mosquitto.home.svc:1883"},
+				},
+			}
+
+			Expect(k8sClient.Create(ctx, recv)).Should(Succeed())
+
+			By("By running reconciler")
+			reconciler := RtlSdrReceiverReconciler{
+				Client:       k8sClient,
+				Scheme:       scheme,
+				Image:        "test-image",
+				DecoderImage: "test-decoder-image",
+			}
+			receiverLookupKey := types.NamespacedName{Name: recv.Name, Namespace: ReceiverNamespace}
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: receiverLookupKey})
+			Expect(err).To(Succeed())
+
+			By("By checking the Pod runs rtl_433 wrapped by rtl-events")
+			pod := &corev1.Pod{}
+			Expect(k8sClient.Get(ctx, receiverLookupKey, pod)).To(Succeed())
+			Expect(pod.Spec.Containers).To(HaveLen(1))
+			container := pod.Spec.Containers[0]
+			Expect(container.Image).To(Equal("test-decoder-image"))
+			Expect(container.Command).To(Equal([]string{"/usr/local/bin/rtl-events"}))
+			Expect(container.Args).To(Equal([]string{
+				"--listen", ":8080",
+				"--source", "ism",
+				"--sink", "mqtt://mosquitto.home.svc:1883",
+				"--topic", "radio/default/test-ism-receiver/events",
+				"--receiver", "default/test-ism-receiver",
+				"--", "rtl_433", "-F", "json", "-M", "time:utc", "-f", "868300000", "-g", "32.8",
+			}))
+			Expect(container.Resources.Limits).To(HaveKey(corev1.ResourceName(RtlSdrResourceName)))
+			Expect(container.Ports).To(ConsistOf(HaveField("ContainerPort", int32(EventsPort))))
+
+			By("By checking the Service and endpoint")
+			service := &corev1.Service{}
+			Expect(k8sClient.Get(ctx, receiverLookupKey, service)).To(Succeed())
+			Expect(service.Spec.Ports).To(HaveLen(1))
+			Expect(service.Spec.Ports[0].Name).To(Equal("http"))
+			Expect(service.Spec.Ports[0].Port).To(Equal(int32(EventsPort)))
+
+			updated := &radiov1.RtlSdrReceiver{}
+			Expect(k8sClient.Get(ctx, receiverLookupKey, updated)).To(Succeed())
+			Expect(updated.Status.Endpoint).To(Equal("http://test-ism-receiver.default.svc:8080/events"))
+		})
+
+		It("Should run AIS-catcher writing its events to stdout by default", func(ctx SpecContext) {
+			recv := &radiov1.RtlSdrReceiver{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-ais-receiver",
+					Namespace: ReceiverNamespace,
+				},
+				Spec: radiov1.RtlSdrReceiverSpec{
+					Version: radiov1.V4,
+					Mode:    radiov1.ModeAIS,
+					Events:  &radiov1.RtlSdrEvents{Topic: "ships"},
+				},
+			}
+
+			Expect(k8sClient.Create(ctx, recv)).Should(Succeed())
+			Expect(recv.Spec.Events.Sink).To(Equal("stdout"))
+
+			reconciler := RtlSdrReceiverReconciler{
+				Client:       k8sClient,
+				Scheme:       scheme,
+				Image:        "test-image",
+				DecoderImage: "test-decoder-image",
+			}
+			receiverLookupKey := types.NamespacedName{Name: recv.Name, Namespace: ReceiverNamespace}
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: receiverLookupKey})
+			Expect(err).To(Succeed())
+
+			pod := &corev1.Pod{}
+			Expect(k8sClient.Get(ctx, receiverLookupKey, pod)).To(Succeed())
+			Expect(pod.Spec.Containers[0].Args).To(Equal([]string{
+				"--listen", ":8080",
+				"--source", "ais",
+				"--sink", "stdout",
+				"--topic", "ships",
+				"--receiver", "default/test-ais-receiver",
+				"--", "AIS-catcher", "-o", "5",
+			}))
+		})
+
+		It("Should reject events outside the AIS and ISM modes", func(ctx SpecContext) {
+			recv := &radiov1.RtlSdrReceiver{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-iq-events-receiver",
+					Namespace: ReceiverNamespace,
+				},
+				Spec: radiov1.RtlSdrReceiverSpec{
+					Version: radiov1.V4,
+					Events:  &radiov1.RtlSdrEvents{},
+				},
+			}
+
+			err := k8sClient.Create(ctx, recv)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue(), "expected invalid, got %v", err)
+
+			recv.Spec.Mode = radiov1.ModeISM
+			recv.Spec.Events.Sink = "ftp://example.com"
+			err = k8sClient.Create(ctx, recv)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue(), "expected invalid, got %v", err)
+		})
+	})
+
+	Context("When simulating a receiver", func() {
+		It("Should run the fake rtl_tcp server without claiming a dongle", func(ctx SpecContext) {
+			By("By creating a new simulated RtlSdrReceiver")
+
+			freq := resource.MustParse("101.9M")
+			recv := &radiov1.RtlSdrReceiver{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-simulated-receiver",
+					Namespace: ReceiverNamespace,
+				},
+				Spec: radiov1.RtlSdrReceiverSpec{
+					Version:   radiov1.V4,
+					Frequency: &freq,
+					Simulation: &radiov1.RtlSdrSimulation{
+						Replay: &radiov1.RtlSdrReplay{
+							ClaimName: "recordings",
+							Path:      "capture.cu8",
+						},
+					},
+				},
+			}
+
+			Expect(k8sClient.Create(ctx, recv)).Should(Succeed())
+
+			By("By running reconciler")
+			reconciler := RtlSdrReceiverReconciler{
+				Client:         k8sClient,
+				Scheme:         scheme,
+				Image:          "test-image",
+				SimulatorImage: "test-simulator-image",
+			}
+			receiverLookupKey := types.NamespacedName{Name: recv.Name, Namespace: ReceiverNamespace}
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: receiverLookupKey})
+			Expect(err).To(Succeed())
+
+			By("By checking the Pod runs the fake server")
+			pod := &corev1.Pod{}
+			Expect(k8sClient.Get(ctx, receiverLookupKey, pod)).To(Succeed())
+			Expect(pod.Spec.Containers).To(HaveLen(1))
+			container := pod.Spec.Containers[0]
+			Expect(container.Image).To(Equal("test-simulator-image"))
+			Expect(container.Command).To(Equal([]string{"/fake-rtltcp"}))
+			Expect(container.Args).To(ContainElements("-f", "101900k", "--replay", "/replay/capture.cu8"))
+			Expect(container.Resources.Limits).ToNot(HaveKey(corev1.ResourceName(RtlSdrResourceName)))
+			Expect(container.VolumeMounts).To(ContainElement(HaveField("MountPath", "/replay")))
+			Expect(pod.Spec.Volumes).To(ContainElement(HaveField("PersistentVolumeClaim.ClaimName", "recordings")))
+		})
+
+		It("Should reject simulation in FM mode", func(ctx SpecContext) {
+			recv := &radiov1.RtlSdrReceiver{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-simulated-fm-receiver",
+					Namespace: ReceiverNamespace,
+				},
+				Spec: radiov1.RtlSdrReceiverSpec{
+					Version:    radiov1.V4,
+					Mode:       radiov1.ModeFM,
+					Simulation: &radiov1.RtlSdrSimulation{},
+				},
+			}
+
+			err := k8sClient.Create(ctx, recv)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue(), "expected invalid, got %v", err)
+		})
+
+		It("Should reject a gain in FM mode", func(ctx SpecContext) {
+			recv := &radiov1.RtlSdrReceiver{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-gain-fm-receiver",
+					Namespace: ReceiverNamespace,
+				},
+				Spec: radiov1.RtlSdrReceiverSpec{
+					Version: radiov1.V4,
+					Mode:    radiov1.ModeFM,
+					Gain:    ptr.To(resource.MustParse("40")),
+				},
+			}
+
+			err := k8sClient.Create(ctx, recv)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue(), "expected invalid, got %v", err)
+			Expect(err.Error()).To(ContainSubstring("gain is not supported in FM mode"))
+		})
+	})
+
+	Context("When sharing a receiver", func() {
+		It("Should put the multiplexing proxy in front of rtl_tcp", func(ctx SpecContext) {
+			By("By creating a new shared RtlSdrReceiver")
+
+			maxClients := int32(4)
+			recv := &radiov1.RtlSdrReceiver{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-shared-receiver",
+					Namespace: ReceiverNamespace,
+				},
+				Spec: radiov1.RtlSdrReceiverSpec{
+					Version: radiov1.V4,
+					Sharing: &radiov1.RtlSdrSharing{
+						Enabled:    true,
+						LockPolicy: radiov1.LockPolicyReadOnly,
+						MaxClients: &maxClients,
+					},
+				},
+			}
+
+			Expect(k8sClient.Create(ctx, recv)).Should(Succeed())
+
+			By("By running reconciler")
+			reconciler := RtlSdrReceiverReconciler{
+				Client:   k8sClient,
+				Scheme:   scheme,
+				Image:    "test-image",
+				MuxImage: "test-mux-image",
+			}
+			receiverLookupKey := types.NamespacedName{Name: recv.Name, Namespace: ReceiverNamespace}
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: receiverLookupKey})
+			Expect(err).To(Succeed())
+
+			By("By checking rtl_tcp is only reachable through the proxy")
+			pod := &corev1.Pod{}
+			Expect(k8sClient.Get(ctx, receiverLookupKey, pod)).To(Succeed())
+			Expect(pod.Spec.Containers).To(HaveLen(2))
+
+			receiver := pod.Spec.Containers[0]
+			Expect(receiver.Args).To(Equal([]string{"-a", "127.0.0.1", "-p", "1235"}))
+
+			mux := pod.Spec.Containers[1]
+			Expect(mux.Image).To(Equal("test-mux-image"))
+			Expect(mux.Args).To(Equal([]string{
+				"--listen", ":1234",
+				"--upstream", "127.0.0.1:1235",
+				"--lock-policy", "ReadOnly",
+				"--max-clients", "4",
+			}))
+
+			service := &corev1.Service{}
+			Expect(k8sClient.Get(ctx, receiverLookupKey, service)).To(Succeed())
+			Expect(service.Spec.Ports[0].Port).To(Equal(int32(1234)))
+		})
+	})
+
+	Context("When recording a receiver", func() {
+		It("Should record the stream through the proxy and report its progress", func(ctx SpecContext) {
+			By("By creating a new recording RtlSdrReceiver")
+
+			freq := resource.MustParse("101.9M")
+			maxFileSize := resource.MustParse("1Gi")
+			maxFiles := int32(24)
+			recv := &radiov1.RtlSdrReceiver{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-recording-receiver",
+					Namespace: ReceiverNamespace,
+				},
+				Spec: radiov1.RtlSdrReceiverSpec{
+					Version:   radiov1.V4,
+					Frequency: &freq,
+					Recording: &radiov1.RtlSdrRecording{
+						ClaimName: "recordings",
+						Duration:  &metav1.Duration{Duration: 24 * time.Hour},
+						Rotation: &radiov1.RtlSdrRotation{
+							Interval:    &metav1.Duration{Duration: time.Hour},
+							MaxFileSize: &maxFileSize,
+							MaxFiles:    &maxFiles,
+						},
+					},
+				},
+			}
+
+			Expect(k8sClient.Create(ctx, recv)).Should(Succeed())
+
+			By("By running reconciler")
+			recorded := &radiov1.RtlSdrRecordingStatus{
+				Files: []string{"test-recording-receiver-20261019T120000.000Z"},
+				Bytes: 4096,
+			}
+			reconciler := RtlSdrReceiverReconciler{
+				Client:   k8sClient,
+				Scheme:   scheme,
+				Image:    "test-image",
+				MuxImage: "test-mux-image",
+				RecordingStatus: func(_ context.Context, pod *corev1.Pod) (*radiov1.RtlSdrRecordingStatus, error) {
+					Expect(pod.Name).To(Equal(recv.Name))
+					return recorded, nil
+				},
+			}
+			receiverLookupKey := types.NamespacedName{Name: recv.Name, Namespace: ReceiverNamespace}
+			result, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: receiverLookupKey})
+			Expect(err).To(Succeed())
+			Expect(result.RequeueAfter).To(BeZero())
+
+			By("By checking the proxy records to the volume")
+			pod := &corev1.Pod{}
+			Expect(k8sClient.Get(ctx, receiverLookupKey, pod)).To(Succeed())
+			Expect(pod.Spec.Containers).To(HaveLen(2))
+			Expect(pod.Spec.Containers[0].Args).To(Equal([]string{"-a", "127.0.0.1", "-f", "101900k", "-p", "1235"}))
+
+			mux := pod.Spec.Containers[1]
+			Expect(mux.Args).To(Equal([]string{
+				"--listen", ":1234",
+				"--upstream", "127.0.0.1:1235",
+				"--max-clients", "1",
+				"--status-listen", ":9180",
+				"--sample-rate", "2048000",
+				"--frequency", "101900000",
+				"--record-dir", "/recordings/test-recording-receiver",
+				"--record-name", "test-recording-receiver",
+				"--receiver", "default/test-recording-receiver",
+				"--hardware", "RTL-SDR v4",
+				"--record-duration", "24h0m0s",
+				"--record-interval", "1h0m0s",
+				"--record-file-size", "1073741824",
+				"--record-max-files", "24",
+			}))
+			Expect(mux.VolumeMounts).To(ContainElement(HaveField("MountPath", "/recordings")))
+			Expect(mux.Env).To(ContainElement(HaveField("ValueFrom.FieldRef.FieldPath", "spec.nodeName")))
+			Expect(pod.Spec.Volumes).To(ContainElement(HaveField("PersistentVolumeClaim.ClaimName", "recordings")))
+			Expect(pod.Spec.SecurityContext.FSGroup).To(HaveValue(Equal(int64(65532))))
+
+			By("By reporting the recording status once the pod is running")
+			pod.Status.Phase = corev1.PodRunning
+			pod.Status.PodIP = "10.0.0.10"
+			Expect(k8sClient.Status().Update(ctx, pod)).To(Succeed())
+
+			result, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: receiverLookupKey})
+			Expect(err).To(Succeed())
+			Expect(result.RequeueAfter).ToNot(BeZero())
+
+			updated := &radiov1.RtlSdrReceiver{}
+			Expect(k8sClient.Get(ctx, receiverLookupKey, updated)).To(Succeed())
+			Expect(updated.Status.State).To(Equal(radiov1.StateRunning))
+			Expect(updated.Status.Recording).To(Equal(recorded))
+
+			By("By no longer polling once the recording has finished")
+			recorded.Finished = true
+			result, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: receiverLookupKey})
+			Expect(err).To(Succeed())
+			Expect(result.RequeueAfter).To(BeZero())
+		})
+
+		It("Should reject recording in FM mode", func(ctx SpecContext) {
+			recv := &radiov1.RtlSdrReceiver{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-recording-fm-receiver",
+					Namespace: ReceiverNamespace,
+				},
+				Spec: radiov1.RtlSdrReceiverSpec{
+					Version:   radiov1.V4,
+					Mode:      radiov1.ModeFM,
+					Recording: &radiov1.RtlSdrRecording{ClaimName: "recordings"},
+				},
+			}
+
+			err := k8sClient.Create(ctx, recv)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue(), "expected invalid, got %v", err)
+		})
+	})
+
+	Context("When scheduling a receiver", func() {
+		stockholm, err := time.LoadLocation("Europe/Stockholm")
+		Expect(err).ToNot(HaveOccurred())
+
+		// scheduled returns a receiver running every evening at 21:00 for an
+		// hour, Stockholm time.
+		scheduled := func(name string, policy radiov1.RtlSdrMissedWindowPolicy) *radiov1.RtlSdrReceiver {
+			return &radiov1.RtlSdrReceiver{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      name,
+					Namespace: ReceiverNamespace,
+				},
+				Spec: radiov1.RtlSdrReceiverSpec{
+					Version: radiov1.V4,
+					Schedule: &radiov1.RtlSdrSchedule{
+						Cron:               "0 21 * * *",
+						Duration:           metav1.Duration{Duration: time.Hour},
+						TimeZone:           ptr.To("Europe/Stockholm"),
+						MissedWindowPolicy: policy,
+					},
+				},
+			}
+		}
+
+		It("Should reject windows that are empty or too long", func(ctx SpecContext) {
+			for _, duration := range []time.Duration{0, -time.Hour, 721 * time.Hour} {
+				recv := scheduled("test-schedule-duration", radiov1.MissedWindowRunRemaining)
+				recv.Spec.Schedule.Duration = metav1.Duration{Duration: duration}
+				Expect(k8sClient.Create(ctx, recv)).To(MatchError(ContainSubstring("duration must be positive and at most 720h")))
+			}
+		})
+
+		It("Should find the latest start without walking every start", func() {
+			// walk is the reference, stepping through every start.
+			walk := func(sched cron.Schedule, after, now time.Time) time.Time {
+				var latest time.Time
+				for t := sched.Next(after); !t.After(now); t = sched.Next(t) {
+					latest = t
+				}
+				return latest
+			}
+
+			now := time.Date(2026, 10, 19, 20, 59, 30, 0, stockholm)
+			for _, expr := range []string{"* * * * *", "*/7 * * * *", "0 21 * * *", "0 3 29 2 *"} {
+				sched, err := cron.ParseStandard(expr)
+				Expect(err).ToNot(HaveOccurred())
+				for _, duration := range []time.Duration{time.Minute, time.Hour, 720 * time.Hour} {
+					Expect(latestStart(sched, now.Add(-duration), now)).To(Equal(walk(sched, now.Add(-duration), now)), "%s over %s", expr, duration)
+				}
+			}
+
+			sched, err := cron.ParseStandard("* * * * *")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(latestStart(sched, now.Add(-time.Minute), time.Date(2026, 10, 19, 21, 0, 0, 0, stockholm))).
+				To(Equal(time.Date(2026, 10, 19, 21, 0, 0, 0, stockholm)))
+		})
+
+		It("Should only run the receiver during its windows", func(ctx SpecContext) {
+			recv := scheduled("test-scheduled-receiver", radiov1.MissedWindowRunRemaining)
+			Expect(k8sClient.Create(ctx, recv)).Should(Succeed())
+
+			clock := clocktesting.NewFakePassiveClock(time.Date(2026, 10, 19, 20, 0, 0, 0, stockholm))
+			reconciler := RtlSdrReceiverReconciler{
+				Client: k8sClient,
+				Scheme: scheme,
+				Image:  "test-image",
+				Clock:  clock,
+			}
+			receiverLookupKey := types.NamespacedName{Name: recv.Name, Namespace: ReceiverNamespace}
+
+			By("By waiting for the first window without a pod")
+			result, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: receiverLookupKey})
+			Expect(err).To(Succeed())
+			Expect(result.RequeueAfter).To(Equal(time.Hour))
+
+			updated := &radiov1.RtlSdrReceiver{}
+			Expect(k8sClient.Get(ctx, receiverLookupKey, updated)).To(Succeed())
+			Expect(updated.Status.State).To(Equal(radiov1.StateScheduled))
+			Expect(updated.Status.Schedule.NextRunTime.Time).To(BeTemporally("==", time.Date(2026, 10, 19, 21, 0, 0, 0, stockholm)))
+			Expect(updated.Status.Schedule.LastRunTime).To(BeNil())
+			Expect(apierrors.IsNotFound(k8sClient.Get(ctx, receiverLookupKey, &corev1.Pod{}))).To(BeTrue())
+
+			By("By starting the receiver when the window opens")
+			clock.SetTime(time.Date(2026, 10, 19, 21, 0, 30, 0, stockholm))
+			result, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: receiverLookupKey})
+			Expect(err).To(Succeed())
+			Expect(result.RequeueAfter).To(Equal(59*time.Minute + 30*time.Second))
+
+			Expect(k8sClient.Get(ctx, receiverLookupKey, &corev1.Pod{})).To(Succeed())
+			Expect(k8sClient.Get(ctx, receiverLookupKey, updated)).To(Succeed())
+			Expect(updated.Status.State).To(Equal(radiov1.StateWaiting))
+			Expect(updated.Status.Schedule.LastRunTime.Time).To(BeTemporally("==", time.Date(2026, 10, 19, 21, 0, 0, 0, stockholm)))
+			Expect(updated.Status.Schedule.ActiveUntil.Time).To(BeTemporally("==", time.Date(2026, 10, 19, 22, 0, 0, 0, stockholm)))
+			Expect(updated.Status.Schedule.NextRunTime.Time).To(BeTemporally("==", time.Date(2026, 10, 20, 21, 0, 0, 0, stockholm)))
+
+			By("By releasing the dongle when the window closes")
+			clock.SetTime(time.Date(2026, 10, 19, 22, 0, 0, 0, stockholm))
+			result, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: receiverLookupKey})
+			Expect(err).To(Succeed())
+			Expect(result.RequeueAfter).To(Equal(23 * time.Hour))
+
+			Expect(k8sClient.Get(ctx, receiverLookupKey, updated)).To(Succeed())
+			Expect(updated.Status.State).To(Equal(radiov1.StateScheduled))
+			Expect(updated.Status.Pod).To(BeNil())
+			Eventually(func() bool {
+				pod := &corev1.Pod{}
+				err := k8sClient.Get(ctx, receiverLookupKey, pod)
+				return apierrors.IsNotFound(err) || !pod.DeletionTimestamp.IsZero()
+			}, timeout, interval).Should(BeTrue())
+		})
+
+		It("Should skip missed windows when told to", func(ctx SpecContext) {
+			recv := scheduled("test-skipping-receiver", radiov1.MissedWindowSkip)
+			Expect(k8sClient.Create(ctx, recv)).Should(Succeed())
+
+			reconciler := RtlSdrReceiverReconciler{
+				Client: k8sClient,
+				Scheme: scheme,
+				Image:  "test-image",
+				Clock:  clocktesting.NewFakePassiveClock(time.Date(2026, 10, 19, 21, 10, 0, 0, stockholm)),
+			}
+			receiverLookupKey := types.NamespacedName{Name: recv.Name, Namespace: ReceiverNamespace}
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: receiverLookupKey})
+			Expect(err).To(Succeed())
+
+			updated := &radiov1.RtlSdrReceiver{}
+			Expect(k8sClient.Get(ctx, receiverLookupKey, updated)).To(Succeed())
+			Expect(updated.Status.State).To(Equal(radiov1.StateScheduled))
+			Expect(updated.Status.Schedule.LastMissedTime.Time).To(BeTemporally("==", time.Date(2026, 10, 19, 21, 0, 0, 0, stockholm)))
+			Expect(apierrors.IsNotFound(k8sClient.Get(ctx, receiverLookupKey, &corev1.Pod{}))).To(BeTrue())
+		})
+
+		It("Should fail receivers with an invalid schedule", func(ctx SpecContext) {
+			recv := scheduled("test-invalid-schedule-receiver", radiov1.MissedWindowRunRemaining)
+			recv.Spec.Schedule.Cron = "every evening"
+			Expect(k8sClient.Create(ctx, recv)).Should(Succeed())
+
+			reconciler := RtlSdrReceiverReconciler{
+				Client: k8sClient,
+				Scheme: scheme,
+				Image:  "test-image",
+			}
+			receiverLookupKey := types.NamespacedName{Name: recv.Name, Namespace: ReceiverNamespace}
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: receiverLookupKey})
+			Expect(err).To(Succeed())
+
+			updated := &radiov1.RtlSdrReceiver{}
+			Expect(k8sClient.Get(ctx, receiverLookupKey, updated)).To(Succeed())
+			Expect(updated.Status.State).To(Equal(radiov1.StateFailed))
+			Expect(updated.Status.Conditions).To(ContainElement(HaveField("Reason", radiov1.InvalidScheduleReason)))
+		})
+	})
+
+	Context("When scanning a receiver", func() {
+		It("Should scan the channels through the proxy and report hits", func(ctx SpecContext) {
+			By("By creating a new scanning RtlSdrReceiver")
+
+			squelch := int32(-30)
+			recv := &radiov1.RtlSdrReceiver{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-scanning-receiver",
+					Namespace: ReceiverNamespace,
+				},
+				Spec: radiov1.RtlSdrReceiverSpec{
+					Version: radiov1.V4,
+					Scan: &radiov1.RtlSdrScan{
+						Range: &radiov1.RtlSdrScanRange{
+							Start: resource.MustParse("145M"),
+							End:   resource.MustParse("145.025M"),
+							Step:  resource.MustParse("12.5k"),
+						},
+						Squelch: &squelch,
+						Hold:    &metav1.Duration{Duration: 5 * time.Second},
+					},
+				},
+			}
+
+			Expect(k8sClient.Create(ctx, recv)).Should(Succeed())
+
+			By("By running reconciler")
+			seen := metav1.NewTime(time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC))
+			freq := resource.MustParse("145012500")
+			scanned := &radiov1.RtlSdrScanStatus{
+				Frequency: &freq,
+				Hits: []radiov1.RtlSdrScanHit{
+					{Frequency: freq, Power: -21, FirstSeen: seen, LastSeen: seen},
+				},
+			}
+			reconciler := RtlSdrReceiverReconciler{
+				Client:   k8sClient,
+				Scheme:   scheme,
+				Image:    "test-image",
+				MuxImage: "test-mux-image",
+				ScanStatus: func(_ context.Context, pod *corev1.Pod) (*radiov1.RtlSdrScanStatus, error) {
+					Expect(pod.Name).To(Equal(recv.Name))
+					return scanned, nil
+				},
+			}
+			receiverLookupKey := types.NamespacedName{Name: recv.Name, Namespace: ReceiverNamespace}
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: receiverLookupKey})
+			Expect(err).To(Succeed())
+
+			By("By checking the proxy scans the channels")
+			pod := &corev1.Pod{}
+			Expect(k8sClient.Get(ctx, receiverLookupKey, pod)).To(Succeed())
+			Expect(pod.Spec.Containers).To(HaveLen(2))
+
+			mux := pod.Spec.Containers[1]
+			Expect(mux.Args).To(Equal([]string{
+				"--listen", ":1234",
+				"--upstream", "127.0.0.1:1235",
+				"--lock-policy", "ReadOnly",
+				"--max-clients", "1",
+				"--status-listen", ":9180",
+				"--sample-rate", "2048000",
+				"--scan-frequencies", "145000000,145012500,145025000",
+				"--scan-bandwidth", "12500",
+				"--scan-squelch", "-30",
+				"--scan-hold", "5s",
+				"--scan-max-hits", "10",
+			}))
+			Expect(mux.Ports).To(ContainElement(HaveField("ContainerPort", int32(ProxyStatusPort))))
+
+			By("By reporting the hits once the pod is running")
+			pod.Status.Phase = corev1.PodRunning
+			pod.Status.PodIP = "10.0.0.11"
+			Expect(k8sClient.Status().Update(ctx, pod)).To(Succeed())
+
+			result, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: receiverLookupKey})
+			Expect(err).To(Succeed())
+			Expect(result.RequeueAfter).ToNot(BeZero())
+
+			updated := &radiov1.RtlSdrReceiver{}
+			Expect(k8sClient.Get(ctx, receiverLookupKey, updated)).To(Succeed())
+			Expect(updated.Status.State).To(Equal(radiov1.StateRunning))
+			Expect(updated.Status.Scan.Frequency.Value()).To(Equal(int64(145_012_500)))
+			Expect(updated.Status.Scan.Hits).To(HaveLen(1))
+			Expect(updated.Status.Scan.Hits[0].Power).To(Equal(int32(-21)))
+			Expect(updated.Status.Scan.Hits[0].FirstSeen.Time).To(BeTemporally("==", seen.Time))
+		})
+
+		It("Should fail receivers with an invalid range", func(ctx SpecContext) {
+			recv := &radiov1.RtlSdrReceiver{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-invalid-scan-receiver",
+					Namespace: ReceiverNamespace,
+				},
+				Spec: radiov1.RtlSdrReceiverSpec{
+					Version: radiov1.V4,
+					Scan: &radiov1.RtlSdrScan{
+						Range: &radiov1.RtlSdrScanRange{
+							Start: resource.MustParse("146M"),
+							End:   resource.MustParse("144M"),
+							Step:  resource.MustParse("12.5k"),
+						},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, recv)).Should(Succeed())
+
+			reconciler := RtlSdrReceiverReconciler{
+				Client:   k8sClient,
+				Scheme:   scheme,
+				Image:    "test-image",
+				MuxImage: "test-mux-image",
+			}
+			receiverLookupKey := types.NamespacedName{Name: recv.Name, Namespace: ReceiverNamespace}
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: receiverLookupKey})
+			Expect(err).To(Succeed())
+
+			updated := &radiov1.RtlSdrReceiver{}
+			Expect(k8sClient.Get(ctx, receiverLookupKey, updated)).To(Succeed())
+			Expect(updated.Status.State).To(Equal(radiov1.StateFailed))
+			Expect(updated.Status.Conditions).To(ContainElement(HaveField("Reason", radiov1.InvalidScanReason)))
+			Expect(apierrors.IsNotFound(k8sClient.Get(ctx, receiverLookupKey, &corev1.Pod{}))).To(BeTrue())
+		})
+
+		It("Should reject scans with both frequencies and a range, or in FM mode", func(ctx SpecContext) {
+			both := &radiov1.RtlSdrReceiver{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-scan-both-receiver",
+					Namespace: ReceiverNamespace,
+				},
+				Spec: radiov1.RtlSdrReceiverSpec{
+					Version: radiov1.V4,
+					Scan: &radiov1.RtlSdrScan{
+						Frequencies: []resource.Quantity{resource.MustParse("145M")},
+						Range: &radiov1.RtlSdrScanRange{
+							Start: resource.MustParse("144M"),
+							End:   resource.MustParse("146M"),
+							Step:  resource.MustParse("12.5k"),
+						},
+					},
+				},
+			}
+			err := k8sClient.Create(ctx, both)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue(), "expected invalid, got %v", err)
+
+			fm := &radiov1.RtlSdrReceiver{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-scan-fm-receiver",
+					Namespace: ReceiverNamespace,
+				},
+				Spec: radiov1.RtlSdrReceiverSpec{
+					Version: radiov1.V4,
+					Mode:    radiov1.ModeFM,
+					Scan: &radiov1.RtlSdrScan{
+						Frequencies: []resource.Quantity{resource.MustParse("145M")},
+					},
+				},
+			}
+			err = k8sClient.Create(ctx, fm)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue(), "expected invalid, got %v", err)
+		})
+	})
+
+	Context("When serving the spectrum of a receiver", func() {
+		It("Should serve the spectrum from the proxy and publish its URL", func(ctx SpecContext) {
+			By("By creating a new RtlSdrReceiver with a spectrum")
+
+			fftSize := int32(2048)
+			recv := &radiov1.RtlSdrReceiver{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-spectrum-receiver",
+					Namespace: ReceiverNamespace,
+				},
+				Spec: radiov1.RtlSdrReceiverSpec{
+					Version:   radiov1.V4,
+					Frequency: ptr.To(resource.MustParse("101.9M")),
+					Spectrum:  &radiov1.RtlSdrSpectrum{FFTSize: &fftSize},
+				},
+			}
+
+			Expect(k8sClient.Create(ctx, recv)).Should(Succeed())
+
+			By("By running reconciler")
+			reconciler := RtlSdrReceiverReconciler{
+				Client:   k8sClient,
+				Scheme:   scheme,
+				Image:    "test-image",
+				MuxImage: "test-mux-image",
+			}
+			receiverLookupKey := types.NamespacedName{Name: recv.Name, Namespace: ReceiverNamespace}
+			result, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: receiverLookupKey})
+			Expect(err).To(Succeed())
+			Expect(result.RequeueAfter).To(BeZero())
+
+			By("By checking the proxy serves the spectrum with the defaulted settings")
+			pod := &corev1.Pod{}
+			Expect(k8sClient.Get(ctx, receiverLookupKey, pod)).To(Succeed())
+			Expect(pod.Spec.Containers).To(HaveLen(2))
+
+			mux := pod.Spec.Containers[1]
+			Expect(mux.Args).To(Equal([]string{
+				"--listen", ":1234",
+				"--upstream", "127.0.0.1:1235",
+				"--max-clients", "1",
+				"--sample-rate", "2048000",
+				"--frequency", "101900000",
+				"--spectrum-listen", ":8090",
+				"--spectrum-fft-size", "2048",
+				"--spectrum-averaging", "4",
+				"--spectrum-frame-rate", "10",
+			}))
+			Expect(mux.Ports).To(ConsistOf(HaveField("ContainerPort", int32(SpectrumPort))))
+
+			service := &corev1.Service{}
+			Expect(k8sClient.Get(ctx, receiverLookupKey, service)).To(Succeed())
+			Expect(service.Spec.Ports).To(HaveLen(2))
+			Expect(service.Spec.Ports[1].Name).To(Equal("spectrum"))
+			Expect(service.Spec.Ports[1].Port).To(Equal(int32(SpectrumPort)))
+
+			updated := &radiov1.RtlSdrReceiver{}
+			Expect(k8sClient.Get(ctx, receiverLookupKey, updated)).To(Succeed())
+			Expect(updated.Status.SpectrumURL).To(Equal("http://test-spectrum-receiver.default.svc:8090/"))
+
+			By("By removing the spectrum again")
+			updated.Spec.Spectrum = nil
+			Expect(k8sClient.Update(ctx, updated)).To(Succeed())
+
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: receiverLookupKey})
+			Expect(err).To(Succeed())
+
+			Expect(k8sClient.Get(ctx, receiverLookupKey, service)).To(Succeed())
+			Expect(service.Spec.Ports).To(HaveLen(1))
+			Expect(k8sClient.Get(ctx, receiverLookupKey, updated)).To(Succeed())
+			Expect(updated.Status.SpectrumURL).To(BeEmpty())
+		})
+
+		It("Should reject invalid FFT sizes and spectrum in FM mode", func(ctx SpecContext) {
+			size := int32(1000)
+			invalid := &radiov1.RtlSdrReceiver{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-spectrum-size-receiver",
+					Namespace: ReceiverNamespace,
+				},
+				Spec: radiov1.RtlSdrReceiverSpec{
+					Version:  radiov1.V4,
+					Spectrum: &radiov1.RtlSdrSpectrum{FFTSize: &size},
+				},
+			}
+			err := k8sClient.Create(ctx, invalid)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue(), "expected invalid, got %v", err)
+
+			fm := &radiov1.RtlSdrReceiver{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-spectrum-fm-receiver",
+					Namespace: ReceiverNamespace,
+				},
+				Spec: radiov1.RtlSdrReceiverSpec{
+					Version:  radiov1.V4,
+					Mode:     radiov1.ModeFM,
+					Spectrum: &radiov1.RtlSdrSpectrum{},
+				},
+			}
+			err = k8sClient.Create(ctx, fm)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue(), "expected invalid, got %v", err)
+		})
+	})
+
+	Context("When publishing a receiver to MQTT", func() {
+		It("Should run the publisher with the signal level measured by the proxy", func(ctx SpecContext) {
+			By("By creating a new RtlSdrReceiver publishing to MQTT")
+
+			recv := &radiov1.RtlSdrReceiver{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-mqtt-receiver",
+					Namespace: ReceiverNamespace,
+				},
+				Spec: radiov1.RtlSdrReceiverSpec{
+					Version:   radiov1.V4,
+					Frequency: ptr.To(resource.MustParse("101.9M")),
+					MQTT: &radiov1.RtlSdrMQTT{
+						Broker:               "mqtt://mosquitto.home.svc:1883",
+						CredentialsSecretRef: &corev1.LocalObjectReference{Name: "mqtt-credentials"},
+						Interval:             &metav1.Duration{Duration: time.Second},
+					},
+				},
+			}
+
+			Expect(k8sClient.Create(ctx, recv)).Should(Succeed())
+			Expect(recv.Spec.MQTT.Topic).To(Equal("radio/{namespace}/{name}"))
+			Expect(recv.Spec.MQTT.QoS).To(Equal(ptr.To[int32](1)))
+
+			By("By running reconciler")
+			reconciler := RtlSdrReceiverReconciler{
+				Client:    k8sClient,
+				Scheme:    scheme,
+				Image:     "test-image",
+				MuxImage:  "test-mux-image",
+				MQTTImage: "test-mqtt-image",
+			}
+			receiverLookupKey := types.NamespacedName{Name: recv.Name, Namespace: ReceiverNamespace}
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: receiverLookupKey})
+			Expect(err).To(Succeed())
+
+			By("By checking the proxy measures the signal level")
+			pod := &corev1.Pod{}
+			Expect(k8sClient.Get(ctx, receiverLookupKey, pod)).To(Succeed())
+			Expect(pod.Spec.Containers).To(HaveLen(3))
+
+			mux := pod.Spec.Containers[1]
+			Expect(mux.Args).To(Equal([]string{
+				"--listen", ":1234",
+				"--upstream", "127.0.0.1:1235",
+				"--max-clients", "1",
+				"--status-listen", ":9180",
+				"--sample-rate", "2048000",
+				"--frequency", "101900000",
+				"--level-interval", "1s",
+			}))
+
+			By("By checking the publisher relays the heartbeats and signal level")
+			publisher := pod.Spec.Containers[2]
+			Expect(publisher.Name).To(Equal("mqtt"))
+			Expect(publisher.Image).To(Equal("test-mqtt-image"))
+			Expect(publisher.Args).To(Equal([]string{
+				"--listen", "127.0.0.1:9181",
+				"--broker", "mqtt://mosquitto.home.svc:1883",
+				"--topic", "radio/default/test-mqtt-receiver",
+				"--qos", "1",
+				"--receiver", "default/test-mqtt-receiver",
+				"--mode", "IQ",
+				"--heartbeat-interval", "30s",
+				"--frequency", "101900000",
+				"--poll", "signal=http://127.0.0.1:9180/level",
+				"--poll-interval", "1s",
+			}))
+			Expect(publisher.Resources.Limits).To(BeEmpty())
+			Expect(publisher.Env).To(ConsistOf(
+				HaveField("ValueFrom.SecretKeyRef", &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "mqtt-credentials"},
+					Key:                  "username",
+				}),
+				HaveField("ValueFrom.SecretKeyRef", &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "mqtt-credentials"},
+					Key:                  "password",
+					Optional:             ptr.To(true),
+				}),
+			))
+		})
+
+		It("Should relay the decoded messages and aircraft of decoders", func(ctx SpecContext) {
+			reconciler := RtlSdrReceiverReconciler{
+				Client:       k8sClient,
+				Scheme:       scheme,
+				Image:        "test-image",
+				ADSBImage:    "test-adsb-image",
+				DecoderImage: "test-decoder-image",
+				MQTTImage:    "test-mqtt-image",
+			}
+
+			By("By creating a receiver in ISM mode publishing to MQTT")
+			ism := &radiov1.RtlSdrReceiver{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-mqtt-ism-receiver",
+					Namespace: ReceiverNamespace,
+				},
+				Spec: radiov1.RtlSdrReceiverSpec{
+					Version: radiov1.V4,
+					Mode:    radiov1.ModeISM,
+					MQTT: &radiov1.RtlSdrMQTT{
+						Broker: "mqtts://broker.example.com:8883",
+						Topic:  "home/{name}",
+						QoS:    ptr.To[int32](0),
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, ism)).Should(Succeed())
+
+			key := types.NamespacedName{Name: ism.Name, Namespace: ReceiverNamespace}
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).To(Succeed())
+
+			pod := &corev1.Pod{}
+			Expect(k8sClient.Get(ctx, key, pod)).To(Succeed())
+			Expect(pod.Spec.Containers).To(HaveLen(2))
+			Expect(pod.Spec.Containers[0].Args).To(ContainElements("--sink", "http://127.0.0.1:9181/publish/events"))
+			Expect(pod.Spec.Containers[1].Args).To(Equal([]string{
+				"--listen", "127.0.0.1:9181",
+				"--broker", "mqtts://broker.example.com:8883",
+				"--topic", "home/test-mqtt-ism-receiver",
+				"--qos", "0",
+				"--receiver", "default/test-mqtt-ism-receiver",
+				"--mode", "ISM",
+				"--heartbeat-interval", "30s",
+			}))
+			Expect(pod.Spec.Containers[1].Env).To(BeEmpty())
+
+			By("By creating a receiver in ADSB mode publishing to MQTT")
+			adsb := &radiov1.RtlSdrReceiver{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-mqtt-adsb-receiver",
+					Namespace: ReceiverNamespace,
+				},
+				Spec: radiov1.RtlSdrReceiverSpec{
+					Version: radiov1.V4,
+					Mode:    radiov1.ModeADSB,
+					MQTT:    &radiov1.RtlSdrMQTT{Broker: "mqtt://mosquitto.home.svc:1883"},
+				},
+			}
+			Expect(k8sClient.Create(ctx, adsb)).Should(Succeed())
+
+			key = types.NamespacedName{Name: adsb.Name, Namespace: ReceiverNamespace}
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).To(Succeed())
+
+			Expect(k8sClient.Get(ctx, key, pod)).To(Succeed())
+			Expect(pod.Spec.Containers).To(HaveLen(2))
+			Expect(pod.Spec.Containers[1].Args).To(ContainElements(
+				"--poll", "aircraft=http://127.0.0.1:8080/data/aircraft.json",
+				"--poll-interval", "5s",
+			))
+		})
+
+		It("Should reject invalid brokers and topics", func(ctx SpecContext) {
+			recv := &radiov1.RtlSdrReceiver{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-mqtt-invalid-receiver",
+					Namespace: ReceiverNamespace,
+				},
+				Spec: radiov1.RtlSdrReceiverSpec{
+					Version: radiov1.V4,
+					MQTT:    &radiov1.RtlSdrMQTT{Broker: "http://broker"},
+				},
+			}
+
+			err := k8sClient.Create(ctx, recv)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue(), "expected invalid, got %v", err)
+
+			recv.Spec.MQTT = &radiov1.RtlSdrMQTT{Broker: "mqtt://broker", Topic: "radio/#"}
+			err = k8sClient.Create(ctx, recv)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue(), "expected invalid, got %v", err)
+
+			recv.Spec.MQTT = &radiov1.RtlSdrMQTT{Broker: "mqtt://broker", QoS: ptr.To[int32](3)}
+			err = k8sClient.Create(ctx, recv)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue(), "expected invalid, got %v", err)
+		})
+	})
+
+	Context("When exporting metrics of a receiver", func() {
+		It("Should run the exporter on a tap of the proxy and create a PodMonitor", func(ctx SpecContext) {
+			By("By creating a new RtlSdrReceiver exporting metrics")
+
+			recv := &radiov1.RtlSdrReceiver{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-metrics-receiver",
+					Namespace: ReceiverNamespace,
+				},
+				Spec: radiov1.RtlSdrReceiverSpec{
+					Version:   radiov1.V4,
+					Frequency: ptr.To(resource.MustParse("101.9M")),
+					Metrics:   &radiov1.RtlSdrMetrics{Interval: &metav1.Duration{Duration: 15 * time.Second}},
+				},
+			}
+
+			Expect(k8sClient.Create(ctx, recv)).Should(Succeed())
+			Expect(recv.Spec.Metrics.FFTSize).To(Equal(ptr.To[int32](1024)))
+
+			By("By running reconciler")
+			reconciler := RtlSdrReceiverReconciler{
+				Client:        k8sClient,
+				Scheme:        scheme,
+				Image:         "test-image",
+				MuxImage:      "test-mux-image",
+				ExporterImage: "test-exporter-image",
+			}
+			receiverLookupKey := types.NamespacedName{Name: recv.Name, Namespace: ReceiverNamespace}
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: receiverLookupKey})
+			Expect(err).To(Succeed())
+
+			By("By checking the proxy serves a tap and its tuning")
+			pod := &corev1.Pod{}
+			Expect(k8sClient.Get(ctx, receiverLookupKey, pod)).To(Succeed())
+			Expect(pod.Spec.Containers).To(HaveLen(3))
+			Expect(pod.Spec.ShareProcessNamespace).To(Equal(ptr.To(true)))
+
+			mux := pod.Spec.Containers[1]
+			Expect(mux.Args).To(Equal([]string{
+				"--listen", ":1234",
+				"--upstream", "127.0.0.1:1235",
+				"--max-clients", "1",
+				"--status-listen", ":9180",
+				"--sample-rate", "2048000",
+				"--frequency", "101900000",
+				"--tap-listen", "127.0.0.1:9182",
+			}))
+
+			By("By checking the exporter measures the tap")
+			exporter := pod.Spec.Containers[2]
+			Expect(exporter.Name).To(Equal("exporter"))
+			Expect(exporter.Image).To(Equal("test-exporter-image"))
+			Expect(exporter.Args).To(Equal([]string{
+				"--listen", ":9190",
+				"--upstream", "127.0.0.1:9182",
+				"--tuning-url", "http://127.0.0.1:9180/tuning",
+				"--receiver", "default/test-metrics-receiver",
+				"--interval", "15s",
+				"--fft-size", "1024",
+				"--sample-rate", "2048000",
+				"--frequency", "101900000",
+			}))
+			Expect(exporter.Ports).To(ConsistOf(And(
+				HaveField("Name", "metrics"),
+				HaveField("ContainerPort", int32(MetricsPort)),
+			)))
+			Expect(exporter.Resources.Limits).To(BeEmpty())
+			Expect(exporter.SecurityContext.RunAsNonRoot).To(Equal(ptr.To(true)))
+
+			By("By checking the PodMonitor scrapes the exporter")
+			monitor := &unstructured.Unstructured{}
+			monitor.SetGroupVersionKind(podMonitorGVK)
+			Expect(k8sClient.Get(ctx, receiverLookupKey, monitor)).To(Succeed())
+			Expect(metav1.IsControlledBy(monitor, recv)).To(BeTrue())
+			Expect(monitor.GetLabels()).To(Equal(receiverLabels(recv)))
+
+			selector, _, err := unstructured.NestedStringMap(monitor.Object, "spec", "selector", "matchLabels")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(selector).To(Equal(receiverLabels(recv)))
+
+			endpoints, _, err := unstructured.NestedSlice(monitor.Object, "spec", "podMetricsEndpoints")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(endpoints).To(ConsistOf(map[string]any{
+				"port":     "metrics",
+				"path":     "/metrics",
+				"interval": "15s",
+			}))
+
+			By("By no longer exporting metrics")
+			updated := &radiov1.RtlSdrReceiver{}
+			Expect(k8sClient.Get(ctx, receiverLookupKey, updated)).To(Succeed())
+			updated.Spec.Metrics = nil
+			Expect(k8sClient.Update(ctx, updated)).To(Succeed())
+
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: receiverLookupKey})
+			Expect(err).To(Succeed())
+
+			err = k8sClient.Get(ctx, receiverLookupKey, monitor)
+			Expect(apierrors.IsNotFound(err)).To(BeTrue(), "expected not found, got %v", err)
+		})
+
+		It("Should run without a PodMonitor when the Prometheus Operator is missing", func(ctx SpecContext) {
+			recv := &radiov1.RtlSdrReceiver{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-metrics-no-operator-receiver",
+					Namespace: ReceiverNamespace,
+				},
+				Spec: radiov1.RtlSdrReceiverSpec{
+					Version: radiov1.V4,
+					Metrics: &radiov1.RtlSdrMetrics{},
+				},
+			}
+			Expect(k8sClient.Create(ctx, recv)).Should(Succeed())
+
+			watchClient, err := client.NewWithWatch(cfg, client.Options{Scheme: k8sClient.Scheme()})
+			Expect(err).To(Succeed())
+
+			// Answer as an API server without the PodMonitor kind.
+			noOperatorClient := interceptor.NewClient(watchClient, interceptor.Funcs{
+				Apply: func(ctx context.Context, c client.WithWatch, obj runtime.ApplyConfiguration, opts ...client.ApplyOption) error {
+					if u, ok := obj.(interface{ GetKind() string }); ok && u.GetKind() == podMonitorGVK.Kind {
+						return &meta.NoKindMatchError{GroupKind: podMonitorGVK.GroupKind(), SearchedVersions: []string{"v1"}}
+					}
+
+					return c.Apply(ctx, obj, opts...)
+				},
+			})
+
+			reconciler := RtlSdrReceiverReconciler{
+				Client:        noOperatorClient,
+				Scheme:        scheme,
+				Image:         "test-image",
+				MuxImage:      "test-mux-image",
+				ExporterImage: "test-exporter-image",
+			}
+			receiverLookupKey := types.NamespacedName{Name: recv.Name, Namespace: ReceiverNamespace}
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: receiverLookupKey})
+			Expect(err).To(Succeed())
+
+			pod := &corev1.Pod{}
+			Expect(k8sClient.Get(ctx, receiverLookupKey, pod)).To(Succeed())
+			Expect(pod.Spec.Containers).To(ContainElement(HaveField("Name", "exporter")))
+
+			monitor := &unstructured.Unstructured{}
+			monitor.SetGroupVersionKind(podMonitorGVK)
+			err = k8sClient.Get(ctx, receiverLookupKey, monitor)
+			Expect(apierrors.IsNotFound(err)).To(BeTrue(), "expected not found, got %v", err)
+		})
+
+		It("Should reject metrics outside IQ mode", func(ctx SpecContext) {
+			recv := &radiov1.RtlSdrReceiver{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-metrics-fm-receiver",
+					Namespace: ReceiverNamespace,
+				},
+				Spec: radiov1.RtlSdrReceiverSpec{
+					Version: radiov1.V4,
+					Mode:    radiov1.ModeFM,
+					Metrics: &radiov1.RtlSdrMetrics{},
+				},
+			}
+
+			err := k8sClient.Create(ctx, recv)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue(), "expected invalid, got %v", err)
+		})
+	})
+
+	Context("When detecting sample loss", func() {
+		It("Should set the SampleLoss condition and emit an Event once samples are dropped", func(ctx SpecContext) {
+			By("By creating a new RtlSdrReceiver detecting sample loss")
+
+			recv := &radiov1.RtlSdrReceiver{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-loss-receiver",
+					Namespace: ReceiverNamespace,
+				},
+				Spec: radiov1.RtlSdrReceiverSpec{
+					Version:    radiov1.V4,
+					Frequency:  ptr.To(resource.MustParse("101.9M")),
+					SampleLoss: &radiov1.RtlSdrSampleLoss{Window: &metav1.Duration{Duration: 30 * time.Second}},
+				},
+			}
+
+			Expect(k8sClient.Create(ctx, recv)).Should(Succeed())
+			Expect(recv.Spec.SampleLoss.Threshold).To(Equal("1%"))
+
+			stats := &loss.Stats{SampleRate: 2_048_000, Samples: 2_048_000 * 30}
+			recorder := events.NewFakeRecorder(10)
+			reconciler := RtlSdrReceiverReconciler{
+				Client:   k8sClient,
+				Scheme:   scheme,
+				Image:    "test-image",
+				MuxImage: "test-mux-image",
+				Recorder: recorder,
+				SampleLossStatus: func(_ context.Context, pod *corev1.Pod) (*loss.Stats, error) {
+					Expect(pod.Name).To(Equal(recv.Name))
+					return stats, nil
+				},
+			}
+			receiverLookupKey := types.NamespacedName{Name: recv.Name, Namespace: ReceiverNamespace}
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: receiverLookupKey})
+			Expect(err).To(Succeed())
+
+			By("By checking the proxy counts the dropped samples")
+			pod := &corev1.Pod{}
+			Expect(k8sClient.Get(ctx, receiverLookupKey, pod)).To(Succeed())
+			Expect(pod.Spec.Containers).To(HaveLen(2))
+			Expect(pod.Spec.Containers[1].Args).To(Equal([]string{
+				"--listen", ":1234",
+				"--upstream", "127.0.0.1:1235",
+				"--max-clients", "1",
+				"--status-listen", ":9180",
+				"--loss-window", "30s",
+				"--sample-rate", "2048000",
+				"--frequency", "101900000",
+			}))
+
+			By("By reporting no loss once the pod is running")
+			pod.Status.Phase = corev1.PodRunning
+			pod.Status.PodIP = "10.0.0.12"
+			Expect(k8sClient.Status().Update(ctx, pod)).To(Succeed())
+
+			result, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: receiverLookupKey})
+			Expect(err).To(Succeed())
+			Expect(result.RequeueAfter).To(Equal(30 * time.Second))
+
+			updated := &radiov1.RtlSdrReceiver{}
+			Expect(k8sClient.Get(ctx, receiverLookupKey, updated)).To(Succeed())
+			Expect(updated.Status.SampleLoss).To(Equal(&radiov1.RtlSdrSampleLossStatus{Ratio: "0%"}))
+			condition := meta.FindStatusCondition(updated.Status.Conditions, radiov1.SampleLossCondition)
+			Expect(condition).ToNot(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal(radiov1.NoSampleLossReason))
+			Expect(recorder.Events).To(BeEmpty())
+
+			By("By reporting the samples dropped above the threshold")
+			stats.DroppedSamples = 4 * loss.BufferSamples
+			stats.DroppedBuffers = 4
+			stats.Ratio = 0.0125
+
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: receiverLookupKey})
+			Expect(err).To(Succeed())
+
+			Expect(k8sClient.Get(ctx, receiverLookupKey, updated)).To(Succeed())
+			Expect(updated.Status.SampleLoss).To(Equal(&radiov1.RtlSdrSampleLossStatus{
+				DroppedSamples: 4 * loss.BufferSamples,
+				DroppedBuffers: 4,
+				Ratio:          "1.25%",
+			}))
+			condition = meta.FindStatusCondition(updated.Status.Conditions, radiov1.SampleLossCondition)
+			Expect(condition).ToNot(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+			Expect(condition.Reason).To(Equal(radiov1.SamplesDroppedReason))
+			Expect(condition.Message).To(ContainSubstring("1.25%"))
+			Expect(recorder.Events).To(Receive(HavePrefix("Warning SamplesDropped")))
+
+			By("By emitting the Event only when the loss starts")
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: receiverLookupKey})
+			Expect(err).To(Succeed())
+			Expect(recorder.Events).To(BeEmpty())
+
+			By("By clearing the status once no longer detected")
+			Expect(k8sClient.Get(ctx, receiverLookupKey, updated)).To(Succeed())
+			updated.Spec.SampleLoss = nil
+			Expect(k8sClient.Update(ctx, updated)).To(Succeed())
+
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: receiverLookupKey})
+			Expect(err).To(Succeed())
+
+			Expect(k8sClient.Get(ctx, receiverLookupKey, updated)).To(Succeed())
+			Expect(updated.Status.SampleLoss).To(BeNil())
+			Expect(meta.FindStatusCondition(updated.Status.Conditions, radiov1.SampleLossCondition)).To(BeNil())
+		})
+
+		It("Should reject sample loss detection outside IQ mode", func(ctx SpecContext) {
+			recv := &radiov1.RtlSdrReceiver{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-loss-fm-receiver",
+					Namespace: ReceiverNamespace,
+				},
+				Spec: radiov1.RtlSdrReceiverSpec{
+					Version:    radiov1.V4,
+					Mode:       radiov1.ModeFM,
+					SampleLoss: &radiov1.RtlSdrSampleLoss{},
+				},
+			}
+
+			err := k8sClient.Create(ctx, recv)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue(), "expected invalid, got %v", err)
+			Expect(err.Error()).To(ContainSubstring("sample loss detection is only supported in IQ mode"))
+		})
+
+		It("Should reject a threshold that is not a percentage", func(ctx SpecContext) {
+			recv := &radiov1.RtlSdrReceiver{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-loss-threshold-receiver",
+					Namespace: ReceiverNamespace,
+				},
+				Spec: radiov1.RtlSdrReceiverSpec{
+					Version:    radiov1.V4,
+					SampleLoss: &radiov1.RtlSdrSampleLoss{Threshold: "0.5"},
+				},
+			}
+
+			err := k8sClient.Create(ctx, recv)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue(), "expected invalid, got %v", err)
+		})
+	})
+
+	Context("When guarding access to a receiver", func() {
+		It("Should serve the stream over TLS to clients with a certificate of the receiver CA", func(ctx SpecContext) {
+			By("By creating a new RtlSdrReceiver with guarded access")
+
+			recv := &radiov1.RtlSdrReceiver{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-access-receiver",
+					Namespace: ReceiverNamespace,
+				},
+				Spec: radiov1.RtlSdrReceiverSpec{
+					Version:   radiov1.V4,
+					Frequency: ptr.To(resource.MustParse("101.9M")),
+					ContainerPort: &corev1.ContainerPort{
+						ContainerPort: 1234,
+						HostPort:      1234,
+					},
+					Access: &radiov1.RtlSdrAccess{Hosts: []string{"192.168.1.10"}},
+				},
+			}
+
+			Expect(k8sClient.Create(ctx, recv)).Should(Succeed())
+			Expect(recv.Spec.Access.Authentication).To(Equal(radiov1.AuthenticationClientCertificate))
+
+			By("By running reconciler")
+			now := time.Now()
+			clock := clocktesting.NewFakePassiveClock(now)
+			reconciler := RtlSdrReceiverReconciler{
+				Client:      k8sClient,
+				Scheme:      scheme,
+				Image:       "test-image",
+				AccessImage: "test-access-image",
+				Clock:       clock,
+			}
+			receiverLookupKey := types.NamespacedName{Name: recv.Name, Namespace: ReceiverNamespace}
+			result, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: receiverLookupKey})
+			Expect(err).To(Succeed())
+			Expect(result.RequeueAfter).To(BeNumerically(">", 200*24*time.Hour))
+
+			By("By checking rtl_tcp only listens behind the access proxy")
+			pod := &corev1.Pod{}
+			Expect(k8sClient.Get(ctx, receiverLookupKey, pod)).To(Succeed())
+			Expect(pod.Spec.Containers).To(HaveLen(2))
+			Expect(pod.Spec.Containers[0].Args).To(Equal([]string{"-a", "127.0.0.1", "-f", "101900k", "-p", "9183"}))
+			Expect(pod.Spec.Containers[0].Ports).To(BeEmpty())
+
+			access := pod.Spec.Containers[1]
+			Expect(access.Name).To(Equal("access"))
+			Expect(access.Image).To(Equal("test-access-image"))
+			Expect(access.Args).To(Equal([]string{
+				"--listen", ":1234",
+				"--upstream", "127.0.0.1:9183",
+				"--tls-cert", "/tls/tls.crt",
+				"--tls-key", "/tls/tls.key",
+				"--client-ca", "/tls/ca.crt",
+			}))
+			Expect(access.Ports).To(ConsistOf(And(
+				HaveField("ContainerPort", int32(1234)),
+				HaveField("HostPort", int32(1234)),
+			)))
+			Expect(access.SecurityContext.RunAsNonRoot).To(Equal(ptr.To(true)))
+			Expect(pod.Spec.Volumes).To(ContainElement(HaveField("Secret.SecretName", "test-access-receiver-access-tls")))
+
+			By("By checking the certificates are issued by the CA of the receiver")
+			caConfigMap := &corev1.ConfigMap{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "test-access-receiver-access-ca", Namespace: ReceiverNamespace}, caConfigMap)).To(Succeed())
+			Expect(metav1.IsControlledBy(caConfigMap, recv)).To(BeTrue())
+			roots := x509.NewCertPool()
+			Expect(roots.AppendCertsFromPEM([]byte(caConfigMap.Data["ca.crt"]))).To(BeTrue())
+
+			server := &corev1.Secret{}
+			serverKey := types.NamespacedName{Name: "test-access-receiver-access-tls", Namespace: ReceiverNamespace}
+			Expect(k8sClient.Get(ctx, serverKey, server)).To(Succeed())
+			Expect(metav1.IsControlledBy(server, recv)).To(BeTrue())
+			Expect(server.Type).To(Equal(corev1.SecretTypeTLS))
+			serverCert, err := tls.X509KeyPair(server.Data["tls.crt"], server.Data["tls.key"])
+			Expect(err).ToNot(HaveOccurred())
+			for _, host := range []string{"test-access-receiver.default.svc", "192.168.1.10"} {
+				_, err = serverCert.Leaf.Verify(x509.VerifyOptions{DNSName: host, Roots: roots})
+				Expect(err).ToNot(HaveOccurred(), host)
+			}
+
+			clientSecret := &corev1.Secret{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "test-access-receiver-access-client", Namespace: ReceiverNamespace}, clientSecret)).To(Succeed())
+			clientCert, err := tls.X509KeyPair(clientSecret.Data["tls.crt"], clientSecret.Data["tls.key"])
+			Expect(err).ToNot(HaveOccurred())
+			_, err = clientCert.Leaf.Verify(x509.VerifyOptions{Roots: roots, KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(clientSecret.Data["ca.crt"]).To(Equal([]byte(caConfigMap.Data["ca.crt"])))
+
+			caSecret := &corev1.Secret{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "test-access-receiver-access-ca-key", Namespace: ReceiverNamespace}, caSecret)).To(Succeed())
+			Expect(caSecret.Data).To(HaveKey("tls.key"))
+
+			updated := &radiov1.RtlSdrReceiver{}
+			Expect(k8sClient.Get(ctx, receiverLookupKey, updated)).To(Succeed())
+			Expect(updated.Status.Endpoint).To(Equal("tls://test-access-receiver.default.svc:1234"))
+			Expect(updated.Status.Access).ToNot(BeNil())
+			Expect(updated.Status.Access.CAConfigMapName).To(Equal("test-access-receiver-access-ca"))
+			Expect(updated.Status.Access.ClientSecretName).To(Equal("test-access-receiver-access-client"))
+			Expect(updated.Status.Access.NotAfter.Time).To(BeTemporally("~", serverCert.Leaf.NotAfter, time.Second))
+
+			By("By keeping the certificates until they are due for renewal")
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: receiverLookupKey})
+			Expect(err).To(Succeed())
+			renewed := &corev1.Secret{}
+			Expect(k8sClient.Get(ctx, serverKey, renewed)).To(Succeed())
+			Expect(renewed.Data["tls.crt"]).To(Equal(server.Data["tls.crt"]))
+
+			clock.SetTime(now.Add(300 * 24 * time.Hour))
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: receiverLookupKey})
+			Expect(err).To(Succeed())
+			Expect(k8sClient.Get(ctx, serverKey, renewed)).To(Succeed())
+			Expect(renewed.Data["tls.crt"]).ToNot(Equal(server.Data["tls.crt"]))
+			Expect(renewed.Data["ca.crt"]).To(Equal(server.Data["ca.crt"]))
+
+			By("By deleting the certificates once access is no longer guarded")
+			Expect(k8sClient.Get(ctx, receiverLookupKey, updated)).To(Succeed())
+			updated.Spec.Access = nil
+			Expect(k8sClient.Update(ctx, updated)).To(Succeed())
+
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: receiverLookupKey})
+			Expect(err).To(Succeed())
+
+			err = k8sClient.Get(ctx, serverKey, renewed)
+			Expect(apierrors.IsNotFound(err)).To(BeTrue(), "expected not found, got %v", err)
+			err = k8sClient.Get(ctx, types.NamespacedName{Name: "test-access-receiver-access-ca", Namespace: ReceiverNamespace}, caConfigMap)
+			Expect(apierrors.IsNotFound(err)).To(BeTrue(), "expected not found, got %v", err)
+
+			Expect(k8sClient.Get(ctx, receiverLookupKey, updated)).To(Succeed())
+			Expect(updated.Status.Access).To(BeNil())
+			Expect(updated.Status.Endpoint).To(Equal("tcp://test-access-receiver.default.svc:1234"))
+		})
+
+		It("Should authenticate the audio of an FM receiver by the tokens of a Secret", func(ctx SpecContext) {
+			recv := &radiov1.RtlSdrReceiver{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-access-fm-receiver",
+					Namespace: ReceiverNamespace,
+				},
+				Spec: radiov1.RtlSdrReceiverSpec{
+					Version:   radiov1.V4,
+					Mode:      radiov1.ModeFM,
+					Frequency: ptr.To(resource.MustParse("101.9M")),
+					Access: &radiov1.RtlSdrAccess{
+						Authentication:  radiov1.AuthenticationToken,
+						TokenSecretName: "radio-tokens",
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, recv)).Should(Succeed())
+
+			reconciler := RtlSdrReceiverReconciler{
+				Client:      k8sClient,
+				Scheme:      scheme,
+				FMImage:     "test-fm-image",
+				AccessImage: "test-access-image",
+			}
+			receiverLookupKey := types.NamespacedName{Name: recv.Name, Namespace: ReceiverNamespace}
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: receiverLookupKey})
+			Expect(err).To(Succeed())
+
+			pod := &corev1.Pod{}
+			Expect(k8sClient.Get(ctx, receiverLookupKey, pod)).To(Succeed())
+			Expect(pod.Spec.Containers).To(HaveLen(2))
+			Expect(pod.Spec.Containers[0].Args).To(HaveExactElements("--listen", "127.0.0.1:9183", "--path", "/audio.wav", "--", "fm-streamer", "--frequency", "101900k"))
+			Expect(pod.Spec.Containers[0].Ports).To(BeEmpty())
+
+			access := pod.Spec.Containers[1]
+			Expect(access.Args).To(Equal([]string{
+				"--listen", ":8000",
+				"--upstream", "127.0.0.1:9183",
+				"--tls-cert", "/tls/tls.crt",
+				"--tls-key", "/tls/tls.key",
+				"--http",
+				"--tokens", "/tokens",
+			}))
+			Expect(access.Ports).To(ConsistOf(HaveField("Name", "audio")))
+			Expect(pod.Spec.Volumes).To(ContainElement(HaveField("Secret.SecretName", "radio-tokens")))
+
+			updated := &radiov1.RtlSdrReceiver{}
+			Expect(k8sClient.Get(ctx, receiverLookupKey, updated)).To(Succeed())
+			Expect(updated.Status.Endpoint).To(Equal("https://test-access-fm-receiver.default.svc:8000/audio.wav"))
+			Expect(updated.Status.Access.ClientSecretName).To(BeEmpty())
+
+			clientSecret := &corev1.Secret{}
+			err = k8sClient.Get(ctx, types.NamespacedName{Name: "test-access-fm-receiver-access-client", Namespace: ReceiverNamespace}, clientSecret)
+			Expect(apierrors.IsNotFound(err)).To(BeTrue(), "expected not found, got %v", err)
+		})
+
+		It("Should review tokens as the service account of the receiver", func(ctx SpecContext) {
+			recv := &radiov1.RtlSdrReceiver{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-access-review-receiver",
+					Namespace: ReceiverNamespace,
+				},
+				Spec: radiov1.RtlSdrReceiverSpec{
+					Version: radiov1.V4,
+					Sharing: &radiov1.RtlSdrSharing{Enabled: true},
+					Access: &radiov1.RtlSdrAccess{
+						Authentication:     radiov1.AuthenticationTokenReview,
+						ServiceAccountName: "radio-reviewer",
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, recv)).Should(Succeed())
+
+			reconciler := RtlSdrReceiverReconciler{
+				Client:      k8sClient,
+				Scheme:      scheme,
+				Image:       "test-image",
+				MuxImage:    "test-mux-image",
+				AccessImage: "test-access-image",
+			}
+			receiverLookupKey := types.NamespacedName{Name: recv.Name, Namespace: ReceiverNamespace}
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: receiverLookupKey})
+			Expect(err).To(Succeed())
+
+			pod := &corev1.Pod{}
+			Expect(k8sClient.Get(ctx, receiverLookupKey, pod)).To(Succeed())
+			Expect(pod.Spec.ServiceAccountName).To(Equal("radio-reviewer"))
+			Expect(pod.Spec.Containers).To(HaveLen(3))
+			Expect(pod.Spec.Containers[0].Args).To(ContainElements("127.0.0.1", "1235"))
+			Expect(pod.Spec.Containers[1].Args[:2]).To(Equal([]string{"--listen", "127.0.0.1:9183"}))
+			Expect(pod.Spec.Containers[2].Args).To(Equal([]string{
+				"--listen", ":1234",
+				"--upstream", "127.0.0.1:9183",
+				"--tls-cert", "/tls/tls.crt",
+				"--tls-key", "/tls/tls.key",
+				"--token-review",
+				"--audiences", "test-access-review-receiver.default.svc",
+			}))
+		})
+
+		It("Should reject Token authentication without a Secret", func(ctx SpecContext) {
+			recv := &radiov1.RtlSdrReceiver{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-access-no-secret-receiver",
+					Namespace: ReceiverNamespace,
+				},
+				Spec: radiov1.RtlSdrReceiverSpec{
+					Version: radiov1.V4,
+					Access:  &radiov1.RtlSdrAccess{Authentication: radiov1.AuthenticationToken},
+				},
+			}
+
+			err := k8sClient.Create(ctx, recv)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue(), "expected invalid, got %v", err)
+			Expect(err.Error()).To(ContainSubstring("tokenSecretName is required for Token authentication"))
+		})
+
+		It("Should reject access outside IQ and FM mode", func(ctx SpecContext) {
+			recv := &radiov1.RtlSdrReceiver{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-access-adsb-receiver",
+					Namespace: ReceiverNamespace,
+				},
+				Spec: radiov1.RtlSdrReceiverSpec{
+					Version: radiov1.V4,
+					Mode:    radiov1.ModeADSB,
+					Access:  &radiov1.RtlSdrAccess{},
+				},
+			}
+
+			err := k8sClient.Create(ctx, recv)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue(), "expected invalid, got %v", err)
+			Expect(err.Error()).To(ContainSubstring("access is only supported in IQ and FM mode"))
+		})
+
+		It("Should reject the spectrum of receivers with access", func(ctx SpecContext) {
+			recv := &radiov1.RtlSdrReceiver{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-access-spectrum-receiver",
+					Namespace: ReceiverNamespace,
+				},
+				Spec: radiov1.RtlSdrReceiverSpec{
+					Version:  radiov1.V4,
+					Access:   &radiov1.RtlSdrAccess{},
+					Spectrum: &radiov1.RtlSdrSpectrum{},
+				},
+			}
+
+			err := k8sClient.Create(ctx, recv)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue(), "expected invalid, got %v", err)
+			Expect(err.Error()).To(ContainSubstring("the spectrum of receivers with access cannot be served"))
+		})
+
+		It("Should only let the controller reach the status of receivers with access", func(ctx SpecContext) {
+			recv := &radiov1.RtlSdrReceiver{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-access-status-receiver",
+					Namespace: ReceiverNamespace,
+				},
+				Spec: radiov1.RtlSdrReceiverSpec{
+					Version: radiov1.V4,
+					Access:  &radiov1.RtlSdrAccess{},
+					Metrics: &radiov1.RtlSdrMetrics{},
+				},
+			}
+			Expect(k8sClient.Create(ctx, recv)).Should(Succeed())
+
+			reconciler := RtlSdrReceiverReconciler{
+				Client:        k8sClient,
+				Scheme:        scheme,
+				Image:         "test-image",
+				MuxImage:      "test-mux-image",
+				ExporterImage: "test-exporter-image",
+				AccessImage:   "test-access-image",
+			}
+			receiverLookupKey := types.NamespacedName{Name: recv.Name, Namespace: ReceiverNamespace}
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: receiverLookupKey})
+			Expect(err).To(Succeed())
+
+			By("By checking anyone reaches the guarded stream and the metrics")
+			policy := &networkingv1.NetworkPolicy{}
+			Expect(k8sClient.Get(ctx, receiverLookupKey, policy)).To(Succeed())
+			Expect(metav1.IsControlledBy(policy, recv)).To(BeTrue())
+			Expect(policy.Spec.Ingress).To(HaveLen(2))
+			Expect(policy.Spec.Ingress[0].From).To(BeEmpty())
+			Expect(policy.Spec.Ingress[0].Ports).To(ConsistOf(
+				HaveField("Port.IntVal", int32(1234)),
+				HaveField("Port.IntVal", int32(MetricsPort)),
+			))
+
+			By("By checking only the controller reaches the status")
+			Expect(policy.Spec.Ingress[1].Ports).To(ConsistOf(HaveField("Port.IntVal", int32(ProxyStatusPort))))
+			Expect(policy.Spec.Ingress[1].From).To(ConsistOf(networkingv1.NetworkPolicyPeer{
+				NamespaceSelector: &metav1.LabelSelector{},
+				PodSelector:       &metav1.LabelSelector{MatchLabels: map[string]string{"control-plane": "controller-manager"}},
+			}))
+		})
+	})
+
+	Context("When restricting who can reach a receiver", func() {
+		It("Should only let the allowed clients, the controller and Prometheus through", func(ctx SpecContext) {
+			By("By creating a new RtlSdrReceiver with a NetworkPolicy")
+
+			recv := &radiov1.RtlSdrReceiver{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-policy-receiver",
+					Namespace: ReceiverNamespace,
+				},
+				Spec: radiov1.RtlSdrReceiverSpec{
+					Version:  radiov1.V4,
+					Spectrum: &radiov1.RtlSdrSpectrum{},
+					Metrics:  &radiov1.RtlSdrMetrics{},
+					NetworkPolicy: &radiov1.RtlSdrNetworkPolicy{
+						From: []networkingv1.NetworkPolicyPeer{
+							{
+								NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"radio": "listeners"}},
+								PodSelector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{
+									Key:      "app",
+									Operator: metav1.LabelSelectorOpIn,
+									Values:   []string{"gqrx", "sdrpp"},
+								}}},
+							},
+							{IPBlock: &networkingv1.IPBlock{CIDR: "192.168.1.0/24", Except: []string{"192.168.1.1/32"}}},
+						},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, recv)).Should(Succeed())
+
+			By("By running reconciler")
+			reconciler := RtlSdrReceiverReconciler{
+				Client:              k8sClient,
+				Scheme:              scheme,
+				Image:               "test-image",
+				MuxImage:            "test-mux-image",
+				ExporterImage:       "test-exporter-image",
+				ControllerNamespace: "radio-system",
+			}
+			receiverLookupKey := types.NamespacedName{Name: recv.Name, Namespace: ReceiverNamespace}
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: receiverLookupKey})
+			Expect(err).To(Succeed())
+
+			By("By checking the NetworkPolicy selects the receiver pods")
+			policy := &networkingv1.NetworkPolicy{}
+			Expect(k8sClient.Get(ctx, receiverLookupKey, policy)).To(Succeed())
+			Expect(metav1.IsControlledBy(policy, recv)).To(BeTrue())
+			Expect(policy.Spec.PodSelector.MatchLabels).To(Equal(receiverLabels(recv)))
+			Expect(policy.Spec.PolicyTypes).To(Equal([]networkingv1.PolicyType{networkingv1.PolicyTypeIngress}))
+			Expect(policy.Spec.Ingress).To(HaveLen(3))
+
+			ports := func(rule networkingv1.NetworkPolicyIngressRule) []int32 {
+				var ports []int32
+				for _, port := range rule.Ports {
+					Expect(port.Protocol).To(Equal(ptr.To(corev1.ProtocolTCP)))
+					ports = append(ports, port.Port.IntVal)
+				}
+				return ports
+			}
+
+			By("By checking the allowed clients reach the stream and the spectrum")
+			clients := policy.Spec.Ingress[0]
+			Expect(ports(clients)).To(Equal([]int32{1234, SpectrumPort}))
+			Expect(clients.From).To(Equal(recv.Spec.NetworkPolicy.From))
+
+			By("By checking the controller reaches the status")
+			controller := policy.Spec.Ingress[1]
+			Expect(ports(controller)).To(Equal([]int32{ProxyStatusPort}))
+			Expect(controller.From).To(ConsistOf(networkingv1.NetworkPolicyPeer{
+				NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"kubernetes.io/metadata.name": "radio-system"}},
+				PodSelector:       &metav1.LabelSelector{MatchLabels: map[string]string{"control-plane": "controller-manager"}},
+			}))
+
+			By("By checking Prometheus reaches the metrics")
+			metrics := policy.Spec.Ingress[2]
+			Expect(ports(metrics)).To(Equal([]int32{MetricsPort}))
+			Expect(metrics.From).To(ConsistOf(networkingv1.NetworkPolicyPeer{
+				NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"metrics": "enabled"}},
+			}))
+
+			By("By letting the clients reach the channels split from the receiver")
+			channel := &radiov1.RtlSdrChannel{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-policy-channel",
+					Namespace: ReceiverNamespace,
+				},
+				Spec: radiov1.RtlSdrChannelSpec{
+					Receiver:  recv.Name,
+					Offset:    resource.MustParse("-200k"),
+					Bandwidth: resource.MustParse("100k"),
+				},
+			}
+			Expect(k8sClient.Create(ctx, channel)).To(Succeed())
+			DeferCleanup(func(ctx SpecContext) {
+				Expect(k8sClient.Delete(ctx, channel)).To(Succeed())
+			})
+
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: receiverLookupKey})
+			Expect(err).To(Succeed())
+			Expect(k8sClient.Get(ctx, receiverLookupKey, policy)).To(Succeed())
+			Expect(ports(policy.Spec.Ingress[0])).To(Equal([]int32{1234, SpectrumPort, channelBasePort}))
+
+			By("By deleting the NetworkPolicy once anyone may connect")
+			updated := &radiov1.RtlSdrReceiver{}
+			Expect(k8sClient.Get(ctx, receiverLookupKey, updated)).To(Succeed())
+			updated.Spec.NetworkPolicy = nil
+			Expect(k8sClient.Update(ctx, updated)).To(Succeed())
+
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: receiverLookupKey})
+			Expect(err).To(Succeed())
+
+			err = k8sClient.Get(ctx, receiverLookupKey, policy)
+			Expect(apierrors.IsNotFound(err)).To(BeTrue(), "expected not found, got %v", err)
+		})
+
+		It("Should only let the controller through without allowed clients", func(ctx SpecContext) {
+			recv := &radiov1.RtlSdrReceiver{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-policy-adsb-receiver",
+					Namespace: ReceiverNamespace,
+				},
+				Spec: radiov1.RtlSdrReceiverSpec{
+					Version:       radiov1.V4,
+					Mode:          radiov1.ModeADSB,
+					NetworkPolicy: &radiov1.RtlSdrNetworkPolicy{},
+				},
+			}
+			Expect(k8sClient.Create(ctx, recv)).Should(Succeed())
+
+			reconciler := RtlSdrReceiverReconciler{
+				Client:    k8sClient,
+				Scheme:    scheme,
+				ADSBImage: "test-adsb-image",
+			}
+			receiverLookupKey := types.NamespacedName{Name: recv.Name, Namespace: ReceiverNamespace}
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: receiverLookupKey})
+			Expect(err).To(Succeed())
+
+			policy := &networkingv1.NetworkPolicy{}
+			Expect(k8sClient.Get(ctx, receiverLookupKey, policy)).To(Succeed())
+			Expect(policy.Spec.Ingress).To(HaveLen(1))
+			Expect(policy.Spec.Ingress[0].Ports).To(ConsistOf(HaveField("Port.IntVal", int32(ADSBPort))))
+			Expect(policy.Spec.Ingress[0].From).To(ConsistOf(networkingv1.NetworkPolicyPeer{
+				NamespaceSelector: &metav1.LabelSelector{},
+				PodSelector:       &metav1.LabelSelector{MatchLabels: map[string]string{"control-plane": "controller-manager"}},
+			}))
+		})
+	})
+
+	Context("When exposing a receiver outside the cluster", func() {
+		It("Should route the spectrum and the stream through a Gateway", func(ctx SpecContext) {
+			By("By creating a new RtlSdrReceiver exposed through a Gateway")
+
+			recv := &radiov1.RtlSdrReceiver{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-expose-gateway-receiver",
+					Namespace: ReceiverNamespace,
+				},
+				Spec: radiov1.RtlSdrReceiverSpec{
+					Version:  radiov1.V4,
+					Spectrum: &radiov1.RtlSdrSpectrum{},
+					Expose: &radiov1.RtlSdrExpose{
+						Hostname:   "radio.example.com",
+						PathPrefix: "/spectrum/",
+						Gateway: &radiov1.RtlSdrGatewayExpose{
+							Name:           "test-expose-gateway",
+							SectionName:    "https",
+							TCPSectionName: "sdr",
+						},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, recv)).Should(Succeed())
+
+			By("By running reconciler before the Gateway exists")
+			reconciler := RtlSdrReceiverReconciler{
+				Client:   k8sClient,
+				Scheme:   scheme,
+				Image:    "test-image",
+				MuxImage: "test-mux-image",
+			}
+			receiverLookupKey := types.NamespacedName{Name: recv.Name, Namespace: ReceiverNamespace}
+			result, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: receiverLookupKey})
+			Expect(err).To(Succeed())
+			Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+			Expect(result.RequeueAfter).To(BeNumerically("<=", gatewayAddressPollInterval))
+
+			By("By checking the HTTPRoute strips the prefix of the spectrum")
+			route := &unstructured.Unstructured{}
+			route.SetGroupVersionKind(httpRouteGVK)
+			Expect(k8sClient.Get(ctx, receiverLookupKey, route)).To(Succeed())
+			Expect(metav1.IsControlledBy(route, recv)).To(BeTrue())
+			Expect(route.Object["spec"]).To(Equal(map[string]any{
+				"parentRefs": []any{map[string]any{"name": "test-expose-gateway", "sectionName": "https"}},
+				"hostnames":  []any{"radio.example.com"},
+				"rules": []any{map[string]any{
+					"matches": []any{map[string]any{
+						"path": map[string]any{"type": "PathPrefix", "value": "/spectrum"},
+					}},
+					"filters": []any{map[string]any{
+						"type": "URLRewrite",
+						"urlRewrite": map[string]any{
+							"path": map[string]any{"type": "ReplacePrefixMatch", "replacePrefixMatch": "/"},
+						},
+					}},
+					"backendRefs": []any{map[string]any{"name": recv.Name, "port": int64(SpectrumPort)}},
+				}},
+			}))
+
+			By("By checking the TCPRoute routes the stream")
+			stream := &unstructured.Unstructured{}
+			stream.SetGroupVersionKind(tcpRouteGVK)
+			Expect(k8sClient.Get(ctx, receiverLookupKey, stream)).To(Succeed())
+			Expect(metav1.IsControlledBy(stream, recv)).To(BeTrue())
+			Expect(stream.Object["spec"]).To(Equal(map[string]any{
+				"parentRefs": []any{map[string]any{"name": "test-expose-gateway", "sectionName": "sdr"}},
+				"rules": []any{map[string]any{
+					"backendRefs": []any{map[string]any{"name": recv.Name, "port": int64(1234)}},
+				}},
+			}))
+
+			updated := &radiov1.RtlSdrReceiver{}
+			Expect(k8sClient.Get(ctx, receiverLookupKey, updated)).To(Succeed())
+			Expect(updated.Status.Expose).To(Equal(&radiov1.RtlSdrExposeStatus{
+				URL: "http://radio.example.com/spectrum/",
+			}))
+
+			By("By publishing the URLs once the Gateway has an address")
+			gateway := &unstructured.Unstructured{Object: map[string]any{
+				"metadata": map[string]any{"name": "test-expose-gateway", "namespace": ReceiverNamespace},
+				"spec": map[string]any{
+					"listeners": []any{
+						map[string]any{"name": "http", "protocol": "HTTP", "port": int64(80)},
+						map[string]any{"name": "https", "protocol": "HTTPS", "port": int64(443)},
+						map[string]any{"name": "sdr", "protocol": "TCP", "port": int64(31234)},
+					},
+				},
+				"status": map[string]any{
+					"addresses": []any{map[string]any{"type": "IPAddress", "value": "192.0.2.1"}},
+				},
+			}}
+			gateway.SetGroupVersionKind(gatewayGVK)
+			Expect(k8sClient.Create(ctx, gateway)).To(Succeed())
+			DeferCleanup(func(ctx SpecContext) {
+				Expect(k8sClient.Delete(ctx, gateway)).To(Succeed())
+			})
+
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: receiverLookupKey})
+			Expect(err).To(Succeed())
+			Expect(k8sClient.Get(ctx, receiverLookupKey, updated)).To(Succeed())
+			Expect(updated.Status.Expose).To(Equal(&radiov1.RtlSdrExposeStatus{
+				URL:       "https://radio.example.com/spectrum/",
+				StreamURL: "tcp://192.0.2.1:31234",
+			}))
+
+			By("By replacing the routes with an Ingress")
+			updated.Spec.Expose.Gateway = nil
+			updated.Spec.Expose.Ingress = &radiov1.RtlSdrIngressExpose{}
+			Expect(k8sClient.Update(ctx, updated)).To(Succeed())
+
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: receiverLookupKey})
+			Expect(err).To(Succeed())
+
+			err = k8sClient.Get(ctx, receiverLookupKey, route)
+			Expect(apierrors.IsNotFound(err)).To(BeTrue(), "expected not found, got %v", err)
+			err = k8sClient.Get(ctx, receiverLookupKey, stream)
+			Expect(apierrors.IsNotFound(err)).To(BeTrue(), "expected not found, got %v", err)
+			ing := &networkingv1.Ingress{}
+			Expect(k8sClient.Get(ctx, receiverLookupKey, ing)).To(Succeed())
+
+			By("By deleting the Ingress once the receiver is no longer exposed")
+			Expect(k8sClient.Get(ctx, receiverLookupKey, updated)).To(Succeed())
+			updated.Spec.Expose = nil
+			Expect(k8sClient.Update(ctx, updated)).To(Succeed())
+
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: receiverLookupKey})
+			Expect(err).To(Succeed())
+
+			err = k8sClient.Get(ctx, receiverLookupKey, ing)
+			Expect(apierrors.IsNotFound(err)).To(BeTrue(), "expected not found, got %v", err)
+			Expect(k8sClient.Get(ctx, receiverLookupKey, updated)).To(Succeed())
+			Expect(updated.Status.Expose).To(BeNil())
+		})
+
+		It("Should route the audio through an Ingress", func(ctx SpecContext) {
+			recv := &radiov1.RtlSdrReceiver{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-expose-ingress-receiver",
+					Namespace: ReceiverNamespace,
+				},
+				Spec: radiov1.RtlSdrReceiverSpec{
+					Version: radiov1.V4,
+					Mode:    radiov1.ModeFM,
+					Expose: &radiov1.RtlSdrExpose{
+						PathPrefix: "/fm",
+						Ingress: &radiov1.RtlSdrIngressExpose{
+							ClassName:   ptr.To("nginx"),
+							Annotations: map[string]string{"nginx.ingress.kubernetes.io/proxy-buffering": "off"},
+						},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, recv)).Should(Succeed())
+
+			reconciler := RtlSdrReceiverReconciler{
+				Client:  k8sClient,
+				Scheme:  scheme,
+				Image:   "test-image",
+				FMImage: "test-fm-image",
+			}
+			receiverLookupKey := types.NamespacedName{Name: recv.Name, Namespace: ReceiverNamespace}
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: receiverLookupKey})
+			Expect(err).To(Succeed())
+
+			By("By checking the Ingress routes the prefix to the audio port")
+			ing := &networkingv1.Ingress{}
+			Expect(k8sClient.Get(ctx, receiverLookupKey, ing)).To(Succeed())
+			Expect(metav1.IsControlledBy(ing, recv)).To(BeTrue())
+			Expect(ing.Annotations).To(HaveKeyWithValue("nginx.ingress.kubernetes.io/proxy-buffering", "off"))
+			Expect(ing.Spec.IngressClassName).To(Equal(ptr.To("nginx")))
+			Expect(ing.Spec.TLS).To(BeEmpty())
+			Expect(ing.Spec.Rules).To(HaveLen(1))
+			Expect(ing.Spec.Rules[0].Host).To(BeEmpty())
+			Expect(ing.Spec.Rules[0].HTTP.Paths).To(ConsistOf(networkingv1.HTTPIngressPath{
+				Path:     "/fm",
+				PathType: ptr.To(networkingv1.PathTypePrefix),
+				Backend: networkingv1.IngressBackend{
+					Service: &networkingv1.IngressServiceBackend{
+						Name: recv.Name,
+						Port: networkingv1.ServiceBackendPort{Number: AudioPort},
+					},
+				},
+			}))
+
+			updated := &radiov1.RtlSdrReceiver{}
+			Expect(k8sClient.Get(ctx, receiverLookupKey, updated)).To(Succeed())
+			Expect(updated.Status.Expose).To(Equal(&radiov1.RtlSdrExposeStatus{}))
+
+			By("By publishing the URL once the Ingress has an address")
+			ing.Status.LoadBalancer.Ingress = []networkingv1.IngressLoadBalancerIngress{{IP: "192.0.2.10"}}
+			Expect(k8sClient.Status().Update(ctx, ing)).To(Succeed())
+
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: receiverLookupKey})
+			Expect(err).To(Succeed())
+			Expect(k8sClient.Get(ctx, receiverLookupKey, updated)).To(Succeed())
+			Expect(updated.Status.Expose).To(Equal(&radiov1.RtlSdrExposeStatus{URL: "http://192.0.2.10/fm/audio.wav"}))
+
+			By("By serving the audio over HTTPS with a certificate")
+			updated.Spec.Expose.Hostname = "radio.example.com"
+			updated.Spec.Expose.Ingress.TLSSecretName = "radio-tls"
+			Expect(k8sClient.Update(ctx, updated)).To(Succeed())
+
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: receiverLookupKey})
+			Expect(err).To(Succeed())
+			Expect(k8sClient.Get(ctx, receiverLookupKey, ing)).To(Succeed())
+			Expect(ing.Spec.Rules[0].Host).To(Equal("radio.example.com"))
+			Expect(ing.Spec.TLS).To(ConsistOf(networkingv1.IngressTLS{
+				Hosts:      []string{"radio.example.com"},
+				SecretName: "radio-tls",
+			}))
+			Expect(k8sClient.Get(ctx, receiverLookupKey, updated)).To(Succeed())
+			Expect(updated.Status.Expose).To(Equal(&radiov1.RtlSdrExposeStatus{URL: "https://radio.example.com/fm/audio.wav"}))
+		})
+
+		It("Should run without routes when the Gateway API is missing", func(ctx SpecContext) {
+			recv := &radiov1.RtlSdrReceiver{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-expose-no-gateway-api-receiver",
+					Namespace: ReceiverNamespace,
+				},
+				Spec: radiov1.RtlSdrReceiverSpec{
+					Version: radiov1.V4,
+					Expose: &radiov1.RtlSdrExpose{
+						Gateway: &radiov1.RtlSdrGatewayExpose{Name: "test-expose-gateway"},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, recv)).Should(Succeed())
+
+			watchClient, err := client.NewWithWatch(cfg, client.Options{Scheme: k8sClient.Scheme()})
+			Expect(err).To(Succeed())
+
+			// Answer as an API server without the TCPRoute kind.
+			noGatewayAPIClient := interceptor.NewClient(watchClient, interceptor.Funcs{
+				Apply: func(ctx context.Context, c client.WithWatch, obj runtime.ApplyConfiguration, opts ...client.ApplyOption) error {
+					if u, ok := obj.(interface{ GetKind() string }); ok && u.GetKind() == tcpRouteGVK.Kind {
+						return &meta.NoKindMatchError{GroupKind: tcpRouteGVK.GroupKind(), SearchedVersions: []string{"v1alpha2"}}
+					}
+
+					return c.Apply(ctx, obj, opts...)
+				},
+			})
+
+			reconciler := RtlSdrReceiverReconciler{
+				Client: noGatewayAPIClient,
+				Scheme: scheme,
+				Image:  "test-image",
+			}
+			receiverLookupKey := types.NamespacedName{Name: recv.Name, Namespace: ReceiverNamespace}
+			result, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: receiverLookupKey})
+			Expect(err).To(Succeed())
+			Expect(result.RequeueAfter).To(BeZero())
+
+			stream := &unstructured.Unstructured{}
+			stream.SetGroupVersionKind(tcpRouteGVK)
+			err = k8sClient.Get(ctx, receiverLookupKey, stream)
+			Expect(apierrors.IsNotFound(err)).To(BeTrue(), "expected not found, got %v", err)
+
+			updated := &radiov1.RtlSdrReceiver{}
+			Expect(k8sClient.Get(ctx, receiverLookupKey, updated)).To(Succeed())
+			Expect(updated.Status.Expose).To(BeNil())
+		})
+
+		It("Should reject exposing the guarded audio of FM receivers", func(ctx SpecContext) {
+			recv := &radiov1.RtlSdrReceiver{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-expose-access-receiver",
+					Namespace: ReceiverNamespace,
+				},
+				Spec: radiov1.RtlSdrReceiverSpec{
+					Version: radiov1.V4,
+					Mode:    radiov1.ModeFM,
+					Access:  &radiov1.RtlSdrAccess{},
+					Expose: &radiov1.RtlSdrExpose{
+						Ingress: &radiov1.RtlSdrIngressExpose{},
+					},
+				},
+			}
+			err := k8sClient.Create(ctx, recv)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue(), "expected invalid, got %v", err)
+			Expect(err.Error()).To(ContainSubstring("the audio of FM receivers with access cannot be exposed"))
+		})
+
+		It("Should not expose the spectrum of receivers with access", func(ctx SpecContext) {
+			By("By creating a new RtlSdrReceiver exposing its spectrum")
+			recv := &radiov1.RtlSdrReceiver{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-expose-access-spectrum-receiver",
+					Namespace: ReceiverNamespace,
+				},
+				Spec: radiov1.RtlSdrReceiverSpec{
+					Version:  radiov1.V4,
+					Spectrum: &radiov1.RtlSdrSpectrum{},
+					Expose: &radiov1.RtlSdrExpose{
+						Hostname: "radio.example.com",
+						Ingress:  &radiov1.RtlSdrIngressExpose{},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, recv)).Should(Succeed())
+
+			reconciler := RtlSdrReceiverReconciler{
+				Client: k8sClient,
+				Scheme: scheme,
+			}
+			_, err := reconciler.reconcileExpose(ctx, recv)
+			Expect(err).To(Succeed())
+			receiverLookupKey := types.NamespacedName{Name: recv.Name, Namespace: ReceiverNamespace}
+			Expect(k8sClient.Get(ctx, receiverLookupKey, &networkingv1.Ingress{})).To(Succeed())
+
+			By("By guarding it like a receiver stored before the API rejected both")
+			recv.Spec.Access = &radiov1.RtlSdrAccess{}
+			_, err = reconciler.reconcileExpose(ctx, recv)
+			Expect(err).To(Succeed())
+			Expect(recv.Status.Expose).To(BeNil())
+
+			err = k8sClient.Get(ctx, receiverLookupKey, &networkingv1.Ingress{})
+			Expect(apierrors.IsNotFound(err)).To(BeTrue(), "expected not found, got %v", err)
+		})
+
+		It("Should reject exposing through both a Gateway and an Ingress", func(ctx SpecContext) {
+			recv := &radiov1.RtlSdrReceiver{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-expose-both-receiver",
+					Namespace: ReceiverNamespace,
+				},
+				Spec: radiov1.RtlSdrReceiverSpec{
+					Version: radiov1.V4,
+					Expose: &radiov1.RtlSdrExpose{
+						Gateway: &radiov1.RtlSdrGatewayExpose{Name: "test-expose-gateway"},
+						Ingress: &radiov1.RtlSdrIngressExpose{},
+					},
+				},
+			}
+			err := k8sClient.Create(ctx, recv)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue(), "expected invalid, got %v", err)
+			Expect(err.Error()).To(ContainSubstring("exactly one of gateway and ingress is required"))
+		})
+	})
+
+	Context("When tuning a receiver live", func() {
+		It("Should retune the running pod instead of recreating it", func(ctx SpecContext) {
+			By("By creating a new RtlSdrReceiver tuned live")
+
+			recv := &radiov1.RtlSdrReceiver{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-live-receiver",
+					Namespace: ReceiverNamespace,
+				},
+				Spec: radiov1.RtlSdrReceiverSpec{
+					Version:    radiov1.V4,
+					Frequency:  ptr.To(resource.MustParse("145.8M")),
+					SampleRate: ptr.To(resource.MustParse("1.024M")),
+					Gain:       ptr.To(resource.MustParse("40.2")),
+					LiveTuning: &radiov1.RtlSdrLiveTuning{},
+				},
+			}
+			Expect(k8sClient.Create(ctx, recv)).Should(Succeed())
+
+			// setting is the tuning of the fake proxy, which starts with
+			// the defaults of rtl_tcp.
+			setting := rtlmux.Setting{Tuning: rtltcp.Tuning{SampleRate: 2_048_000}}
+			var retunes []rtlmux.Setting
+			var controlToken string
+			recorder := events.NewFakeRecorder(10)
+			reconciler := RtlSdrReceiverReconciler{
+				Client:   k8sClient,
+				Scheme:   scheme,
+				Image:    "test-image",
+				MuxImage: "test-mux-image",
+				Recorder: recorder,
+				TuningStatus: func(_ context.Context, pod *corev1.Pod, token string) (*rtlmux.Setting, error) {
+					Expect(pod.Name).To(Equal(recv.Name))
+					Expect(token).To(Equal(controlToken))
+					return ptr.To(setting), nil
+				},
+				Retune: func(_ context.Context, pod *corev1.Pod, token string, s rtlmux.Setting) (*rtlmux.Setting, error) {
+					Expect(pod.Name).To(Equal(recv.Name))
+					Expect(token).To(Equal(controlToken))
+					retunes = append(retunes, s)
+					setting = s
+					return ptr.To(setting), nil
+				},
+			}
+			receiverLookupKey := types.NamespacedName{Name: recv.Name, Namespace: ReceiverNamespace}
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: receiverLookupKey})
+			Expect(err).To(Succeed())
+
+			By("By starting rtl_tcp untuned behind the proxy")
+			pod := &corev1.Pod{}
+			Expect(k8sClient.Get(ctx, receiverLookupKey, pod)).To(Succeed())
+			Expect(pod.Spec.Containers).To(HaveLen(2))
+			Expect(pod.Spec.Containers[0].Args).To(Equal([]string{"-a", "127.0.0.1", "-p", "1235"}))
+			Expect(pod.Spec.Containers[1].Args).To(Equal([]string{
+				"--listen", ":1234",
+				"--upstream", "127.0.0.1:1235",
+				"--max-clients", "1",
+				"--status-listen", ":9180",
+				"--control",
+				"--control-token-file", "/control/token",
+			}))
+			Expect(retunes).To(BeEmpty())
+
+			By("By only letting the controller call the control API")
+			secret := &corev1.Secret{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: recv.Name + "-control", Namespace: ReceiverNamespace}, secret)).To(Succeed())
+			Expect(metav1.IsControlledBy(secret, recv)).To(BeTrue())
+			controlToken = string(secret.Data[ControlTokenKey])
+			Expect(controlToken).ToNot(BeEmpty())
+			Expect(pod.Spec.Containers[1].VolumeMounts).To(ContainElement(And(
+				HaveField("Name", "control-token"),
+				HaveField("MountPath", "/control"),
+			)))
+			Expect(pod.Spec.Volumes).To(ContainElement(HaveField("Secret.SecretName", recv.Name+"-control")))
+
+			policy := &networkingv1.NetworkPolicy{}
+			Expect(k8sClient.Get(ctx, receiverLookupKey, policy)).To(Succeed())
+			Expect(policy.Spec.Ingress).To(HaveLen(2))
+			Expect(policy.Spec.Ingress[0].From).To(BeEmpty())
+			Expect(policy.Spec.Ingress[0].Ports).To(ConsistOf(HaveField("Port.IntVal", int32(1234))))
+			Expect(policy.Spec.Ingress[1].Ports).To(ConsistOf(HaveField("Port.IntVal", int32(ProxyStatusPort))))
+			Expect(policy.Spec.Ingress[1].From).To(ConsistOf(HaveField("PodSelector.MatchLabels", map[string]string{"control-plane": "controller-manager"})))
+
+			By("By tuning the pod once it is running")
+			pod.Status.Phase = corev1.PodRunning
+			pod.Status.PodIP = "10.0.0.13"
+			Expect(k8sClient.Status().Update(ctx, pod)).To(Succeed())
+
+			result, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: receiverLookupKey})
+			Expect(err).To(Succeed())
+			Expect(result.RequeueAfter).To(Equal(DefaultLiveTuningInterval))
+
+			updated := &radiov1.RtlSdrReceiver{}
+			Expect(k8sClient.Get(ctx, receiverLookupKey, updated)).To(Succeed())
+			Expect(retunes).To(Equal([]rtlmux.Setting{{
+				Generation: updated.Generation,
+				Tuning:     rtltcp.Tuning{Frequency: 145_800_000, SampleRate: 1_024_000, Gain: 402, ManualGain: true},
+			}}))
+			Expect(updated.Status.ObservedGeneration).To(Equal(updated.Generation))
+			Expect(updated.Status.ObservedFrequency.Value()).To(Equal(int64(145_800_000)))
+			Expect(recorder.Events).To(Receive(HavePrefix("Normal Retuned")))
+
+			By("By leaving a tuned pod alone")
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: receiverLookupKey})
+			Expect(err).To(Succeed())
+			Expect(retunes).To(HaveLen(1))
+			Expect(recorder.Events).To(BeEmpty())
+
+			By("By retuning the same pod when the frequency changes")
+			updated.Spec.Frequency = ptr.To(resource.MustParse("146M"))
+			updated.Spec.Gain = nil
+			Expect(k8sClient.Update(ctx, updated)).To(Succeed())
+
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: receiverLookupKey})
+			Expect(err).To(Succeed())
+
+			retuned := &corev1.Pod{}
+			Expect(k8sClient.Get(ctx, receiverLookupKey, retuned)).To(Succeed())
+			Expect(retuned.UID).To(Equal(pod.UID))
+			Expect(retuned.Spec.Containers[0].Args).To(Equal(pod.Spec.Containers[0].Args))
+
+			Expect(k8sClient.Get(ctx, receiverLookupKey, updated)).To(Succeed())
+			Expect(retunes).To(HaveLen(2))
+			Expect(retunes[1]).To(Equal(rtlmux.Setting{
+				Generation: updated.Generation,
+				Tuning:     rtltcp.Tuning{Frequency: 146_000_000, SampleRate: 1_024_000},
+			}))
+			Expect(updated.Status.ObservedGeneration).To(Equal(updated.Generation))
+			Expect(updated.Status.ObservedFrequency.Value()).To(Equal(int64(146_000_000)))
+
+			By("By reporting the frequency a client retuned to without fighting it")
+			setting.Tuning.Frequency = 145_500_000
+
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: receiverLookupKey})
+			Expect(err).To(Succeed())
+			Expect(retunes).To(HaveLen(2))
+			Expect(k8sClient.Get(ctx, receiverLookupKey, updated)).To(Succeed())
+			Expect(updated.Status.ObservedFrequency.Value()).To(Equal(int64(145_500_000)))
+
+			By("By tuning the pod again once it restarted")
+			setting = rtlmux.Setting{Tuning: rtltcp.Tuning{SampleRate: 2_048_000}}
+
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: receiverLookupKey})
+			Expect(err).To(Succeed())
+			Expect(retunes).To(HaveLen(3))
+			Expect(retunes[2].Tuning.Frequency).To(Equal(uint32(146_000_000)))
+			Expect(k8sClient.Get(ctx, receiverLookupKey, updated)).To(Succeed())
+			Expect(updated.Status.ObservedGeneration).To(Equal(updated.Generation))
+			Expect(updated.Status.ObservedFrequency.Value()).To(Equal(int64(146_000_000)))
+
+			By("By keeping the token while tuned live")
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: recv.Name + "-control", Namespace: ReceiverNamespace}, secret)).To(Succeed())
+			Expect(string(secret.Data[ControlTokenKey])).To(Equal(controlToken))
+
+			By("By deleting the token and the NetworkPolicy once not tuned live")
+			updated.Spec.LiveTuning = nil
+			Expect(k8sClient.Update(ctx, updated)).To(Succeed())
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: receiverLookupKey})
+			Expect(err).To(Succeed())
+
+			err = k8sClient.Get(ctx, types.NamespacedName{Name: recv.Name + "-control", Namespace: ReceiverNamespace}, secret)
+			Expect(apierrors.IsNotFound(err)).To(BeTrue(), "expected not found, got %v", err)
+			err = k8sClient.Get(ctx, receiverLookupKey, policy)
+			Expect(apierrors.IsNotFound(err)).To(BeTrue(), "expected not found, got %v", err)
+		})
+
+		It("Should reject live tuning outside IQ mode", func(ctx SpecContext) {
+			recv := &radiov1.RtlSdrReceiver{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-live-fm-receiver",
+					Namespace: ReceiverNamespace,
+				},
+				Spec: radiov1.RtlSdrReceiverSpec{
+					Version:    radiov1.V4,
+					Mode:       radiov1.ModeFM,
+					LiveTuning: &radiov1.RtlSdrLiveTuning{},
+				},
+			}
+
+			err := k8sClient.Create(ctx, recv)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue(), "expected invalid, got %v", err)
+			Expect(err.Error()).To(ContainSubstring("live tuning is only supported in IQ mode"))
+		})
+
+		It("Should reject live tuning while scanning", func(ctx SpecContext) {
+			recv := &radiov1.RtlSdrReceiver{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-live-scan-receiver",
+					Namespace: ReceiverNamespace,
+				},
+				Spec: radiov1.RtlSdrReceiverSpec{
+					Version:    radiov1.V4,
+					LiveTuning: &radiov1.RtlSdrLiveTuning{},
+					Scan: &radiov1.RtlSdrScan{
+						Frequencies: []resource.Quantity{resource.MustParse("145.5M")},
+					},
+				},
+			}
+
+			err := k8sClient.Create(ctx, recv)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue(), "expected invalid, got %v", err)
+			Expect(err.Error()).To(ContainSubstring("live tuning is not supported while scanning"))
+		})
+	})
+
+	Context("When another client edits objects concurrently", func() {
+		It("Should apply without conflicts and keep the foreign labels", func(ctx SpecContext) {
+			By("By creating a new RtlSdrReceiver")
+
+			recv := &radiov1.RtlSdrReceiver{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-concurrent-receiver",
+					Namespace: ReceiverNamespace,
+				},
+				Spec: radiov1.RtlSdrReceiverSpec{
+					Version: radiov1.V4,
+				},
+			}
+
+			Expect(k8sClient.Create(ctx, recv)).Should(Succeed())
+			receiverLookupKey := types.NamespacedName{Name: recv.Name, Namespace: ReceiverNamespace}
+
+			By("By editing the labels between the reconciler reading and writing the receiver")
+			watchClient, err := client.NewWithWatch(cfg, client.Options{Scheme: k8sClient.Scheme()})
+			Expect(err).To(Succeed())
+
+			edits := 0
+			racingClient := interceptor.NewClient(watchClient, interceptor.Funcs{
+				Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+					if err := c.Get(ctx, key, obj, opts...); err != nil {
+						return err
+					}
+
+					if _, ok := obj.(*radiov1.RtlSdrReceiver); ok {
+						edits++
+						edited := obj.DeepCopyObject().(*radiov1.RtlSdrReceiver)
+						edited.Labels = map[string]string{"edit": strconv.Itoa(edits)}
+						return k8sClient.Patch(ctx, edited, client.MergeFrom(obj))
+					}
+
+					return nil
+				},
+			})
+
+			reconciler := RtlSdrReceiverReconciler{
+				Client: racingClient,
+				Scheme: scheme,
+				Image:  "test-image",
+			}
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: receiverLookupKey})
+			Expect(err).To(Succeed())
+
+			By("By adding a label to the Pod from another client")
+			pod := &corev1.Pod{}
+			Expect(k8sClient.Get(ctx, receiverLookupKey, pod)).To(Succeed())
+			pod.Labels["team"] = "analysts"
+			Expect(k8sClient.Update(ctx, pod)).To(Succeed())
+
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: receiverLookupKey})
+			Expect(err).To(Succeed())
+
+			By("By checking that both the controller and the other client's changes are kept")
+			Expect(k8sClient.Get(ctx, receiverLookupKey, pod)).To(Succeed())
+			Expect(pod.Labels).To(HaveKeyWithValue("team", "analysts"))
+			Expect(pod.Labels).To(HaveKeyWithValue("app.kubernetes.io/instance", recv.Name))
+
+			updated := &radiov1.RtlSdrReceiver{}
+			Expect(k8sClient.Get(ctx, receiverLookupKey, updated)).To(Succeed())
+			Expect(updated.Labels).To(HaveKeyWithValue("edit", strconv.Itoa(edits)))
+			Expect(updated.Finalizers).To(ContainElement(ReceiverFinalizer))
+			Expect(updated.Status.State).To(Equal(radiov1.StateWaiting))
+			Expect(updated.Status.Pod).ToNot(BeNil())
+
+			service := &corev1.Service{}
+			Expect(k8sClient.Get(ctx, receiverLookupKey, service)).To(Succeed())
+			Expect(service.Spec.Ports).To(HaveLen(1))
+			Expect(service.Spec.Ports[0].Port).To(Equal(int32(1234)))
+
+			var managers []string
+			for _, entry := range updated.ManagedFields {
+				managers = append(managers, entry.Manager)
+			}
+			Expect(managers).To(ContainElement(FieldManager))
+		})
+	})
+
+	Context("When deleting a RtlSdrReceiver", func() {
+		It("Should tear down the Pod before removing the finalizer", func(ctx SpecContext) {
+			By("By creating a new RtlSdrReceiver")
+
+			grace := int64(5)
+			recv := &radiov1.RtlSdrReceiver{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-deleted-receiver",
+					Namespace: ReceiverNamespace,
+				},
+				Spec: radiov1.RtlSdrReceiverSpec{
+					Version:                       radiov1.V4,
+					TerminationGracePeriodSeconds: &grace,
+				},
+			}
+
+			Expect(k8sClient.Create(ctx, recv)).Should(Succeed())
+
+			By("By running reconciler")
+			reconciler := RtlSdrReceiverReconciler{
+				Client: k8sClient,
+				Scheme: scheme,
+				Image:  "test-image",
+			}
+			receiverLookupKey := types.NamespacedName{Name: recv.Name, Namespace: ReceiverNamespace}
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: receiverLookupKey})
+			Expect(err).To(Succeed())
+
+			created := &radiov1.RtlSdrReceiver{}
+			Expect(k8sClient.Get(ctx, receiverLookupKey, created)).To(Succeed())
+			Expect(created.Finalizers).To(ContainElement(ReceiverFinalizer))
+
+			pod := &corev1.Pod{}
+			Expect(k8sClient.Get(ctx, receiverLookupKey, pod)).To(Succeed())
+			Expect(*pod.Spec.TerminationGracePeriodSeconds).To(Equal(grace))
+
+			By("By deleting the RtlSdrReceiver")
+			Expect(k8sClient.Delete(ctx, created)).To(Succeed())
+
+			result, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: receiverLookupKey})
+			Expect(err).To(Succeed())
+			Expect(result.RequeueAfter).ToNot(BeZero())
+
+			deleting := &radiov1.RtlSdrReceiver{}
+			Expect(k8sClient.Get(ctx, receiverLookupKey, deleting)).To(Succeed())
+			Expect(deleting.Status.State).To(Equal(radiov1.StateTerminating))
+
+			By("By checking the finalizer is removed once the Pod is gone")
+			Eventually(func() error {
+				_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: receiverLookupKey})
+				if err != nil {
+					return err
+				}
+				return k8sClient.Get(ctx, receiverLookupKey, &radiov1.RtlSdrReceiver{})
+			}, timeout, interval).Should(Satisfy(apierrors.IsNotFound))
+		})
+
+		It("Should force delete the pods and remove the finalizer after the timeout", func(ctx SpecContext) {
+			By("By creating a RtlSdrReceiver kept around by another finalizer")
+
+			const keep = "test.frelon.se/keep"
+			recv := &radiov1.RtlSdrReceiver{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       "test-timeout-receiver",
+					Namespace:  ReceiverNamespace,
+					Finalizers: []string{keep},
+				},
+				Spec: radiov1.RtlSdrReceiverSpec{
+					Version:  radiov1.V4,
+					Workload: radiov1.WorkloadDeployment,
+				},
+			}
+			Expect(k8sClient.Create(ctx, recv)).Should(Succeed())
+
+			reconciler := RtlSdrReceiverReconciler{
+				Client:          k8sClient,
+				Scheme:          scheme,
+				Image:           "test-image",
+				DeletionTimeout: time.Minute,
+			}
+			receiverLookupKey := types.NamespacedName{Name: recv.Name, Namespace: ReceiverNamespace}
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: receiverLookupKey})
+			Expect(err).To(Succeed())
+
+			By("By running a pod of the Deployment on a node")
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-timeout-receiver-7d9f8",
+					Namespace: ReceiverNamespace,
+					Labels: map[string]string{
+						"app.kubernetes.io/name":       "rtlsdrreceiver",
+						"app.kubernetes.io/instance":   recv.Name,
+						"app.kubernetes.io/managed-by": "k8s-radio",
+					},
+				},
+				Spec: corev1.PodSpec{
+					NodeName:   "test-timeout-node",
+					Containers: []corev1.Container{{Name: "rtl-tcp", Image: "test-image"}},
+				},
+			}
+			Expect(k8sClient.Create(ctx, pod)).To(Succeed())
+
+			By("By deleting the RtlSdrReceiver")
+			created := &radiov1.RtlSdrReceiver{}
+			Expect(k8sClient.Get(ctx, receiverLookupKey, created)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, created)).To(Succeed())
+			Expect(k8sClient.Get(ctx, receiverLookupKey, created)).To(Succeed())
+
+			// Without a garbage collector the Deployment is never gone.
+			result, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: receiverLookupKey})
+			Expect(err).To(Succeed())
+			Expect(result.RequeueAfter).ToNot(BeZero())
+
+			By("By reconciling past the deletion timeout")
+			reconciler.Clock = clocktesting.NewFakePassiveClock(created.DeletionTimestamp.Add(2 * time.Minute))
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: receiverLookupKey})
+			Expect(err).To(Succeed())
+
+			deleting := &radiov1.RtlSdrReceiver{}
+			Expect(k8sClient.Get(ctx, receiverLookupKey, deleting)).To(Succeed())
+			Expect(deleting.Finalizers).To(Equal([]string{keep}))
+			ready := meta.FindStatusCondition(deleting.Status.Conditions, radiov1.ReadyCondition)
+			Expect(ready).NotTo(BeNil())
+			Expect(ready.Reason).To(Equal(radiov1.TeardownTimeoutReason))
+
+			By("By checking the pod of the Deployment was killed")
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(pod), &corev1.Pod{})).To(Satisfy(apierrors.IsNotFound))
+
+			deleting.Finalizers = nil
+			Expect(k8sClient.Update(ctx, deleting)).To(Succeed())
+		})
+	})
 })
@@ -0,0 +1,112 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"strconv"
+
+	corev1ac "k8s.io/client-go/applyconfigurations/core/v1"
+
+	radiov1beta1 "github.com/frelon/k8s-radio/api/v1beta1"
+	"github.com/frelon/k8s-radio/pkg/events"
+)
+
+const (
+	// EventsPort is the default port the recent events of receivers in AIS
+	// and ISM mode are served on.
+	EventsPort = 8080
+
+	// EventsPath is the HTTP path the recent events are served on.
+	EventsPath = "/events"
+)
+
+// decoderMode reports whether the receiver decodes messages into events.
+func decoderMode(receiver *radiov1beta1.RtlSdrReceiver) bool {
+	return receiver.Spec.Mode == radiov1beta1.ModeAIS || receiver.Spec.Mode == radiov1beta1.ModeISM
+}
+
+// eventsSink returns the sink and the MQTT topic the events of the receiver
+// are published to.
+func eventsSink(receiver *radiov1beta1.RtlSdrReceiver) (string, string) {
+	sink, topic := events.StdoutSink, ""
+	if e := receiver.Spec.Events; e != nil {
+		if e.Sink != "" {
+			sink = e.Sink
+		}
+		topic = e.Topic
+	}
+
+	if topic == "" {
+		topic = fmt.Sprintf("radio/%s/%s/events", receiver.Namespace, receiver.Name)
+	}
+
+	return sink, topic
+}
+
+// decoderContainer returns the container decoding AIS messages with
+// AIS-catcher or ISM-band sensors with rtl_433, wrapped by rtl-events
+// publishing what they decode.
+func (r *RtlSdrReceiverReconciler) decoderContainer(receiver *radiov1beta1.RtlSdrReceiver) *corev1ac.ContainerApplyConfiguration {
+	source := events.SourceISM
+	if receiver.Spec.Mode == radiov1beta1.ModeAIS {
+		source = events.SourceAIS
+	}
+
+	sink, topic := eventsSink(receiver)
+	if relay := mqttEventsSink(receiver); relay != "" {
+		sink = relay
+	}
+	args := []string{
+		"--listen", fmt.Sprintf(":%d", listenPort(receiver)),
+		"--source", string(source),
+		"--sink", sink,
+		"--topic", topic,
+		"--receiver", receiver.Namespace + "/" + receiver.Name,
+		"--",
+	}
+
+	// Both decoders pick a sample rate suited to them unless told
+	// otherwise. AIS-catcher always listens to AIS channels A and B.
+	switch source {
+	case events.SourceAIS:
+		args = append(args, "AIS-catcher", "-o", "5")
+		if receiver.Spec.SampleRate != nil {
+			args = append(args, "-s", strconv.FormatInt(receiver.Spec.SampleRate.Value(), 10))
+		}
+		if receiver.Spec.Gain != nil {
+			args = append(args, "-gr", "TUNER", gainDB(receiver))
+		}
+	case events.SourceISM:
+		args = append(args, "rtl_433", "-F", "json", "-M", "time:utc")
+		if receiver.Spec.Frequency != nil {
+			args = append(args, "-f", strconv.FormatInt(receiver.Spec.Frequency.Value(), 10))
+		}
+		if receiver.Spec.SampleRate != nil {
+			args = append(args, "-s", strconv.FormatInt(receiver.Spec.SampleRate.Value(), 10))
+		}
+		if receiver.Spec.Gain != nil {
+			args = append(args, "-g", gainDB(receiver))
+		}
+	}
+
+	return corev1ac.Container().
+		WithName("receiver").
+		WithImage(r.DecoderImage).
+		WithCommand("/usr/local/bin/rtl-events").
+		WithArgs(args...)
+}
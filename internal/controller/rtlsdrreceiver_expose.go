@@ -0,0 +1,390 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	networkingv1ac "k8s.io/client-go/applyconfigurations/networking/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	radiov1beta1 "github.com/frelon/k8s-radio/api/v1beta1"
+)
+
+// gatewayAddressPollInterval is how often the Gateway of an exposed receiver
+// is checked for an address, as Gateways are not watched.
+const gatewayAddressPollInterval = 30 * time.Second
+
+// The kinds of the Gateway API routing to receivers.
+var (
+	gatewayGVK   = schema.GroupVersionKind{Group: "gateway.networking.k8s.io", Version: "v1", Kind: "Gateway"}
+	httpRouteGVK = schema.GroupVersionKind{Group: "gateway.networking.k8s.io", Version: "v1", Kind: "HTTPRoute"}
+	tcpRouteGVK  = schema.GroupVersionKind{Group: "gateway.networking.k8s.io", Version: "v1alpha2", Kind: "TCPRoute"}
+)
+
+// rawStream reports whether the receiver serves the raw I/Q stream, which is
+// routed over TCP.
+func rawStream(receiver *radiov1beta1.RtlSdrReceiver) bool {
+	return receiver.Spec.Mode == "" || receiver.Spec.Mode == radiov1beta1.ModeIQ
+}
+
+// httpPort returns the port of the HTTP endpoint of the receiver, if it has
+// one.
+func httpPort(receiver *radiov1beta1.RtlSdrReceiver) (int32, bool) {
+	if rawStream(receiver) {
+		return SpectrumPort, spectrumEnabled(receiver)
+	}
+
+	return listenPort(receiver), true
+}
+
+// httpPath returns the path of the HTTP endpoint of the receiver.
+func httpPath(receiver *radiov1beta1.RtlSdrReceiver) string {
+	switch receiver.Spec.Mode {
+	case radiov1beta1.ModeFM:
+		return AudioPath
+	case radiov1beta1.ModeADSB:
+		return ADSBPath
+	case radiov1beta1.ModeAIS, radiov1beta1.ModeISM:
+		return EventsPath
+	}
+
+	return "/"
+}
+
+// exposePathPrefix returns the path the HTTP endpoint is served under from
+// outside the cluster, without a trailing slash unless it is the root.
+func exposePathPrefix(receiver *radiov1beta1.RtlSdrReceiver) string {
+	prefix := strings.TrimSuffix(receiver.Spec.Expose.PathPrefix, "/")
+	if prefix == "" {
+		return "/"
+	}
+
+	return prefix
+}
+
+// externalURL returns the URL of the HTTP endpoint on host, leaving out the
+// default port of the scheme.
+func externalURL(receiver *radiov1beta1.RtlSdrReceiver, scheme, host string, port int64) string {
+	if port != 0 && !(scheme == "http" && port == 80) && !(scheme == "https" && port == 443) {
+		host = net.JoinHostPort(host, strconv.FormatInt(port, 10))
+	}
+
+	return scheme + "://" + host + strings.TrimSuffix(exposePathPrefix(receiver), "/") + httpPath(receiver)
+}
+
+// parentRef returns the reference of a route to a listener of the Gateway.
+func parentRef(receiver *radiov1beta1.RtlSdrReceiver, sectionName string) map[string]any {
+	gateway := receiver.Spec.Expose.Gateway
+	ref := map[string]any{"name": gateway.Name}
+	if gateway.Namespace != "" {
+		ref["namespace"] = gateway.Namespace
+	}
+	if sectionName != "" {
+		ref["sectionName"] = sectionName
+	}
+
+	return ref
+}
+
+// backendRef returns the reference of a route to a port of the receiver
+// Service.
+func backendRef(receiver *radiov1beta1.RtlSdrReceiver, port int32) map[string]any {
+	return map[string]any{"name": receiver.Name, "port": int64(port)}
+}
+
+// httpRoute returns the HTTPRoute of the HTTP endpoint of the receiver,
+// stripping the path prefix before requests reach it.
+func httpRoute(receiver *radiov1beta1.RtlSdrReceiver, port int32) *unstructured.Unstructured {
+	prefix := exposePathPrefix(receiver)
+	rule := map[string]any{
+		"matches": []any{map[string]any{
+			"path": map[string]any{"type": "PathPrefix", "value": prefix},
+		}},
+		"backendRefs": []any{backendRef(receiver, port)},
+	}
+	if prefix != "/" {
+		rule["filters"] = []any{map[string]any{
+			"type": "URLRewrite",
+			"urlRewrite": map[string]any{
+				"path": map[string]any{"type": "ReplacePrefixMatch", "replacePrefixMatch": "/"},
+			},
+		}}
+	}
+
+	spec := map[string]any{
+		"parentRefs": []any{parentRef(receiver, receiver.Spec.Expose.Gateway.SectionName)},
+		"rules":      []any{rule},
+	}
+	if hostname := receiver.Spec.Expose.Hostname; hostname != "" {
+		spec["hostnames"] = []any{hostname}
+	}
+
+	return ownedUnstructured(receiver, httpRouteGVK, spec)
+}
+
+// tcpRoute returns the TCPRoute of the raw stream of the receiver.
+func tcpRoute(receiver *radiov1beta1.RtlSdrReceiver) *unstructured.Unstructured {
+	return ownedUnstructured(receiver, tcpRouteGVK, map[string]any{
+		"parentRefs": []any{parentRef(receiver, receiver.Spec.Expose.Gateway.TCPSectionName)},
+		"rules": []any{map[string]any{
+			"backendRefs": []any{backendRef(receiver, listenPort(receiver))},
+		}},
+	})
+}
+
+// ingress returns the Ingress of the HTTP endpoint of the receiver.
+func ingress(receiver *radiov1beta1.RtlSdrReceiver, port int32) *networkingv1ac.IngressApplyConfiguration {
+	expose := receiver.Spec.Expose
+	rule := networkingv1ac.IngressRule().
+		WithHTTP(networkingv1ac.HTTPIngressRuleValue().
+			WithPaths(networkingv1ac.HTTPIngressPath().
+				WithPath(exposePathPrefix(receiver)).
+				WithPathType(networkingv1.PathTypePrefix).
+				WithBackend(networkingv1ac.IngressBackend().
+					WithService(networkingv1ac.IngressServiceBackend().
+						WithName(receiver.Name).
+						WithPort(networkingv1ac.ServiceBackendPort().WithNumber(port))))))
+	if expose.Hostname != "" {
+		rule.WithHost(expose.Hostname)
+	}
+
+	spec := networkingv1ac.IngressSpec().WithRules(rule)
+	if expose.Ingress.ClassName != nil {
+		spec.WithIngressClassName(*expose.Ingress.ClassName)
+	}
+	if expose.Ingress.TLSSecretName != "" {
+		tls := networkingv1ac.IngressTLS().WithSecretName(expose.Ingress.TLSSecretName)
+		if expose.Hostname != "" {
+			tls.WithHosts(expose.Hostname)
+		}
+		spec.WithTLS(tls)
+	}
+
+	ac := networkingv1ac.Ingress(receiver.Name, receiver.Namespace).
+		WithLabels(receiverLabels(receiver)).
+		WithOwnerReferences(ownerReference(receiver)).
+		WithSpec(spec)
+	if len(expose.Ingress.Annotations) > 0 {
+		ac.WithAnnotations(expose.Ingress.Annotations)
+	}
+
+	return ac
+}
+
+// reconcileRoute applies a route of the receiver, or deletes the route of
+// kind gvk when obj is nil. It reports whether the route is in place, as
+// without the Gateway API installed there is nothing to route with.
+func (r *RtlSdrReceiverReconciler) reconcileRoute(ctx context.Context, receiver *radiov1beta1.RtlSdrReceiver, gvk schema.GroupVersionKind, obj *unstructured.Unstructured) (bool, error) {
+	var err error
+	apply := obj != nil
+	if apply {
+		ac := client.ApplyConfigurationFromUnstructured(obj)
+		err = r.Apply(ctx, ac, client.FieldOwner(FieldManager), client.ForceOwnership)
+	} else {
+		obj = &unstructured.Unstructured{}
+		obj.SetGroupVersionKind(gvk)
+		err = r.deleteOwnedNamed(ctx, receiver, receiver.Name, obj)
+	}
+
+	if meta.IsNoMatchError(err) {
+		if apply {
+			log.FromContext(ctx).Info("Routes are not served, not creating one", "kind", gvk.Kind, "error", err)
+		}
+		return false, nil
+	}
+
+	return apply, err
+}
+
+// gatewayListener returns the protocol and port of the listener of the
+// Gateway named sectionName, or of the first listener with one of protocols
+// when sectionName is empty.
+func gatewayListener(gateway *unstructured.Unstructured, sectionName string, protocols ...string) (string, int64, bool) {
+	listeners, _, _ := unstructured.NestedSlice(gateway.Object, "spec", "listeners")
+	for _, l := range listeners {
+		listener, ok := l.(map[string]any)
+		if !ok {
+			continue
+		}
+		name, _, _ := unstructured.NestedString(listener, "name")
+		protocol, _, _ := unstructured.NestedString(listener, "protocol")
+		port, _, _ := unstructured.NestedInt64(listener, "port")
+		if sectionName != "" && name == sectionName {
+			return protocol, port, true
+		}
+		if sectionName == "" && slices.Contains(protocols, protocol) {
+			return protocol, port, true
+		}
+	}
+
+	return "", 0, false
+}
+
+// gatewayAddress returns the first address of the Gateway, if it has one.
+func gatewayAddress(gateway *unstructured.Unstructured) string {
+	addresses, _, _ := unstructured.NestedSlice(gateway.Object, "status", "addresses")
+	for _, a := range addresses {
+		if address, ok := a.(map[string]any); ok {
+			if value, _, _ := unstructured.NestedString(address, "value"); value != "" {
+				return value
+			}
+		}
+	}
+
+	return ""
+}
+
+// gatewayStatus returns the external URLs of a receiver routed through a
+// Gateway, as far as the Gateway tells.
+func (r *RtlSdrReceiverReconciler) gatewayStatus(ctx context.Context, receiver *radiov1beta1.RtlSdrReceiver, exposeHTTP, exposeTCP bool) (*radiov1beta1.RtlSdrExposeStatus, error) {
+	expose := receiver.Spec.Expose
+	gateway := &unstructured.Unstructured{}
+	gateway.SetGroupVersionKind(gatewayGVK)
+	namespace := expose.Gateway.Namespace
+	if namespace == "" {
+		namespace = receiver.Namespace
+	}
+	err := r.Get(ctx, client.ObjectKey{Namespace: namespace, Name: expose.Gateway.Name}, gateway)
+	if err != nil && !apierrors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+		return nil, err
+	}
+	address := gatewayAddress(gateway)
+
+	status := &radiov1beta1.RtlSdrExposeStatus{}
+	if exposeHTTP {
+		host := expose.Hostname
+		if host == "" {
+			host = address
+		}
+		protocol, port, _ := gatewayListener(gateway, expose.Gateway.SectionName, "HTTP", "HTTPS")
+		scheme := "http"
+		if protocol == "HTTPS" {
+			scheme = "https"
+		}
+		if host != "" {
+			status.URL = externalURL(receiver, scheme, host, port)
+		}
+	}
+	if exposeTCP && address != "" {
+		if _, port, ok := gatewayListener(gateway, expose.Gateway.TCPSectionName, "TCP"); ok {
+			scheme := "tcp"
+			if accessEnabled(receiver) {
+				scheme = "tls"
+			}
+			status.StreamURL = scheme + "://" + net.JoinHostPort(address, strconv.FormatInt(port, 10))
+		}
+	}
+
+	return status, nil
+}
+
+// ingressStatus returns the external URL of a receiver routed through an
+// Ingress, once the hostname is set or the Ingress has an address.
+func (r *RtlSdrReceiverReconciler) ingressStatus(ctx context.Context, receiver *radiov1beta1.RtlSdrReceiver) (*radiov1beta1.RtlSdrExposeStatus, error) {
+	expose := receiver.Spec.Expose
+	scheme := "http"
+	if expose.Ingress.TLSSecretName != "" {
+		scheme = "https"
+	}
+
+	host := expose.Hostname
+	if host == "" {
+		ing := &networkingv1.Ingress{}
+		if err := r.Get(ctx, client.ObjectKey{Namespace: receiver.Namespace, Name: receiver.Name}, ing); err != nil {
+			return nil, client.IgnoreNotFound(err)
+		}
+		for _, lb := range ing.Status.LoadBalancer.Ingress {
+			if host = lb.Hostname; host == "" {
+				host = lb.IP
+			}
+			if host != "" {
+				break
+			}
+		}
+	}
+	if host == "" {
+		return &radiov1beta1.RtlSdrExposeStatus{}, nil
+	}
+
+	return &radiov1beta1.RtlSdrExposeStatus{URL: externalURL(receiver, scheme, host, 0)}, nil
+}
+
+// reconcileExpose routes the endpoints of the receiver from outside the
+// cluster through a Gateway or an Ingress, deleting the routes it no longer
+// needs, and publishes the external URLs. It returns when to look for the
+// address of the Gateway again, as Gateways are not watched.
+func (r *RtlSdrReceiverReconciler) reconcileExpose(ctx context.Context, receiver *radiov1beta1.RtlSdrReceiver) (time.Duration, error) {
+	expose := receiver.Spec.Expose
+	port, hasHTTP := httpPort(receiver)
+	useGateway := expose != nil && expose.Gateway != nil
+	useIngress := expose != nil && expose.Ingress != nil
+
+	var route, stream *unstructured.Unstructured
+	if useGateway && hasHTTP {
+		route = httpRoute(receiver, port)
+	}
+	if useGateway && rawStream(receiver) {
+		stream = tcpRoute(receiver)
+	}
+	exposeHTTP, err := r.reconcileRoute(ctx, receiver, httpRouteGVK, route)
+	if err != nil {
+		return 0, err
+	}
+	exposeTCP, err := r.reconcileRoute(ctx, receiver, tcpRouteGVK, stream)
+	if err != nil {
+		return 0, err
+	}
+
+	if useIngress && hasHTTP {
+		err = r.Apply(ctx, ingress(receiver, port), client.FieldOwner(FieldManager), client.ForceOwnership)
+	} else {
+		err = r.deleteOwnedNamed(ctx, receiver, receiver.Name, &networkingv1.Ingress{})
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	var status *radiov1beta1.RtlSdrExposeStatus
+	switch {
+	case useGateway && (exposeHTTP || exposeTCP):
+		status, err = r.gatewayStatus(ctx, receiver, exposeHTTP, exposeTCP)
+	case useIngress && hasHTTP:
+		status, err = r.ingressStatus(ctx, receiver)
+	}
+	if err != nil {
+		return 0, err
+	}
+	receiver.Status.Expose = status
+
+	if useGateway && status != nil && (exposeHTTP && status.URL == "" || exposeTCP && status.StreamURL == "") {
+		return gatewayAddressPollInterval, nil
+	}
+
+	return 0, nil
+}
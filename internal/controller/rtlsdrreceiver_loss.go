@@ -0,0 +1,159 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	radiov1beta1 "github.com/frelon/k8s-radio/api/v1beta1"
+	"github.com/frelon/k8s-radio/pkg/loss"
+)
+
+const (
+	// DefaultSampleLossThreshold and DefaultSampleLossWindow are used when
+	// the API server has not defaulted the sample loss settings.
+	DefaultSampleLossThreshold = "1%"
+	DefaultSampleLossWindow    = 10 * time.Second
+)
+
+// sampleLossEnabled reports whether the samples dropped by the receiver are
+// followed.
+func sampleLossEnabled(receiver *radiov1beta1.RtlSdrReceiver) bool {
+	return receiver.Spec.SampleLoss != nil
+}
+
+// sampleLossWindow returns the time the dropped samples are measured over.
+func sampleLossWindow(receiver *radiov1beta1.RtlSdrReceiver) time.Duration {
+	if l := receiver.Spec.SampleLoss; l != nil && l.Window != nil {
+		return l.Window.Duration
+	}
+
+	return DefaultSampleLossWindow
+}
+
+// sampleLossThreshold returns the ratio of dropped samples above which the
+// receiver is losing samples.
+func sampleLossThreshold(receiver *radiov1beta1.RtlSdrReceiver) float64 {
+	threshold := DefaultSampleLossThreshold
+	if l := receiver.Spec.SampleLoss; l != nil && l.Threshold != "" {
+		threshold = l.Threshold
+	}
+
+	percent, err := strconv.ParseFloat(strings.TrimSuffix(threshold, "%"), 64)
+	if err != nil {
+		percent, _ = strconv.ParseFloat(strings.TrimSuffix(DefaultSampleLossThreshold, "%"), 64)
+	}
+
+	return percent / 100
+}
+
+// sampleLossArgs returns the proxy arguments counting the dropped samples.
+func sampleLossArgs(receiver *radiov1beta1.RtlSdrReceiver) []string {
+	return []string{"--loss-window", sampleLossWindow(receiver).String()}
+}
+
+// formatRatio formats a ratio as a percentage.
+func formatRatio(ratio float64) string {
+	return strconv.FormatFloat(math.Round(ratio*10000)/100, 'f', -1, 64) + "%"
+}
+
+// reconcileSampleLoss updates the dropped samples and the SampleLoss
+// condition from the running pod of the receiver and returns when to check
+// them again, or zero if there is nothing to follow. An Event is emitted
+// when the receiver starts losing samples. Failing to reach the proxy is not
+// an error, the previous status is kept until the next attempt.
+func (r *RtlSdrReceiverReconciler) reconcileSampleLoss(ctx context.Context, receiver *radiov1beta1.RtlSdrReceiver, pod *corev1.Pod) time.Duration {
+	if !sampleLossEnabled(receiver) {
+		receiver.Status.SampleLoss = nil
+		meta.RemoveStatusCondition(&receiver.Status.Conditions, radiov1beta1.SampleLossCondition)
+		return 0
+	}
+
+	if receiver.Status.State != radiov1beta1.StateRunning {
+		return 0
+	}
+
+	window := sampleLossWindow(receiver)
+	if pod == nil {
+		return window
+	}
+
+	fetch := r.SampleLossStatus
+	if fetch == nil {
+		fetch = fetchSampleLoss
+	}
+
+	stats, err := fetch(ctx, pod)
+	if err != nil {
+		log.FromContext(ctx).Info("Failed fetching sample loss", "pod", pod.Name, "error", err)
+		return window
+	}
+
+	receiver.Status.SampleLoss = &radiov1beta1.RtlSdrSampleLossStatus{
+		DroppedSamples: int64(stats.DroppedSamples),
+		DroppedBuffers: int64(stats.DroppedBuffers),
+		Ratio:          formatRatio(stats.Ratio),
+	}
+
+	threshold := sampleLossThreshold(receiver)
+	if stats.Ratio <= threshold {
+		meta.SetStatusCondition(&receiver.Status.Conditions, metav1.Condition{
+			Type:   radiov1beta1.SampleLossCondition,
+			Status: metav1.ConditionFalse,
+			Reason: radiov1beta1.NoSampleLossReason,
+			Message: fmt.Sprintf("%s of the samples dropped over the last %s, within the threshold of %s",
+				formatRatio(stats.Ratio), window, formatRatio(threshold)),
+		})
+		return window
+	}
+
+	message := fmt.Sprintf("rtl_tcp dropped %s of the samples over the last %s, above the threshold of %s, %d buffers in total",
+		formatRatio(stats.Ratio), window, formatRatio(threshold), stats.DroppedBuffers)
+	starting := !meta.IsStatusConditionTrue(receiver.Status.Conditions, radiov1beta1.SampleLossCondition)
+	meta.SetStatusCondition(&receiver.Status.Conditions, metav1.Condition{
+		Type:    radiov1beta1.SampleLossCondition,
+		Status:  metav1.ConditionTrue,
+		Reason:  radiov1beta1.SamplesDroppedReason,
+		Message: message,
+	})
+	if starting && r.Recorder != nil {
+		r.Recorder.Eventf(receiver, pod, corev1.EventTypeWarning, radiov1beta1.SamplesDroppedReason, "DetectSampleLoss", message)
+	}
+
+	return window
+}
+
+// fetchSampleLoss asks the proxy running in the pod for the samples rtl_tcp
+// dropped.
+func fetchSampleLoss(ctx context.Context, pod *corev1.Pod) (*loss.Stats, error) {
+	stats := &loss.Stats{}
+	if err := fetchProxyStatus(ctx, pod, "/loss", stats); err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
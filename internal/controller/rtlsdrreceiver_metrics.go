@@ -0,0 +1,141 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	corev1ac "k8s.io/client-go/applyconfigurations/core/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	radiov1beta1 "github.com/frelon/k8s-radio/api/v1beta1"
+)
+
+const (
+	// MetricsPort is the port the exporter serves the metrics of the
+	// receiver on.
+	MetricsPort = 9190
+
+	// metricsTapPort is the port the proxy serves the stream to the
+	// exporter on. It only listens on the loopback interface.
+	metricsTapPort = 9182
+
+	// DefaultMetricsInterval and DefaultMetricsFFTSize are used when the
+	// API server has not defaulted the metrics settings.
+	DefaultMetricsInterval = 5 * time.Second
+	DefaultMetricsFFTSize  = 1024
+)
+
+// podMonitorGVK is the kind of the Prometheus Operator scraping the metrics.
+var podMonitorGVK = schema.GroupVersionKind{Group: "monitoring.coreos.com", Version: "v1", Kind: "PodMonitor"}
+
+// metricsEnabled reports whether the signal of the receiver is exported to
+// Prometheus.
+func metricsEnabled(receiver *radiov1beta1.RtlSdrReceiver) bool {
+	return receiver.Spec.Metrics != nil
+}
+
+// metricsInterval returns the time each measurement is taken over.
+func metricsInterval(receiver *radiov1beta1.RtlSdrReceiver) time.Duration {
+	if interval := receiver.Spec.Metrics.Interval; interval != nil {
+		return interval.Duration
+	}
+
+	return DefaultMetricsInterval
+}
+
+// metricsTapArgs returns the proxy arguments serving the stream to the
+// exporter.
+func metricsTapArgs() []string {
+	return []string{"--tap-listen", fmt.Sprintf("127.0.0.1:%d", metricsTapPort)}
+}
+
+// exporterContainer returns the sidecar measuring the stream tapped from the
+// proxy and serving the measurements as Prometheus metrics.
+func (r *RtlSdrReceiverReconciler) exporterContainer(receiver *radiov1beta1.RtlSdrReceiver) *corev1ac.ContainerApplyConfiguration {
+	args := []string{
+		"--listen", fmt.Sprintf(":%d", MetricsPort),
+		"--upstream", fmt.Sprintf("127.0.0.1:%d", metricsTapPort),
+		"--tuning-url", fmt.Sprintf("http://127.0.0.1:%d/tuning", ProxyStatusPort),
+		"--receiver", receiver.Namespace + "/" + receiver.Name,
+		"--interval", metricsInterval(receiver).String(),
+		"--fft-size", strconv.Itoa(int(ptr.Deref(receiver.Spec.Metrics.FFTSize, DefaultMetricsFFTSize))),
+	}
+
+	return corev1ac.Container().
+		WithName("exporter").
+		WithImage(r.ExporterImage).
+		WithCommand("/rtl-exporter").
+		WithArgs(append(args, proxyTuningArgs(receiver)...)...).
+		WithPorts(corev1ac.ContainerPort().
+			WithName("metrics").
+			WithContainerPort(MetricsPort).
+			WithProtocol(corev1.ProtocolTCP))
+}
+
+// podMonitor returns the PodMonitor scraping the exporter of the receiver.
+func podMonitor(receiver *radiov1beta1.RtlSdrReceiver) *unstructured.Unstructured {
+	labels := map[string]any{}
+	for k, v := range receiverLabels(receiver) {
+		labels[k] = v
+	}
+
+	return ownedUnstructured(receiver, podMonitorGVK, map[string]any{
+		"selector": map[string]any{
+			"matchLabels": labels,
+		},
+		"podMetricsEndpoints": []any{map[string]any{
+			"port":     "metrics",
+			"path":     "/metrics",
+			"interval": metricsInterval(receiver).String(),
+		}},
+	})
+}
+
+// reconcilePodMonitor creates the PodMonitor scraping the metrics of the
+// receiver, or deletes it when they are not exported. Without the Prometheus
+// Operator installed there is nothing to do, and the metrics have to be
+// scraped some other way.
+func (r *RtlSdrReceiverReconciler) reconcilePodMonitor(ctx context.Context, receiver *radiov1beta1.RtlSdrReceiver) error {
+	var err error
+	if metricsEnabled(receiver) {
+		ac := client.ApplyConfigurationFromUnstructured(podMonitor(receiver))
+		err = r.Apply(ctx, ac, client.FieldOwner(FieldManager), client.ForceOwnership)
+	} else {
+		obj := &unstructured.Unstructured{}
+		obj.SetGroupVersionKind(podMonitorGVK)
+		err = r.deleteOwnedNamed(ctx, receiver, receiver.Name, obj)
+	}
+
+	if meta.IsNoMatchError(err) {
+		if metricsEnabled(receiver) {
+			log.FromContext(ctx).Info("PodMonitors are not served, not creating one", "error", err)
+		}
+		return nil
+	}
+
+	return err
+}
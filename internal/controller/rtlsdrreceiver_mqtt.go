@@ -0,0 +1,154 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1ac "k8s.io/client-go/applyconfigurations/core/v1"
+	"k8s.io/utils/ptr"
+
+	radiov1beta1 "github.com/frelon/k8s-radio/api/v1beta1"
+)
+
+const (
+	// MQTTRelayPort is the port containers of the pod post what they
+	// publish to MQTT to. It only listens on the loopback interface.
+	MQTTRelayPort = 9181
+
+	// DefaultMQTTTopic, DefaultMQTTQoS, DefaultMQTTHeartbeatInterval and
+	// DefaultMQTTInterval are used when the API server has not defaulted
+	// the MQTT settings.
+	DefaultMQTTTopic             = "radio/{namespace}/{name}"
+	DefaultMQTTQoS               = 1
+	DefaultMQTTHeartbeatInterval = 30 * time.Second
+	DefaultMQTTInterval          = 5 * time.Second
+)
+
+// levelEnabled reports whether the proxy measures the signal level of the
+// receiver, which is published to MQTT in IQ mode.
+func levelEnabled(receiver *radiov1beta1.RtlSdrReceiver) bool {
+	mode := receiver.Spec.Mode
+	return receiver.Spec.MQTT != nil && (mode == "" || mode == radiov1beta1.ModeIQ)
+}
+
+// mqttInterval returns the time between signal levels and publications of
+// the aircraft.
+func mqttInterval(receiver *radiov1beta1.RtlSdrReceiver) time.Duration {
+	if interval := receiver.Spec.MQTT.Interval; interval != nil {
+		return interval.Duration
+	}
+
+	return DefaultMQTTInterval
+}
+
+// levelArgs returns the proxy arguments measuring the signal level.
+func levelArgs(receiver *radiov1beta1.RtlSdrReceiver) []string {
+	return []string{"--level-interval", mqttInterval(receiver).String()}
+}
+
+// mqttTopic returns the topic the topics of the receiver are below.
+func mqttTopic(receiver *radiov1beta1.RtlSdrReceiver) string {
+	topic := receiver.Spec.MQTT.Topic
+	if topic == "" {
+		topic = DefaultMQTTTopic
+	}
+
+	return strings.NewReplacer("{namespace}", receiver.Namespace, "{name}", receiver.Name).Replace(topic)
+}
+
+// mqttEventsSink returns the sink the decoder publishes its events to when
+// they are relayed to MQTT, or an empty string if they are not.
+func mqttEventsSink(receiver *radiov1beta1.RtlSdrReceiver) string {
+	if receiver.Spec.MQTT == nil {
+		return ""
+	}
+	if e := receiver.Spec.Events; e != nil && e.Sink != "" && e.Sink != "stdout" {
+		return ""
+	}
+
+	return fmt.Sprintf("http://127.0.0.1:%d/publish/events", MQTTRelayPort)
+}
+
+// mqttContainer returns the sidecar publishing the heartbeats, signal level
+// and decoder output of the receiver to MQTT.
+func (r *RtlSdrReceiverReconciler) mqttContainer(receiver *radiov1beta1.RtlSdrReceiver) *corev1ac.ContainerApplyConfiguration {
+	mqtt := receiver.Spec.MQTT
+
+	mode := receiver.Spec.Mode
+	if mode == "" {
+		mode = radiov1beta1.ModeIQ
+	}
+
+	heartbeat := DefaultMQTTHeartbeatInterval
+	if mqtt.HeartbeatInterval != nil {
+		heartbeat = mqtt.HeartbeatInterval.Duration
+	}
+
+	args := []string{
+		"--listen", fmt.Sprintf("127.0.0.1:%d", MQTTRelayPort),
+		"--broker", mqtt.Broker,
+		"--topic", mqttTopic(receiver),
+		"--qos", strconv.Itoa(int(ptr.Deref(mqtt.QoS, DefaultMQTTQoS))),
+		"--receiver", receiver.Namespace + "/" + receiver.Name,
+		"--mode", string(mode),
+		"--heartbeat-interval", heartbeat.String(),
+	}
+	// The frequency of receivers tuned live changes without restarting the
+	// sidecar, it is in the status of the receiver instead.
+	if receiver.Spec.Frequency != nil && !liveTuningEnabled(receiver) {
+		args = append(args, "--frequency", strconv.FormatInt(receiver.Spec.Frequency.Value(), 10))
+	}
+
+	switch {
+	case levelEnabled(receiver):
+		args = append(args, "--poll", fmt.Sprintf("signal=http://127.0.0.1:%d/level", ProxyStatusPort))
+	case mode == radiov1beta1.ModeADSB:
+		args = append(args, "--poll", fmt.Sprintf("aircraft=http://127.0.0.1:%d%s", listenPort(receiver), ADSBPath))
+	}
+	if levelEnabled(receiver) || mode == radiov1beta1.ModeADSB {
+		args = append(args, "--poll-interval", mqttInterval(receiver).String())
+	}
+
+	container := corev1ac.Container().
+		WithName("mqtt").
+		WithImage(r.MQTTImage).
+		WithCommand("/rtl-mqtt").
+		WithArgs(args...)
+
+	if ref := mqtt.CredentialsSecretRef; ref != nil {
+		container.WithEnv(
+			corev1ac.EnvVar().
+				WithName("MQTT_USERNAME").
+				WithValueFrom(corev1ac.EnvVarSource().
+					WithSecretKeyRef(corev1ac.SecretKeySelector().
+						WithName(ref.Name).
+						WithKey("username"))),
+			corev1ac.EnvVar().
+				WithName("MQTT_PASSWORD").
+				WithValueFrom(corev1ac.EnvVarSource().
+					WithSecretKeyRef(corev1ac.SecretKeySelector().
+						WithName(ref.Name).
+						WithKey("password").
+						WithOptional(true))))
+	}
+
+	return container
+}
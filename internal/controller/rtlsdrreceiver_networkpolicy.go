@@ -0,0 +1,199 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	metav1ac "k8s.io/client-go/applyconfigurations/meta/v1"
+	networkingv1ac "k8s.io/client-go/applyconfigurations/networking/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	radiov1beta1 "github.com/frelon/k8s-radio/api/v1beta1"
+)
+
+// controllerLabels are the labels of the controller pods, which fetch the
+// status of receivers.
+var controllerLabels = map[string]string{"control-plane": "controller-manager"}
+
+// metricsNamespaceLabels are the labels of the namespaces allowed to scrape
+// the metrics of receivers, as for the metrics of the controller.
+var metricsNamespaceLabels = map[string]string{"metrics": "enabled"}
+
+// clientPorts returns the ports clients of the receiver connect to: the
+// stream, the decoded messages, the spectrum and the channels.
+func clientPorts(receiver *radiov1beta1.RtlSdrReceiver, channels []radiov1beta1.RtlSdrChannel) []int32 {
+	ports := []int32{listenPort(receiver)}
+	if receiver.Spec.Mode == radiov1beta1.ModeADSB {
+		ports = append(ports, SBSPort, BeastPort)
+	}
+	if spectrumEnabled(receiver) {
+		ports = append(ports, SpectrumPort)
+	}
+
+	var channelPortList []int32
+	for _, port := range channelPorts(channels) {
+		channelPortList = append(channelPortList, port)
+	}
+	slices.Sort(channelPortList)
+
+	return append(ports, channelPortList...)
+}
+
+// controllerPorts returns the ports the controller fetches the status of the
+// receiver from.
+func controllerPorts(receiver *radiov1beta1.RtlSdrReceiver) []int32 {
+	var ports []int32
+	if proxyStatusEnabled(receiver) {
+		ports = append(ports, ProxyStatusPort)
+	}
+	if receiver.Spec.Mode == radiov1beta1.ModeADSB {
+		ports = append(ports, listenPort(receiver))
+	}
+
+	return ports
+}
+
+// statusGuarded reports whether only the controller may reach the status
+// port of the proxy, even when anyone may reach the receiver, as it would
+// leak what access guards or serves the control API retuning the receiver.
+func statusGuarded(receiver *radiov1beta1.RtlSdrReceiver) bool {
+	return proxyStatusEnabled(receiver) && accessEnabled(receiver) || liveTuningEnabled(receiver)
+}
+
+// networkPolicyPorts returns the TCP ports of an ingress rule.
+func networkPolicyPorts(ports []int32) []*networkingv1ac.NetworkPolicyPortApplyConfiguration {
+	acs := make([]*networkingv1ac.NetworkPolicyPortApplyConfiguration, 0, len(ports))
+	for _, port := range ports {
+		acs = append(acs, networkingv1ac.NetworkPolicyPort().
+			WithProtocol(corev1.ProtocolTCP).
+			WithPort(intstr.FromInt32(port)))
+	}
+
+	return acs
+}
+
+// labelSelector converts a label selector into an apply configuration.
+func labelSelector(selector *metav1.LabelSelector) *metav1ac.LabelSelectorApplyConfiguration {
+	ac := metav1ac.LabelSelector()
+	if selector.MatchLabels != nil {
+		ac.WithMatchLabels(selector.MatchLabels)
+	}
+	for _, req := range selector.MatchExpressions {
+		ac.WithMatchExpressions(metav1ac.LabelSelectorRequirement().
+			WithKey(req.Key).
+			WithOperator(req.Operator).
+			WithValues(req.Values...))
+	}
+
+	return ac
+}
+
+// networkPolicyPeer converts a peer of the receiver spec into an apply
+// configuration.
+func networkPolicyPeer(peer *networkingv1.NetworkPolicyPeer) *networkingv1ac.NetworkPolicyPeerApplyConfiguration {
+	ac := networkingv1ac.NetworkPolicyPeer()
+	if peer.PodSelector != nil {
+		ac.WithPodSelector(labelSelector(peer.PodSelector))
+	}
+	if peer.NamespaceSelector != nil {
+		ac.WithNamespaceSelector(labelSelector(peer.NamespaceSelector))
+	}
+	if peer.IPBlock != nil {
+		ac.WithIPBlock(networkingv1ac.IPBlock().
+			WithCIDR(peer.IPBlock.CIDR).
+			WithExcept(peer.IPBlock.Except...))
+	}
+
+	return ac
+}
+
+// controllerPeer returns the peer matching the controller pods, in the
+// namespace of the controller if known.
+func (r *RtlSdrReceiverReconciler) controllerPeer() *networkingv1ac.NetworkPolicyPeerApplyConfiguration {
+	namespaces := metav1ac.LabelSelector()
+	if r.ControllerNamespace != "" {
+		namespaces.WithMatchLabels(map[string]string{corev1.LabelMetadataName: r.ControllerNamespace})
+	}
+
+	return networkingv1ac.NetworkPolicyPeer().
+		WithNamespaceSelector(namespaces).
+		WithPodSelector(metav1ac.LabelSelector().WithMatchLabels(controllerLabels))
+}
+
+// networkPolicy returns the NetworkPolicy only letting the allowed clients
+// reach the receiver pods, besides the controller and Prometheus. Without a
+// NetworkPolicy in the spec anyone may reach the receiver, but its status.
+func (r *RtlSdrReceiverReconciler) networkPolicy(receiver *radiov1beta1.RtlSdrReceiver, channels []radiov1beta1.RtlSdrChannel) *networkingv1ac.NetworkPolicyApplyConfiguration {
+	spec := networkingv1ac.NetworkPolicySpec().
+		WithPodSelector(metav1ac.LabelSelector().WithMatchLabels(receiverLabels(receiver))).
+		WithPolicyTypes(networkingv1.PolicyTypeIngress)
+
+	// A rule without peers allows everyone, so it is only added without a
+	// NetworkPolicy in the spec, and left out to allow no clients at all.
+	if receiver.Spec.NetworkPolicy == nil {
+		ports := clientPorts(receiver, channels)
+		if metricsEnabled(receiver) {
+			ports = append(ports, MetricsPort)
+		}
+		spec.WithIngress(networkingv1ac.NetworkPolicyIngressRule().
+			WithPorts(networkPolicyPorts(ports)...))
+	} else if from := receiver.Spec.NetworkPolicy.From; len(from) > 0 {
+		rule := networkingv1ac.NetworkPolicyIngressRule().
+			WithPorts(networkPolicyPorts(clientPorts(receiver, channels))...)
+		for i := range from {
+			rule.WithFrom(networkPolicyPeer(&from[i]))
+		}
+		spec.WithIngress(rule)
+	}
+	if ports := controllerPorts(receiver); len(ports) > 0 {
+		spec.WithIngress(networkingv1ac.NetworkPolicyIngressRule().
+			WithFrom(r.controllerPeer()).
+			WithPorts(networkPolicyPorts(ports)...))
+	}
+	if metricsEnabled(receiver) && receiver.Spec.NetworkPolicy != nil {
+		spec.WithIngress(networkingv1ac.NetworkPolicyIngressRule().
+			WithFrom(networkingv1ac.NetworkPolicyPeer().
+				WithNamespaceSelector(metav1ac.LabelSelector().WithMatchLabels(metricsNamespaceLabels))).
+			WithPorts(networkPolicyPorts([]int32{MetricsPort})...))
+	}
+
+	return networkingv1ac.NetworkPolicy(receiver.Name, receiver.Namespace).
+		WithLabels(receiverLabels(receiver)).
+		WithOwnerReferences(ownerReference(receiver)).
+		WithSpec(spec)
+}
+
+// reconcileNetworkPolicy creates the NetworkPolicy restricting who can reach
+// the receiver, or deletes it when anyone may reach all of it.
+func (r *RtlSdrReceiverReconciler) reconcileNetworkPolicy(ctx context.Context, receiver *radiov1beta1.RtlSdrReceiver) error {
+	if receiver.Spec.NetworkPolicy == nil && !statusGuarded(receiver) {
+		return r.deleteOwnedNamed(ctx, receiver, receiver.Name, &networkingv1.NetworkPolicy{})
+	}
+
+	channels, err := receiverChannels(ctx, r.Client, receiver)
+	if err != nil {
+		return err
+	}
+
+	return r.Apply(ctx, r.networkPolicy(receiver, channels), client.FieldOwner(FieldManager), client.ForceOwnership)
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1ac "k8s.io/client-go/applyconfigurations/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	radiov1beta1 "github.com/frelon/k8s-radio/api/v1beta1"
+	rtlsdr "github.com/frelon/k8s-radio/device-plugin/rtl-sdr"
+)
+
+// reconcilePool updates where the receiver stands in its pool and reports
+// whether it may run, which a receiver of a pool only may once the pool
+// admitted it. active tells whether the receiver should be running at all.
+// Receivers without a pool always may.
+func (r *RtlSdrReceiverReconciler) reconcilePool(ctx context.Context, receiver *radiov1beta1.RtlSdrReceiver, active bool) (bool, error) {
+	if receiver.Spec.Pool == nil || !active {
+		clearInvalid(receiver, radiov1beta1.QueuedReason)
+		receiver.Status.Pool = nil
+		return active, nil
+	}
+
+	name := receiver.Spec.Pool.Name
+	pool := &radiov1beta1.RtlSdrReceiverPool{}
+	if err := r.Get(ctx, client.ObjectKey{Name: name}, pool); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return false, err
+		}
+		queue(receiver, 0, fmt.Sprintf("Pool %s not found", name))
+		return false, nil
+	}
+
+	for _, a := range pool.Status.Admitted {
+		if a.Namespace == receiver.Namespace && a.Name == receiver.Name {
+			clearInvalid(receiver, radiov1beta1.QueuedReason)
+			receiver.Status.Pool = &radiov1beta1.RtlSdrPoolStatus{Node: a.Node, Serial: a.Serial}
+			return true, nil
+		}
+	}
+
+	for i, q := range pool.Status.Queue {
+		if q.Namespace == receiver.Namespace && q.Name == receiver.Name {
+			position := int32(i + 1)
+			message := fmt.Sprintf("Waiting for a dongle of pool %s at position %d", name, position)
+			if q.Reason == radiov1beta1.QuotaExceededReason {
+				message = fmt.Sprintf("Waiting for the quota of the namespace in pool %s at position %d", name, position)
+			}
+			queue(receiver, position, message)
+			return false, nil
+		}
+	}
+
+	queue(receiver, 0, fmt.Sprintf("Waiting for pool %s to admit the receiver", name))
+	return false, nil
+}
+
+// queue marks the receiver as waiting for its pool at position, zero when
+// the pool has not seen it yet.
+func queue(receiver *radiov1beta1.RtlSdrReceiver, position int32, message string) {
+	receiver.Status.State = radiov1beta1.StateQueued
+	receiver.Status.Pool = &radiov1beta1.RtlSdrPoolStatus{Position: position}
+	meta.SetStatusCondition(&receiver.Status.Conditions, metav1.Condition{
+		Type:    radiov1beta1.ReadyCondition,
+		Status:  metav1.ConditionFalse,
+		Reason:  radiov1beta1.QueuedReason,
+		Message: message,
+	})
+}
+
+// poolResource returns the extended resource the pod of the receiver is
+// allocated a dongle as: the one of the serial the pool assigned, or any.
+func poolResource(receiver *radiov1beta1.RtlSdrReceiver) corev1.ResourceName {
+	pool := receiver.Status.Pool
+	if receiver.Spec.Pool == nil || pool == nil || pool.Serial == "" {
+		return RtlSdrResourceName
+	}
+
+	name, ok := rtlsdr.SerialResourceName(pool.Serial)
+	if !ok {
+		return RtlSdrResourceName
+	}
+
+	return corev1.ResourceName(name)
+}
+
+// poolAffinity pins the pod of an admitted receiver to the node the pool
+// assigned it. The pod waits in Pending rather than failing when the dongle
+// is still being freed by a pod outside the pool.
+func poolAffinity(receiver *radiov1beta1.RtlSdrReceiver) *corev1ac.AffinityApplyConfiguration {
+	pool := receiver.Status.Pool
+	if receiver.Spec.Pool == nil || pool == nil || pool.Node == "" {
+		return nil
+	}
+
+	return corev1ac.Affinity().
+		WithNodeAffinity(corev1ac.NodeAffinity().
+			WithRequiredDuringSchedulingIgnoredDuringExecution(corev1ac.NodeSelector().
+				WithNodeSelectorTerms(corev1ac.NodeSelectorTerm().
+					WithMatchFields(corev1ac.NodeSelectorRequirement().
+						WithKey("metadata.name").
+						WithOperator(corev1.NodeSelectorOpIn).
+						WithValues(pool.Node)))))
+}
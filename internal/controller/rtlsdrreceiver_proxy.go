@@ -0,0 +1,196 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	corev1ac "k8s.io/client-go/applyconfigurations/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	radiov1beta1 "github.com/frelon/k8s-radio/api/v1beta1"
+)
+
+const (
+	// ProxyStatusPort is the port the proxy serves the status of its
+	// recorder and scanner, and the signal level, on.
+	ProxyStatusPort = 9180
+
+	// proxyStatusTimeout bounds how long fetching the status of the proxy,
+	// or any other container of a receiver, may take.
+	proxyStatusTimeout = 5 * time.Second
+)
+
+// proxyStatusEnabled reports whether the proxy of the receiver runs a
+// recorder, scanner or level meter, which serve their status over HTTP,
+// serves its tuning to the exporter, counts the dropped samples or retunes
+// rtl_tcp on behalf of the controller.
+func proxyStatusEnabled(receiver *radiov1beta1.RtlSdrReceiver) bool {
+	return receiver.Spec.Recording != nil || receiver.Spec.Scan != nil || levelEnabled(receiver) ||
+		metricsEnabled(receiver) || sampleLossEnabled(receiver) || liveTuningEnabled(receiver)
+}
+
+// proxyStatusArgs returns the proxy arguments serving the status.
+func proxyStatusArgs(receiver *radiov1beta1.RtlSdrReceiver) []string {
+	args := []string{"--status-listen", fmt.Sprintf(":%d", ProxyStatusPort)}
+	if sampleLossEnabled(receiver) {
+		args = append(args, sampleLossArgs(receiver)...)
+	}
+	if liveTuningEnabled(receiver) {
+		args = append(args, controlArgs()...)
+	}
+
+	return args
+}
+
+// proxyTuningArgs returns the proxy arguments telling it the tuning rtl_tcp
+// was started with, which the recorder, scanner and spectrum need before a
+// client tunes the receiver. Receivers tuned live start with the defaults of
+// rtl_tcp, which the proxy assumes too.
+func proxyTuningArgs(receiver *radiov1beta1.RtlSdrReceiver) []string {
+	if liveTuningEnabled(receiver) {
+		return nil
+	}
+
+	args := []string{"--sample-rate", strconv.FormatInt(sampleRate(receiver), 10)}
+	if receiver.Spec.Frequency != nil {
+		args = append(args, "--frequency", strconv.FormatInt(receiver.Spec.Frequency.Value(), 10))
+	}
+
+	return args
+}
+
+// withProxyStatus exposes the status port of the proxy container.
+func withProxyStatus(container *corev1ac.ContainerApplyConfiguration) *corev1ac.ContainerApplyConfiguration {
+	return container.WithPorts(corev1ac.ContainerPort().
+		WithName("status").
+		WithContainerPort(ProxyStatusPort).
+		WithProtocol(corev1.ProtocolTCP))
+}
+
+// reconcilePodStatus updates the recording, scan, sample loss, ADS-B and
+// tuning status of a running receiver from its pod and returns when to check them
+// again, or zero if there is nothing to follow.
+func (r *RtlSdrReceiverReconciler) reconcilePodStatus(ctx context.Context, receiver *radiov1beta1.RtlSdrReceiver) time.Duration {
+	var pod *corev1.Pod
+	reporting := proxyStatusEnabled(receiver) || receiver.Spec.Mode == radiov1beta1.ModeADSB
+	if reporting && receiver.Status.State == radiov1beta1.StateRunning {
+		var err error
+		if pod, err = r.runningPod(ctx, receiver); err != nil || pod == nil {
+			log.FromContext(ctx).Info("No running pod to fetch the status from", "error", err)
+		}
+	}
+
+	return minRequeue(
+		r.reconcileRecording(ctx, receiver, pod),
+		r.reconcileScan(ctx, receiver, pod),
+		r.reconcileSampleLoss(ctx, receiver, pod),
+		r.reconcileADSB(ctx, receiver, pod),
+		r.reconcileTuning(ctx, receiver, pod))
+}
+
+// minRequeue returns the shortest of the non-zero durations, or zero if
+// there is none.
+func minRequeue(durations ...time.Duration) time.Duration {
+	var shortest time.Duration
+	for _, d := range durations {
+		if d > 0 && (shortest == 0 || d < shortest) {
+			shortest = d
+		}
+	}
+
+	return shortest
+}
+
+// runningPod returns a running pod of the receiver, or nil if there is none.
+func (r *RtlSdrReceiverReconciler) runningPod(ctx context.Context, receiver *radiov1beta1.RtlSdrReceiver) (*corev1.Pod, error) {
+	pods := &corev1.PodList{}
+	if err := r.List(ctx, pods, client.InNamespace(receiver.Namespace), client.MatchingLabels(receiverLabels(receiver))); err != nil {
+		return nil, err
+	}
+
+	for i := range pods.Items {
+		if pod := &pods.Items[i]; pod.Status.Phase == corev1.PodRunning && pod.Status.PodIP != "" {
+			return pod, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// fetchProxyStatus asks the proxy running in the pod for the status served on
+// path and decodes it into v.
+func fetchProxyStatus(ctx context.Context, pod *corev1.Pod, path string, v any) error {
+	return fetchPodStatus(ctx, pod, ProxyStatusPort, path, v)
+}
+
+// fetchPodStatus fetches the JSON status served on port and path of the pod
+// and decodes it into v.
+func fetchPodStatus(ctx context.Context, pod *corev1.Pod, port int32, path string, v any) error {
+	return podRequest(ctx, pod, http.MethodGet, port, path, "", nil, v)
+}
+
+// podRequest sends a request with body encoded as JSON, if any, to port and
+// path of the pod and decodes the JSON response into v. The request carries
+// token as bearer token, if any.
+func podRequest(ctx context.Context, pod *corev1.Pod, method string, port int32, path, token string, body, v any) error {
+	ctx, cancel := context.WithTimeout(ctx, proxyStatusTimeout)
+	defer cancel()
+
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	url := fmt.Sprintf("http://%s%s", net.JoinHostPort(pod.Status.PodIP, strconv.Itoa(int(port))), path)
+	req, err := http.NewRequestWithContext(ctx, method, url, reader)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %s from %s", resp.Status, url)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(v)
+}
@@ -0,0 +1,153 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"path"
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	corev1ac "k8s.io/client-go/applyconfigurations/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	radiov1beta1 "github.com/frelon/k8s-radio/api/v1beta1"
+)
+
+const (
+	recordingsVolume    = "recordings"
+	recordingsMountPath = "/recordings"
+
+	// recordingPollInterval is the time between updates of the recording
+	// status of a running receiver.
+	recordingPollInterval = 30 * time.Second
+)
+
+// recordingDir returns the directory the datasets of the receiver are written
+// to inside the proxy container.
+func recordingDir(receiver *radiov1beta1.RtlSdrReceiver) string {
+	dir := receiver.Spec.Recording.Path
+	if dir == "" {
+		dir = receiver.Name
+	}
+
+	return path.Join(recordingsMountPath, dir)
+}
+
+// hardware describes the dongle of the receiver in the dataset metadata.
+func hardware(receiver *radiov1beta1.RtlSdrReceiver) string {
+	if receiver.Spec.Simulation != nil {
+		return "Simulated RTL-SDR"
+	}
+
+	return "RTL-SDR " + string(receiver.Spec.Version)
+}
+
+// recorderArgs returns the proxy arguments recording the receiver stream.
+func recorderArgs(receiver *radiov1beta1.RtlSdrReceiver) []string {
+	recording := receiver.Spec.Recording
+
+	args := []string{
+		"--record-dir", recordingDir(receiver),
+		"--record-name", receiver.Name,
+		"--receiver", receiver.Namespace + "/" + receiver.Name,
+		"--hardware", hardware(receiver),
+	}
+	if recording.Duration != nil {
+		args = append(args, "--record-duration", recording.Duration.Duration.String())
+	}
+	if recording.MaxSize != nil {
+		args = append(args, "--record-max-size", strconv.FormatInt(recording.MaxSize.Value(), 10))
+	}
+
+	if rotation := recording.Rotation; rotation != nil {
+		if rotation.Interval != nil {
+			args = append(args, "--record-interval", rotation.Interval.Duration.String())
+		}
+		if rotation.MaxFileSize != nil {
+			args = append(args, "--record-file-size", strconv.FormatInt(rotation.MaxFileSize.Value(), 10))
+		}
+		if rotation.MaxFiles != nil {
+			args = append(args, "--record-max-files", strconv.Itoa(int(*rotation.MaxFiles)))
+		}
+	}
+
+	return args
+}
+
+// withRecorder mounts the recording volume into the proxy container and
+// tells it the node it runs on.
+func withRecorder(container *corev1ac.ContainerApplyConfiguration) *corev1ac.ContainerApplyConfiguration {
+	return container.
+		WithEnv(corev1ac.EnvVar().
+			WithName("NODE_NAME").
+			WithValueFrom(corev1ac.EnvVarSource().
+				WithFieldRef(corev1ac.ObjectFieldSelector().
+					WithFieldPath("spec.nodeName")))).
+		WithVolumeMounts(corev1ac.VolumeMount().
+			WithName(recordingsVolume).
+			WithMountPath(recordingsMountPath))
+}
+
+// reconcileRecording updates the recording status from the running pod of
+// the receiver and returns when to check it again, or zero if there is
+// nothing to follow. Failing to reach the recorder is not an error, the
+// previous status is kept until the next attempt.
+func (r *RtlSdrReceiverReconciler) reconcileRecording(ctx context.Context, receiver *radiov1beta1.RtlSdrReceiver, pod *corev1.Pod) time.Duration {
+	if receiver.Spec.Recording == nil {
+		receiver.Status.Recording = nil
+		return 0
+	}
+
+	if receiver.Status.State != radiov1beta1.StateRunning {
+		return 0
+	}
+
+	if pod == nil {
+		return recordingPollInterval
+	}
+
+	fetch := r.RecordingStatus
+	if fetch == nil {
+		fetch = fetchRecordingStatus
+	}
+
+	status, err := fetch(ctx, pod)
+	if err != nil {
+		log.FromContext(ctx).Info("Failed fetching recording status", "pod", pod.Name, "error", err)
+		return recordingPollInterval
+	}
+
+	receiver.Status.Recording = status
+	if status.Finished {
+		return 0
+	}
+
+	return recordingPollInterval
+}
+
+// fetchRecordingStatus asks the proxy running in the pod for the status of
+// its recorder.
+func fetchRecordingStatus(ctx context.Context, pod *corev1.Pod) (*radiov1beta1.RtlSdrRecordingStatus, error) {
+	status := &radiov1beta1.RtlSdrRecordingStatus{}
+	if err := fetchProxyStatus(ctx, pod, "/recording", status); err != nil {
+		return nil, err
+	}
+
+	return status, nil
+}
@@ -0,0 +1,229 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	radiov1beta1 "github.com/frelon/k8s-radio/api/v1beta1"
+	"github.com/frelon/k8s-radio/pkg/scanner"
+)
+
+const (
+	// DefaultScanBandwidth is the bandwidth of the scanned channels when
+	// neither the scan nor its range says.
+	DefaultScanBandwidth = 25_000
+
+	// maxScanChannels bounds the number of channels of a scan.
+	maxScanChannels = 1000
+
+	// scanPollInterval is the time between updates of the scan status of a
+	// running receiver.
+	scanPollInterval = 10 * time.Second
+)
+
+// scanFrequency returns the frequency in Hz, or an error if rtl_tcp cannot
+// tune to it.
+func scanFrequency(q resource.Quantity) (uint32, error) {
+	hz := q.Value()
+	if hz <= 0 || hz > math.MaxUint32 {
+		return 0, fmt.Errorf("frequency %s is out of range", q.String())
+	}
+
+	return uint32(hz), nil
+}
+
+// planScan returns the frequencies of the channels to scan in Hz, or an
+// error if the scan is invalid.
+func planScan(scan *radiov1beta1.RtlSdrScan) ([]uint32, error) {
+	if scan.Range == nil {
+		if len(scan.Frequencies) == 0 {
+			return nil, errors.New("no frequencies to scan")
+		}
+
+		frequencies := make([]uint32, 0, len(scan.Frequencies))
+		for _, q := range scan.Frequencies {
+			hz, err := scanFrequency(q)
+			if err != nil {
+				return nil, err
+			}
+			frequencies = append(frequencies, hz)
+		}
+
+		return frequencies, nil
+	}
+
+	start, err := scanFrequency(scan.Range.Start)
+	if err != nil {
+		return nil, err
+	}
+	end, err := scanFrequency(scan.Range.End)
+	if err != nil {
+		return nil, err
+	}
+	step := scan.Range.Step.Value()
+
+	switch {
+	case step <= 0:
+		return nil, fmt.Errorf("step %s must be positive", scan.Range.Step.String())
+	case end < start:
+		return nil, fmt.Errorf("end %s is below start %s", scan.Range.End.String(), scan.Range.Start.String())
+	case int64(end-start)/step >= maxScanChannels:
+		return nil, fmt.Errorf("range has more than %d channels", maxScanChannels)
+	}
+
+	var frequencies []uint32
+	for hz := int64(start); hz <= int64(end); hz += step {
+		frequencies = append(frequencies, uint32(hz))
+	}
+
+	return frequencies, nil
+}
+
+// scanBandwidth returns the bandwidth of the scanned channels in Hz.
+func scanBandwidth(scan *radiov1beta1.RtlSdrScan) int64 {
+	switch {
+	case scan.Bandwidth != nil:
+		return scan.Bandwidth.Value()
+	case scan.Range != nil:
+		return scan.Range.Step.Value()
+	default:
+		return DefaultScanBandwidth
+	}
+}
+
+// scanArgs returns the proxy arguments scanning the channels of the
+// receiver. The scan must have been validated.
+func scanArgs(receiver *radiov1beta1.RtlSdrReceiver) []string {
+	scan := receiver.Spec.Scan
+	frequencies, _ := planScan(scan)
+
+	channels := make([]string, 0, len(frequencies))
+	for _, hz := range frequencies {
+		channels = append(channels, strconv.FormatUint(uint64(hz), 10))
+	}
+
+	args := []string{
+		"--scan-frequencies", strings.Join(channels, ","),
+		"--scan-bandwidth", strconv.FormatInt(scanBandwidth(scan), 10),
+	}
+	if scan.Squelch != nil {
+		args = append(args, "--scan-squelch", strconv.Itoa(int(*scan.Squelch)))
+	}
+	if scan.Dwell != nil {
+		args = append(args, "--scan-dwell", scan.Dwell.Duration.String())
+	}
+	if scan.Hold != nil {
+		args = append(args, "--scan-hold", scan.Hold.Duration.String())
+	}
+	if scan.MaxHits != nil {
+		args = append(args, "--scan-max-hits", strconv.Itoa(int(*scan.MaxHits)))
+	}
+
+	return args
+}
+
+// validateScan reports whether the scan of the receiver, if any, is valid.
+// An invalid scan fails the receiver.
+func (r *RtlSdrReceiverReconciler) validateScan(ctx context.Context, receiver *radiov1beta1.RtlSdrReceiver) bool {
+	if receiver.Spec.Scan != nil {
+		if _, err := planScan(receiver.Spec.Scan); err != nil {
+			log.FromContext(ctx).Error(err, "Invalid scan")
+			markInvalid(receiver, radiov1beta1.InvalidScanReason, err)
+			return false
+		}
+	}
+
+	clearInvalid(receiver, radiov1beta1.InvalidScanReason)
+
+	return true
+}
+
+// reconcileScan updates the scan status from the running pod of the
+// receiver and returns when to check it again, or zero if there is nothing
+// to follow. Failing to reach the scanner is not an error, the previous
+// status is kept until the next attempt.
+func (r *RtlSdrReceiverReconciler) reconcileScan(ctx context.Context, receiver *radiov1beta1.RtlSdrReceiver, pod *corev1.Pod) time.Duration {
+	if receiver.Spec.Scan == nil {
+		receiver.Status.Scan = nil
+		return 0
+	}
+
+	if receiver.Status.State != radiov1beta1.StateRunning {
+		return 0
+	}
+
+	if pod == nil {
+		return scanPollInterval
+	}
+
+	fetch := r.ScanStatus
+	if fetch == nil {
+		fetch = fetchScanStatus
+	}
+
+	status, err := fetch(ctx, pod)
+	if err != nil {
+		log.FromContext(ctx).Info("Failed fetching scan status", "pod", pod.Name, "error", err)
+		return scanPollInterval
+	}
+
+	receiver.Status.Scan = status
+
+	return scanPollInterval
+}
+
+// fetchScanStatus asks the proxy running in the pod for the status of its
+// scanner.
+func fetchScanStatus(ctx context.Context, pod *corev1.Pod) (*radiov1beta1.RtlSdrScanStatus, error) {
+	status := &scanner.Status{}
+	if err := fetchProxyStatus(ctx, pod, "/scan", status); err != nil {
+		return nil, err
+	}
+
+	return scanStatus(status), nil
+}
+
+// scanStatus converts the status of a scanner to the receiver status.
+func scanStatus(status *scanner.Status) *radiov1beta1.RtlSdrScanStatus {
+	out := &radiov1beta1.RtlSdrScanStatus{}
+	if status.Frequency != 0 {
+		out.Frequency = resource.NewQuantity(int64(status.Frequency), resource.DecimalSI)
+	}
+
+	for _, hit := range status.Hits {
+		out.Hits = append(out.Hits, radiov1beta1.RtlSdrScanHit{
+			Frequency: *resource.NewQuantity(int64(hit.Frequency), resource.DecimalSI),
+			Power:     int32(math.Round(hit.Power)),
+			FirstSeen: metav1.NewTime(hit.FirstSeen),
+			LastSeen:  metav1.NewTime(hit.LastSeen),
+		})
+	}
+
+	return out
+}
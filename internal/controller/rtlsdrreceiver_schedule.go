@@ -0,0 +1,168 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	radiov1beta1 "github.com/frelon/k8s-radio/api/v1beta1"
+)
+
+// DefaultStartingDeadline is how late a window may start before it counts as
+// missed when the schedule does not say.
+const DefaultStartingDeadline = time.Minute
+
+// parseSchedule parses the cron expression of the schedule and loads its
+// time zone.
+func parseSchedule(schedule *radiov1beta1.RtlSdrSchedule) (cron.Schedule, *time.Location, error) {
+	location := time.UTC
+	if schedule.TimeZone != nil {
+		var err error
+		if location, err = time.LoadLocation(*schedule.TimeZone); err != nil {
+			return nil, nil, fmt.Errorf("invalid time zone %q: %w", *schedule.TimeZone, err)
+		}
+	}
+
+	sched, err := cron.ParseStandard(schedule.Cron)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid cron expression %q: %w", schedule.Cron, err)
+	}
+
+	return sched, location, nil
+}
+
+// latestStart returns the latest start of the schedule after after and no
+// later than now, or the zero time if there is none. A schedule only tells
+// the next start, so the latest one is found by bisecting the range rather
+// than walking every start in it.
+func latestStart(sched cron.Schedule, after, now time.Time) time.Time {
+	latest := sched.Next(after)
+	if latest.IsZero() || latest.After(now) {
+		return time.Time{}
+	}
+
+	// latest is a start no later than now, and none is after end and no
+	// later than now. Starts are whole seconds apart.
+	end := now
+	for end.Sub(latest) > time.Second {
+		mid := latest.Add(end.Sub(latest) / 2)
+		if next := sched.Next(mid); next.After(now) {
+			end = mid
+		} else {
+			latest = next
+		}
+	}
+	if next := sched.Next(latest); !next.IsZero() && !next.After(now) {
+		latest = next
+	}
+
+	return latest
+}
+
+// scheduleWindow updates the schedule status for now and reports whether the
+// receiver should be running. A window that started within the starting
+// deadline is run until its end. A later one is missed, and run for what
+// remains of it only if the policy says so. Overlapping windows are merged.
+func scheduleWindow(schedule *radiov1beta1.RtlSdrSchedule, sched cron.Schedule, status *radiov1beta1.RtlSdrScheduleStatus, now time.Time) bool {
+	duration := schedule.Duration.Duration
+	deadline := DefaultStartingDeadline
+	if schedule.StartingDeadlineSeconds != nil {
+		deadline = time.Duration(*schedule.StartingDeadlineSeconds) * time.Second
+	}
+
+	start := latestStart(sched, now.Add(-duration), now)
+	if !start.IsZero() && (status.LastRunTime == nil || start.After(status.LastRunTime.Time)) {
+		missed := now.Sub(start) > deadline
+		if missed && schedule.MissedWindowPolicy == radiov1beta1.MissedWindowSkip {
+			status.LastMissedTime = &metav1.Time{Time: start}
+		} else {
+			status.LastRunTime = &metav1.Time{Time: start}
+			if end := start.Add(duration); status.ActiveUntil == nil || end.After(status.ActiveUntil.Time) {
+				status.ActiveUntil = &metav1.Time{Time: end}
+			}
+		}
+	}
+
+	status.NextRunTime = nil
+	if next := sched.Next(now); !next.IsZero() {
+		status.NextRunTime = &metav1.Time{Time: next}
+	}
+
+	return status.ActiveUntil != nil && now.Before(status.ActiveUntil.Time)
+}
+
+// reconcileSchedule updates the schedule status of the receiver and reports
+// whether it should be running, and when to reconcile it next for the
+// schedule. Receivers without a schedule always run. An invalid schedule
+// fails the receiver.
+func (r *RtlSdrReceiverReconciler) reconcileSchedule(ctx context.Context, receiver *radiov1beta1.RtlSdrReceiver) (bool, time.Duration) {
+	schedule := receiver.Spec.Schedule
+	if schedule == nil {
+		clearInvalid(receiver, radiov1beta1.InvalidScheduleReason)
+		receiver.Status.Schedule = nil
+		return true, 0
+	}
+
+	sched, location, err := parseSchedule(schedule)
+	if err != nil {
+		log.FromContext(ctx).Error(err, "Invalid schedule")
+		markInvalid(receiver, radiov1beta1.InvalidScheduleReason, err)
+		return false, 0
+	}
+
+	clearInvalid(receiver, radiov1beta1.InvalidScheduleReason)
+
+	if receiver.Status.Schedule == nil {
+		receiver.Status.Schedule = &radiov1beta1.RtlSdrScheduleStatus{}
+	}
+	status := receiver.Status.Schedule
+
+	now := r.now().In(location)
+	active := scheduleWindow(schedule, sched, status, now)
+	if !active {
+		receiver.Status.State = radiov1beta1.StateScheduled
+	}
+
+	// Wake up when the window ends or the next one starts.
+	var wake time.Time
+	if status.NextRunTime != nil {
+		wake = status.NextRunTime.Time
+	}
+	if active && (wake.IsZero() || status.ActiveUntil.Time.Before(wake)) {
+		wake = status.ActiveUntil.Time
+	}
+	if wake.IsZero() {
+		return active, 0
+	}
+
+	return active, wake.Sub(now)
+}
+
+// now returns the current time of the reconciler clock.
+func (r *RtlSdrReceiverReconciler) now() time.Time {
+	if r.Clock != nil {
+		return r.Clock.Now()
+	}
+
+	return time.Now()
+}
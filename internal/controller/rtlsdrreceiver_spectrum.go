@@ -0,0 +1,88 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	corev1ac "k8s.io/client-go/applyconfigurations/core/v1"
+	"k8s.io/utils/ptr"
+
+	radiov1beta1 "github.com/frelon/k8s-radio/api/v1beta1"
+)
+
+const (
+	// SpectrumPort is the port the proxy serves the live spectrum on.
+	SpectrumPort = 8090
+
+	// DefaultSpectrumFFTSize, DefaultSpectrumAveraging and
+	// DefaultSpectrumFrameRate are used when the API server has not
+	// defaulted the spectrum settings.
+	DefaultSpectrumFFTSize   = 1024
+	DefaultSpectrumAveraging = 4
+	DefaultSpectrumFrameRate = 10
+)
+
+// spectrumEnabled reports whether the proxy serves the live spectrum, which
+// it never does for receivers with access, as the spectrum is served in
+// plaintext to anyone. Receivers stored before the API rejected both may
+// still ask for it.
+func spectrumEnabled(receiver *radiov1beta1.RtlSdrReceiver) bool {
+	return receiver.Spec.Spectrum != nil && !accessEnabled(receiver)
+}
+
+// spectrumArgs returns the proxy arguments serving the live spectrum.
+func spectrumArgs(receiver *radiov1beta1.RtlSdrReceiver) []string {
+	spectrum := receiver.Spec.Spectrum
+
+	return []string{
+		"--spectrum-listen", fmt.Sprintf(":%d", SpectrumPort),
+		"--spectrum-fft-size", strconv.Itoa(int(ptr.Deref(spectrum.FFTSize, DefaultSpectrumFFTSize))),
+		"--spectrum-averaging", strconv.Itoa(int(ptr.Deref(spectrum.Averaging, DefaultSpectrumAveraging))),
+		"--spectrum-frame-rate", strconv.Itoa(int(ptr.Deref(spectrum.FrameRate, DefaultSpectrumFrameRate))),
+	}
+}
+
+// withSpectrum exposes the spectrum port of the proxy container.
+func withSpectrum(container *corev1ac.ContainerApplyConfiguration) *corev1ac.ContainerApplyConfiguration {
+	return container.WithPorts(corev1ac.ContainerPort().
+		WithName("spectrum").
+		WithContainerPort(SpectrumPort).
+		WithProtocol(corev1.ProtocolTCP))
+}
+
+// spectrumServicePort returns the Service port of the live spectrum.
+func spectrumServicePort() *corev1ac.ServicePortApplyConfiguration {
+	return corev1ac.ServicePort().
+		WithName("spectrum").
+		WithProtocol(corev1.ProtocolTCP).
+		WithPort(SpectrumPort).
+		WithTargetPort(intstr.FromString("spectrum"))
+}
+
+// spectrumURL returns the in-cluster URL of the live spectrum, or an empty
+// string if it is disabled.
+func spectrumURL(receiver *radiov1beta1.RtlSdrReceiver) string {
+	if !spectrumEnabled(receiver) {
+		return ""
+	}
+
+	return fmt.Sprintf("http://%s.%s.svc:%d/", receiver.Name, receiver.Namespace, SpectrumPort)
+}
@@ -0,0 +1,241 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"math"
+	"net/http"
+	"path"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	corev1ac "k8s.io/client-go/applyconfigurations/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	radiov1beta1 "github.com/frelon/k8s-radio/api/v1beta1"
+	"github.com/frelon/k8s-radio/pkg/rtlmux"
+	"github.com/frelon/k8s-radio/pkg/rtltcp"
+)
+
+// DefaultLiveTuningInterval is used when the interval of live tuning is not
+// set.
+const DefaultLiveTuningInterval = 30 * time.Second
+
+const (
+	// ControlTokenKey is the key of the token the controller calls the
+	// control API of the proxy with in the control Secret.
+	ControlTokenKey = "token"
+
+	controlVolume    = "control-token"
+	controlMountPath = "/control"
+)
+
+// liveTuningEnabled reports whether the receiver is retuned in place instead
+// of restarting its pod.
+func liveTuningEnabled(receiver *radiov1beta1.RtlSdrReceiver) bool {
+	return receiver.Spec.LiveTuning != nil
+}
+
+// liveTuningInterval returns the time between checks of the tuning of the
+// running receiver.
+func liveTuningInterval(receiver *radiov1beta1.RtlSdrReceiver) time.Duration {
+	if interval := receiver.Spec.LiveTuning.Interval; interval != nil {
+		return interval.Duration
+	}
+
+	return DefaultLiveTuningInterval
+}
+
+// controlSecretName returns the name of the Secret holding the token the
+// controller calls the control API of the proxy with.
+func controlSecretName(receiver *radiov1beta1.RtlSdrReceiver) string {
+	return receiver.Name + "-control"
+}
+
+// controlArgs returns the proxy arguments serving the control API to the
+// controller only.
+func controlArgs() []string {
+	return []string{"--control", "--control-token-file", path.Join(controlMountPath, ControlTokenKey)}
+}
+
+// withControl mounts the control token into the proxy container.
+func withControl(container *corev1ac.ContainerApplyConfiguration) *corev1ac.ContainerApplyConfiguration {
+	return container.WithVolumeMounts(corev1ac.VolumeMount().
+		WithName(controlVolume).
+		WithMountPath(controlMountPath).
+		WithReadOnly(true))
+}
+
+// controlVolumeSource returns the volume of the control token.
+func controlVolumeSource(receiver *radiov1beta1.RtlSdrReceiver) *corev1ac.VolumeApplyConfiguration {
+	return corev1ac.Volume().
+		WithName(controlVolume).
+		WithSecret(corev1ac.SecretVolumeSource().
+			WithSecretName(controlSecretName(receiver)))
+}
+
+// reconcileControlToken creates the Secret holding the token the controller
+// calls the control API with, keeping the token once created, or deletes it
+// when the receiver is not tuned live.
+func (r *RtlSdrReceiverReconciler) reconcileControlToken(ctx context.Context, receiver *radiov1beta1.RtlSdrReceiver) error {
+	name := controlSecretName(receiver)
+	if !liveTuningEnabled(receiver) {
+		return r.deleteOwnedNamed(ctx, receiver, name, &corev1.Secret{})
+	}
+
+	secret := &corev1.Secret{}
+	err := r.Get(ctx, client.ObjectKey{Namespace: receiver.Namespace, Name: name}, secret)
+	switch {
+	case err == nil && len(secret.Data[ControlTokenKey]) > 0:
+		return nil
+	case err != nil && !apierrors.IsNotFound(err):
+		return err
+	}
+
+	ac := corev1ac.Secret(name, receiver.Namespace).
+		WithLabels(receiverLabels(receiver)).
+		WithOwnerReferences(ownerReference(receiver)).
+		WithType(corev1.SecretTypeOpaque).
+		WithData(map[string][]byte{ControlTokenKey: []byte(rand.Text())})
+
+	return r.Apply(ctx, ac, client.FieldOwner(FieldManager), client.ForceOwnership)
+}
+
+// controlToken returns the token the controller calls the control API of the
+// proxy with.
+func (r *RtlSdrReceiverReconciler) controlToken(ctx context.Context, receiver *radiov1beta1.RtlSdrReceiver) (string, error) {
+	name := controlSecretName(receiver)
+	secret := &corev1.Secret{}
+	if err := r.Get(ctx, client.ObjectKey{Namespace: receiver.Namespace, Name: name}, secret); err != nil {
+		return "", err
+	}
+
+	token := secret.Data[ControlTokenKey]
+	if len(token) == 0 {
+		return "", fmt.Errorf("secret %s holds no %s", name, ControlTokenKey)
+	}
+
+	return string(token), nil
+}
+
+// specTuning returns the tuning the spec of the receiver asks for. The
+// frequency is left as it is when unset.
+func specTuning(receiver *radiov1beta1.RtlSdrReceiver) rtltcp.Tuning {
+	t := rtltcp.Tuning{SampleRate: uint32(sampleRate(receiver))}
+	if receiver.Spec.Frequency != nil {
+		t.Frequency = uint32(receiver.Spec.Frequency.Value())
+	}
+	if receiver.Spec.Gain != nil {
+		t.ManualGain = true
+		t.Gain = int32(math.Round(receiver.Spec.Gain.AsApproximateFloat64() * 10))
+	}
+
+	return t
+}
+
+// reconcileTuning tunes the running pod of a receiver tuned live when it was
+// tuned from an older generation of the receiver, or not at all since it
+// started, and reports its tuning in the status. It returns when to check
+// the tuning again, or zero if there is nothing to follow. Failing to reach
+// the proxy is not an error, the receiver is retuned at the next attempt.
+func (r *RtlSdrReceiverReconciler) reconcileTuning(ctx context.Context, receiver *radiov1beta1.RtlSdrReceiver, pod *corev1.Pod) time.Duration {
+	if !liveTuningEnabled(receiver) || receiver.Status.State != radiov1beta1.StateRunning {
+		receiver.Status.ObservedGeneration = 0
+		receiver.Status.ObservedFrequency = nil
+		return 0
+	}
+
+	interval := liveTuningInterval(receiver)
+	if pod == nil {
+		return interval
+	}
+
+	logger := log.FromContext(ctx)
+
+	token, err := r.controlToken(ctx, receiver)
+	if err != nil {
+		logger.Info("No token to call the control API with", "error", err)
+		return interval
+	}
+
+	fetch := r.TuningStatus
+	if fetch == nil {
+		fetch = fetchTuning
+	}
+
+	setting, err := fetch(ctx, pod, token)
+	if err != nil {
+		logger.Info("Failed fetching tuning", "pod", pod.Name, "error", err)
+		return interval
+	}
+
+	if setting.Generation != receiver.Generation {
+		retune := r.Retune
+		if retune == nil {
+			retune = retunePod
+		}
+
+		tuning := specTuning(receiver)
+		setting, err = retune(ctx, pod, token, rtlmux.Setting{Generation: receiver.Generation, Tuning: tuning})
+		if err != nil {
+			logger.Info("Failed retuning", "pod", pod.Name, "error", err)
+			return interval
+		}
+
+		logger.Info("Retuned receiver", "pod", pod.Name, "generation", setting.Generation, "frequency", setting.Tuning.Frequency)
+		if r.Recorder != nil {
+			r.Recorder.Eventf(receiver, pod, corev1.EventTypeNormal, radiov1beta1.RetunedReason, "Retune",
+				"Tuned to %d Hz at %d S/s from generation %d", setting.Tuning.Frequency, setting.Tuning.SampleRate, setting.Generation)
+		}
+	}
+
+	receiver.Status.ObservedGeneration = setting.Generation
+	receiver.Status.ObservedFrequency = nil
+	if setting.Tuning.Frequency != 0 {
+		receiver.Status.ObservedFrequency = resource.NewQuantity(int64(setting.Tuning.Frequency), resource.DecimalSI)
+	}
+
+	return interval
+}
+
+// fetchTuning asks the proxy running in the pod for the tuning of rtl_tcp,
+// authenticated by token.
+func fetchTuning(ctx context.Context, pod *corev1.Pod, token string) (*rtlmux.Setting, error) {
+	setting := &rtlmux.Setting{}
+	if err := podRequest(ctx, pod, http.MethodGet, ProxyStatusPort, "/control", token, nil, setting); err != nil {
+		return nil, err
+	}
+
+	return setting, nil
+}
+
+// retunePod asks the proxy running in the pod to tune rtl_tcp, authenticated
+// by token.
+func retunePod(ctx context.Context, pod *corev1.Pod, token string, setting rtlmux.Setting) (*rtlmux.Setting, error) {
+	result := &rtlmux.Setting{}
+	if err := podRequest(ctx, pod, http.MethodPut, ProxyStatusPort, "/control", token, setting, result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,472 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	radiov1beta1 "github.com/frelon/k8s-radio/api/v1beta1"
+	radiov1beta1ac "github.com/frelon/k8s-radio/api/v1beta1/applyconfiguration/api/v1beta1"
+	rtlsdr "github.com/frelon/k8s-radio/device-plugin/rtl-sdr"
+)
+
+// RtlSdrReceiverPoolReconciler reconciles a RtlSdrReceiverPool object
+type RtlSdrReceiverPoolReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+// +kubebuilder:rbac:groups=radio.frelon.se,resources=rtlsdrreceiverpools,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=radio.frelon.se,resources=rtlsdrreceiverpools/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=radio.frelon.se,resources=rtlsdrreceiverpools/finalizers,verbs=update
+// +kubebuilder:rbac:groups="",resources=nodes,verbs=get;list;watch
+
+// Reconcile admits the receivers referencing the pool to the nodes with free
+// dongles, and queues the others. The receiver controller runs the admitted
+// receivers on their node.
+func (r *RtlSdrReceiverPoolReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx).WithValues("name", req.String())
+	logger.Info("Reconciling RtlSdrReceiverPool")
+
+	pool := &radiov1beta1.RtlSdrReceiverPool{}
+	if err := r.Get(ctx, req.NamespacedName, pool); err != nil {
+		if apierrors.IsNotFound(err) {
+			logger.Info("Object was not found, not an error")
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, fmt.Errorf("failed to get pool object: %w", err)
+	}
+
+	receivers := &radiov1beta1.RtlSdrReceiverList{}
+	if err := r.List(ctx, receivers); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	selector := labels.Everything()
+	if pool.Spec.NodeSelector != nil {
+		var err error
+		if selector, err = metav1.LabelSelectorAsSelector(pool.Spec.NodeSelector); err != nil {
+			return reconcile.Result{}, fmt.Errorf("invalid node selector: %w", err)
+		}
+	}
+	nodes := &corev1.NodeList{}
+	if err := r.List(ctx, nodes, client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	pods := &corev1.PodList{}
+	if err := r.List(ctx, pods); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	// Pools may share nodes, so the admissions of the others hold dongles
+	// too.
+	pools := &radiov1beta1.RtlSdrReceiverPoolList{}
+	if err := r.List(ctx, pools); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	pool.Status = admit(ctx, pool, pools.Items, receivers.Items, nodes.Items, pods.Items)
+
+	logger.Info("Updating status", "capacity", pool.Status.Capacity, "allocated", pool.Status.Allocated, "queued", pool.Status.Queued)
+	if err := r.Status().Apply(ctx, poolStatusApplyConfiguration(pool), client.FieldOwner(FieldManager), client.ForceOwnership); err != nil {
+		logger.Error(err, "Error updating RtlSdrReceiverPool status")
+		return reconcile.Result{}, err
+	}
+
+	return reconcile.Result{}, nil
+}
+
+// admit assigns the receivers of the pool to the nodes with free dongles,
+// in order of priority, then age. When the pool lists serials, each receiver
+// is assigned one of those dongles as well. Admitted receivers keep their
+// dongle as long as it is in the pool, even when their quota has been
+// lowered since. The others are queued, skipping those over their quota so
+// that they do not hold up other namespaces. The admissions of the other
+// pools count as busy dongles of their node, whether their receivers run yet
+// or not.
+func admit(ctx context.Context, pool *radiov1beta1.RtlSdrReceiverPool, pools []radiov1beta1.RtlSdrReceiverPool, receivers []radiov1beta1.RtlSdrReceiver, nodes []corev1.Node, pods []corev1.Pod) radiov1beta1.RtlSdrReceiverPoolStatus {
+	candidates := poolCandidates(pool, receivers)
+
+	inPool := map[string]bool{}
+	for _, node := range nodes {
+		inPool[node.Name] = true
+	}
+
+	previous := map[types.NamespacedName]radiov1beta1.RtlSdrPoolAdmission{}
+	for _, a := range pool.Status.Admitted {
+		previous[types.NamespacedName{Namespace: a.Namespace, Name: a.Name}] = a
+	}
+
+	assigned := map[types.NamespacedName]radiov1beta1.RtlSdrPoolAdmission{}
+	used := map[string]int32{}
+	for _, receiver := range candidates {
+		key := client.ObjectKeyFromObject(&receiver)
+		a, ok := previous[key]
+		if ok && inPool[a.Node] && (a.Serial == "" && len(pool.Spec.Serials) == 0 || slices.Contains(pool.Spec.Serials, a.Serial)) {
+			assigned[key] = a
+			used[receiver.Namespace]++
+		}
+	}
+
+	others := otherAdmissions(pool, pools, candidates)
+
+	status := radiov1beta1.RtlSdrReceiverPoolStatus{}
+	free := map[string]int32{}
+	serials := map[string][]string{}
+	for _, node := range nodes {
+		capacity, busy := nodeDongles(&node, pods, assigned, others)
+		free[node.Name] = capacity - busy
+		if len(pool.Spec.Serials) == 0 {
+			status.Capacity += capacity
+			continue
+		}
+
+		var dongles int32
+		dongles, serials[node.Name] = poolSerials(ctx, pool, &node, pods, assigned, others)
+		status.Capacity += dongles
+	}
+	for _, a := range assigned {
+		free[a.Node]--
+	}
+	if len(pool.Spec.Serials) > 0 {
+		for node, idle := range serials {
+			free[node] = min(free[node], int32(len(idle)))
+		}
+	}
+
+	for _, receiver := range candidates {
+		key := client.ObjectKeyFromObject(&receiver)
+		a, ok := assigned[key]
+		if !ok {
+			a = radiov1beta1.RtlSdrPoolAdmission{Namespace: receiver.Namespace, Name: receiver.Name}
+
+			reason := radiov1beta1.PoolExhaustedReason
+			if quota, limited := poolQuota(pool, receiver.Namespace); limited && used[receiver.Namespace] >= quota {
+				reason = radiov1beta1.QuotaExceededReason
+			} else {
+				a.Node = freestNode(free)
+			}
+
+			if a.Node == "" {
+				status.Queue = append(status.Queue, radiov1beta1.RtlSdrPoolQueueEntry{
+					Namespace: receiver.Namespace,
+					Name:      receiver.Name,
+					Priority:  receiver.Spec.Pool.Priority,
+					Reason:    reason,
+				})
+				continue
+			}
+
+			if idle := serials[a.Node]; len(idle) > 0 {
+				a.Serial, serials[a.Node] = idle[0], idle[1:]
+			}
+
+			assigned[key] = a
+			used[receiver.Namespace]++
+			free[a.Node]--
+		}
+
+		status.Admitted = append(status.Admitted, a)
+	}
+
+	status.Allocated = int32(len(status.Admitted))
+	status.Queued = int32(len(status.Queue))
+
+	return status
+}
+
+// poolCandidates returns the receivers of the pool that want a dongle, in
+// the order they are admitted. Receivers being deleted or sleeping outside
+// their schedule do not.
+func poolCandidates(pool *radiov1beta1.RtlSdrReceiverPool, receivers []radiov1beta1.RtlSdrReceiver) []radiov1beta1.RtlSdrReceiver {
+	var candidates []radiov1beta1.RtlSdrReceiver
+	for _, receiver := range receivers {
+		if receiver.Spec.Pool == nil || receiver.Spec.Pool.Name != pool.Name {
+			continue
+		}
+		if !receiver.DeletionTimestamp.IsZero() || receiver.Status.State == radiov1beta1.StateScheduled {
+			continue
+		}
+		candidates = append(candidates, receiver)
+	}
+
+	slices.SortFunc(candidates, func(a, b radiov1beta1.RtlSdrReceiver) int {
+		return cmp.Or(
+			cmp.Compare(b.Spec.Pool.Priority, a.Spec.Pool.Priority),
+			a.CreationTimestamp.Compare(b.CreationTimestamp.Time),
+			cmp.Compare(a.Namespace, b.Namespace),
+			cmp.Compare(a.Name, b.Name),
+		)
+	})
+
+	return candidates
+}
+
+// poolQuota returns the number of receivers of the namespace the pool admits
+// at once, and whether there is a limit at all.
+func poolQuota(pool *radiov1beta1.RtlSdrReceiverPool, namespace string) (int32, bool) {
+	for _, quota := range pool.Spec.Quotas {
+		if quota.Namespace == namespace {
+			return quota.Receivers, true
+		}
+	}
+
+	if pool.Spec.DefaultQuota != nil {
+		return *pool.Spec.DefaultQuota, true
+	}
+
+	return 0, false
+}
+
+// freestNode returns the node with the most free dongles, the first by name
+// among equals, or an empty string if all are in use.
+func freestNode(free map[string]int32) string {
+	var best string
+	for node, n := range free {
+		if n > 0 && (best == "" || n > free[best] || n == free[best] && node < best) {
+			best = node
+		}
+	}
+
+	return best
+}
+
+// otherAdmissions returns the admissions of the other pools by receiver. A
+// receiver that moved to the pool is left out, even when the pool it left
+// has not freed its dongle yet.
+func otherAdmissions(pool *radiov1beta1.RtlSdrReceiverPool, pools []radiov1beta1.RtlSdrReceiverPool, candidates []radiov1beta1.RtlSdrReceiver) map[types.NamespacedName]radiov1beta1.RtlSdrPoolAdmission {
+	admitted := map[types.NamespacedName]radiov1beta1.RtlSdrPoolAdmission{}
+	for _, other := range pools {
+		if other.Name == pool.Name {
+			continue
+		}
+		for _, a := range other.Status.Admitted {
+			admitted[types.NamespacedName{Namespace: a.Namespace, Name: a.Name}] = a
+		}
+	}
+
+	for _, receiver := range candidates {
+		delete(admitted, client.ObjectKeyFromObject(&receiver))
+	}
+
+	return admitted
+}
+
+// nodeDongles returns the number of dongles plugged into the node and how
+// many of them are busy. Dongles admitted to by other pools are, and so are
+// those used by pods other than those of the receivers admitted by any pool.
+// A pod uses one when it is bound to the node and not done.
+func nodeDongles(node *corev1.Node, pods []corev1.Pod, assigned, others map[types.NamespacedName]radiov1beta1.RtlSdrPoolAdmission) (int32, int32) {
+	var busy int32
+	for _, a := range others {
+		if a.Node == node.Name {
+			busy++
+		}
+	}
+
+	for i := range pods {
+		pod := &pods[i]
+		if !runsOn(pod, node) || !usesDongle(pod) {
+			continue
+		}
+		receiver := podReceiver(pod)
+		if _, ok := assigned[receiver]; ok {
+			continue
+		}
+		if _, ok := others[receiver]; !ok {
+			busy++
+		}
+	}
+
+	allocatable := node.Status.Allocatable[RtlSdrResourceName]
+	return int32(allocatable.Value()), busy
+}
+
+// poolSerials returns the number of dongles of the pool plugged into the
+// node and the serials of those free, as published by the device plugin. A
+// dongle is busy while allocated to a running pod or assigned a receiver by
+// any pool. Dongles with serials the device plugin cannot serve as their own
+// resource are left out of the pool.
+func poolSerials(ctx context.Context, pool *radiov1beta1.RtlSdrReceiverPool, node *corev1.Node, pods []corev1.Pod, assigned, others map[types.NamespacedName]radiov1beta1.RtlSdrPoolAdmission) (int32, []string) {
+	devices, err := rtlsdr.ParseDevices(node.Annotations)
+	if err != nil {
+		log.FromContext(ctx).Info("Ignoring the dongles of node", "node", node.Name, "error", err)
+		return 0, nil
+	}
+
+	taken := map[string]bool{}
+	for _, admissions := range []map[types.NamespacedName]radiov1beta1.RtlSdrPoolAdmission{assigned, others} {
+		for _, a := range admissions {
+			if a.Node == node.Name && a.Serial != "" {
+				taken[a.Serial] = true
+			}
+		}
+	}
+
+	running := map[types.NamespacedName]bool{}
+	for i := range pods {
+		if runsOn(&pods[i], node) {
+			running[client.ObjectKeyFromObject(&pods[i])] = true
+		}
+	}
+
+	var dongles int32
+	var idle []string
+	for _, device := range devices {
+		if !slices.Contains(pool.Spec.Serials, device.Serial) {
+			continue
+		}
+		if _, ok := rtlsdr.SerialResourceName(device.Serial); !ok {
+			continue
+		}
+		dongles++
+
+		namespace, name, _ := strings.Cut(device.Pod, "/")
+		if !taken[device.Serial] && !running[types.NamespacedName{Namespace: namespace, Name: name}] {
+			idle = append(idle, device.Serial)
+		}
+	}
+
+	return dongles, idle
+}
+
+// runsOn reports whether the pod is bound to the node and not done.
+func runsOn(pod *corev1.Pod, node *corev1.Node) bool {
+	return pod.Spec.NodeName == node.Name && pod.Status.Phase != corev1.PodSucceeded && pod.Status.Phase != corev1.PodFailed
+}
+
+// usesDongle reports whether a container of the pod is allocated a dongle,
+// any or one of a serial.
+func usesDongle(pod *corev1.Pod) bool {
+	for _, container := range pod.Spec.Containers {
+		for name := range container.Resources.Limits {
+			if name == RtlSdrResourceName || strings.HasPrefix(string(name), RtlSdrResourceName+"-") {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// podReceiver returns the receiver running the pod, or an empty name if the
+// pod does not belong to a receiver. Pods of receivers using the Deployment
+// workload are owned by a ReplicaSet, so the labels tell the receiver.
+func podReceiver(pod *corev1.Pod) types.NamespacedName {
+	labels := pod.Labels
+	if labels["app.kubernetes.io/name"] != "rtlsdrreceiver" || labels["app.kubernetes.io/managed-by"] != "k8s-radio" {
+		return types.NamespacedName{}
+	}
+
+	return types.NamespacedName{Namespace: pod.Namespace, Name: labels["app.kubernetes.io/instance"]}
+}
+
+// poolStatusApplyConfiguration converts the status of the pool into an apply
+// configuration.
+func poolStatusApplyConfiguration(pool *radiov1beta1.RtlSdrReceiverPool) *radiov1beta1ac.RtlSdrReceiverPoolApplyConfiguration {
+	status := radiov1beta1ac.RtlSdrReceiverPoolStatus().
+		WithCapacity(pool.Status.Capacity).
+		WithAllocated(pool.Status.Allocated).
+		WithQueued(pool.Status.Queued)
+
+	for _, a := range pool.Status.Admitted {
+		ac := radiov1beta1ac.RtlSdrPoolAdmission().
+			WithNamespace(a.Namespace).
+			WithName(a.Name).
+			WithNode(a.Node)
+		if a.Serial != "" {
+			ac.WithSerial(a.Serial)
+		}
+		status.WithAdmitted(ac)
+	}
+	for _, q := range pool.Status.Queue {
+		status.WithQueue(radiov1beta1ac.RtlSdrPoolQueueEntry().
+			WithNamespace(q.Namespace).
+			WithName(q.Name).
+			WithPriority(q.Priority).
+			WithReason(q.Reason))
+	}
+
+	return radiov1beta1ac.RtlSdrReceiverPool(pool.Name, "").
+		WithStatus(status)
+}
+
+// receiverPool maps a receiver to the pool it references. On updates the
+// handler maps both the old and the new receiver, so that a pool left by the
+// receiver frees its dongle as well.
+func receiverPool(_ context.Context, obj client.Object) []reconcile.Request {
+	receiver, ok := obj.(*radiov1beta1.RtlSdrReceiver)
+	if !ok || receiver.Spec.Pool == nil {
+		return nil
+	}
+
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: receiver.Spec.Pool.Name}}}
+}
+
+// receiverPoolHandler enqueues the pools a receiver references.
+var receiverPoolHandler = handler.EnqueueRequestsFromMapFunc(receiverPool)
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *RtlSdrReceiverPoolReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	// Dongles are freed and plugged in outside of the pool, so reconcile
+	// all pools when a pod using one or a node changes.
+	allPools := func(ctx context.Context, obj client.Object) []reconcile.Request {
+		if pod, ok := obj.(*corev1.Pod); ok && !usesDongle(pod) {
+			return nil
+		}
+
+		pools := &radiov1beta1.RtlSdrReceiverPoolList{}
+		if err := mgr.GetClient().List(ctx, pools); err != nil {
+			log.FromContext(ctx).Error(err, "Failed listing pools")
+			return nil
+		}
+
+		requests := make([]reconcile.Request, 0, len(pools.Items))
+		for _, pool := range pools.Items {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: pool.Name}})
+		}
+
+		return requests
+	}
+
+	// The admissions of a pool hold dongles of the nodes it shares with
+	// others, so reconcile all pools when one changes too.
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&radiov1beta1.RtlSdrReceiverPool{}).
+		Watches(&radiov1beta1.RtlSdrReceiverPool{}, handler.EnqueueRequestsFromMapFunc(allPools)).
+		Watches(&radiov1beta1.RtlSdrReceiver{}, receiverPoolHandler).
+		Watches(&corev1.Pod{}, handler.EnqueueRequestsFromMapFunc(allPools)).
+		Watches(&corev1.Node{}, handler.EnqueueRequestsFromMapFunc(allPools)).
+		Complete(r)
+}
@@ -0,0 +1,326 @@
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	radiov1 "github.com/frelon/k8s-radio/api/v1beta1"
+	rtlsdr "github.com/frelon/k8s-radio/device-plugin/rtl-sdr"
+)
+
+var _ = Describe("RtlSdrReceiverPool controller", func() {
+	const namespace = "default"
+
+	poolReconciler := func() *RtlSdrReceiverPoolReconciler {
+		return &RtlSdrReceiverPoolReconciler{
+			Client: k8sClient,
+			Scheme: scheme.Scheme,
+		}
+	}
+	receiverReconciler := func() *RtlSdrReceiverReconciler {
+		return &RtlSdrReceiverReconciler{
+			Client: k8sClient,
+			Scheme: scheme.Scheme,
+			Image:  "test-image",
+		}
+	}
+
+	createNode := func(ctx SpecContext, name, site string, dongles int64, annotations map[string]string) {
+		node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Labels:      map[string]string{"radio.frelon.se/site": site},
+			Annotations: annotations,
+		}}
+		Expect(k8sClient.Create(ctx, node)).To(Succeed())
+		node.Status.Capacity = corev1.ResourceList{RtlSdrResourceName: *resource.NewQuantity(dongles, resource.DecimalSI)}
+		node.Status.Allocatable = node.Status.Capacity
+		Expect(k8sClient.Status().Update(ctx, node)).To(Succeed())
+	}
+
+	createPool := func(ctx SpecContext, name, site string, spec radiov1.RtlSdrReceiverPoolSpec) {
+		spec.NodeSelector = &metav1.LabelSelector{MatchLabels: map[string]string{"radio.frelon.se/site": site}}
+		Expect(k8sClient.Create(ctx, &radiov1.RtlSdrReceiverPool{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       spec,
+		})).To(Succeed())
+	}
+
+	createReceiver := func(ctx SpecContext, ns, name, pool string, priority int32) types.NamespacedName {
+		Expect(k8sClient.Create(ctx, &radiov1.RtlSdrReceiver{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: ns},
+			Spec: radiov1.RtlSdrReceiverSpec{
+				Version: radiov1.V4,
+				Pool:    &radiov1.RtlSdrPoolReference{Name: pool, Priority: priority},
+			},
+		})).To(Succeed())
+
+		return types.NamespacedName{Name: name, Namespace: ns}
+	}
+
+	reconcilePool := func(ctx SpecContext, name string) *radiov1.RtlSdrReceiverPool {
+		_, err := poolReconciler().Reconcile(ctx, reconcile.Request{NamespacedName: types.NamespacedName{Name: name}})
+		Expect(err).To(Succeed())
+
+		pool := &radiov1.RtlSdrReceiverPool{}
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Name: name}, pool)).To(Succeed())
+		return pool
+	}
+
+	reconcileReceiver := func(ctx SpecContext, key types.NamespacedName) *radiov1.RtlSdrReceiver {
+		_, err := receiverReconciler().Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).To(Succeed())
+
+		receiver := &radiov1.RtlSdrReceiver{}
+		Expect(k8sClient.Get(ctx, key, receiver)).To(Succeed())
+		return receiver
+	}
+
+	It("Should admit receivers by priority as dongles free up", func(ctx SpecContext) {
+		By("By creating a pool of the nodes of a site, one of them outside")
+		createNode(ctx, "pool-roof-a", "roof", 2, nil)
+		createNode(ctx, "pool-basement-a", "basement", 1, nil)
+		createPool(ctx, "test-roof", "roof", radiov1.RtlSdrReceiverPoolSpec{})
+
+		By("By using a dongle of the pool outside of it")
+		foreign := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "pool-foreign", Namespace: namespace},
+			Spec: corev1.PodSpec{
+				NodeName: "pool-roof-a",
+				Containers: []corev1.Container{{
+					Name:  "rtl-tcp",
+					Image: "test-image",
+					Resources: corev1.ResourceRequirements{Limits: corev1.ResourceList{
+						RtlSdrResourceName: *resource.NewQuantity(1, resource.DecimalSI),
+					}},
+				}},
+			},
+		}
+		Expect(k8sClient.Create(ctx, foreign)).To(Succeed())
+
+		low := createReceiver(ctx, namespace, "pool-low-a", "test-roof", 0)
+		other := createReceiver(ctx, namespace, "pool-low-b", "test-roof", 0)
+		high := createReceiver(ctx, namespace, "pool-high", "test-roof", 10)
+
+		By("By checking the receiver with the highest priority is admitted")
+		pool := reconcilePool(ctx, "test-roof")
+		Expect(pool.Status.Capacity).To(Equal(int32(2)))
+		Expect(pool.Status.Allocated).To(Equal(int32(1)))
+		Expect(pool.Status.Queued).To(Equal(int32(2)))
+		Expect(pool.Status.Admitted).To(Equal([]radiov1.RtlSdrPoolAdmission{
+			{Namespace: namespace, Name: "pool-high", Node: "pool-roof-a"},
+		}))
+		Expect(pool.Status.Queue).To(Equal([]radiov1.RtlSdrPoolQueueEntry{
+			{Namespace: namespace, Name: "pool-low-a", Reason: radiov1.PoolExhaustedReason},
+			{Namespace: namespace, Name: "pool-low-b", Reason: radiov1.PoolExhaustedReason},
+		}))
+
+		By("By checking the admitted receiver runs on its node")
+		receiver := reconcileReceiver(ctx, high)
+		Expect(receiver.Status.State).To(Equal(radiov1.StateWaiting))
+		Expect(receiver.Status.Pool).To(Equal(&radiov1.RtlSdrPoolStatus{Node: "pool-roof-a"}))
+
+		pod := &corev1.Pod{}
+		Expect(k8sClient.Get(ctx, high, pod)).To(Succeed())
+		terms := pod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+		Expect(terms).To(HaveLen(1))
+		Expect(terms[0].MatchFields).To(Equal([]corev1.NodeSelectorRequirement{{
+			Key:      "metadata.name",
+			Operator: corev1.NodeSelectorOpIn,
+			Values:   []string{"pool-roof-a"},
+		}}))
+
+		By("By checking the others are queued in order")
+		receiver = reconcileReceiver(ctx, other)
+		Expect(receiver.Status.State).To(Equal(radiov1.StateQueued))
+		Expect(receiver.Status.Pool).To(Equal(&radiov1.RtlSdrPoolStatus{Position: 2}))
+		ready := meta.FindStatusCondition(receiver.Status.Conditions, radiov1.ReadyCondition)
+		Expect(ready).NotTo(BeNil())
+		Expect(ready.Reason).To(Equal(radiov1.QueuedReason))
+		Expect(ready.Message).To(ContainSubstring("position 2"))
+		Expect(receiver.Status.Pod).To(BeNil())
+		Expect(k8sClient.Get(ctx, other, &corev1.Pod{})).NotTo(Succeed())
+
+		By("By freeing the dongle used outside the pool")
+		foreign.Status.Phase = corev1.PodSucceeded
+		Expect(k8sClient.Status().Update(ctx, foreign)).To(Succeed())
+
+		pool = reconcilePool(ctx, "test-roof")
+		Expect(pool.Status.Admitted).To(Equal([]radiov1.RtlSdrPoolAdmission{
+			{Namespace: namespace, Name: "pool-high", Node: "pool-roof-a"},
+			{Namespace: namespace, Name: "pool-low-a", Node: "pool-roof-a"},
+		}))
+		Expect(pool.Status.Queue).To(HaveLen(1))
+
+		receiver = reconcileReceiver(ctx, low)
+		Expect(receiver.Status.State).To(Equal(radiov1.StateWaiting))
+		Expect(meta.FindStatusCondition(receiver.Status.Conditions, radiov1.ReadyCondition)).To(BeNil())
+		Expect(k8sClient.Get(ctx, low, &corev1.Pod{})).To(Succeed())
+
+		receiver = reconcileReceiver(ctx, other)
+		Expect(receiver.Status.Pool).To(Equal(&radiov1.RtlSdrPoolStatus{Position: 1}))
+
+		By("By checking a new receiver of a higher priority does not preempt")
+		createReceiver(ctx, namespace, "pool-urgent", "test-roof", 100)
+		pool = reconcilePool(ctx, "test-roof")
+		Expect(pool.Status.Allocated).To(Equal(int32(2)))
+		Expect(pool.Status.Queue).To(Equal([]radiov1.RtlSdrPoolQueueEntry{
+			{Namespace: namespace, Name: "pool-urgent", Priority: 100, Reason: radiov1.PoolExhaustedReason},
+			{Namespace: namespace, Name: "pool-low-b", Reason: radiov1.PoolExhaustedReason},
+		}))
+	})
+
+	It("Should keep namespaces within their quota", func(ctx SpecContext) {
+		Expect(k8sClient.Create(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "pool-team-b"}})).To(Succeed())
+		createNode(ctx, "pool-mast-a", "mast", 3, nil)
+		createPool(ctx, "test-mast", "mast", radiov1.RtlSdrReceiverPoolSpec{
+			Quotas: []radiov1.RtlSdrPoolQuota{{Namespace: namespace, Receivers: 1}},
+		})
+
+		createReceiver(ctx, namespace, "pool-quota-a", "test-mast", 0)
+		blocked := createReceiver(ctx, namespace, "pool-quota-b", "test-mast", 0)
+		createReceiver(ctx, "pool-team-b", "pool-quota-c", "test-mast", 0)
+
+		pool := reconcilePool(ctx, "test-mast")
+		Expect(pool.Status.Admitted).To(ConsistOf(
+			radiov1.RtlSdrPoolAdmission{Namespace: namespace, Name: "pool-quota-a", Node: "pool-mast-a"},
+			radiov1.RtlSdrPoolAdmission{Namespace: "pool-team-b", Name: "pool-quota-c", Node: "pool-mast-a"},
+		))
+		Expect(pool.Status.Queue).To(Equal([]radiov1.RtlSdrPoolQueueEntry{
+			{Namespace: namespace, Name: "pool-quota-b", Reason: radiov1.QuotaExceededReason},
+		}))
+
+		receiver := reconcileReceiver(ctx, blocked)
+		Expect(receiver.Status.State).To(Equal(radiov1.StateQueued))
+		Expect(meta.FindStatusCondition(receiver.Status.Conditions, radiov1.ReadyCondition).Message).To(ContainSubstring("quota"))
+	})
+
+	It("Should allocate receivers exactly the dongles with the listed serials", func(ctx SpecContext) {
+		createNode(ctx, "pool-shed-a", "shed", 3, map[string]string{
+			rtlsdr.DevicesAnnotation: `[{"serial":"00000001","product":"0bda:2838","pod":"default/pool-serial-foreign"},` +
+				`{"serial":"00000002","product":"0bda:2838"},{"serial":"00000003","product":"0bda:2838"}]`,
+		})
+		createPool(ctx, "test-shed", "shed", radiov1.RtlSdrReceiverPoolSpec{Serials: []string{"00000001", "00000002"}})
+
+		By("By using a dongle of the pool outside of it")
+		Expect(k8sClient.Create(ctx, &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "pool-serial-foreign", Namespace: namespace},
+			Spec: corev1.PodSpec{
+				NodeName: "pool-shed-a",
+				Containers: []corev1.Container{{
+					Name:  "rtl-tcp",
+					Image: "test-image",
+					Resources: corev1.ResourceRequirements{Limits: corev1.ResourceList{
+						RtlSdrResourceName: *resource.NewQuantity(1, resource.DecimalSI),
+					}},
+				}},
+			},
+		})).To(Succeed())
+
+		key := createReceiver(ctx, namespace, "pool-serial-a", "test-shed", 0)
+		createReceiver(ctx, namespace, "pool-serial-b", "test-shed", 0)
+
+		By("By checking only the free dongle of the pool is assigned")
+		pool := reconcilePool(ctx, "test-shed")
+		Expect(pool.Status.Capacity).To(Equal(int32(2)))
+		Expect(pool.Status.Admitted).To(Equal([]radiov1.RtlSdrPoolAdmission{
+			{Namespace: namespace, Name: "pool-serial-a", Node: "pool-shed-a", Serial: "00000002"},
+		}))
+		Expect(pool.Status.Queue).To(HaveLen(1))
+
+		By("By checking the pod is allocated the dongle of the serial")
+		receiver := reconcileReceiver(ctx, key)
+		Expect(receiver.Status.Pool).To(Equal(&radiov1.RtlSdrPoolStatus{Node: "pool-shed-a", Serial: "00000002"}))
+
+		pod := &corev1.Pod{}
+		Expect(k8sClient.Get(ctx, key, pod)).To(Succeed())
+		Expect(pod.Spec.Containers[0].Resources.Limits).To(HaveKey(corev1.ResourceName("frelon.se/rtl-sdr-00000002")))
+		Expect(pod.Spec.Containers[0].Resources.Limits).NotTo(HaveKey(RtlSdrResourceName))
+	})
+
+	It("Should free the dongle of the old pool when a receiver moves", func(ctx SpecContext) {
+		createNode(ctx, "pool-move-a", "move", 1, nil)
+		createPool(ctx, "test-move-old", "move", radiov1.RtlSdrReceiverPoolSpec{})
+		createPool(ctx, "test-move-new", "move", radiov1.RtlSdrReceiverPoolSpec{})
+		key := createReceiver(ctx, namespace, "pool-move", "test-move-old", 0)
+		Expect(reconcilePool(ctx, "test-move-old").Status.Admitted).To(HaveLen(1))
+
+		By("By moving the receiver to the new pool")
+		old := &radiov1.RtlSdrReceiver{}
+		Expect(k8sClient.Get(ctx, key, old)).To(Succeed())
+		moved := old.DeepCopy()
+		moved.Spec.Pool.Name = "test-move-new"
+		Expect(k8sClient.Update(ctx, moved)).To(Succeed())
+
+		queue := workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[reconcile.Request]())
+		DeferCleanup(queue.ShutDown)
+		receiverPoolHandler.Update(ctx, event.UpdateEvent{ObjectOld: old, ObjectNew: moved}, queue)
+
+		By("By reconciling both pools")
+		var pools []string
+		for queue.Len() > 0 {
+			req, _ := queue.Get()
+			pools = append(pools, req.Name)
+			queue.Done(req)
+		}
+		Expect(pools).To(ConsistOf("test-move-old", "test-move-new"))
+
+		Expect(reconcilePool(ctx, "test-move-old").Status.Admitted).To(BeEmpty())
+		Expect(reconcilePool(ctx, "test-move-new").Status.Admitted).To(Equal([]radiov1.RtlSdrPoolAdmission{
+			{Namespace: namespace, Name: "pool-move", Node: "pool-move-a"},
+		}))
+	})
+
+	It("Should not admit to a dongle another pool admitted to", func(ctx SpecContext) {
+		createNode(ctx, "pool-shared-a", "shared", 1, nil)
+		createPool(ctx, "test-shared-a", "shared", radiov1.RtlSdrReceiverPoolSpec{})
+		createPool(ctx, "test-shared-b", "shared", radiov1.RtlSdrReceiverPoolSpec{})
+		createReceiver(ctx, namespace, "pool-shared-a", "test-shared-a", 0)
+		createReceiver(ctx, namespace, "pool-shared-b", "test-shared-b", 0)
+
+		By("By admitting the receiver of the first pool before its pod is bound")
+		Expect(reconcilePool(ctx, "test-shared-a").Status.Admitted).To(Equal([]radiov1.RtlSdrPoolAdmission{
+			{Namespace: namespace, Name: "pool-shared-a", Node: "pool-shared-a"},
+		}))
+
+		By("By checking the second pool queues its receiver")
+		pool := reconcilePool(ctx, "test-shared-b")
+		Expect(pool.Status.Admitted).To(BeEmpty())
+		Expect(pool.Status.Queue).To(Equal([]radiov1.RtlSdrPoolQueueEntry{
+			{Namespace: namespace, Name: "pool-shared-b", Reason: radiov1.PoolExhaustedReason},
+		}))
+
+		By("By checking the first pool keeps its admission")
+		Expect(reconcilePool(ctx, "test-shared-a").Status.Admitted).To(HaveLen(1))
+	})
+
+	It("Should queue receivers of a missing pool", func(ctx SpecContext) {
+		key := createReceiver(ctx, namespace, "pool-missing", "test-missing", 0)
+
+		receiver := reconcileReceiver(ctx, key)
+		Expect(receiver.Status.State).To(Equal(radiov1.StateQueued))
+		Expect(receiver.Status.Pool).To(Equal(&radiov1.RtlSdrPoolStatus{}))
+		Expect(meta.FindStatusCondition(receiver.Status.Conditions, radiov1.ReadyCondition).Message).To(Equal("Pool test-missing not found"))
+	})
+
+	It("Should reject simulated receivers in a pool", func(ctx SpecContext) {
+		err := k8sClient.Create(ctx, &radiov1.RtlSdrReceiver{
+			ObjectMeta: metav1.ObjectMeta{Name: "pool-simulated", Namespace: namespace},
+			Spec: radiov1.RtlSdrReceiverSpec{
+				Version:    radiov1.V4,
+				Simulation: &radiov1.RtlSdrSimulation{},
+				Pool:       &radiov1.RtlSdrPoolReference{Name: "test-roof"},
+			},
+		})
+		Expect(err).To(MatchError(ContainSubstring("simulated receivers cannot use a pool")))
+	})
+})
@@ -0,0 +1,345 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	batchv1ac "k8s.io/client-go/applyconfigurations/batch/v1"
+	corev1ac "k8s.io/client-go/applyconfigurations/core/v1"
+	metav1ac "k8s.io/client-go/applyconfigurations/meta/v1"
+	rbacv1ac "k8s.io/client-go/applyconfigurations/rbac/v1"
+
+	radiov1beta1 "github.com/frelon/k8s-radio/api/v1beta1"
+	radiov1beta1ac "github.com/frelon/k8s-radio/api/v1beta1/applyconfiguration/api/v1beta1"
+)
+
+const (
+	// DefaultIntegration is the time the power of every bin is averaged
+	// over when the survey does not say, like rtl_power.
+	DefaultIntegration = 10 * time.Second
+
+	// surveyContainer is the name of the container summarising the sweep,
+	// whose termination message is the summary.
+	surveyContainer = "survey"
+
+	sweepVolume    = "sweep"
+	sweepMountPath = "/sweep"
+	sweepFile      = "survey.csv"
+
+	surveyOutputVolume    = "output"
+	surveyOutputMountPath = "/output"
+)
+
+// surveyLabels returns the labels identifying the objects of a survey.
+func surveyLabels(survey *radiov1beta1.RtlSdrSurvey) map[string]string {
+	return map[string]string{
+		"app.kubernetes.io/name":       "rtlsdrsurvey",
+		"app.kubernetes.io/instance":   survey.Name,
+		"app.kubernetes.io/managed-by": "k8s-radio",
+	}
+}
+
+// surveyOwnerReference returns the controller reference pointing at the
+// survey.
+func surveyOwnerReference(survey *radiov1beta1.RtlSdrSurvey) *metav1ac.OwnerReferenceApplyConfiguration {
+	return metav1ac.OwnerReference().
+		WithAPIVersion(radiov1beta1.GroupVersion.String()).
+		WithKind("RtlSdrSurvey").
+		WithName(survey.Name).
+		WithUID(survey.UID).
+		WithController(true).
+		WithBlockOwnerDeletion(true)
+}
+
+// surveyServiceAccountName returns the name of the ServiceAccount, Role and
+// RoleBinding letting the survey pod write its results to a ConfigMap.
+func surveyServiceAccountName(survey *radiov1beta1.RtlSdrSurvey) string {
+	return "rtlsdrsurvey-" + survey.Name
+}
+
+// configMapOutput reports whether the results of the survey are written to
+// a ConfigMap.
+func configMapOutput(survey *radiov1beta1.RtlSdrSurvey) bool {
+	return survey.Spec.Output != nil && survey.Spec.Output.ConfigMap != ""
+}
+
+// validateSurvey returns an error if rtl_power cannot sweep the range of the
+// survey.
+func validateSurvey(survey *radiov1beta1.RtlSdrSurvey) error {
+	spec := survey.Spec
+	switch {
+	case spec.Start.Value() <= 0:
+		return fmt.Errorf("start %s must be positive", spec.Start.String())
+	case spec.End.Cmp(spec.Start) <= 0:
+		return fmt.Errorf("end %s must be above start %s", spec.End.String(), spec.Start.String())
+	case spec.BinSize.Value() <= 0:
+		return fmt.Errorf("bin size %s must be positive", spec.BinSize.String())
+	case spec.Integration != nil && spec.Integration.Duration < time.Second:
+		return errors.New("integration must be at least 1s")
+	case spec.Duration != nil && spec.Duration.Duration < integration(survey):
+		return errors.New("duration must be at least the integration")
+	case spec.Gain != nil && spec.Gain.Sign() < 0:
+		return fmt.Errorf("gain %s must not be negative", spec.Gain.String())
+	}
+
+	return nil
+}
+
+// integration returns the integration time of the survey.
+func integration(survey *radiov1beta1.RtlSdrSurvey) time.Duration {
+	if survey.Spec.Integration != nil {
+		return survey.Spec.Integration.Duration
+	}
+
+	return DefaultIntegration
+}
+
+// seconds formats d as a whole number of seconds, the time format of
+// rtl_power.
+func seconds(d time.Duration) string {
+	return strconv.FormatInt(int64(d/time.Second), 10)
+}
+
+// rtlPowerArgs returns the rtl_power arguments sweeping the range of the
+// survey.
+func rtlPowerArgs(survey *radiov1beta1.RtlSdrSurvey) []string {
+	spec := survey.Spec
+
+	args := []string{
+		"-f", fmt.Sprintf("%d:%d:%d", spec.Start.Value(), spec.End.Value(), spec.BinSize.Value()),
+		"-i", seconds(integration(survey)),
+	}
+	if spec.Duration != nil {
+		args = append(args, "-e", seconds(spec.Duration.Duration))
+	} else {
+		args = append(args, "-1")
+	}
+	if spec.Gain != nil {
+		args = append(args, "-g", strconv.FormatFloat(spec.Gain.AsApproximateFloat64(), 'f', -1, 64))
+	}
+
+	return append(args, path.Join(sweepMountPath, sweepFile))
+}
+
+// surveyArgs returns the arguments of the tool summarising the sweep.
+func surveyArgs(survey *radiov1beta1.RtlSdrSurvey) []string {
+	peaks := int32(10)
+	if survey.Spec.Peaks != nil {
+		peaks = *survey.Spec.Peaks
+	}
+
+	args := []string{
+		"--input", path.Join(sweepMountPath, sweepFile),
+		"--peaks", strconv.Itoa(int(peaks)),
+	}
+
+	switch output := survey.Spec.Output; {
+	case output == nil:
+	case output.ConfigMap != "":
+		args = append(args, "--configmap", output.ConfigMap)
+	default:
+		dir := output.Path
+		if dir == "" {
+			dir = survey.Name
+		}
+		args = append(args, "--output-dir", path.Join(surveyOutputMountPath, dir))
+	}
+
+	return args
+}
+
+// surveyPodSpec returns the spec of the pod running the survey. rtl_power
+// sweeps the range in an init container holding the dongle, then the survey
+// container summarises its output.
+func (r *RtlSdrSurveyReconciler) surveyPodSpec(survey *radiov1beta1.RtlSdrSurvey) *corev1ac.PodSpecApplyConfiguration {
+	sweep := corev1ac.Container().
+		WithName("rtl-power").
+		WithImage(r.Image).
+		WithCommand("/bin/rtl_power").
+		WithArgs(rtlPowerArgs(survey)...).
+		WithResources(corev1ac.ResourceRequirements().
+			WithLimits(corev1.ResourceList{
+				RtlSdrResourceName: *resource.NewQuantity(1, resource.DecimalSI),
+			})).
+		WithVolumeMounts(corev1ac.VolumeMount().
+			WithName(sweepVolume).
+			WithMountPath(sweepMountPath)).
+		WithSecurityContext(restrictedSecurityContext())
+
+	summarise := corev1ac.Container().
+		WithName(surveyContainer).
+		WithImage(r.SurveyImage).
+		WithCommand("/rtl-survey").
+		WithArgs(surveyArgs(survey)...).
+		WithEnv(corev1ac.EnvVar().
+			WithName("POD_NAMESPACE").
+			WithValueFrom(corev1ac.EnvVarSource().
+				WithFieldRef(corev1ac.ObjectFieldSelector().
+					WithFieldPath("metadata.namespace")))).
+		WithVolumeMounts(corev1ac.VolumeMount().
+			WithName(sweepVolume).
+			WithMountPath(sweepMountPath).
+			WithReadOnly(true)).
+		WithSecurityContext(restrictedSecurityContext())
+
+	spec := corev1ac.PodSpec().
+		WithRestartPolicy(corev1.RestartPolicyNever).
+		WithInitContainers(sweep).
+		WithContainers(summarise).
+		WithVolumes(corev1ac.Volume().
+			WithName(sweepVolume).
+			WithEmptyDir(corev1ac.EmptyDirVolumeSource()))
+
+	// Only a survey writing a ConfigMap needs to talk to the API server.
+	if configMapOutput(survey) {
+		spec.WithServiceAccountName(surveyServiceAccountName(survey))
+	} else {
+		spec.WithAutomountServiceAccountToken(false)
+	}
+
+	if output := survey.Spec.Output; output != nil && output.ClaimName != "" {
+		summarise.WithVolumeMounts(corev1ac.VolumeMount().
+			WithName(surveyOutputVolume).
+			WithMountPath(surveyOutputMountPath))
+		spec.WithVolumes(corev1ac.Volume().
+			WithName(surveyOutputVolume).
+			WithPersistentVolumeClaim(corev1ac.PersistentVolumeClaimVolumeSource().
+				WithClaimName(output.ClaimName))).
+			// Let the non-root container write to the volume.
+			WithSecurityContext(corev1ac.PodSecurityContext().
+				WithFSGroup(65532))
+	}
+
+	if survey.Spec.NodeName != "" {
+		spec.WithNodeName(survey.Spec.NodeName)
+	}
+	if len(survey.Spec.NodeSelector) > 0 {
+		spec.WithNodeSelector(survey.Spec.NodeSelector)
+	}
+
+	return spec
+}
+
+// surveyJob returns the Job running the survey. A failed sweep is not
+// retried, as it would most likely fail again the same way.
+func (r *RtlSdrSurveyReconciler) surveyJob(survey *radiov1beta1.RtlSdrSurvey) *batchv1ac.JobApplyConfiguration {
+	labels := surveyLabels(survey)
+
+	return batchv1ac.Job(survey.Name, survey.Namespace).
+		WithLabels(labels).
+		WithOwnerReferences(surveyOwnerReference(survey)).
+		WithSpec(batchv1ac.JobSpec().
+			WithBackoffLimit(0).
+			WithTemplate(corev1ac.PodTemplateSpec().
+				WithLabels(labels).
+				WithSpec(r.surveyPodSpec(survey))))
+}
+
+// surveyConfigMap returns the ConfigMap the survey pod writes its results
+// to. The controller only owns its metadata.
+func surveyConfigMap(survey *radiov1beta1.RtlSdrSurvey) *corev1ac.ConfigMapApplyConfiguration {
+	return corev1ac.ConfigMap(survey.Spec.Output.ConfigMap, survey.Namespace).
+		WithLabels(surveyLabels(survey)).
+		WithOwnerReferences(surveyOwnerReference(survey))
+}
+
+// surveyServiceAccount returns the ServiceAccount of a survey pod writing a
+// ConfigMap.
+func surveyServiceAccount(survey *radiov1beta1.RtlSdrSurvey) *corev1ac.ServiceAccountApplyConfiguration {
+	return corev1ac.ServiceAccount(surveyServiceAccountName(survey), survey.Namespace).
+		WithLabels(surveyLabels(survey)).
+		WithOwnerReferences(surveyOwnerReference(survey))
+}
+
+// surveyRole returns the Role letting the survey pod write its results to
+// the output ConfigMap, and nothing else.
+func surveyRole(survey *radiov1beta1.RtlSdrSurvey) *rbacv1ac.RoleApplyConfiguration {
+	return rbacv1ac.Role(surveyServiceAccountName(survey), survey.Namespace).
+		WithLabels(surveyLabels(survey)).
+		WithOwnerReferences(surveyOwnerReference(survey)).
+		WithRules(rbacv1ac.PolicyRule().
+			WithAPIGroups("").
+			WithResources("configmaps").
+			WithResourceNames(survey.Spec.Output.ConfigMap).
+			WithVerbs("get", "patch"))
+}
+
+// surveyRoleBinding returns the RoleBinding granting surveyRole to the
+// survey ServiceAccount.
+func surveyRoleBinding(survey *radiov1beta1.RtlSdrSurvey) *rbacv1ac.RoleBindingApplyConfiguration {
+	name := surveyServiceAccountName(survey)
+
+	return rbacv1ac.RoleBinding(name, survey.Namespace).
+		WithLabels(surveyLabels(survey)).
+		WithOwnerReferences(surveyOwnerReference(survey)).
+		WithRoleRef(rbacv1ac.RoleRef().
+			WithAPIGroup(rbacv1.GroupName).
+			WithKind("Role").
+			WithName(name)).
+		WithSubjects(rbacv1ac.Subject().
+			WithKind(rbacv1.ServiceAccountKind).
+			WithName(name).
+			WithNamespace(survey.Namespace))
+}
+
+// surveyStatusApplyConfiguration converts the status of the survey into an
+// apply configuration.
+func surveyStatusApplyConfiguration(survey *radiov1beta1.RtlSdrSurvey) *radiov1beta1ac.RtlSdrSurveyApplyConfiguration {
+	status := radiov1beta1ac.RtlSdrSurveyStatus()
+
+	if survey.Status.State != "" {
+		status.WithState(survey.Status.State)
+	}
+	if survey.Status.Job != nil {
+		status.WithJob(*survey.Status.Job)
+	}
+	if survey.Status.StartTime != nil {
+		status.WithStartTime(*survey.Status.StartTime)
+	}
+	if survey.Status.CompletionTime != nil {
+		status.WithCompletionTime(*survey.Status.CompletionTime)
+	}
+	if survey.Status.Sweeps != 0 {
+		status.WithSweeps(survey.Status.Sweeps)
+	}
+	for _, peak := range survey.Status.Peaks {
+		status.WithPeaks(radiov1beta1ac.RtlSdrSurveyPeak().
+			WithFrequency(peak.Frequency).
+			WithPower(peak.Power))
+	}
+
+	for _, c := range survey.Status.Conditions {
+		status.WithConditions(metav1ac.Condition().
+			WithType(c.Type).
+			WithStatus(c.Status).
+			WithObservedGeneration(c.ObservedGeneration).
+			WithLastTransitionTime(c.LastTransitionTime).
+			WithReason(c.Reason).
+			WithMessage(c.Message))
+	}
+
+	return radiov1beta1ac.RtlSdrSurvey(survey.Name, survey.Namespace).
+		WithStatus(status)
+}
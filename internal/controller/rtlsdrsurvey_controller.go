@@ -0,0 +1,282 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	radiov1beta1 "github.com/frelon/k8s-radio/api/v1beta1"
+	"github.com/frelon/k8s-radio/pkg/survey"
+)
+
+// SurveyDefaultImage is the default image summarising the output of
+// rtl_power.
+const SurveyDefaultImage = "rtl-survey:dev"
+
+// RtlSdrSurveyReconciler reconciles a RtlSdrSurvey object
+type RtlSdrSurveyReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+
+	// Image is the image running rtl_power.
+	Image string
+
+	// SurveyImage is the image summarising the output of rtl_power.
+	SurveyImage string
+}
+
+// +kubebuilder:rbac:groups=radio.frelon.se,resources=rtlsdrsurveys,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=radio.frelon.se,resources=rtlsdrsurveys/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=radio.frelon.se,resources=rtlsdrsurveys/finalizers,verbs=update
+// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=serviceaccounts,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=roles;rolebindings,verbs=get;list;watch;create;update;patch;delete
+
+// Reconcile runs the survey as a Job and summarises its outcome. A finished
+// survey is left alone; its spec is immutable, so a new sweep needs a new
+// survey.
+func (r *RtlSdrSurveyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx).WithValues("name", req.String())
+	logger.Info("Reconciling RtlSdrSurvey")
+
+	s := &radiov1beta1.RtlSdrSurvey{}
+	if err := r.Get(ctx, req.NamespacedName, s); err != nil {
+		if apierrors.IsNotFound(err) {
+			logger.Info("Object was not found, not an error")
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, fmt.Errorf("failed to get survey object: %w", err)
+	}
+
+	if s.Status.State == radiov1beta1.SurveySucceeded || s.Status.State == radiov1beta1.SurveyFailed {
+		return reconcile.Result{}, nil
+	}
+
+	if err := validateSurvey(s); err != nil {
+		logger.Info("Invalid survey", "reason", err.Error())
+		s.Status.State = radiov1beta1.SurveyFailed
+		setComplete(s, metav1.ConditionFalse, radiov1beta1.InvalidSurveyReason, err.Error())
+		return reconcile.Result{}, r.applyStatus(ctx, s)
+	}
+
+	if configMapOutput(s) {
+		if err := r.applyConfigMapAccess(ctx, s); err != nil {
+			logger.Error(err, "Error applying the output ConfigMap")
+			return reconcile.Result{}, err
+		}
+	}
+
+	job := r.surveyJob(s)
+	if err := r.Apply(ctx, job, client.FieldOwner(FieldManager), client.ForceOwnership); err != nil {
+		logger.Error(err, "Error applying job")
+		return reconcile.Result{}, err
+	}
+	s.Status.Job = objectReference(job.GetKind(), job.GetAPIVersion(), job.ObjectMetaApplyConfiguration)
+
+	status := batchv1.JobStatus{}
+	if job.Status != nil {
+		status.StartTime = job.Status.StartTime
+		status.CompletionTime = job.Status.CompletionTime
+		for _, c := range job.Status.Conditions {
+			status.Conditions = append(status.Conditions, batchv1.JobCondition{
+				Type:               ptr.Deref(c.Type, ""),
+				Status:             ptr.Deref(c.Status, ""),
+				LastTransitionTime: ptr.Deref(c.LastTransitionTime, metav1.Time{}),
+				Reason:             ptr.Deref(c.Reason, ""),
+				Message:            ptr.Deref(c.Message, ""),
+			})
+		}
+	}
+	s.Status.StartTime = status.StartTime
+
+	pods := &corev1.PodList{}
+	if err := r.List(ctx, pods, client.InNamespace(s.Namespace), client.MatchingLabels(surveyLabels(s))); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	if err := r.summarise(ctx, s, status, pods.Items); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	logger.Info("Updating status")
+	if err := r.applyStatus(ctx, s); err != nil {
+		logger.Error(err, "Error updating RtlSdrSurvey status")
+		return reconcile.Result{}, err
+	}
+
+	return reconcile.Result{}, nil
+}
+
+// summarise derives the state of the survey from its Job and pods, and reads
+// the summary once it has succeeded.
+func (r *RtlSdrSurveyReconciler) summarise(ctx context.Context, s *radiov1beta1.RtlSdrSurvey, status batchv1.JobStatus, pods []corev1.Pod) error {
+	for _, c := range status.Conditions {
+		if c.Status != corev1.ConditionTrue {
+			continue
+		}
+
+		switch c.Type {
+		case batchv1.JobComplete:
+			summary, err := podSummary(pods)
+			if err != nil {
+				log.FromContext(ctx).Error(err, "Failed reading the survey summary")
+			}
+
+			s.Status.State = radiov1beta1.SurveySucceeded
+			s.Status.CompletionTime = status.CompletionTime
+			if summary != nil {
+				s.Status.Sweeps = int32(summary.Sweeps)
+				s.Status.Peaks = surveyPeaks(summary)
+			}
+			setComplete(s, metav1.ConditionTrue, string(radiov1beta1.SurveySucceeded),
+				fmt.Sprintf("Found %d peaks in %d sweeps", len(s.Status.Peaks), s.Status.Sweeps))
+			return nil
+		case batchv1.JobFailed:
+			s.Status.State = radiov1beta1.SurveyFailed
+			s.Status.CompletionTime = &c.LastTransitionTime
+			setComplete(s, metav1.ConditionFalse, radiov1beta1.JobFailedReason, c.Message)
+			return nil
+		}
+	}
+
+	// The pod is bound to a node once a dongle is free for it.
+	s.Status.State = radiov1beta1.SurveyPending
+	message := "Waiting for a free dongle"
+	for _, pod := range pods {
+		if pod.Spec.NodeName != "" && (pod.Status.Phase == corev1.PodPending || pod.Status.Phase == corev1.PodRunning) {
+			s.Status.State = radiov1beta1.SurveyRunning
+			message = "Sweeping on " + pod.Spec.NodeName
+		}
+	}
+	setComplete(s, metav1.ConditionFalse, string(s.Status.State), message)
+
+	return nil
+}
+
+// podSummary returns the summary the survey container of the succeeded pod
+// left as its termination message.
+func podSummary(pods []corev1.Pod) (*survey.Summary, error) {
+	for _, pod := range pods {
+		if pod.Status.Phase != corev1.PodSucceeded {
+			continue
+		}
+
+		for _, cs := range pod.Status.ContainerStatuses {
+			if cs.Name != surveyContainer || cs.State.Terminated == nil {
+				continue
+			}
+
+			summary := &survey.Summary{}
+			if err := json.Unmarshal([]byte(cs.State.Terminated.Message), summary); err != nil {
+				return nil, fmt.Errorf("invalid summary in pod %s: %w", pod.Name, err)
+			}
+			return summary, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no succeeded pod with a summary")
+}
+
+// surveyPeaks converts the peaks of the summary.
+func surveyPeaks(summary *survey.Summary) []radiov1beta1.RtlSdrSurveyPeak {
+	peaks := make([]radiov1beta1.RtlSdrSurveyPeak, 0, len(summary.Peaks))
+	for _, peak := range summary.Peaks {
+		peaks = append(peaks, radiov1beta1.RtlSdrSurveyPeak{
+			Frequency: *resource.NewQuantity(int64(math.Round(peak.Frequency)), resource.DecimalSI),
+			Power:     int32(math.Round(peak.Power)),
+		})
+	}
+
+	return peaks
+}
+
+// setComplete sets the Complete condition of the survey.
+func setComplete(s *radiov1beta1.RtlSdrSurvey, status metav1.ConditionStatus, reason, message string) {
+	meta.SetStatusCondition(&s.Status.Conditions, metav1.Condition{
+		Type:    radiov1beta1.CompleteCondition,
+		Status:  status,
+		Reason:  reason,
+		Message: message,
+	})
+}
+
+// applyConfigMapAccess creates the output ConfigMap, and the ServiceAccount
+// allowed to write it.
+func (r *RtlSdrSurveyReconciler) applyConfigMapAccess(ctx context.Context, s *radiov1beta1.RtlSdrSurvey) error {
+	if err := r.Apply(ctx, surveyConfigMap(s), client.FieldOwner(FieldManager), client.ForceOwnership); err != nil {
+		return err
+	}
+	if err := r.Apply(ctx, surveyServiceAccount(s), client.FieldOwner(FieldManager), client.ForceOwnership); err != nil {
+		return err
+	}
+	if err := r.Apply(ctx, surveyRole(s), client.FieldOwner(FieldManager), client.ForceOwnership); err != nil {
+		return err
+	}
+
+	return r.Apply(ctx, surveyRoleBinding(s), client.FieldOwner(FieldManager), client.ForceOwnership)
+}
+
+// applyStatus applies the status of the survey.
+func (r *RtlSdrSurveyReconciler) applyStatus(ctx context.Context, s *radiov1beta1.RtlSdrSurvey) error {
+	return r.Status().Apply(ctx, surveyStatusApplyConfiguration(s), client.FieldOwner(FieldManager), client.ForceOwnership)
+}
+
+// surveyPod maps a survey pod, which is owned by the Job, to its survey.
+func surveyPod(_ context.Context, obj client.Object) []reconcile.Request {
+	labels := obj.GetLabels()
+	if labels["app.kubernetes.io/name"] != "rtlsdrsurvey" || labels["app.kubernetes.io/managed-by"] != "k8s-radio" {
+		return nil
+	}
+
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{
+		Name:      labels["app.kubernetes.io/instance"],
+		Namespace: obj.GetNamespace(),
+	}}}
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *RtlSdrSurveyReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	// The Job status does not tell when its pod got a dongle, so watch the
+	// pods too.
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&radiov1beta1.RtlSdrSurvey{}).
+		Owns(&batchv1.Job{}).
+		Watches(&corev1.Pod{}, handler.EnqueueRequestsFromMapFunc(surveyPod)).
+		Owns(&corev1.ServiceAccount{}).
+		Owns(&rbacv1.Role{}).
+		Owns(&rbacv1.RoleBinding{}).
+		Complete(r)
+}
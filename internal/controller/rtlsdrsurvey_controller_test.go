@@ -0,0 +1,228 @@
+package controller
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	radiov1 "github.com/frelon/k8s-radio/api/v1beta1"
+)
+
+var _ = Describe("RtlSdrSurvey controller", func() {
+	const namespace = "default"
+
+	reconciler := func() *RtlSdrSurveyReconciler {
+		return &RtlSdrSurveyReconciler{
+			Client:      k8sClient,
+			Scheme:      scheme.Scheme,
+			Image:       "test-image",
+			SurveyImage: "test-survey-image",
+		}
+	}
+
+	It("Should sweep the range in a Job and summarise the peaks", func(ctx SpecContext) {
+		By("By creating a survey writing to a ConfigMap")
+
+		s := &radiov1.RtlSdrSurvey{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-survey",
+				Namespace: namespace,
+			},
+			Spec: radiov1.RtlSdrSurveySpec{
+				Start:       resource.MustParse("88M"),
+				End:         resource.MustParse("108M"),
+				BinSize:     resource.MustParse("10k"),
+				Integration: &metav1.Duration{Duration: 30 * time.Second},
+				Gain:        resource.NewScaledQuantity(297, -1),
+				NodeName:    "site-a",
+				Output:      &radiov1.RtlSdrSurveyOutput{ConfigMap: "test-survey-results"},
+			},
+		}
+		Expect(k8sClient.Create(ctx, s)).Should(Succeed())
+
+		key := types.NamespacedName{Name: s.Name, Namespace: namespace}
+		_, err := reconciler().Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).To(Succeed())
+
+		By("By checking the Job runs rtl_power and the summary")
+		job := &batchv1.Job{}
+		Expect(k8sClient.Get(ctx, key, job)).To(Succeed())
+		Expect(job.Spec.BackoffLimit).To(HaveValue(BeZero()))
+
+		spec := job.Spec.Template.Spec
+		Expect(spec.NodeName).To(Equal("site-a"))
+		Expect(spec.ServiceAccountName).To(Equal("rtlsdrsurvey-test-survey"))
+		Expect(spec.InitContainers).To(HaveLen(1))
+		Expect(spec.InitContainers[0].Args).To(Equal([]string{
+			"-f", "88000000:108000000:10000",
+			"-i", "30",
+			"-1",
+			"-g", "29.7",
+			"/sweep/survey.csv",
+		}))
+		Expect(spec.InitContainers[0].Resources.Limits).To(HaveKey(corev1.ResourceName(RtlSdrResourceName)))
+		Expect(spec.Containers).To(HaveLen(1))
+		Expect(spec.Containers[0].Args).To(Equal([]string{
+			"--input", "/sweep/survey.csv",
+			"--peaks", "10",
+			"--configmap", "test-survey-results",
+		}))
+
+		By("By checking the pod may only write the output ConfigMap")
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "test-survey-results", Namespace: namespace}, &corev1.ConfigMap{})).To(Succeed())
+		role := &rbacv1.Role{}
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "rtlsdrsurvey-test-survey", Namespace: namespace}, role)).To(Succeed())
+		Expect(role.Rules).To(HaveLen(1))
+		Expect(role.Rules[0].ResourceNames).To(Equal([]string{"test-survey-results"}))
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "rtlsdrsurvey-test-survey", Namespace: namespace}, &rbacv1.RoleBinding{})).To(Succeed())
+
+		updated := &radiov1.RtlSdrSurvey{}
+		Expect(k8sClient.Get(ctx, key, updated)).To(Succeed())
+		Expect(updated.Status.State).To(Equal(radiov1.SurveyPending))
+		Expect(updated.Status.Job.Name).To(Equal(s.Name))
+
+		By("By reading the summary once the Job has completed")
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-survey-abcde",
+				Namespace: namespace,
+				Labels:    job.Spec.Template.Labels,
+			},
+			Spec: spec,
+		}
+		Expect(k8sClient.Create(ctx, pod)).To(Succeed())
+		pod.Status.Phase = corev1.PodSucceeded
+		pod.Status.ContainerStatuses = []corev1.ContainerStatus{{
+			Name: "survey",
+			State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+				Message: `{"sweeps":1,"peaks":[{"frequency":101905000,"power":-4.6},{"frequency":94005000,"power":-12.2}]}`,
+			}},
+		}}
+		Expect(k8sClient.Status().Update(ctx, pod)).To(Succeed())
+
+		now := metav1.Now()
+		job.Status.StartTime = &now
+		job.Status.CompletionTime = &now
+		job.Status.Succeeded = 1
+		job.Status.Conditions = []batchv1.JobCondition{
+			{Type: batchv1.JobSuccessCriteriaMet, Status: corev1.ConditionTrue, LastTransitionTime: now},
+			{Type: batchv1.JobComplete, Status: corev1.ConditionTrue, LastTransitionTime: now},
+		}
+		Expect(k8sClient.Status().Update(ctx, job)).To(Succeed())
+
+		_, err = reconciler().Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).To(Succeed())
+
+		Expect(k8sClient.Get(ctx, key, updated)).To(Succeed())
+		Expect(updated.Status.State).To(Equal(radiov1.SurveySucceeded))
+		Expect(updated.Status.CompletionTime).ToNot(BeNil())
+		Expect(updated.Status.Sweeps).To(Equal(int32(1)))
+		Expect(updated.Status.Peaks).To(HaveLen(2))
+		Expect(updated.Status.Peaks[0].Frequency.Value()).To(Equal(int64(101_905_000)))
+		Expect(updated.Status.Peaks[0].Power).To(Equal(int32(-5)))
+	})
+
+	It("Should write the results to a volume", func(ctx SpecContext) {
+		s := &radiov1.RtlSdrSurvey{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-volume-survey",
+				Namespace: namespace,
+			},
+			Spec: radiov1.RtlSdrSurveySpec{
+				Start:    resource.MustParse("430M"),
+				End:      resource.MustParse("440M"),
+				BinSize:  resource.MustParse("25k"),
+				Duration: &metav1.Duration{Duration: time.Hour},
+				Output:   &radiov1.RtlSdrSurveyOutput{ClaimName: "surveys"},
+			},
+		}
+		Expect(k8sClient.Create(ctx, s)).Should(Succeed())
+
+		key := types.NamespacedName{Name: s.Name, Namespace: namespace}
+		_, err := reconciler().Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).To(Succeed())
+
+		job := &batchv1.Job{}
+		Expect(k8sClient.Get(ctx, key, job)).To(Succeed())
+
+		spec := job.Spec.Template.Spec
+		Expect(spec.AutomountServiceAccountToken).To(HaveValue(BeFalse()))
+		Expect(spec.InitContainers[0].Args).To(ContainElements("-e", "3600"))
+		Expect(spec.Containers[0].Args).To(ContainElements("--output-dir", "/output/test-volume-survey"))
+		Expect(spec.Volumes).To(ContainElement(HaveField("PersistentVolumeClaim.ClaimName", "surveys")))
+		Expect(spec.SecurityContext.FSGroup).To(HaveValue(Equal(int64(65532))))
+
+		err = k8sClient.Get(ctx, types.NamespacedName{Name: "rtlsdrsurvey-test-volume-survey", Namespace: namespace}, &corev1.ServiceAccount{})
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("Should fail surveys with an empty range", func(ctx SpecContext) {
+		s := &radiov1.RtlSdrSurvey{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-invalid-survey",
+				Namespace: namespace,
+			},
+			Spec: radiov1.RtlSdrSurveySpec{
+				Start:   resource.MustParse("108M"),
+				End:     resource.MustParse("88M"),
+				BinSize: resource.MustParse("10k"),
+			},
+		}
+		Expect(k8sClient.Create(ctx, s)).Should(Succeed())
+
+		key := types.NamespacedName{Name: s.Name, Namespace: namespace}
+		_, err := reconciler().Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).To(Succeed())
+
+		updated := &radiov1.RtlSdrSurvey{}
+		Expect(k8sClient.Get(ctx, key, updated)).To(Succeed())
+		Expect(updated.Status.State).To(Equal(radiov1.SurveyFailed))
+		Expect(updated.Status.Conditions).To(ContainElement(HaveField("Reason", radiov1.InvalidSurveyReason)))
+		Expect(apierrors.IsNotFound(k8sClient.Get(ctx, key, &batchv1.Job{}))).To(BeTrue())
+	})
+
+	It("Should reject changes to the spec and ambiguous outputs", func(ctx SpecContext) {
+		s := &radiov1.RtlSdrSurvey{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-immutable-survey",
+				Namespace: namespace,
+			},
+			Spec: radiov1.RtlSdrSurveySpec{
+				Start:   resource.MustParse("88M"),
+				End:     resource.MustParse("108M"),
+				BinSize: resource.MustParse("10k"),
+			},
+		}
+		Expect(k8sClient.Create(ctx, s)).Should(Succeed())
+
+		s.Spec.End = resource.MustParse("98M")
+		err := k8sClient.Update(ctx, s)
+		Expect(apierrors.IsInvalid(err)).To(BeTrue(), "expected invalid, got %v", err)
+
+		both := &radiov1.RtlSdrSurvey{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-ambiguous-survey",
+				Namespace: namespace,
+			},
+			Spec: radiov1.RtlSdrSurveySpec{
+				Start:   resource.MustParse("88M"),
+				End:     resource.MustParse("108M"),
+				BinSize: resource.MustParse("10k"),
+				Output:  &radiov1.RtlSdrSurveyOutput{ClaimName: "surveys", ConfigMap: "results"},
+			},
+		}
+		err = k8sClient.Create(ctx, both)
+		Expect(apierrors.IsInvalid(err)).To(BeTrue(), "expected invalid, got %v", err)
+	})
+})
@@ -0,0 +1,221 @@
+// Package access guards the stream of a receiver, which rtl_tcp and the
+// audio server serve unauthenticated in plaintext, behind TLS. Clients are
+// authenticated by the certificates they present, or by bearer tokens.
+//
+// Clients of a raw TCP stream send their token in an HTTP CONNECT request
+// before the stream starts, like they would to an HTTP proxy:
+//
+//	CONNECT receiver:1234 HTTP/1.1
+//	Authorization: Bearer <token>
+//
+// Clients of an HTTP stream send the Authorization header with their
+// requests.
+package access
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// handshakeTimeout bounds how long the TLS handshake and the authentication
+// of a client may take.
+const handshakeTimeout = 10 * time.Second
+
+// ErrUnauthenticated is returned when a client could not be authenticated.
+var ErrUnauthenticated = errors.New("access: unauthenticated")
+
+// Authenticator authenticates the bearer token of a client and returns the
+// name of its user.
+type Authenticator interface {
+	Authenticate(ctx context.Context, token string) (string, error)
+}
+
+// Proxy accepts TLS connections and forwards the authenticated ones to an
+// upstream stream.
+type Proxy struct {
+	// Upstream is the address of the guarded stream.
+	Upstream string
+	// HTTP proxies requests to an HTTP upstream instead of a raw TCP
+	// stream.
+	HTTP bool
+	// Authenticator authenticates the bearer tokens of clients. Without
+	// one clients are authenticated by their certificates, which the TLS
+	// listener has to require.
+	Authenticator Authenticator
+}
+
+// Serve accepts clients on the TLS listener l until ctx is done.
+func (p *Proxy) Serve(ctx context.Context, l net.Listener) error {
+	if p.HTTP {
+		return p.serveHTTP(ctx, l)
+	}
+
+	stop := context.AfterFunc(ctx, func() {
+		_ = l.Close()
+	})
+	defer stop()
+
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+
+		go func() {
+			defer func() { _ = conn.Close() }()
+			if err := p.handle(ctx, conn); err != nil {
+				slog.Info("Rejecting client", slog.String("client", conn.RemoteAddr().String()), slog.Any("error", err))
+			}
+		}()
+	}
+}
+
+// handle authenticates a client of a TCP stream and copies the stream both
+// ways until either side is done.
+func (p *Proxy) handle(ctx context.Context, conn net.Conn) error {
+	_ = conn.SetDeadline(time.Now().Add(handshakeTimeout))
+
+	tlsConn, ok := conn.(*tls.Conn)
+	if !ok {
+		return errors.New("access: not a TLS connection")
+	}
+	if err := tlsConn.HandshakeContext(ctx); err != nil {
+		return err
+	}
+
+	var client io.Reader = conn
+	user := peerName(tlsConn.ConnectionState())
+	if p.Authenticator != nil {
+		r := bufio.NewReader(conn)
+		req, err := http.ReadRequest(r)
+		if err != nil {
+			return err
+		}
+		if req.Method != http.MethodConnect {
+			_, _ = io.WriteString(conn, "HTTP/1.1 405 Method Not Allowed\r\n\r\n")
+			return fmt.Errorf("access: unexpected %s request", req.Method)
+		}
+		if user, err = p.authenticate(ctx, req); err != nil {
+			_, _ = io.WriteString(conn, "HTTP/1.1 401 Unauthorized\r\n\r\n")
+			return err
+		}
+		client = r
+	}
+
+	var dialer net.Dialer
+	upstream, err := dialer.DialContext(ctx, "tcp", p.Upstream)
+	if err != nil {
+		if p.Authenticator != nil {
+			_, _ = io.WriteString(conn, "HTTP/1.1 502 Bad Gateway\r\n\r\n")
+		}
+		return err
+	}
+	defer func() { _ = upstream.Close() }()
+
+	if p.Authenticator != nil {
+		if _, err := io.WriteString(conn, "HTTP/1.1 200 Connection Established\r\n\r\n"); err != nil {
+			return err
+		}
+	}
+	_ = conn.SetDeadline(time.Time{})
+
+	slog.Info("Client connected", slog.String("client", conn.RemoteAddr().String()), slog.String("user", user))
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _ = io.Copy(upstream, client)
+		_ = upstream.Close()
+	}()
+	_, _ = io.Copy(conn, upstream)
+	_ = conn.Close()
+	<-done
+
+	slog.Info("Client disconnected", slog.String("client", conn.RemoteAddr().String()), slog.String("user", user))
+
+	return nil
+}
+
+// serveHTTP proxies the authenticated requests on l to the upstream until
+// ctx is done.
+func (p *Proxy) serveHTTP(ctx context.Context, l net.Listener) error {
+	upstream := &url.URL{Scheme: "http", Host: p.Upstream}
+	proxy := &httputil.ReverseProxy{
+		Rewrite: func(r *httputil.ProxyRequest) {
+			r.SetURL(upstream)
+			r.SetXForwarded()
+			r.Out.Header.Del("Authorization")
+		},
+		// Pass the audio on as it is streamed.
+		FlushInterval: -1,
+	}
+
+	server := &http.Server{
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user, err := p.authenticate(r.Context(), r)
+			if err != nil {
+				slog.Info("Rejecting request", slog.String("client", r.RemoteAddr), slog.Any("error", err))
+				w.Header().Set("WWW-Authenticate", `Bearer realm="rtlsdr"`)
+				http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+				return
+			}
+
+			slog.Info("Proxying request", slog.String("client", r.RemoteAddr), slog.String("user", user), slog.String("path", r.URL.Path))
+			proxy.ServeHTTP(w, r)
+		}),
+		ReadHeaderTimeout: handshakeTimeout,
+	}
+
+	stop := context.AfterFunc(ctx, func() {
+		_ = server.Close()
+	})
+	defer stop()
+
+	if err := server.Serve(l); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	return nil
+}
+
+// authenticate returns the user of a request, named by its bearer token or
+// by the certificate of the client.
+func (p *Proxy) authenticate(ctx context.Context, req *http.Request) (string, error) {
+	if p.Authenticator == nil {
+		if req.TLS == nil {
+			return "", ErrUnauthenticated
+		}
+		return peerName(*req.TLS), nil
+	}
+
+	token, ok := strings.CutPrefix(req.Header.Get("Authorization"), "Bearer ")
+	if !ok || strings.TrimSpace(token) == "" {
+		return "", ErrUnauthenticated
+	}
+
+	return p.Authenticator.Authenticate(ctx, strings.TrimSpace(token))
+}
+
+// peerName returns the common name of the certificate the client presented,
+// if any.
+func peerName(state tls.ConnectionState) string {
+	if len(state.PeerCertificates) == 0 {
+		return ""
+	}
+
+	return state.PeerCertificates[0].Subject.CommonName
+}
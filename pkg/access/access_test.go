@@ -0,0 +1,314 @@
+package access
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/frelon/k8s-radio/pkg/pki"
+)
+
+func TestAccess(t *testing.T) {
+	RegisterFailHandler(Fail)
+
+	RunSpecs(t, "Access Suite")
+}
+
+// echo serves a TCP stream sending back what it receives.
+func echo() net.Listener {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	Expect(err).ToNot(HaveOccurred())
+	DeferCleanup(l.Close)
+
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer func() { _ = conn.Close() }()
+				_, _ = io.Copy(conn, conn)
+			}()
+		}
+	}()
+
+	return l
+}
+
+// writeFile writes data to a file in dir and returns its path.
+func writeFile(dir, name string, data []byte) string {
+	path := filepath.Join(dir, name)
+	Expect(os.WriteFile(path, data, 0o600)).To(Succeed())
+
+	return path
+}
+
+var _ = Describe("Proxy", func() {
+	var (
+		ca     *pki.KeyPair
+		client *pki.KeyPair
+		certs  *Certificates
+		roots  *x509.CertPool
+	)
+
+	BeforeEach(func() {
+		now := time.Now()
+		var err error
+		ca, err = pki.NewCA("test-ca", now, pki.CAValidity)
+		Expect(err).ToNot(HaveOccurred())
+		server, err := ca.Issue(pki.Request{CommonName: "receiver", Hosts: []string{"127.0.0.1"}}, now, pki.CertificateValidity)
+		Expect(err).ToNot(HaveOccurred())
+		client, err = ca.Issue(pki.Request{CommonName: "alice", Client: true}, now, pki.CertificateValidity)
+		Expect(err).ToNot(HaveOccurred())
+
+		dir := GinkgoT().TempDir()
+		certs = &Certificates{
+			CertFile: writeFile(dir, "tls.crt", server.CertPEM),
+			KeyFile:  writeFile(dir, "tls.key", server.KeyPEM),
+		}
+		roots = x509.NewCertPool()
+		roots.AddCert(ca.Cert)
+	})
+
+	// serve starts p on a TLS listener and returns its address.
+	serve := func(ctx context.Context, p *Proxy) string {
+		config, err := certs.TLSConfig()
+		Expect(err).ToNot(HaveOccurred())
+		l, err := tls.Listen("tcp", "127.0.0.1:0", config)
+		Expect(err).ToNot(HaveOccurred())
+
+		go func() {
+			defer GinkgoRecover()
+			Expect(p.Serve(ctx, l)).To(Succeed())
+		}()
+
+		return l.Addr().String()
+	}
+
+	// roundTrip sends msg over conn and returns what comes back.
+	roundTrip := func(conn io.ReadWriter, msg string) string {
+		_, err := io.WriteString(conn, msg)
+		Expect(err).ToNot(HaveOccurred())
+		buf := make([]byte, len(msg))
+		_, err = io.ReadFull(conn, buf)
+		Expect(err).ToNot(HaveOccurred())
+
+		return string(buf)
+	}
+
+	It("authenticates clients of a TCP stream by their certificates", func(ctx SpecContext) {
+		certs.ClientCAFile = writeFile(GinkgoT().TempDir(), "ca.crt", ca.CertPEM)
+		addr := serve(ctx, &Proxy{Upstream: echo().Addr().String()})
+
+		cert, err := tls.X509KeyPair(client.CertPEM, client.KeyPEM)
+		Expect(err).ToNot(HaveOccurred())
+		conn, err := tls.Dial("tcp", addr, &tls.Config{RootCAs: roots, Certificates: []tls.Certificate{cert}})
+		Expect(err).ToNot(HaveOccurred())
+		defer func() { _ = conn.Close() }()
+		Expect(roundTrip(conn, "hello")).To(Equal("hello"))
+
+		By("rejecting clients without a certificate")
+		conn, err = tls.Dial("tcp", addr, &tls.Config{RootCAs: roots})
+		if err == nil {
+			defer func() { _ = conn.Close() }()
+			_, err = conn.Read(make([]byte, 1))
+		}
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("authenticates clients of a TCP stream by the token they connect with", func(ctx SpecContext) {
+		dir := GinkgoT().TempDir()
+		writeFile(dir, "alice", []byte("s3cret\n"))
+		addr := serve(ctx, &Proxy{Upstream: echo().Addr().String(), Authenticator: Tokens{Dir: dir}})
+
+		connect := func(token string) (*tls.Conn, *http.Response) {
+			conn, err := tls.Dial("tcp", addr, &tls.Config{RootCAs: roots})
+			Expect(err).ToNot(HaveOccurred())
+			DeferCleanup(conn.Close)
+
+			req, err := http.NewRequest(http.MethodConnect, "http://"+addr, nil)
+			Expect(err).ToNot(HaveOccurred())
+			req.Header.Set("Authorization", "Bearer "+token)
+			Expect(req.Write(conn)).To(Succeed())
+
+			resp, err := http.ReadResponse(bufio.NewReader(conn), req)
+			Expect(err).ToNot(HaveOccurred())
+
+			return conn, resp
+		}
+
+		conn, resp := connect("s3cret")
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		Expect(roundTrip(conn, "hello")).To(Equal("hello"))
+
+		_, resp = connect("wrong")
+		Expect(resp.StatusCode).To(Equal(http.StatusUnauthorized))
+	})
+
+	It("authenticates the requests to an HTTP stream by their token", func(ctx SpecContext) {
+		upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = io.WriteString(w, r.URL.Path+" "+r.Header.Get("Authorization"))
+		}))
+		defer upstream.Close()
+
+		dir := GinkgoT().TempDir()
+		writeFile(dir, "alice", []byte("s3cret"))
+		addr := serve(ctx, &Proxy{
+			Upstream:      upstream.Listener.Addr().String(),
+			HTTP:          true,
+			Authenticator: Tokens{Dir: dir},
+		})
+
+		httpClient := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: roots}}}
+		get := func(token string) *http.Response {
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://"+addr+"/audio.wav", nil)
+			Expect(err).ToNot(HaveOccurred())
+			if token != "" {
+				req.Header.Set("Authorization", "Bearer "+token)
+			}
+			resp, err := httpClient.Do(req)
+			Expect(err).ToNot(HaveOccurred())
+			DeferCleanup(resp.Body.Close)
+
+			return resp
+		}
+
+		resp := get("s3cret")
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		body, err := io.ReadAll(resp.Body)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(body)).To(Equal("/audio.wav "))
+
+		Expect(get("").StatusCode).To(Equal(http.StatusUnauthorized))
+		Expect(get("wrong").StatusCode).To(Equal(http.StatusUnauthorized))
+	})
+})
+
+var _ = Describe("Tokens", func() {
+	It("skips the hidden files of a mounted Secret", func(ctx SpecContext) {
+		dir := GinkgoT().TempDir()
+		writeFile(dir, ".hidden", []byte("s3cret"))
+		writeFile(dir, "empty", nil)
+
+		_, err := Tokens{Dir: dir}.Authenticate(ctx, "s3cret")
+		Expect(err).To(MatchError(ErrUnauthenticated))
+		_, err = Tokens{Dir: dir}.Authenticate(ctx, "")
+		Expect(err).To(MatchError(ErrUnauthenticated))
+	})
+})
+
+var _ = Describe("TokenReviewer", func() {
+	It("returns the user the API server authenticated", func(ctx SpecContext) {
+		clientset := fake.NewClientset()
+		clientset.PrependReactor("create", "tokenreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			review := action.(k8stesting.CreateAction).GetObject().(*authenticationv1.TokenReview)
+			Expect(review.Spec.Audiences).To(Equal([]string{"receiver.default.svc"}))
+			review.Status.Authenticated = review.Spec.Token == "good"
+			review.Status.User.Username = "system:serviceaccount:default:sdr"
+			review.Status.Audiences = review.Spec.Audiences
+
+			return true, review, nil
+		})
+		reviewer := TokenReviewer{
+			Reviews:   clientset.AuthenticationV1().TokenReviews(),
+			Audiences: []string{"receiver.default.svc"},
+		}
+
+		user, err := reviewer.Authenticate(ctx, "good")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(user).To(Equal("system:serviceaccount:default:sdr"))
+
+		_, err = reviewer.Authenticate(ctx, "bad")
+		Expect(err).To(MatchError(ErrUnauthenticated))
+	})
+
+	It("rejects tokens the API server did not confirm the audience of", func(ctx SpecContext) {
+		audiences := []string{}
+		clientset := fake.NewClientset()
+		clientset.PrependReactor("create", "tokenreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			review := action.(k8stesting.CreateAction).GetObject().(*authenticationv1.TokenReview)
+			review.Status.Authenticated = true
+			review.Status.User.Username = "system:serviceaccount:default:other"
+			review.Status.Audiences = audiences
+
+			return true, review, nil
+		})
+		reviewer := TokenReviewer{
+			Reviews:   clientset.AuthenticationV1().TokenReviews(),
+			Audiences: []string{"receiver.default.svc"},
+		}
+
+		By("rejecting a review without audiences")
+		_, err := reviewer.Authenticate(ctx, "token")
+		Expect(err).To(MatchError(ErrUnauthenticated))
+
+		By("rejecting a review of other audiences")
+		audiences = []string{"https://kubernetes.default.svc"}
+		_, err = reviewer.Authenticate(ctx, "token")
+		Expect(err).To(MatchError(ErrUnauthenticated))
+
+		By("accepting a review of one of the audiences")
+		audiences = []string{"https://kubernetes.default.svc", "receiver.default.svc"}
+		user, err := reviewer.Authenticate(ctx, "token")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(user).To(Equal("system:serviceaccount:default:other"))
+	})
+})
+
+var _ = Describe("Certificates", func() {
+	It("reloads the certificate once it changes", func() {
+		now := time.Now()
+		ca, err := pki.NewCA("test-ca", now, pki.CAValidity)
+		Expect(err).ToNot(HaveOccurred())
+		first, err := ca.Issue(pki.Request{CommonName: "first"}, now, pki.CertificateValidity)
+		Expect(err).ToNot(HaveOccurred())
+		second, err := ca.Issue(pki.Request{CommonName: "second"}, now, pki.CertificateValidity)
+		Expect(err).ToNot(HaveOccurred())
+
+		dir := GinkgoT().TempDir()
+		certs := &Certificates{
+			CertFile: writeFile(dir, "tls.crt", first.CertPEM),
+			KeyFile:  writeFile(dir, "tls.key", first.KeyPEM),
+		}
+		config, err := certs.TLSConfig()
+		Expect(err).ToNot(HaveOccurred())
+
+		served := func() string {
+			c, err := config.GetConfigForClient(nil)
+			Expect(err).ToNot(HaveOccurred())
+			leaf, err := x509.ParseCertificate(c.Certificates[0].Certificate[0])
+			Expect(err).ToNot(HaveOccurred())
+
+			return leaf.Subject.CommonName
+		}
+		Expect(served()).To(Equal("first"))
+
+		writeFile(dir, "tls.crt", second.CertPEM)
+		writeFile(dir, "tls.key", second.KeyPEM)
+		later := now.Add(time.Minute)
+		Expect(os.Chtimes(certs.CertFile, later, later)).To(Succeed())
+		Expect(os.Chtimes(certs.KeyFile, later, later)).To(Succeed())
+		Expect(served()).To(Equal("second"))
+
+		By("serving the last certificate while the files are replaced")
+		Expect(os.Remove(certs.KeyFile)).To(Succeed())
+		Expect(served()).To(Equal("second"))
+	})
+})
@@ -0,0 +1,102 @@
+package access
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"os"
+	"sync"
+	"time"
+)
+
+// Certificates loads the certificate of the server, and the CA client
+// certificates are verified with, from files such as a mounted Secret. The
+// files are reloaded once they change, so renewed certificates are served
+// without a restart.
+type Certificates struct {
+	CertFile string
+	KeyFile  string
+	// ClientCAFile requires clients to present a certificate signed by
+	// the CA in the file when set.
+	ClientCAFile string
+
+	mu      sync.Mutex
+	config  *tls.Config
+	modTime time.Time
+}
+
+// TLSConfig returns the TLS configuration of the server, reloading the
+// certificates for every client as needed.
+func (c *Certificates) TLSConfig() (*tls.Config, error) {
+	if _, err := c.load(); err != nil {
+		return nil, err
+	}
+
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			return c.load()
+		},
+	}, nil
+}
+
+// load returns the configuration of the current certificates, reading them
+// again if any of the files changed since they were last read.
+func (c *Certificates) load() (*tls.Config, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var modTime time.Time
+	for _, name := range []string{c.CertFile, c.KeyFile, c.ClientCAFile} {
+		if name == "" {
+			continue
+		}
+		info, err := os.Stat(name)
+		if err != nil {
+			return c.fallback(err)
+		}
+		if info.ModTime().After(modTime) {
+			modTime = info.ModTime()
+		}
+	}
+	if c.config != nil && modTime.Equal(c.modTime) {
+		return c.config, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+	if err != nil {
+		return c.fallback(err)
+	}
+	config := &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{cert},
+	}
+
+	if c.ClientCAFile != "" {
+		pem, err := os.ReadFile(c.ClientCAFile)
+		if err != nil {
+			return c.fallback(err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return c.fallback(errors.New("access: no client CA certificates found"))
+		}
+		config.ClientCAs = pool
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	c.config, c.modTime = config, modTime
+
+	return config, nil
+}
+
+// fallback keeps serving the certificates loaded before, such as while a
+// Secret is being updated, and returns err if there are none. c.mu must be
+// held.
+func (c *Certificates) fallback(err error) (*tls.Config, error) {
+	if c.config != nil {
+		return c.config, nil
+	}
+
+	return nil, err
+}
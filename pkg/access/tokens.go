@@ -0,0 +1,89 @@
+package access
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	authenticationv1client "k8s.io/client-go/kubernetes/typed/authentication/v1"
+)
+
+// Tokens authenticates the bearer tokens stored in the files of a directory,
+// such as a mounted Secret, each holding the token of the user it is named
+// after. The files are read on every authentication, so rotated tokens are
+// accepted without a restart.
+type Tokens struct {
+	Dir string
+}
+
+// Authenticate returns the name of the file holding token.
+func (t Tokens) Authenticate(_ context.Context, token string) (string, error) {
+	entries, err := os.ReadDir(t.Dir)
+	if err != nil {
+		return "", err
+	}
+
+	for _, entry := range entries {
+		// Skip the hidden directories a mounted Secret is updated through.
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+
+		want, err := os.ReadFile(filepath.Join(t.Dir, entry.Name()))
+		if err != nil {
+			return "", err
+		}
+		want = bytes.TrimSpace(want)
+		if len(want) > 0 && subtle.ConstantTimeCompare(want, []byte(token)) == 1 {
+			return entry.Name(), nil
+		}
+	}
+
+	return "", ErrUnauthenticated
+}
+
+// TokenReviewer authenticates bearer tokens with TokenReviews, accepting the
+// tokens of any user of the cluster issued for one of the audiences.
+type TokenReviewer struct {
+	Reviews authenticationv1client.TokenReviewInterface
+	// Audiences are the audiences tokens must be issued for, defaults to
+	// the audiences of the API server.
+	Audiences []string
+}
+
+// Authenticate returns the user the token was issued to.
+func (r TokenReviewer) Authenticate(ctx context.Context, token string) (string, error) {
+	review, err := r.Reviews.Create(ctx, &authenticationv1.TokenReview{
+		Spec: authenticationv1.TokenReviewSpec{
+			Token:     token,
+			Audiences: r.Audiences,
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return "", err
+	}
+
+	if !review.Status.Authenticated {
+		if review.Status.Error != "" {
+			return "", fmt.Errorf("%w: %s", ErrUnauthenticated, review.Status.Error)
+		}
+		return "", ErrUnauthenticated
+	}
+
+	// Authenticators not supporting audiences return none, which would
+	// accept any token of the cluster.
+	if len(r.Audiences) > 0 && !slices.ContainsFunc(review.Status.Audiences, func(a string) bool {
+		return slices.Contains(r.Audiences, a)
+	}) {
+		return "", fmt.Errorf("%w: token not issued for %s", ErrUnauthenticated, strings.Join(r.Audiences, ", "))
+	}
+
+	return review.Status.User.Username, nil
+}
@@ -0,0 +1,173 @@
+// Package adsb follows the aircraft tracked by a Mode-S decoder such as
+// readsb or dump1090, which periodically write them to aircraft.json, and
+// serves them over HTTP with the rate of decoded messages.
+package adsb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const (
+	// AircraftFile is the name of the file the decoder writes the aircraft
+	// to.
+	AircraftFile = "aircraft.json"
+
+	// DefaultInterval is the default time between reads of the aircraft.
+	DefaultInterval = 5 * time.Second
+
+	// ActiveWindow is how recently an aircraft must have been heard to be
+	// counted.
+	ActiveWindow = 60 * time.Second
+)
+
+// Snapshot is the content of aircraft.json.
+type Snapshot struct {
+	// Now is when the snapshot was written, in seconds since the epoch.
+	Now float64 `json:"now"`
+	// Messages is the number of messages decoded since the decoder started.
+	Messages int64 `json:"messages"`
+	// Aircraft are the aircraft tracked by the decoder.
+	Aircraft []Aircraft `json:"aircraft"`
+}
+
+// Aircraft is an aircraft tracked by the decoder. Only the fields needed to
+// count aircraft are decoded, the file is served as written.
+type Aircraft struct {
+	// Hex is the ICAO address of the aircraft.
+	Hex string `json:"hex"`
+	// Seen is how many seconds ago the aircraft was last heard.
+	Seen float64 `json:"seen"`
+}
+
+// Stats describes the traffic heard by the decoder.
+type Stats struct {
+	// Time is when the decoder wrote the snapshot the stats are based on.
+	Time time.Time `json:"time"`
+	// Messages is the number of messages decoded since the decoder started.
+	Messages int64 `json:"messages"`
+	// MessageRate is the number of messages decoded per second since the
+	// previous snapshot.
+	MessageRate float64 `json:"messageRate"`
+	// Aircraft is the number of aircraft heard within ActiveWindow.
+	Aircraft int `json:"aircraft"`
+}
+
+// Tracker reads the aircraft written by the decoder to Dir.
+type Tracker struct {
+	// Dir is the directory the decoder writes aircraft.json to.
+	Dir string
+	// Interval is the time between reads, defaults to DefaultInterval.
+	Interval time.Duration
+
+	mu    sync.Mutex
+	stats Stats
+	last  *Snapshot
+}
+
+// Stats returns the latest stats.
+func (t *Tracker) Stats() Stats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.stats
+}
+
+// Run reads the aircraft every Interval until ctx is done. The file is
+// missing until the decoder has written it the first time, which is not an
+// error.
+func (t *Tracker) Run(ctx context.Context) error {
+	interval := t.Interval
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		snapshot, err := t.read()
+		switch {
+		case errors.Is(err, os.ErrNotExist):
+		case err != nil:
+			return err
+		default:
+			t.Update(snapshot)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (t *Tracker) read() (*Snapshot, error) {
+	data, err := os.ReadFile(filepath.Join(t.Dir, AircraftFile))
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &Snapshot{}
+	if err := json.Unmarshal(data, snapshot); err != nil {
+		return nil, err
+	}
+
+	return snapshot, nil
+}
+
+// Update computes the stats from a snapshot, and the message rate from the
+// difference to the previous one.
+func (t *Tracker) Update(snapshot *Snapshot) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	stats := Stats{
+		Time:     time.UnixMilli(int64(snapshot.Now * 1000)).UTC(),
+		Messages: snapshot.Messages,
+	}
+	for _, aircraft := range snapshot.Aircraft {
+		if aircraft.Seen < ActiveWindow.Seconds() {
+			stats.Aircraft++
+		}
+	}
+
+	switch last := t.last; {
+	case last == nil || snapshot.Messages < last.Messages:
+		// The decoder restarted, there is nothing to compare with.
+	case snapshot.Now > last.Now:
+		stats.MessageRate = float64(snapshot.Messages-last.Messages) / (snapshot.Now - last.Now)
+	default:
+		// The decoder has not written a new snapshot.
+		stats.MessageRate = t.stats.MessageRate
+	}
+
+	t.stats = stats
+	t.last = snapshot
+}
+
+// Handler serves the aircraft as written by the decoder at
+// /data/aircraft.json, and the stats at /stats.
+func (t *Tracker) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /data/"+AircraftFile, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		http.ServeFile(w, r, filepath.Join(t.Dir, AircraftFile))
+	})
+	mux.HandleFunc("GET /stats", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(t.Stats())
+	})
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	return mux
+}
@@ -0,0 +1,110 @@
+package adsb
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestAdsb(t *testing.T) {
+	RegisterFailHandler(Fail)
+
+	RunSpecs(t, "ADS-B Suite")
+}
+
+// aircraft is a snapshot written by readsb with two aircraft heard recently
+// and one that has gone quiet.
+const aircraft = `{ "now" : 1792411200.0,
+  "messages" : 1500,
+  "aircraft" : [
+    {"hex":"4ca7b5","flight":"RYR12AB ","alt_baro":37000,"seen":0.4,"rssi":-21.3},
+    {"hex":"4aca11","alt_baro":4200,"seen":12.0,"rssi":-30.1},
+    {"hex":"3c6444","seen":120.5,"rssi":-35.0}
+  ]
+}`
+
+func write(dir, data string) {
+	Expect(os.WriteFile(filepath.Join(dir, AircraftFile), []byte(data), 0o644)).To(Succeed())
+}
+
+var _ = Describe("Tracker", func() {
+	var dir string
+	var tracker *Tracker
+
+	BeforeEach(func() {
+		dir = GinkgoT().TempDir()
+		tracker = &Tracker{Dir: dir, Interval: 10 * time.Millisecond}
+	})
+
+	It("counts the aircraft heard recently", func() {
+		snapshot := &Snapshot{}
+		Expect(json.Unmarshal([]byte(aircraft), snapshot)).To(Succeed())
+		tracker.Update(snapshot)
+
+		stats := tracker.Stats()
+		Expect(stats.Time).To(Equal(time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)))
+		Expect(stats.Messages).To(Equal(int64(1500)))
+		Expect(stats.Aircraft).To(Equal(2))
+		Expect(stats.MessageRate).To(BeZero())
+	})
+
+	It("computes the message rate between snapshots", func() {
+		tracker.Update(&Snapshot{Now: 100, Messages: 1000})
+		tracker.Update(&Snapshot{Now: 105, Messages: 1500})
+		Expect(tracker.Stats().MessageRate).To(Equal(100.0))
+
+		By("keeping the rate while the snapshot is unchanged")
+		tracker.Update(&Snapshot{Now: 105, Messages: 1500})
+		Expect(tracker.Stats().MessageRate).To(Equal(100.0))
+
+		By("starting over when the decoder restarts")
+		tracker.Update(&Snapshot{Now: 110, Messages: 20})
+		Expect(tracker.Stats().MessageRate).To(BeZero())
+	})
+
+	It("reads the snapshots the decoder writes", func(ctx SpecContext) {
+		done := make(chan error)
+		go func() { done <- tracker.Run(ctx) }()
+
+		Consistently(tracker.Stats).Within(50 * time.Millisecond).Should(BeZero())
+
+		write(dir, aircraft)
+		Eventually(func() int { return tracker.Stats().Aircraft }).Should(Equal(2))
+
+		By("failing on files that are not aircraft")
+		write(dir, "not json")
+		Eventually(done).Should(Receive(HaveOccurred()))
+	})
+
+	It("serves the aircraft and the stats", func() {
+		write(dir, aircraft)
+		tracker.Update(&Snapshot{Now: 100, Messages: 1000, Aircraft: []Aircraft{{Hex: "4ca7b5"}}})
+
+		server := httptest.NewServer(tracker.Handler())
+		DeferCleanup(server.Close)
+
+		resp, err := http.Get(server.URL + "/data/aircraft.json")
+		Expect(err).ToNot(HaveOccurred())
+		body, err := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resp.Header.Get("Content-Type")).To(Equal("application/json"))
+		Expect(string(body)).To(Equal(aircraft))
+
+		resp, err = http.Get(server.URL + "/stats")
+		Expect(err).ToNot(HaveOccurred())
+		defer func() { _ = resp.Body.Close() }()
+		stats := Stats{}
+		Expect(json.NewDecoder(resp.Body).Decode(&stats)).To(Succeed())
+		Expect(stats.Messages).To(Equal(int64(1000)))
+		Expect(stats.Aircraft).To(Equal(1))
+	})
+})
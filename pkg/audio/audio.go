@@ -0,0 +1,205 @@
+// Package audio streams raw PCM audio to HTTP clients.
+package audio
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"sync"
+)
+
+const (
+	// chunkSize is the number of bytes read from the source at a time.
+	chunkSize = 4096
+
+	// clientBuffer is the number of chunks buffered per client before it
+	// is considered too slow and dropped.
+	clientBuffer = 64
+)
+
+// Format describes raw little-endian PCM audio.
+type Format struct {
+	SampleRate    uint32
+	Channels      uint16
+	BitsPerSample uint16
+}
+
+// BlockAlign returns the size in bytes of one sample for all channels.
+func (f Format) BlockAlign() int {
+	return int(f.Channels) * int(f.BitsPerSample) / 8
+}
+
+// WAVHeader returns a WAV header for a stream of unknown length. The sizes are
+// set to their maximum so players keep reading until the connection closes.
+func WAVHeader(f Format) []byte {
+	const unknownSize = 0xFFFFFFFF
+
+	header := make([]byte, 44)
+	copy(header[0:], "RIFF")
+	binary.LittleEndian.PutUint32(header[4:], unknownSize)
+	copy(header[8:], "WAVE")
+	copy(header[12:], "fmt ")
+	binary.LittleEndian.PutUint32(header[16:], 16)
+	binary.LittleEndian.PutUint16(header[20:], 1) // PCM
+	binary.LittleEndian.PutUint16(header[22:], f.Channels)
+	binary.LittleEndian.PutUint32(header[24:], f.SampleRate)
+	binary.LittleEndian.PutUint32(header[28:], f.SampleRate*uint32(f.BlockAlign()))
+	binary.LittleEndian.PutUint16(header[32:], uint16(f.BlockAlign()))
+	binary.LittleEndian.PutUint16(header[34:], f.BitsPerSample)
+	copy(header[36:], "data")
+	binary.LittleEndian.PutUint32(header[40:], unknownSize)
+
+	return header
+}
+
+// Broadcaster fans out a PCM stream to any number of HTTP clients. Clients
+// that cannot keep up are dropped instead of stalling the others.
+type Broadcaster struct {
+	format Format
+
+	mu      sync.Mutex
+	clients map[chan []byte]struct{}
+	closed  bool
+}
+
+// NewBroadcaster returns a Broadcaster of audio in the given format without
+// any clients.
+func NewBroadcaster(format Format) *Broadcaster {
+	return &Broadcaster{
+		format:  format,
+		clients: make(map[chan []byte]struct{}),
+	}
+}
+
+// Clients returns the number of connected clients.
+func (b *Broadcaster) Clients() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return len(b.clients)
+}
+
+// Run copies audio from r to all clients until r is exhausted. Chunks are
+// always a multiple of the block alignment, so clients never start in the
+// middle of a sample.
+func (b *Broadcaster) Run(r io.Reader) error {
+	defer b.close()
+
+	align := b.format.BlockAlign()
+	if align <= 0 {
+		return errors.New("invalid audio format")
+	}
+
+	buf := make([]byte, chunkSize)
+	pending := 0
+	for {
+		n, err := r.Read(buf[pending:])
+		pending += n
+
+		if whole := pending - pending%align; whole > 0 {
+			chunk := make([]byte, whole)
+			copy(chunk, buf[:whole])
+			b.broadcast(chunk)
+
+			pending = copy(buf, buf[whole:pending])
+		}
+
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+func (b *Broadcaster) broadcast(chunk []byte) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for c := range b.clients {
+		select {
+		case c <- chunk:
+		default:
+			slog.Info("Dropping slow client")
+			delete(b.clients, c)
+			close(c)
+		}
+	}
+}
+
+func (b *Broadcaster) subscribe() (chan []byte, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return nil, false
+	}
+
+	c := make(chan []byte, clientBuffer)
+	b.clients[c] = struct{}{}
+
+	return c, true
+}
+
+func (b *Broadcaster) unsubscribe(c chan []byte) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, ok := b.clients[c]; ok {
+		delete(b.clients, c)
+		close(c)
+	}
+}
+
+func (b *Broadcaster) close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	for c := range b.clients {
+		delete(b.clients, c)
+		close(c)
+	}
+}
+
+// ServeHTTP streams the audio as WAV until the client disconnects or the
+// source ends.
+func (b *Broadcaster) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c, ok := b.subscribe()
+	if !ok {
+		http.Error(w, "audio stream has ended", http.StatusServiceUnavailable)
+		return
+	}
+	defer b.unsubscribe(c)
+
+	w.Header().Set("Content-Type", "audio/wav")
+	w.Header().Set("Cache-Control", "no-cache")
+
+	flusher, _ := w.(http.Flusher)
+
+	if _, err := w.Write(WAVHeader(b.format)); err != nil {
+		return
+	}
+
+	for {
+		if flusher != nil {
+			flusher.Flush()
+		}
+
+		select {
+		case <-r.Context().Done():
+			return
+		case chunk, ok := <-c:
+			if !ok {
+				return
+			}
+
+			if _, err := w.Write(chunk); err != nil {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package audio
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestAudio(t *testing.T) {
+	RegisterFailHandler(Fail)
+
+	RunSpecs(t, "Audio Suite")
+}
+
+var _ = Describe("Audio", func() {
+	format := Format{SampleRate: 16000, Channels: 1, BitsPerSample: 16}
+
+	It("writes a streaming WAV header", func() {
+		header := WAVHeader(format)
+		Expect(header).To(HaveLen(44))
+		Expect(string(header[0:4])).To(Equal("RIFF"))
+		Expect(string(header[8:12])).To(Equal("WAVE"))
+		Expect(binary.LittleEndian.Uint32(header[24:])).To(Equal(uint32(16000)))
+		Expect(binary.LittleEndian.Uint32(header[28:])).To(Equal(uint32(32000)))
+		Expect(binary.LittleEndian.Uint16(header[34:])).To(Equal(uint16(16)))
+		Expect(binary.LittleEndian.Uint32(header[40:])).To(Equal(uint32(0xFFFFFFFF)))
+	})
+
+	It("streams audio to HTTP clients", func(ctx SpecContext) {
+		b := NewBroadcaster(format)
+		server := httptest.NewServer(b)
+		defer server.Close()
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+		Expect(err).ToNot(HaveOccurred())
+		resp, err := http.DefaultClient.Do(req)
+		Expect(err).ToNot(HaveOccurred())
+		defer resp.Body.Close()
+
+		Expect(resp.Header.Get("Content-Type")).To(Equal("audio/wav"))
+		Eventually(b.Clients).Should(Equal(1))
+
+		source, sink := io.Pipe()
+		go func() {
+			defer GinkgoRecover()
+			Expect(b.Run(source)).To(Succeed())
+		}()
+
+		// An odd write must not be split mid-sample.
+		_, err = sink.Write([]byte{1, 2, 3})
+		Expect(err).ToNot(HaveOccurred())
+		_, err = sink.Write([]byte{4})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(sink.Close()).To(Succeed())
+
+		body, err := io.ReadAll(resp.Body)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(body[:44]).To(Equal(WAVHeader(format)))
+		Expect(body[44:]).To(Equal([]byte{1, 2, 3, 4}))
+	})
+
+	It("drops clients that cannot keep up", func() {
+		b := NewBroadcaster(format)
+		c, ok := b.subscribe()
+		Expect(ok).To(BeTrue())
+
+		data := bytes.Repeat([]byte{0}, chunkSize*(clientBuffer+1))
+		Expect(b.Run(bytes.NewReader(data))).To(Succeed())
+
+		Expect(b.Clients()).To(BeZero())
+		Expect(c).To(HaveLen(clientBuffer))
+		_, ok = b.subscribe()
+		Expect(ok).To(BeFalse())
+	})
+})
@@ -0,0 +1,163 @@
+// Package dsp contains the signal processing used to split a wideband I/Q
+// stream into narrowband channels.
+package dsp
+
+import (
+	"math"
+)
+
+// tapsPerDecimation is the length of the low-pass filter per unit of
+// decimation. Longer filters have a sharper transition band.
+const tapsPerDecimation = 8
+
+// DDC is a digital down-converter. It shifts a channel at an offset from the
+// center of a wideband stream down to zero, low-pass filters it and decimates
+// it. Input and output are interleaved unsigned 8-bit I/Q samples like
+// rtl_tcp produces.
+type DDC struct {
+	decimation int
+	taps       []float32
+
+	// step and phase are the per sample rotation and the current phase of
+	// the oscillator mixing the channel down to zero.
+	step  float64
+	phase float64
+
+	// history holds the mixed samples still needed by the filter, and skip
+	// is the number of samples to consume before the next output.
+	history []complex64
+	skip    int
+}
+
+// NewDDC returns a down-converter for a channel at offset Hz from the center
+// of a stream sampled at sampleRate Hz. The channel is filtered to bandwidth
+// Hz and decimated by decimation.
+func NewDDC(sampleRate, offset, bandwidth float64, decimation int) *DDC {
+	decimation = max(decimation, 1)
+
+	return &DDC{
+		decimation: decimation,
+		taps:       LowPass(bandwidth/2/sampleRate, tapsPerDecimation*decimation+1),
+		step:       -2 * math.Pi * offset / sampleRate,
+	}
+}
+
+// Decimation returns the decimation factor.
+func (d *DDC) Decimation() int {
+	return d.decimation
+}
+
+// Process converts the samples in src and appends the output to dst. An odd
+// trailing byte in src is ignored.
+func (d *DDC) Process(dst, src []byte) []byte {
+	for i := 0; i+1 < len(src); i += 2 {
+		s := complex(toFloat(src[i]), toFloat(src[i+1]))
+
+		sin, cos := math.Sincos(d.phase)
+		d.history = append(d.history, s*complex(float32(cos), float32(sin)))
+
+		d.phase = math.Remainder(d.phase+d.step, 2*math.Pi)
+	}
+
+	for {
+		if d.skip > 0 {
+			n := min(d.skip, len(d.history))
+			d.history = d.history[n:]
+			d.skip -= n
+			if d.skip > 0 {
+				break
+			}
+		}
+
+		if len(d.history) < len(d.taps) {
+			break
+		}
+
+		var acc complex64
+		for k, t := range d.taps {
+			acc += d.history[k] * complex(t, 0)
+		}
+
+		dst = append(dst, fromFloat(real(acc)), fromFloat(imag(acc)))
+		d.skip = d.decimation
+	}
+
+	// Move the remaining samples to the front so the history does not grow
+	// without bounds.
+	d.history = append(d.history[:0:0], d.history...)
+
+	return dst
+}
+
+// LowPass returns a windowed-sinc low-pass filter with n taps and unity gain
+// at zero. cutoff is relative to the sample rate.
+func LowPass(cutoff float64, n int) []float32 {
+	taps := make([]float32, n)
+	mid := float64(n-1) / 2
+
+	var sum float64
+	for i := range taps {
+		x := float64(i) - mid
+
+		h := 2 * cutoff
+		if x != 0 {
+			h = math.Sin(2*math.Pi*cutoff*x) / (math.Pi * x)
+		}
+
+		// Blackman window
+		if n > 1 {
+			w := 0.42 - 0.5*math.Cos(2*math.Pi*float64(i)/float64(n-1)) + 0.08*math.Cos(4*math.Pi*float64(i)/float64(n-1))
+			h *= w
+		}
+
+		taps[i] = float32(h)
+		sum += h
+	}
+
+	for i := range taps {
+		taps[i] = float32(float64(taps[i]) / sum)
+	}
+
+	return taps
+}
+
+// toFloat converts an unsigned 8-bit sample to the range [-1, 1].
+func toFloat(b byte) float32 {
+	return (float32(b) - 127.5) / 127.5
+}
+
+// fromFloat converts a sample in the range [-1, 1] to unsigned 8 bits.
+func fromFloat(f float32) byte {
+	v := math.Round(float64(f)*127.5 + 127.5)
+	return byte(min(max(v, 0), 255))
+}
+
+// MinDBFS is the lowest power returned by DBFS, standing in for silence.
+const MinDBFS = -150
+
+// Power returns the mean power of the unsigned 8-bit I/Q samples in src
+// relative to full scale. An odd trailing byte is ignored.
+func Power(src []byte) float64 {
+	n := len(src) / 2
+	if n == 0 {
+		return 0
+	}
+
+	var sum float64
+	for i := 0; i+1 < len(src); i += 2 {
+		re, im := float64(toFloat(src[i])), float64(toFloat(src[i+1]))
+		sum += re*re + im*im
+	}
+
+	return sum / float64(n)
+}
+
+// DBFS converts a power relative to full scale to decibels, no lower than
+// MinDBFS.
+func DBFS(power float64) float64 {
+	if power <= 0 {
+		return MinDBFS
+	}
+
+	return max(10*math.Log10(power), MinDBFS)
+}
@@ -0,0 +1,126 @@
+package dsp
+
+import (
+	"math"
+	"math/cmplx"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestDsp(t *testing.T) {
+	RegisterFailHandler(Fail)
+
+	RunSpecs(t, "DSP Suite")
+}
+
+const sampleRate = 2_048_000
+
+// tone returns n unsigned 8-bit I/Q samples of a tone at freq Hz.
+func tone(freq float64, n int) []byte {
+	buf := make([]byte, 2*n)
+	for i := range n {
+		phase := 2 * math.Pi * freq * float64(i) / sampleRate
+		buf[2*i] = fromFloat(float32(0.5 * math.Cos(phase)))
+		buf[2*i+1] = fromFloat(float32(0.5 * math.Sin(phase)))
+	}
+
+	return buf
+}
+
+var _ = Describe("DDC", func() {
+	It("decimates the stream", func() {
+		ddc := NewDDC(sampleRate, 200_000, 50_000, 32)
+		out := ddc.Process(nil, tone(200_000, 32*1000))
+
+		Expect(len(out) / 2).To(BeNumerically("~", 1000, 10))
+	})
+
+	It("keeps the channel at its offset", func() {
+		ddc := NewDDC(sampleRate, 200_000, 50_000, 32)
+		out := ddc.Process(nil, tone(200_000, 32*1000))
+
+		Expect(Power(out)).To(BeNumerically("~", 0.25, 0.02))
+	})
+
+	It("rejects signals outside the channel", func() {
+		ddc := NewDDC(sampleRate, 200_000, 50_000, 32)
+		out := ddc.Process(nil, tone(400_000, 32*1000))
+
+		Expect(Power(out)).To(BeNumerically("<", 0.001))
+	})
+
+	It("gives the same output regardless of how the input is split", func() {
+		in := tone(210_000, 32*100)
+
+		whole := NewDDC(sampleRate, 200_000, 50_000, 32).Process(nil, in)
+
+		split := NewDDC(sampleRate, 200_000, 50_000, 32)
+		var out []byte
+		for chunk := range sliceChunks(in, 334) {
+			out = split.Process(out, chunk)
+		}
+
+		Expect(out).To(Equal(whole))
+	})
+})
+
+var _ = Describe("Power", func() {
+	It("measures the power relative to full scale", func() {
+		Expect(Power(tone(100_000, 1000))).To(BeNumerically("~", 0.25, 0.01))
+		Expect(DBFS(Power(tone(100_000, 1000)))).To(BeNumerically("~", -6, 0.2))
+	})
+
+	It("has a floor for silence", func() {
+		Expect(DBFS(0)).To(Equal(float64(MinDBFS)))
+	})
+})
+
+// sliceChunks splits b into chunks of at most n bytes.
+func sliceChunks(b []byte, n int) func(func([]byte) bool) {
+	return func(yield func([]byte) bool) {
+		for len(b) > 0 {
+			c := b[:min(n, len(b))]
+			b = b[len(c):]
+			if !yield(c) {
+				return
+			}
+		}
+	}
+}
+
+var _ = Describe("FFT", func() {
+	It("puts a tone in its bin", func() {
+		x := Complex(nil, tone(sampleRate/8, 64))
+		FFT(x)
+
+		peak := 0
+		for i := range x {
+			if cmplx.Abs(x[i]) > cmplx.Abs(x[peak]) {
+				peak = i
+			}
+		}
+		Expect(peak).To(Equal(8))
+		Expect(cmplx.Abs(x[peak]) / 64).To(BeNumerically("~", 0.5, 0.01))
+	})
+
+	It("matches the discrete Fourier transform", func() {
+		x := Complex(nil, tone(300_000, 16))
+		want := make([]complex128, len(x))
+		for k := range want {
+			for n, v := range x {
+				want[k] += v * cmplx.Exp(complex(0, -2*math.Pi*float64(k*n)/float64(len(x))))
+			}
+		}
+
+		FFT(x)
+		for k := range x {
+			Expect(cmplx.Abs(x[k] - want[k])).To(BeNumerically("<", 1e-9))
+		}
+	})
+
+	It("rejects lengths that are not a power of two", func() {
+		Expect(func() { FFT(make([]complex128, 12)) }).To(Panic())
+	})
+})
@@ -0,0 +1,61 @@
+package dsp
+
+import (
+	"math"
+	"math/bits"
+	"math/cmplx"
+)
+
+// FFT replaces x with its discrete Fourier transform. The length of x must
+// be a power of two.
+func FFT(x []complex128) {
+	n := len(x)
+	if n <= 1 {
+		return
+	}
+	if n&(n-1) != 0 {
+		panic("dsp: FFT length is not a power of two")
+	}
+
+	// Bit-reversal permutation.
+	shift := bits.UintSize - bits.TrailingZeros(uint(n))
+	for i := range n {
+		if j := int(bits.Reverse(uint(i)) >> shift); j > i {
+			x[i], x[j] = x[j], x[i]
+		}
+	}
+
+	// Iterative radix-2 butterflies.
+	for size := 2; size <= n; size <<= 1 {
+		step := cmplx.Exp(complex(0, -2*math.Pi/float64(size)))
+		for start := 0; start < n; start += size {
+			w := complex(1, 0)
+			for k := range size / 2 {
+				a, b := x[start+k], w*x[start+k+size/2]
+				x[start+k], x[start+k+size/2] = a+b, a-b
+				w *= step
+			}
+		}
+	}
+}
+
+// Hann returns a Hann window of n points, which tapers blocks of samples to
+// reduce spectral leakage in their FFT.
+func Hann(n int) []float64 {
+	window := make([]float64, n)
+	for i := range window {
+		window[i] = 0.5 - 0.5*math.Cos(2*math.Pi*float64(i)/float64(n))
+	}
+
+	return window
+}
+
+// Complex converts unsigned 8-bit I/Q samples to complex numbers in the
+// range [-1, 1], appending them to dst. An odd trailing byte is ignored.
+func Complex(dst []complex128, src []byte) []complex128 {
+	for i := 0; i+1 < len(src); i += 2 {
+		dst = append(dst, complex(float64(toFloat(src[i])), float64(toFloat(src[i+1]))))
+	}
+
+	return dst
+}
@@ -0,0 +1,140 @@
+// Package events normalises the JSON messages written by decoders such as
+// AIS-catcher and rtl_433 into events, and publishes them to a sink.
+package events
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Source is the decoder an event came from.
+type Source string
+
+const (
+	// SourceAIS is AIS-catcher decoding maritime AIS messages.
+	SourceAIS Source = "ais"
+	// SourceISM is rtl_433 decoding sensors in the ISM bands.
+	SourceISM Source = "ism"
+)
+
+// ParseSource parses the name of a source.
+func ParseSource(s string) (Source, error) {
+	switch source := Source(strings.ToLower(s)); source {
+	case SourceAIS, SourceISM:
+		return source, nil
+	default:
+		return "", fmt.Errorf("events: unknown source %q, must be ais or ism", s)
+	}
+}
+
+// Event is a message decoded by a receiver, in the same shape whatever the
+// decoder.
+type Event struct {
+	// Time is when the message was received.
+	Time time.Time `json:"time"`
+	// Source is the decoder of the message.
+	Source Source `json:"source"`
+	// Receiver is the namespace and name of the receiver.
+	Receiver string `json:"receiver,omitempty"`
+	// Type is the kind of message: the AIS message type, or the rtl_433
+	// model of the sensor.
+	Type string `json:"type"`
+	// ID identifies the sender: the MMSI of a vessel, or the id of a
+	// sensor.
+	ID string `json:"id,omitempty"`
+	// Data is the message as decoded.
+	Data map[string]any `json:"data"`
+}
+
+// timeFormats are the formats decoders write the time of a message in.
+var timeFormats = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"20060102150405",
+}
+
+// Normalize turns a JSON message written by the decoder into an event. The
+// time of the message defaults to now when the decoder did not write one.
+func Normalize(source Source, line []byte, now time.Time) (Event, error) {
+	data := map[string]any{}
+	if err := json.Unmarshal(line, &data); err != nil {
+		return Event{}, err
+	}
+
+	event := Event{Time: now.UTC(), Source: source, Data: data}
+
+	timeField, typeField, idField := "time", "model", "id"
+	if source == SourceAIS {
+		timeField, typeField, idField = "rxtime", "type", "mmsi"
+	}
+
+	if s, ok := data[timeField].(string); ok {
+		for _, format := range timeFormats {
+			if t, err := time.ParseInLocation(format, s, time.UTC); err == nil {
+				event.Time = t.UTC()
+				break
+			}
+		}
+	}
+	event.Type = field(data[typeField])
+	event.ID = field(data[idField])
+
+	if event.Type == "" {
+		return Event{}, fmt.Errorf("events: message has no %s", typeField)
+	}
+
+	return event, nil
+}
+
+// field formats a JSON value as a string. Numbers are integers in practice,
+// MMSIs and sensor ids.
+func field(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return ""
+	}
+}
+
+// Publish reads the JSON lines written by the decoder from r and publishes
+// them to the sink as events until r ends or ctx is done. Lines that are not
+// messages, and events the sink fails to take, are logged and skipped so
+// that the decoder is never held up.
+func Publish(ctx context.Context, r io.Reader, source Source, receiver string, sink Sink) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(strings.TrimSpace(string(line))) == 0 {
+			continue
+		}
+
+		event, err := Normalize(source, line, time.Now())
+		if err != nil {
+			slog.Info("Skipping decoder output", slog.String("line", string(line)), slog.Any("error", err))
+			continue
+		}
+		event.Receiver = receiver
+
+		if err := sink.Publish(ctx, event); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			slog.Error("Failed publishing event", slog.String("type", event.Type), slog.Any("error", err))
+		}
+	}
+
+	return scanner.Err()
+}
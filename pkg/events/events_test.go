@@ -0,0 +1,208 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/frelon/k8s-radio/pkg/mqtttest"
+)
+
+func TestEvents(t *testing.T) {
+	RegisterFailHandler(Fail)
+
+	RunSpecs(t, "Events Suite")
+}
+
+var now = time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+
+// aisLine is a position report as written by AIS-catcher with -o 5.
+const aisLine = `{"class":"AIS","device":"AIS-catcher","rxtime":"20261019115959","channel":"A","type":1,"repeat":0,"mmsi":265547250,"status":0,"lon":11.8325,"lat":57.6961,"speed":12.3}`
+
+// ismLine is a weather sensor as written by rtl_433 with -F json.
+const ismLine = `{"time":"2026-10-19 11:59:58","model":"Nexus-TH","id":187,"channel":1,"battery_ok":1,"temperature_C":14.2,"humidity":71}`
+
+var _ = Describe("Normalize", func() {
+	It("normalises AIS messages", func() {
+		event, err := Normalize(SourceAIS, []byte(aisLine), now)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(event.Time).To(Equal(time.Date(2026, 10, 19, 11, 59, 59, 0, time.UTC)))
+		Expect(event.Source).To(Equal(SourceAIS))
+		Expect(event.Type).To(Equal("1"))
+		Expect(event.ID).To(Equal("265547250"))
+		Expect(event.Data).To(HaveKeyWithValue("lat", 57.6961))
+	})
+
+	It("normalises rtl_433 messages", func() {
+		event, err := Normalize(SourceISM, []byte(ismLine), now)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(event.Time).To(Equal(time.Date(2026, 10, 19, 11, 59, 58, 0, time.UTC)))
+		Expect(event.Type).To(Equal("Nexus-TH"))
+		Expect(event.ID).To(Equal("187"))
+		Expect(event.Data).To(HaveKeyWithValue("temperature_C", 14.2))
+	})
+
+	It("defaults the time to now", func() {
+		event, err := Normalize(SourceISM, []byte(`{"model":"Acurite-Tower","id":"A"}`), now)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(event.Time).To(Equal(now))
+		Expect(event.ID).To(Equal("A"))
+	})
+
+	It("rejects lines that are not messages", func() {
+		_, err := Normalize(SourceISM, []byte(`rtl_433 version 23.11`), now)
+		Expect(err).To(HaveOccurred())
+
+		_, err = Normalize(SourceISM, []byte(`{"time":"2026-10-19 11:59:58"}`), now)
+		Expect(err).To(MatchError(ContainSubstring("no model")))
+	})
+
+	It("parses sources", func() {
+		Expect(ParseSource("AIS")).To(Equal(SourceAIS))
+		_, err := ParseSource("adsb")
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("Publish", func() {
+	It("publishes the messages and skips the rest", func(ctx SpecContext) {
+		var out bytes.Buffer
+		input := strings.Join([]string{"Found Rafael Micro R828D tuner", ismLine, "", ismLine}, "\n")
+
+		Expect(Publish(ctx, strings.NewReader(input), SourceISM, "radio/weather", &WriterSink{W: &out})).To(Succeed())
+
+		lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+		Expect(lines).To(HaveLen(2))
+
+		var event Event
+		Expect(json.Unmarshal([]byte(lines[0]), &event)).To(Succeed())
+		Expect(event.Receiver).To(Equal("radio/weather"))
+		Expect(event.Type).To(Equal("Nexus-TH"))
+	})
+})
+
+var _ = Describe("Sinks", func() {
+	It("writes to stdout by default", func() {
+		sink, err := NewSink("", "", "test")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(sink).To(BeAssignableToTypeOf(&WriterSink{}))
+
+		_, err = NewSink("ftp://example.com", "", "test")
+		Expect(err).To(MatchError(ContainSubstring("unsupported sink")))
+	})
+
+	It("posts events to a webhook", func(ctx SpecContext) {
+		var mu sync.Mutex
+		var received []Event
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer GinkgoRecover()
+			Expect(r.Method).To(Equal(http.MethodPost))
+			Expect(r.Header.Get("Content-Type")).To(Equal("application/json"))
+
+			var event Event
+			Expect(json.NewDecoder(r.Body).Decode(&event)).To(Succeed())
+			mu.Lock()
+			received = append(received, event)
+			mu.Unlock()
+		}))
+		DeferCleanup(server.Close)
+
+		sink, err := NewSink(server.URL+"/hook", "", "test")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(sink.Publish(ctx, Event{Source: SourceAIS, Type: "1"})).To(Succeed())
+
+		mu.Lock()
+		defer mu.Unlock()
+		Expect(received).To(ConsistOf(HaveField("Type", "1")))
+	})
+
+	It("fails when the webhook rejects the event", func(ctx SpecContext) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+		}))
+		DeferCleanup(server.Close)
+
+		sink := &WebhookSink{URL: server.URL}
+		Expect(sink.Publish(ctx, Event{})).To(MatchError(ContainSubstring("400")))
+	})
+
+	It("publishes events to an MQTT broker", func(ctx SpecContext) {
+		broker, err := mqtttest.NewServer()
+		Expect(err).ToNot(HaveOccurred())
+		DeferCleanup(broker.Close)
+
+		u, err := url.Parse(broker.URL)
+		Expect(err).ToNot(HaveOccurred())
+		u.Scheme = "mqtt"
+		u.User = url.UserPassword("radio", "secret")
+
+		sink, err := NewSink(u.String(), "radio/default/ais/events", "test-client")
+		Expect(err).ToNot(HaveOccurred())
+		DeferCleanup(sink.Close)
+
+		Expect(sink.Publish(ctx, Event{Source: SourceAIS, Type: "1", ID: "265547250"})).To(Succeed())
+
+		Eventually(broker.Messages).Should(HaveLen(1))
+		msg := broker.Messages()[0]
+		Expect(msg.Topic).To(Equal("radio/default/ais/events"))
+		Expect(msg.QoS).To(Equal(byte(1)))
+
+		var event Event
+		Expect(json.Unmarshal(msg.Payload, &event)).To(Succeed())
+		Expect(event.ID).To(Equal("265547250"))
+
+		Expect(broker.Clients()).To(ConsistOf(mqtttest.Client{ID: "test-client", Username: "radio", Password: "secret"}))
+	})
+
+	It("needs a topic to publish to MQTT", func() {
+		_, err := NewSink("mqtt://broker:1883", "", "test")
+		Expect(err).To(MatchError(ContainSubstring("topic")))
+	})
+
+	It("gives up publishing when the context is done", func() {
+		// Nothing listens on the port, so the sink never connects.
+		sink, err := NewSink("mqtt://127.0.0.1:1", "events", "test")
+		Expect(err).ToNot(HaveOccurred())
+		DeferCleanup(sink.Close)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		Expect(sink.Publish(ctx, Event{Type: "1"})).To(MatchError(context.DeadlineExceeded))
+	})
+})
+
+var _ = Describe("RecentSink", func() {
+	It("serves the most recent events, newest first", func(ctx SpecContext) {
+		recent := &RecentSink{Max: 2}
+		var out bytes.Buffer
+		sink := MultiSink{recent, &WriterSink{W: &out}}
+
+		for _, id := range []string{"1", "2", "3"} {
+			Expect(sink.Publish(ctx, Event{Type: "Nexus-TH", ID: id})).To(Succeed())
+		}
+		Expect(strings.Count(out.String(), "\n")).To(Equal(3))
+
+		server := httptest.NewServer(recent)
+		DeferCleanup(server.Close)
+
+		resp, err := http.Get(server.URL)
+		Expect(err).ToNot(HaveOccurred())
+		defer func() { _ = resp.Body.Close() }()
+
+		var events []Event
+		Expect(json.NewDecoder(resp.Body).Decode(&events)).To(Succeed())
+		Expect(events).To(HaveLen(2))
+		Expect(events[0].ID).To(Equal("3"))
+		Expect(events[1].ID).To(Equal("2"))
+	})
+})
@@ -0,0 +1,220 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/frelon/k8s-radio/pkg/mqttpub"
+)
+
+const (
+	// StdoutSink is the sink writing events to standard output.
+	StdoutSink = "stdout"
+
+	// publishTimeout bounds how long publishing an event may take.
+	publishTimeout = 10 * time.Second
+)
+
+// Sink is where events are published.
+type Sink interface {
+	// Publish publishes the event.
+	Publish(ctx context.Context, event Event) error
+	// Close releases the resources of the sink.
+	Close() error
+}
+
+// NewSink returns the sink for the URL: "stdout" writes JSON lines to
+// standard output, an http or https URL posts every event to a webhook, and
+// an mqtt, mqtts or tcp URL publishes every event to topic on a broker.
+func NewSink(sink, topic, clientID string) (Sink, error) {
+	if sink == StdoutSink || sink == "" {
+		return &WriterSink{W: os.Stdout}, nil
+	}
+
+	u, err := url.Parse(sink)
+	if err != nil {
+		return nil, err
+	}
+
+	switch u.Scheme {
+	case "http", "https":
+		return &WebhookSink{URL: sink}, nil
+	case "mqtt", "mqtts", "tcp", "ssl":
+		return NewMQTTSink(u, topic, clientID)
+	default:
+		return nil, fmt.Errorf("events: unsupported sink %q", sink)
+	}
+}
+
+// WriterSink writes events to W as JSON lines.
+type WriterSink struct {
+	W io.Writer
+
+	mu sync.Mutex
+}
+
+func (s *WriterSink) Publish(_ context.Context, event Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return json.NewEncoder(s.W).Encode(event)
+}
+
+func (s *WriterSink) Close() error {
+	return nil
+}
+
+// WebhookSink posts every event to URL as JSON.
+type WebhookSink struct {
+	URL string
+	// Client defaults to http.DefaultClient.
+	Client *http.Client
+}
+
+func (s *WebhookSink) Publish(ctx context.Context, event Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, publishTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("events: webhook answered %s", resp.Status)
+	}
+
+	return nil
+}
+
+func (s *WebhookSink) Close() error {
+	return nil
+}
+
+// MQTTSink publishes every event to a topic on an MQTT broker with QoS 1.
+type MQTTSink struct {
+	Topic string
+
+	publisher *mqttpub.Publisher
+}
+
+// NewMQTTSink connects to the broker at u, which may carry a username and
+// password, retrying in the background until it is reachable.
+func NewMQTTSink(u *url.URL, topic, clientID string) (*MQTTSink, error) {
+	if topic == "" {
+		return nil, fmt.Errorf("events: MQTT sink %s needs a topic", u.Redacted())
+	}
+
+	publisher, err := mqttpub.New(mqttpub.Config{Broker: u, ClientID: clientID, QoS: 1})
+	if err != nil {
+		return nil, err
+	}
+
+	return &MQTTSink{Topic: topic, publisher: publisher}, nil
+}
+
+func (s *MQTTSink) Publish(ctx context.Context, event Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	return s.publisher.Publish(ctx, mqttpub.Message{Topic: s.Topic, Payload: payload})
+}
+
+func (s *MQTTSink) Close() error {
+	return s.publisher.Close()
+}
+
+// DefaultRecent is the default number of events kept by a RecentSink.
+const DefaultRecent = 100
+
+// RecentSink keeps the most recent events and serves them as JSON, newest
+// first.
+type RecentSink struct {
+	// Max is the number of events kept, defaults to DefaultRecent.
+	Max int
+
+	mu     sync.Mutex
+	events []Event
+}
+
+func (s *RecentSink) Publish(_ context.Context, event Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	limit := s.Max
+	if limit <= 0 {
+		limit = DefaultRecent
+	}
+
+	recent := append([]Event{event}, s.events...)
+	s.events = recent[:min(len(recent), limit)]
+
+	return nil
+}
+
+func (s *RecentSink) Close() error {
+	return nil
+}
+
+// Events returns the events kept, newest first.
+func (s *RecentSink) Events() []Event {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Event{}, s.events...)
+}
+
+func (s *RecentSink) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(s.Events())
+}
+
+// MultiSink publishes every event to all of its sinks.
+type MultiSink []Sink
+
+func (m MultiSink) Publish(ctx context.Context, event Event) error {
+	var errs []error
+	for _, sink := range m {
+		errs = append(errs, sink.Publish(ctx, event))
+	}
+
+	return errors.Join(errs...)
+}
+
+func (m MultiSink) Close() error {
+	var errs []error
+	for _, sink := range m {
+		errs = append(errs, sink.Close())
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,221 @@
+// Package exporter measures the signal level, noise floor, SNR and clipping
+// of an I/Q stream and exports them as Prometheus metrics.
+package exporter
+
+import (
+	"context"
+	"errors"
+	"math"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/frelon/k8s-radio/pkg/dsp"
+	"github.com/frelon/k8s-radio/pkg/loss"
+	"github.com/frelon/k8s-radio/pkg/rtltcp"
+)
+
+const (
+	// DefaultInterval is the default time between measurements.
+	DefaultInterval = 5 * time.Second
+	// DefaultFFTSize is the default number of bins the noise floor is
+	// estimated from.
+	DefaultFFTSize = 1024
+
+	// averaging is the number of FFTs averaged into the spectrum the noise
+	// floor and SNR are measured from.
+	averaging = 8
+)
+
+// ErrStreamEnded is returned by Run when the sample stream ends.
+var ErrStreamEnded = errors.New("exporter: stream ended")
+
+// Measurement is the state of the signal over an interval.
+type Measurement struct {
+	// Power is the mean power of the samples in dBFS.
+	Power float64
+	// NoiseFloor is the median power of the FFT bins in dBFS.
+	NoiseFloor float64
+	// SNR is the power of the strongest FFT bin above the noise floor in
+	// dB.
+	SNR float64
+	// Clipping is the ratio of I and Q values at either end of the range
+	// of the ADC.
+	Clipping float64
+}
+
+// Analyze measures the samples, estimating the noise floor and SNR from the
+// spectrum of the last samples.
+func Analyze(iq []byte, fftSize int) Measurement {
+	m := Measurement{Power: dsp.DBFS(dsp.Power(iq))}
+
+	clipped := 0
+	for _, v := range iq {
+		if v == 0 || v == 255 {
+			clipped++
+		}
+	}
+	if len(iq) > 0 {
+		m.Clipping = float64(clipped) / float64(len(iq))
+	}
+
+	blocks := min(averaging, len(iq)/(2*fftSize))
+	if blocks == 0 {
+		m.NoiseFloor = m.Power
+		return m
+	}
+
+	window := dsp.Hann(fftSize)
+	var gain float64
+	for _, w := range window {
+		gain += w
+	}
+
+	power := make([]float64, fftSize)
+	block := make([]complex128, 0, fftSize)
+	last := iq[len(iq)-2*fftSize*blocks:]
+	for i := range blocks {
+		block = dsp.Complex(block[:0], last[2*fftSize*i:2*fftSize*(i+1)])
+		for j, w := range window {
+			block[j] *= complex(w, 0)
+		}
+		dsp.FFT(block)
+		for j, v := range block {
+			power[j] += (real(v)*real(v) + imag(v)*imag(v)) / (float64(blocks) * gain * gain)
+		}
+	}
+
+	peak := slices.Max(power)
+	slices.Sort(power)
+	m.NoiseFloor = dsp.DBFS(power[len(power)/2])
+	m.SNR = dsp.DBFS(peak) - m.NoiseFloor
+
+	return m
+}
+
+// Exporter measures the stream every Interval and exports the latest
+// measurement and the sample counters as Prometheus metrics, labelled with
+// the receiver, its frequency and the serial number of its dongle.
+type Exporter struct {
+	// Receiver is the namespace and name of the receiver.
+	Receiver string
+	// Serial is the serial number of the dongle.
+	Serial string
+	// Interval is the time between measurements, defaults to
+	// DefaultInterval.
+	Interval time.Duration
+	// FFTSize is the number of bins the noise floor is estimated from, a
+	// power of two. Defaults to DefaultFFTSize.
+	FFTSize int
+
+	// Now returns the current time, defaults to time.Now.
+	Now func() time.Time
+
+	loss loss.Counter
+
+	mu        sync.Mutex
+	latest    *Measurement
+	frequency uint32
+}
+
+// Run measures the stream until ctx is done or the stream ends. tuning
+// returns the frequency and sample rate of the receiver. Run may be called
+// again with a new stream, and counts the samples missed in between as
+// dropped.
+func (e *Exporter) Run(ctx context.Context, samples <-chan []byte, tuning func() rtltcp.Tuning) error {
+	interval := e.Interval
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	size := e.FFTSize
+	if size <= 0 {
+		size = DefaultFFTSize
+	}
+
+	var buf []byte
+	next := e.now().Add(interval)
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case chunk, ok := <-samples:
+			if !ok {
+				return ErrStreamEnded
+			}
+			buf = append(buf, chunk...)
+			e.loss.Add(e.now(), len(chunk)/2, tuning().SampleRate)
+		}
+
+		if now := e.now(); !now.Before(next) {
+			m := Analyze(buf, size)
+
+			e.mu.Lock()
+			e.latest = &m
+			e.frequency = tuning().Frequency
+			e.mu.Unlock()
+
+			buf, next = buf[:0], now.Add(interval)
+		}
+	}
+}
+
+func (e *Exporter) now() time.Time {
+	if e.Now != nil {
+		return e.Now()
+	}
+
+	return time.Now()
+}
+
+var (
+	labels = []string{"receiver", "frequency", "serial"}
+
+	powerDesc = prometheus.NewDesc("rtlsdr_signal_power_dbfs",
+		"Mean power of the I/Q samples relative to full scale.", labels, nil)
+	noiseFloorDesc = prometheus.NewDesc("rtlsdr_noise_floor_dbfs",
+		"Median power of the FFT bins relative to full scale.", labels, nil)
+	snrDesc = prometheus.NewDesc("rtlsdr_snr_db",
+		"Power of the strongest FFT bin above the noise floor.", labels, nil)
+	clippingDesc = prometheus.NewDesc("rtlsdr_clipping_ratio",
+		"Ratio of I and Q values at either end of the range of the ADC.", labels, nil)
+	samplesDesc = prometheus.NewDesc("rtlsdr_samples_total",
+		"I/Q samples received from the receiver.", labels, nil)
+	droppedDesc = prometheus.NewDesc("rtlsdr_dropped_samples_total",
+		"I/Q samples missing from the stream at its sample rate.", labels, nil)
+)
+
+// Describe implements prometheus.Collector.
+func (e *Exporter) Describe(ch chan<- *prometheus.Desc) {
+	for _, desc := range []*prometheus.Desc{powerDesc, noiseFloorDesc, snrDesc, clippingDesc, samplesDesc, droppedDesc} {
+		ch <- desc
+	}
+}
+
+// Collect implements prometheus.Collector. Only the current frequency is
+// exported, so that retuning does not leave stale series behind.
+func (e *Exporter) Collect(ch chan<- prometheus.Metric) {
+	stats := e.loss.Stats(e.now())
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	values := []string{e.Receiver, strconv.FormatUint(uint64(e.frequency), 10), e.Serial}
+
+	ch <- prometheus.MustNewConstMetric(samplesDesc, prometheus.CounterValue, float64(stats.Samples), values...)
+	ch <- prometheus.MustNewConstMetric(droppedDesc, prometheus.CounterValue, float64(stats.DroppedSamples), values...)
+
+	if m := e.latest; m != nil {
+		ch <- prometheus.MustNewConstMetric(powerDesc, prometheus.GaugeValue, round(m.Power), values...)
+		ch <- prometheus.MustNewConstMetric(noiseFloorDesc, prometheus.GaugeValue, round(m.NoiseFloor), values...)
+		ch <- prometheus.MustNewConstMetric(snrDesc, prometheus.GaugeValue, round(m.SNR), values...)
+		ch <- prometheus.MustNewConstMetric(clippingDesc, prometheus.GaugeValue, m.Clipping, values...)
+	}
+}
+
+// round rounds a level to a tenth of a dB.
+func round(db float64) float64 {
+	return math.Round(db*10) / 10
+}
@@ -0,0 +1,167 @@
+package exporter
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	clocktesting "k8s.io/utils/clock/testing"
+
+	"github.com/frelon/k8s-radio/pkg/fakesdr"
+	"github.com/frelon/k8s-radio/pkg/rtltcp"
+)
+
+func TestExporter(t *testing.T) {
+	RegisterFailHandler(Fail)
+
+	RunSpecs(t, "Exporter Suite")
+}
+
+var tuning = rtltcp.Tuning{Frequency: 101_000_000, SampleRate: 240_000}
+
+func tuned() rtltcp.Tuning {
+	return tuning
+}
+
+// samples returns n bytes of noise and the carriers.
+func samples(n int, carriers ...*fakesdr.Carrier) []byte {
+	buf := make([]byte, n)
+	Expect(fakesdr.NewGenerator(0.01, carriers...).ReadIQ(buf, tuning)).To(Succeed())
+
+	return buf
+}
+
+// value returns the value of the metric named name exported by e.
+func value(e *Exporter, name string) float64 {
+	registry := prometheus.NewPedanticRegistry()
+	Expect(registry.Register(e)).To(Succeed())
+
+	families, err := registry.Gather()
+	Expect(err).ToNot(HaveOccurred())
+
+	for _, family := range families {
+		if family.GetName() == name {
+			Expect(family.GetMetric()).To(HaveLen(1))
+			metric := family.GetMetric()[0]
+			if metric.GetCounter() != nil {
+				return metric.GetCounter().GetValue()
+			}
+			return metric.GetGauge().GetValue()
+		}
+	}
+
+	Fail("no metric " + name)
+	return 0
+}
+
+var _ = Describe("Analyze", func() {
+	It("measures a carrier well above the noise", func() {
+		m := Analyze(samples(64*1024, &fakesdr.Carrier{Frequency: 101_060_000, Amplitude: 0.5}), 1024)
+
+		Expect(m.Power).To(BeNumerically("~", -6, 1))
+		Expect(m.NoiseFloor).To(BeNumerically("<", m.Power-30))
+		Expect(m.SNR).To(BeNumerically(">", 30))
+		Expect(m.Clipping).To(BeZero())
+	})
+
+	It("finds no signal in noise", func() {
+		m := Analyze(samples(64*1024), 1024)
+
+		Expect(m.SNR).To(BeNumerically("<", 15))
+		Expect(m.NoiseFloor).To(BeNumerically("<", m.Power))
+	})
+
+	It("measures clipping", func() {
+		iq := samples(4096)
+		for i := range 1024 {
+			iq[i] = 255
+		}
+
+		Expect(Analyze(iq, 1024).Clipping).To(BeNumerically("~", 0.25, 0.01))
+	})
+})
+
+var _ = Describe("Exporter", func() {
+	It("exports the measurements and counters", func(ctx SpecContext) {
+		c := clocktesting.NewFakeClock(time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC))
+		e := &Exporter{Receiver: "default/fm", Serial: "00000001", Interval: time.Second, Now: c.Now}
+
+		ch := make(chan []byte)
+		done := make(chan error)
+		go func() { done <- e.Run(ctx, ch, tuned) }()
+
+		// Two seconds of samples, delivered in time.
+		chunk := samples(48_000, &fakesdr.Carrier{Frequency: 101_060_000, Amplitude: 0.5})
+		for range 20 {
+			c.Step(100 * time.Millisecond)
+			ch <- chunk
+		}
+		close(ch)
+		Expect(<-done).To(MatchError(ErrStreamEnded))
+
+		Expect(testutil.CollectAndCount(e)).To(Equal(6))
+		Expect(testutil.CollectAndCompare(e, strings.NewReader(`
+# HELP rtlsdr_samples_total I/Q samples received from the receiver.
+# TYPE rtlsdr_samples_total counter
+rtlsdr_samples_total{frequency="101000000",receiver="default/fm",serial="00000001"} 480000
+# HELP rtlsdr_dropped_samples_total I/Q samples missing from the stream at its sample rate.
+# TYPE rtlsdr_dropped_samples_total counter
+rtlsdr_dropped_samples_total{frequency="101000000",receiver="default/fm",serial="00000001"} 0
+`), "rtlsdr_samples_total", "rtlsdr_dropped_samples_total")).To(Succeed())
+
+		power := value(e, "rtlsdr_signal_power_dbfs")
+		Expect(power).To(BeNumerically("~", -6, 1))
+	})
+
+	It("counts the samples missing from the stream as dropped", func(ctx SpecContext) {
+		c := clocktesting.NewFakeClock(time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC))
+		e := &Exporter{Now: c.Now}
+
+		ch := make(chan []byte, 1)
+		ch <- samples(48_000)
+		close(ch)
+		Expect(e.Run(ctx, ch, tuned)).To(MatchError(ErrStreamEnded))
+
+		// The stream resumes after three seconds.
+		c.Step(3 * time.Second)
+		ch = make(chan []byte, 1)
+		ch <- samples(48_000)
+		close(ch)
+		Expect(e.Run(ctx, ch, tuned)).To(MatchError(ErrStreamEnded))
+
+		// The 3.1s since the first chunk was taken at 240k, less the second
+		// of tolerance and the two chunks of samples received.
+		Expect(value(e, "rtlsdr_dropped_samples_total")).To(Equal(float64(21*24_000 - 2*24_000)))
+	})
+
+	It("stops when the context is done", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		e := &Exporter{}
+		Expect(e.Run(ctx, make(chan []byte), tuned)).To(MatchError(context.Canceled))
+	})
+})
+
+var _ = Describe("FindSerial", func() {
+	It("finds the serial in the environment of another process", func() {
+		proc := fstest.MapFS{
+			"1/environ":    {Data: []byte("PATH=/bin\x00HOME=/\x00")},
+			"7/environ":    {Data: []byte("PATH=/bin\x00RTLSDR_SERIAL=00000001\x00")},
+			"self/environ": {Data: []byte("RTLSDR_SERIAL=wrong\x00")},
+			"uptime":       {Data: []byte("1.0 1.0")},
+		}
+
+		Expect(FindSerial(proc)).To(Equal("00000001"))
+	})
+
+	It("finds nothing when no process has the serial", func() {
+		Expect(FindSerial(fstest.MapFS{"1/environ": {Data: []byte("PATH=/bin")}})).To(BeEmpty())
+	})
+})
@@ -0,0 +1,42 @@
+package exporter
+
+import (
+	"bytes"
+	"io/fs"
+	"path"
+	"strings"
+)
+
+// SerialEnv is the environment variable the device plugin sets to the serial
+// number of the dongle in the container it is allocated to.
+const SerialEnv = "RTLSDR_SERIAL"
+
+// FindSerial returns the serial number of the dongle from the environment of
+// the processes in proc, a /proc filesystem. With a process namespace shared
+// by the pod it finds the serial set in the container holding the dongle.
+// It returns an empty string when no process has it or may be inspected.
+func FindSerial(proc fs.FS) string {
+	entries, err := fs.ReadDir(proc, ".")
+	if err != nil {
+		return ""
+	}
+
+	for _, entry := range entries {
+		if strings.Trim(entry.Name(), "0123456789") != "" {
+			continue
+		}
+
+		environ, err := fs.ReadFile(proc, path.Join(entry.Name(), "environ"))
+		if err != nil {
+			continue
+		}
+
+		for _, v := range bytes.Split(environ, []byte{0}) {
+			if serial, ok := bytes.CutPrefix(v, []byte(SerialEnv+"=")); ok && len(serial) > 0 {
+				return string(serial)
+			}
+		}
+	}
+
+	return ""
+}
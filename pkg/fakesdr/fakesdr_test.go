@@ -0,0 +1,102 @@
+package fakesdr
+
+import (
+	"bytes"
+	"math"
+	"math/cmplx"
+	"net"
+
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/frelon/k8s-radio/pkg/rtltcp"
+)
+
+func TestFakeSdr(t *testing.T) {
+	RegisterFailHandler(Fail)
+
+	RunSpecs(t, "Fake SDR Suite")
+}
+
+// power returns the power of the samples at the given frequency offset.
+func power(iq []byte, offset, rate float64) float64 {
+	samples := make([]complex64, len(iq)/2)
+	rtltcp.ToComplex(samples, iq)
+
+	var sum complex128
+	for n, s := range samples {
+		sum += complex128(s) * cmplx.Exp(complex(0, -2*math.Pi*offset*float64(n)/rate))
+	}
+
+	return cmplx.Abs(sum) / float64(len(samples))
+}
+
+var _ = Describe("Fake SDR", func() {
+	tuning := Tuning{Frequency: 100_000_000, SampleRate: 1_000_000}
+
+	It("places a tone at its offset from the tuned frequency", func() {
+		g := NewGenerator(0, &Carrier{Frequency: 100_100_000, Amplitude: 0.5})
+
+		buf := make([]byte, 2*1000)
+		Expect(g.ReadIQ(buf, tuning)).To(Succeed())
+
+		Expect(power(buf, 100_000, 1_000_000)).To(BeNumerically("~", 0.5, 0.01))
+		Expect(power(buf, -100_000, 1_000_000)).To(BeNumerically("<", 0.01))
+	})
+
+	It("hides carriers outside the sampled bandwidth", func() {
+		g := NewGenerator(0, &Carrier{Frequency: 101_000_000, Amplitude: 0.5})
+
+		buf := make([]byte, 2*1000)
+		Expect(g.ReadIQ(buf, tuning)).To(Succeed())
+
+		Expect(power(buf, 0, 1_000_000)).To(BeNumerically("<", 0.01))
+	})
+
+	It("replays a file in a loop", func() {
+		r := NewReplay(bytes.NewReader([]byte{1, 2, 3, 4}))
+
+		buf := make([]byte, 10)
+		Expect(r.ReadIQ(buf, tuning)).To(Succeed())
+		Expect(buf).To(Equal([]byte{1, 2, 3, 4, 1, 2, 3, 4, 1, 2}))
+	})
+
+	It("fails replaying an empty file", func() {
+		r := NewReplay(bytes.NewReader(nil))
+		Expect(r.ReadIQ(make([]byte, 2), tuning)).ToNot(Succeed())
+	})
+
+	It("serves samples and honours tuning commands", func(ctx SpecContext) {
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).ToNot(HaveOccurred())
+
+		server := NewServer(NewGenerator(0.01, &Carrier{Frequency: 100_100_000, Amplitude: 0.5}), tuning)
+		go func() {
+			defer GinkgoRecover()
+			Expect(server.Serve(ctx, l)).To(Succeed())
+		}()
+
+		c, err := rtltcp.Dial(ctx, l.Addr().String())
+		Expect(err).ToNot(HaveOccurred())
+		defer c.Close()
+
+		Expect(c.Info().Tuner).To(Equal(rtltcp.TunerR820T))
+
+		Expect(c.SetFrequency(100_050_000)).To(Succeed())
+		Expect(c.SetSampleRate(250_000)).To(Succeed())
+		Expect(c.SetGainMode(true)).To(Succeed())
+		Expect(c.SetGain(-60)).To(Succeed())
+		Eventually(server.Tuning).Should(Equal(Tuning{
+			Frequency:  100_050_000,
+			SampleRate: 250_000,
+			Gain:       -60,
+			ManualGain: true,
+		}))
+
+		buf := make([]byte, 2*1000)
+		_, err = c.Read(buf)
+		Expect(err).ToNot(HaveOccurred())
+	})
+})
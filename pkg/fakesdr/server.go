@@ -0,0 +1,132 @@
+package fakesdr
+
+import (
+	"context"
+	"log/slog"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/frelon/k8s-radio/pkg/rtltcp"
+)
+
+// blockDuration is the amount of signal sent to the client at a time.
+const blockDuration = 20 * time.Millisecond
+
+// Server is a fake rtl_tcp server. Like rtl_tcp it serves one client at a
+// time.
+type Server struct {
+	Info   rtltcp.DongleInfo
+	Source Source
+
+	mu     sync.Mutex
+	tuning Tuning
+}
+
+// NewServer returns a server streaming from source with the initial tuning.
+func NewServer(source Source, tuning Tuning) *Server {
+	return &Server{
+		Info:   rtltcp.DongleInfo{Tuner: rtltcp.TunerR820T, GainCount: 29},
+		Source: source,
+		tuning: tuning,
+	}
+}
+
+// Tuning returns the current tuning.
+func (s *Server) Tuning() Tuning {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.tuning
+}
+
+// Serve accepts clients from l until ctx is done.
+func (s *Server) Serve(ctx context.Context, l net.Listener) error {
+	stop := context.AfterFunc(ctx, func() {
+		_ = l.Close()
+	})
+	defer stop()
+
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+
+		slog.Info("Client accepted", slog.String("remote", conn.RemoteAddr().String()))
+		if err := s.serveConn(ctx, conn); err != nil {
+			slog.Info("Client disconnected", slog.String("remote", conn.RemoteAddr().String()), slog.Any("error", err))
+		}
+	}
+}
+
+func (s *Server) serveConn(ctx context.Context, conn net.Conn) error {
+	defer conn.Close()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stop := context.AfterFunc(ctx, func() {
+		_ = conn.Close()
+	})
+	defer stop()
+
+	if err := rtltcp.WriteDongleInfo(conn, s.Info); err != nil {
+		return err
+	}
+
+	go func() {
+		defer cancel()
+		for {
+			cmd, param, err := rtltcp.ReadCommand(conn)
+			if err != nil {
+				return
+			}
+			s.handleCommand(cmd, param)
+		}
+	}()
+
+	next := time.Now()
+	for {
+		tuning := s.Tuning()
+
+		// Keep the block a whole number of samples.
+		samples := max(1, int(time.Duration(tuning.SampleRate)*blockDuration/time.Second))
+		buf := make([]byte, 2*samples)
+		if err := s.Source.ReadIQ(buf, tuning); err != nil {
+			return err
+		}
+
+		// Pace the stream at the configured sample rate.
+		next = next.Add(blockDuration)
+		if d := time.Until(next); d > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(d):
+			}
+		}
+
+		if _, err := conn.Write(buf); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return err
+		}
+	}
+}
+
+func (s *Server) handleCommand(cmd rtltcp.Command, param uint32) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.tuning.Apply(cmd, param) {
+		slog.Debug("Ignoring command", slog.String("command", cmd.String()), slog.Any("param", param))
+		return
+	}
+
+	slog.Info("Command handled", slog.String("command", cmd.String()), slog.Any("param", param))
+}
@@ -0,0 +1,155 @@
+// Package fakesdr implements a fake rtl_tcp server for testing without a
+// dongle. It streams synthetic signals or replays recorded I/Q files, and
+// honours the tuning commands sent by the client.
+package fakesdr
+
+import (
+	"errors"
+	"io"
+	"math"
+	"math/rand/v2"
+
+	"github.com/frelon/k8s-radio/pkg/rtltcp"
+)
+
+// Tuning is the state of the fake dongle as set by the client.
+type Tuning = rtltcp.Tuning
+
+// Source produces interleaved unsigned 8-bit I/Q samples.
+type Source interface {
+	// ReadIQ fills dst with samples for the given tuning. The length of
+	// dst is always a multiple of two.
+	ReadIQ(dst []byte, t Tuning) error
+}
+
+// Modulation is how a carrier is modulated.
+type Modulation int
+
+const (
+	// Unmodulated is a plain tone.
+	Unmodulated Modulation = iota
+	// FM is a carrier frequency modulated by a test tone.
+	FM
+)
+
+// Carrier is a signal at a fixed radio frequency. It shows up in the
+// baseband at its offset from the tuned frequency, and is invisible when it
+// falls outside the sampled bandwidth.
+type Carrier struct {
+	// Frequency is the radio frequency in Hz.
+	Frequency float64
+	// Amplitude is the peak amplitude relative to full scale.
+	Amplitude float64
+	// Modulation is how the carrier is modulated.
+	Modulation Modulation
+	// AudioFrequency is the frequency of the test tone for FM.
+	AudioFrequency float64
+	// Deviation is the peak frequency deviation for FM in Hz.
+	Deviation float64
+
+	phase      float64
+	audioPhase float64
+}
+
+// Generator synthesizes carriers on top of Gaussian noise.
+type Generator struct {
+	Carriers []*Carrier
+	// Noise is the standard deviation of the noise relative to full scale.
+	Noise float64
+
+	rng *rand.Rand
+}
+
+// NewGenerator returns a generator for the given carriers and noise level.
+func NewGenerator(noise float64, carriers ...*Carrier) *Generator {
+	return &Generator{
+		Carriers: carriers,
+		Noise:    noise,
+		rng:      rand.New(rand.NewPCG(1, 2)),
+	}
+}
+
+// ReadIQ implements Source.
+func (g *Generator) ReadIQ(dst []byte, t Tuning) error {
+	if t.SampleRate == 0 {
+		return errors.New("fakesdr: sample rate is not set")
+	}
+
+	rate := float64(t.SampleRate)
+	gain := 1.0
+	if t.ManualGain {
+		gain = math.Pow(10, float64(t.Gain)/200)
+	}
+
+	for i := 0; i+1 < len(dst); i += 2 {
+		var re, im float64
+
+		for _, c := range g.Carriers {
+			offset := c.Frequency - float64(t.Frequency)
+			if math.Abs(offset) > rate/2 {
+				continue
+			}
+
+			if c.Modulation == FM {
+				offset += c.Deviation * math.Sin(c.audioPhase)
+				c.audioPhase = math.Mod(c.audioPhase+2*math.Pi*c.AudioFrequency/rate, 2*math.Pi)
+			}
+
+			re += c.Amplitude * math.Cos(c.phase)
+			im += c.Amplitude * math.Sin(c.phase)
+			c.phase = math.Mod(c.phase+2*math.Pi*offset/rate, 2*math.Pi)
+		}
+
+		if g.Noise > 0 {
+			re += g.rng.NormFloat64() * g.Noise
+			im += g.rng.NormFloat64() * g.Noise
+		}
+
+		dst[i] = quantize(re * gain)
+		dst[i+1] = quantize(im * gain)
+	}
+
+	return nil
+}
+
+// quantize converts a sample in [-1, 1] to an unsigned 8-bit sample,
+// clipping values outside the range like the ADC would.
+func quantize(v float64) byte {
+	return byte(math.Round(max(0, min(255, 127.5+127.5*v))))
+}
+
+// Replay replays a recorded file of unsigned 8-bit I/Q samples in a loop. The
+// tuning is ignored since the recording is fixed.
+type Replay struct {
+	r io.ReadSeeker
+}
+
+// NewReplay returns a source replaying the samples read from r.
+func NewReplay(r io.ReadSeeker) *Replay {
+	return &Replay{r: r}
+}
+
+// ReadIQ implements Source.
+func (p *Replay) ReadIQ(dst []byte, _ Tuning) error {
+	rewound := false
+	for len(dst) > 0 {
+		n, err := io.ReadFull(p.r, dst)
+		dst = dst[n:]
+
+		switch {
+		case err == nil:
+		case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+			if n == 0 && rewound {
+				return errors.New("fakesdr: replay file is empty")
+			}
+			if _, err := p.r.Seek(0, io.SeekStart); err != nil {
+				return err
+			}
+			rewound = n == 0
+		default:
+			return err
+		}
+	}
+
+	return nil
+}
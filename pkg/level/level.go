@@ -0,0 +1,119 @@
+// Package level measures the signal level of an I/Q stream.
+package level
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/frelon/k8s-radio/pkg/dsp"
+	"github.com/frelon/k8s-radio/pkg/rtltcp"
+)
+
+// DefaultInterval is the default time the level is averaged over.
+const DefaultInterval = time.Second
+
+// ErrStreamEnded is returned by Run when the sample stream ends.
+var ErrStreamEnded = errors.New("level: stream ended")
+
+// Sample is the signal level of the stream over an interval.
+type Sample struct {
+	// Time is when the interval ended.
+	Time time.Time `json:"time"`
+	// Frequency is the centre frequency of the receiver in Hz.
+	Frequency uint32 `json:"frequency"`
+	// Power is the mean power over the interval in dBFS.
+	Power float64 `json:"power"`
+	// Peak is the power of the strongest chunk of samples in dBFS.
+	Peak float64 `json:"peak"`
+}
+
+// Meter averages the power of the stream over intervals of the stream time,
+// so the levels follow the samples however late they are delivered.
+type Meter struct {
+	// Interval is the time averaged into each sample, defaults to
+	// DefaultInterval.
+	Interval time.Duration
+
+	// Now returns the current time, defaults to time.Now.
+	Now func() time.Time
+
+	mu     sync.Mutex
+	latest *Sample
+}
+
+// Latest returns the most recent sample, if any.
+func (m *Meter) Latest() (Sample, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.latest == nil {
+		return Sample{}, false
+	}
+
+	return *m.latest, true
+}
+
+// Run measures the level until ctx is done or the stream ends. tuning returns
+// the frequency and sample rate of the receiver.
+func (m *Meter) Run(ctx context.Context, samples <-chan []byte, tuning func() rtltcp.Tuning) error {
+	interval := m.Interval
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+
+	var sum, peak float64
+	var n int
+	for {
+		var chunk []byte
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case c, ok := <-samples:
+			if !ok {
+				return ErrStreamEnded
+			}
+			chunk = c
+		}
+
+		if len(chunk) < 2 {
+			continue
+		}
+
+		power := dsp.Power(chunk)
+		sum += power * float64(len(chunk)/2)
+		peak = max(peak, power)
+		n += len(chunk) / 2
+
+		t := tuning()
+		if float64(n) < interval.Seconds()*float64(t.SampleRate) {
+			continue
+		}
+
+		m.mu.Lock()
+		m.latest = &Sample{
+			Time:      m.now(),
+			Frequency: t.Frequency,
+			Power:     round(dsp.DBFS(sum / float64(n))),
+			Peak:      round(dsp.DBFS(peak)),
+		}
+		m.mu.Unlock()
+
+		sum, peak, n = 0, 0, 0
+	}
+}
+
+func (m *Meter) now() time.Time {
+	if m.Now != nil {
+		return m.Now()
+	}
+
+	return time.Now()
+}
+
+// round rounds a level to a tenth of a dB.
+func round(db float64) float64 {
+	return math.Round(db*10) / 10
+}
@@ -0,0 +1,81 @@
+package level
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/frelon/k8s-radio/pkg/fakesdr"
+	"github.com/frelon/k8s-radio/pkg/rtltcp"
+)
+
+func TestLevel(t *testing.T) {
+	RegisterFailHandler(Fail)
+
+	RunSpecs(t, "Level Suite")
+}
+
+var tuning = rtltcp.Tuning{Frequency: 101_000_000, SampleRate: 240_000}
+
+func tuned() rtltcp.Tuning {
+	return tuning
+}
+
+// stream returns n bytes of samples of a carrier at half of full scale in
+// chunks, then ends.
+func stream(n int) <-chan []byte {
+	gen := fakesdr.NewGenerator(0.01, &fakesdr.Carrier{Frequency: 101_060_000, Amplitude: 0.5})
+
+	ch := make(chan []byte, 4)
+	go func() {
+		defer close(ch)
+
+		for ; n > 0; n -= 8192 {
+			buf := make([]byte, min(n, 8192))
+			if err := gen.ReadIQ(buf, tuning); err != nil {
+				return
+			}
+			ch <- buf
+		}
+	}()
+
+	return ch
+}
+
+var _ = Describe("Meter", func() {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+
+	It("measures the level of the stream over each interval", func(ctx SpecContext) {
+		m := &Meter{Interval: 500 * time.Millisecond, Now: func() time.Time { return now }}
+
+		// One second of samples.
+		err := m.Run(ctx, stream(2*int(tuning.SampleRate)), tuned)
+		Expect(err).To(MatchError(ErrStreamEnded))
+
+		sample, ok := m.Latest()
+		Expect(ok).To(BeTrue())
+		Expect(sample.Time).To(Equal(now))
+		Expect(sample.Frequency).To(Equal(tuning.Frequency))
+		Expect(sample.Power).To(BeNumerically("~", -6, 1))
+		Expect(sample.Peak).To(BeNumerically(">=", sample.Power))
+	})
+
+	It("has no level before the first interval", func(ctx SpecContext) {
+		m := &Meter{}
+
+		Expect(m.Run(ctx, stream(1000), tuned)).To(MatchError(ErrStreamEnded))
+		_, ok := m.Latest()
+		Expect(ok).To(BeFalse())
+	})
+
+	It("stops when the context is done", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		m := &Meter{}
+		Expect(m.Run(ctx, make(chan []byte), tuned)).To(MatchError(context.Canceled))
+	})
+})
@@ -0,0 +1,137 @@
+// Package loss counts the samples missing from an I/Q stream, which rtl_tcp
+// drops without telling anyone when the USB bus or the network can't keep up
+// with the sample rate.
+package loss
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultTolerance is the default lag behind the sample rate allowed
+	// before samples are counted as dropped. It covers the buffers between
+	// the dongle and the counter.
+	DefaultTolerance = time.Second
+	// DefaultWindow is the default time the loss ratio is measured over.
+	DefaultWindow = 10 * time.Second
+
+	// BufferSamples is the number of samples in each buffer rtl_tcp reads
+	// from the dongle, its default of 16 transfers of 16 KiB of I/Q bytes.
+	// Samples are dropped in whole buffers.
+	BufferSamples = 16 * 16384 / 2
+)
+
+// Stats is the loss of a stream.
+type Stats struct {
+	// SampleRate is the sample rate the stream is expected to keep up
+	// with, zero when unknown.
+	SampleRate uint32 `json:"sampleRate"`
+	// Samples is the number of samples received.
+	Samples uint64 `json:"samples"`
+	// DroppedSamples is the number of samples missing from the stream at
+	// its sample rate.
+	DroppedSamples uint64 `json:"droppedSamples"`
+	// DroppedBuffers is the number of rtl_tcp buffers the dropped samples
+	// make up.
+	DroppedBuffers uint64 `json:"droppedBuffers"`
+	// Ratio is the ratio of the samples dropped over the last window.
+	Ratio float64 `json:"ratio"`
+}
+
+// Counter counts the samples received from a stream, and the samples
+// missing from it at its sample rate as dropped. It is safe for concurrent
+// use.
+type Counter struct {
+	// Tolerance is the lag allowed before samples are counted as dropped,
+	// defaults to DefaultTolerance.
+	Tolerance time.Duration
+	// Window is the time the loss ratio is measured over, defaults to
+	// DefaultWindow.
+	Window time.Duration
+
+	mu      sync.Mutex
+	samples uint64
+	dropped uint64
+
+	// The samples received since since at rate, and the samples dropped
+	// before.
+	since    time.Time
+	rate     uint32
+	received uint64
+	base     uint64
+
+	// The counters at the start of the window, and the ratio of the last
+	// one.
+	windowStart   time.Time
+	windowSamples uint64
+	windowDropped uint64
+	ratio         float64
+}
+
+// Add counts n samples received at now from a stream at rate. Samples of a
+// stream at an unknown rate of zero are counted but never as dropped.
+func (c *Counter) Add(now time.Time, n int, rate uint32) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.samples += uint64(n)
+	c.update(now, uint64(n), rate)
+}
+
+// Stats returns the loss at now. A stream that has stopped counts as
+// dropping all of its samples.
+func (c *Counter) Stats(now time.Time) Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.since.IsZero() {
+		c.update(now, 0, c.rate)
+	}
+
+	return Stats{
+		SampleRate:     c.rate,
+		Samples:        c.samples,
+		DroppedSamples: c.dropped,
+		DroppedBuffers: (c.dropped + BufferSamples - 1) / BufferSamples,
+		Ratio:          c.ratio,
+	}
+}
+
+// update counts n samples received at now. c.mu must be held.
+func (c *Counter) update(now time.Time, n uint64, rate uint32) {
+	if rate == 0 {
+		return
+	}
+
+	// Start over when the sample rate changes, from when the first samples
+	// were taken.
+	if c.since.IsZero() || rate != c.rate {
+		since := now.Add(-time.Duration(float64(n) / float64(rate) * float64(time.Second)))
+		c.since, c.rate, c.received, c.base = since, rate, 0, c.dropped
+		c.windowStart, c.windowSamples, c.windowDropped = since, c.samples-n, c.dropped
+	}
+	c.received += n
+
+	tolerance := c.Tolerance
+	if tolerance <= 0 {
+		tolerance = DefaultTolerance
+	}
+	expected := (now.Sub(c.since) - tolerance).Seconds() * float64(c.rate)
+	if missing := expected - float64(c.received); missing > float64(c.dropped-c.base) {
+		c.dropped = c.base + uint64(missing)
+	}
+
+	window := c.Window
+	if window <= 0 {
+		window = DefaultWindow
+	}
+	if now.Sub(c.windowStart) >= window {
+		dropped, received := c.dropped-c.windowDropped, c.samples-c.windowSamples
+		c.ratio = 0
+		if dropped > 0 {
+			c.ratio = float64(dropped) / float64(dropped+received)
+		}
+		c.windowStart, c.windowSamples, c.windowDropped = now, c.samples, c.dropped
+	}
+}
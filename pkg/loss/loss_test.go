@@ -0,0 +1,100 @@
+package loss
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestLoss(t *testing.T) {
+	RegisterFailHandler(Fail)
+
+	RunSpecs(t, "Loss Suite")
+}
+
+const rate = 240_000
+
+var start = time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+
+// stream adds the samples of every tenth of a second from from to to as it
+// ends, skipping the tenths skip returns true for.
+func stream(c *Counter, from, to time.Duration, skip func(time.Duration) bool) {
+	for t := from; t < to; t += 100 * time.Millisecond {
+		if skip == nil || !skip(t) {
+			c.Add(start.Add(t+100*time.Millisecond), rate/10, rate)
+		}
+	}
+}
+
+var _ = Describe("Counter", func() {
+	It("counts nothing as dropped while the stream keeps up", func() {
+		c := &Counter{}
+		stream(c, 0, 20*time.Second, nil)
+
+		stats := c.Stats(start.Add(20 * time.Second))
+		Expect(stats.SampleRate).To(Equal(uint32(rate)))
+		Expect(stats.Samples).To(Equal(uint64(20 * rate)))
+		Expect(stats.DroppedSamples).To(BeZero())
+		Expect(stats.DroppedBuffers).To(BeZero())
+		Expect(stats.Ratio).To(BeZero())
+	})
+
+	It("counts the samples missing from the stream as dropped buffers", func() {
+		c := &Counter{Tolerance: 200 * time.Millisecond}
+		// Every tenth chunk of the second window goes missing, a second
+		// of samples of which the tolerance covers a fifth.
+		stream(c, 0, 20*time.Second, func(t time.Duration) bool {
+			return t >= 10*time.Second && t.Milliseconds()%1000 == 500
+		})
+
+		stats := c.Stats(start.Add(20 * time.Second))
+		Expect(stats.DroppedSamples).To(Equal(uint64(rate * 8 / 10)))
+		Expect(stats.DroppedBuffers).To(Equal(uint64(2)))
+		Expect(stats.Ratio).To(BeNumerically("~", 0.08, 0.01))
+
+		By("recovering once the stream keeps up again")
+		stream(c, 20*time.Second, 40*time.Second, nil)
+		stats = c.Stats(start.Add(40 * time.Second))
+		Expect(stats.Ratio).To(BeZero())
+		Expect(stats.DroppedSamples).To(Equal(uint64(rate * 8 / 10)))
+	})
+
+	It("counts a stopped stream as dropping everything", func() {
+		c := &Counter{Window: time.Second}
+		stream(c, 0, 5*time.Second, nil)
+
+		for t := 6 * time.Second; t <= 10*time.Second; t += time.Second {
+			c.Stats(start.Add(t))
+		}
+
+		stats := c.Stats(start.Add(10 * time.Second))
+		Expect(stats.DroppedSamples).To(Equal(uint64(4 * rate)))
+		Expect(stats.Ratio).To(Equal(1.0))
+	})
+
+	It("starts over when the sample rate changes", func() {
+		c := &Counter{}
+		stream(c, 0, 5*time.Second, nil)
+
+		// Half the samples at twice the rate are a loss of half, but only
+		// since the change.
+		for t := 5 * time.Second; t < 10*time.Second; t += 100 * time.Millisecond {
+			c.Add(start.Add(t+100*time.Millisecond), rate/10, 2*rate)
+		}
+
+		stats := c.Stats(start.Add(10 * time.Second))
+		Expect(stats.SampleRate).To(Equal(uint32(2 * rate)))
+		Expect(stats.DroppedSamples).To(BeNumerically("~", 3*rate, rate/10))
+	})
+
+	It("never counts samples at an unknown rate as dropped", func() {
+		c := &Counter{}
+		c.Add(start, 1000, 0)
+
+		stats := c.Stats(start.Add(time.Minute))
+		Expect(stats.Samples).To(Equal(uint64(1000)))
+		Expect(stats.DroppedSamples).To(BeZero())
+	})
+})
@@ -0,0 +1,118 @@
+// Package mqttpub publishes the status, signal level and decoded messages of
+// a receiver to an MQTT broker.
+package mqttpub
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+)
+
+// publishTimeout bounds how long publishing a message may take.
+const publishTimeout = 10 * time.Second
+
+// Message is a message published to the broker.
+type Message struct {
+	Topic    string
+	Payload  []byte
+	Retained bool
+}
+
+// Config configures the connection to the broker.
+type Config struct {
+	// Broker is the URL of the broker: mqtt, tcp, mqtts or ssl. A username
+	// and password in the URL are used unless Username is set.
+	Broker *url.URL
+	// ClientID identifies the client to the broker.
+	ClientID string
+	// Username and Password authenticate the client.
+	Username string
+	Password string
+	// QoS is the quality of service messages are published with, 0 to 2.
+	QoS byte
+	// Will is published by the broker when the client goes away without
+	// disconnecting.
+	Will *Message
+}
+
+// Publisher publishes messages to a broker, connecting and reconnecting in
+// the background.
+type Publisher struct {
+	qos       byte
+	client    mqtt.Client
+	connected mqtt.Token
+}
+
+// New starts connecting to the broker, retrying until it is reachable.
+func New(cfg Config) (*Publisher, error) {
+	if cfg.QoS > 2 {
+		return nil, fmt.Errorf("mqttpub: invalid QoS %d", cfg.QoS)
+	}
+
+	broker := *cfg.Broker
+	broker.User = nil
+	switch broker.Scheme {
+	case "mqtt":
+		broker.Scheme = "tcp"
+	case "mqtts":
+		broker.Scheme = "ssl"
+	case "tcp", "ssl":
+	default:
+		return nil, fmt.Errorf("mqttpub: unsupported broker %s", cfg.Broker.Redacted())
+	}
+
+	username, password := cfg.Username, cfg.Password
+	if username == "" && cfg.Broker.User != nil {
+		username = cfg.Broker.User.Username()
+		password, _ = cfg.Broker.User.Password()
+	}
+
+	opts := mqtt.NewClientOptions().
+		AddBroker(broker.String()).
+		SetClientID(cfg.ClientID).
+		SetUsername(username).
+		SetPassword(password).
+		SetConnectRetry(true).
+		SetAutoReconnect(true)
+	if will := cfg.Will; will != nil {
+		opts.SetBinaryWill(will.Topic, will.Payload, cfg.QoS, will.Retained)
+	}
+
+	client := mqtt.NewClient(opts)
+
+	return &Publisher{qos: cfg.QoS, client: client, connected: client.Connect()}, nil
+}
+
+// Publish publishes the message, waiting for the broker to acknowledge it
+// when the QoS asks for it.
+func (p *Publisher) Publish(ctx context.Context, msg Message) error {
+	timeout := time.After(publishTimeout)
+
+	// Messages published before the first connection are not sent.
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-p.connected.Done():
+	case <-timeout:
+		return fmt.Errorf("mqttpub: timed out connecting to publish to %s", msg.Topic)
+	}
+
+	token := p.client.Publish(msg.Topic, p.qos, msg.Retained, msg.Payload)
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-token.Done():
+		return token.Error()
+	case <-timeout:
+		return fmt.Errorf("mqttpub: timed out publishing to %s", msg.Topic)
+	}
+}
+
+// Close disconnects from the broker.
+func (p *Publisher) Close() error {
+	p.client.Disconnect(250)
+	return nil
+}
@@ -0,0 +1,191 @@
+package mqttpub
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/frelon/k8s-radio/pkg/mqtttest"
+)
+
+func TestMqttpub(t *testing.T) {
+	RegisterFailHandler(Fail)
+
+	RunSpecs(t, "MQTT Publisher Suite")
+}
+
+// connect starts a broker and a publisher connected to it.
+func connect(cfg Config) (*mqtttest.Server, *Publisher) {
+	broker, err := mqtttest.NewServer()
+	Expect(err).ToNot(HaveOccurred())
+	DeferCleanup(broker.Close)
+
+	u, err := url.Parse(strings.Replace(broker.URL, "tcp://", "mqtt://", 1))
+	Expect(err).ToNot(HaveOccurred())
+	if cfg.Broker != nil {
+		u.User = cfg.Broker.User
+	}
+	cfg.Broker = u
+	if cfg.ClientID == "" {
+		cfg.ClientID = "test"
+	}
+
+	publisher, err := New(cfg)
+	Expect(err).ToNot(HaveOccurred())
+	DeferCleanup(publisher.Close)
+
+	return broker, publisher
+}
+
+var _ = Describe("Publisher", func() {
+	It("publishes at the configured QoS", func(ctx SpecContext) {
+		for _, qos := range []byte{0, 1, 2} {
+			broker, publisher := connect(Config{QoS: qos})
+
+			Expect(publisher.Publish(ctx, Message{Topic: "radio/test", Payload: []byte(`{}`), Retained: true})).To(Succeed())
+			Eventually(broker.Messages).Should(ConsistOf(mqtttest.Message{
+				Topic: "radio/test", Payload: []byte(`{}`), QoS: qos, Retained: true,
+			}))
+		}
+	})
+
+	It("authenticates with the credentials given, or those of the URL", func(ctx SpecContext) {
+		broker, publisher := connect(Config{Username: "radio", Password: "secret"})
+		Expect(publisher.Publish(ctx, Message{Topic: "t", Payload: []byte(`1`)})).To(Succeed())
+		Expect(broker.Clients()).To(ConsistOf(HaveField("Username", "radio")))
+		Expect(broker.Clients()).To(ConsistOf(HaveField("Password", "secret")))
+
+		broker, publisher = connect(Config{Broker: &url.URL{User: url.UserPassword("url", "pw")}})
+		Expect(publisher.Publish(ctx, Message{Topic: "t", Payload: []byte(`1`)})).To(Succeed())
+		Expect(broker.Clients()).To(ConsistOf(HaveField("Username", "url")))
+	})
+
+	It("rejects invalid settings", func() {
+		_, err := New(Config{Broker: &url.URL{Scheme: "mqtt", Host: "broker:1883"}, QoS: 3})
+		Expect(err).To(MatchError(ContainSubstring("QoS")))
+
+		_, err = New(Config{Broker: &url.URL{Scheme: "http", Host: "broker"}})
+		Expect(err).To(MatchError(ContainSubstring("unsupported broker")))
+	})
+})
+
+var _ = Describe("Relay", func() {
+	var broker *mqtttest.Server
+	var relay *Relay
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+
+	BeforeEach(func() {
+		relay = &Relay{
+			Prefix:            "radio/default/weather",
+			Status:            Status{Receiver: "default/weather", Mode: "ISM", Frequency: 433_920_000},
+			HeartbeatInterval: 10 * time.Millisecond,
+			Now:               func() time.Time { return now },
+		}
+		broker, relay.Publisher = connect(Config{QoS: 1, Will: relay.Will()})
+	})
+
+	// published returns the messages published to topic.
+	published := func(topic string) func() []mqtttest.Message {
+		return func() []mqtttest.Message {
+			var msgs []mqtttest.Message
+			for _, msg := range broker.Messages() {
+				if msg.Topic == topic {
+					msgs = append(msgs, msg)
+				}
+			}
+			return msgs
+		}
+	}
+
+	It("publishes heartbeats until stopped, then that it is offline", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		DeferCleanup(cancel)
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			relay.Heartbeat(ctx)
+		}()
+
+		Eventually(func() int {
+			return len(published("radio/default/weather/status")())
+		}).Should(BeNumerically(">=", 2))
+		cancel()
+		Eventually(done).Should(BeClosed())
+
+		msgs := published("radio/default/weather/status")()
+		var first, last Status
+		Expect(json.Unmarshal(msgs[0].Payload, &first)).To(Succeed())
+		Expect(json.Unmarshal(msgs[len(msgs)-1].Payload, &last)).To(Succeed())
+
+		Expect(msgs[0].Retained).To(BeTrue())
+		Expect(first).To(Equal(Status{Time: now, Receiver: "default/weather", Online: true, Mode: "ISM", Frequency: 433_920_000}))
+		Expect(last.Online).To(BeFalse())
+	})
+
+	It("leaves a will saying the receiver is offline", func() {
+		Eventually(broker.Clients).Should(HaveLen(1))
+		will := broker.Clients()[0].Will
+		Expect(will).ToNot(BeNil())
+		Expect(will.Topic).To(Equal("radio/default/weather/status"))
+		Expect(will.Retained).To(BeTrue())
+
+		var status Status
+		Expect(json.Unmarshal(will.Payload, &status)).To(Succeed())
+		Expect(status.Online).To(BeFalse())
+		Expect(status.Receiver).To(Equal("default/weather"))
+	})
+
+	It("relays the JSON polled from a container", func(ctx SpecContext) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(`{"power":-32.5}`))
+		}))
+		DeferCleanup(server.Close)
+
+		pollCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		go relay.Poll(pollCtx, "signal", server.URL, 10*time.Millisecond)
+
+		Eventually(published("radio/default/weather/signal")).Should(ContainElement(
+			HaveField("Payload", []byte(`{"power":-32.5}`))))
+	})
+
+	It("skips what is not JSON", func(ctx SpecContext) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(`no signal level yet`))
+		}))
+		DeferCleanup(server.Close)
+
+		pollCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+		defer cancel()
+		relay.Poll(pollCtx, "signal", server.URL, 10*time.Millisecond)
+
+		Expect(published("radio/default/weather/signal")()).To(BeEmpty())
+	})
+
+	It("publishes the JSON posted by containers", func() {
+		server := httptest.NewServer(relay.Handler())
+		DeferCleanup(server.Close)
+
+		resp, err := http.Post(server.URL+"/publish/events", "application/json", strings.NewReader(`{"type":"Nexus-TH"}`))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resp.Body.Close()).To(Succeed())
+		Expect(resp.StatusCode).To(Equal(http.StatusNoContent))
+
+		Expect(published("radio/default/weather/events")()).To(ConsistOf(mqtttest.Message{
+			Topic: "radio/default/weather/events", Payload: []byte(`{"type":"Nexus-TH"}`), QoS: 1,
+		}))
+
+		resp, err = http.Post(server.URL+"/publish/events", "application/json", strings.NewReader(`rtl_433`))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resp.Body.Close()).To(Succeed())
+		Expect(resp.StatusCode).To(Equal(http.StatusBadRequest))
+	})
+})
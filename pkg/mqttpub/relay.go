@@ -0,0 +1,211 @@
+package mqttpub
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"path"
+	"time"
+)
+
+const (
+	// StatusTopic is the topic the heartbeats are published to, below the
+	// prefix of the receiver.
+	StatusTopic = "status"
+
+	// DefaultHeartbeatInterval is the default time between heartbeats.
+	DefaultHeartbeatInterval = 30 * time.Second
+
+	// maxBody bounds the size of the data polled or posted to the relay.
+	maxBody = 4 << 20
+)
+
+// Status is the heartbeat of a receiver. It is published retained, so that
+// subscribers learn the state of the receiver as soon as they subscribe, and
+// with online false when the relay goes away.
+type Status struct {
+	// Time is when the heartbeat was published.
+	Time time.Time `json:"time"`
+	// Receiver is the namespace and name of the receiver.
+	Receiver string `json:"receiver"`
+	// Online is whether the receiver is running.
+	Online bool `json:"online"`
+	// Mode is the mode of the receiver.
+	Mode string `json:"mode,omitempty"`
+	// Frequency is the frequency the receiver was tuned to in Hz.
+	Frequency int64 `json:"frequency,omitempty"`
+	// Uptime is the number of seconds the relay has run for.
+	Uptime int64 `json:"uptime"`
+}
+
+// Relay publishes the heartbeats of a receiver, and the data polled from or
+// posted by the containers of its pod, to topics below Prefix.
+type Relay struct {
+	Publisher *Publisher
+	// Prefix is the topic the topics of the receiver are below, e.g.
+	// radio/default/weather.
+	Prefix string
+	// Status describes the receiver in the heartbeats.
+	Status Status
+	// HeartbeatInterval is the time between heartbeats, defaults to
+	// DefaultHeartbeatInterval.
+	HeartbeatInterval time.Duration
+
+	// Now returns the current time, defaults to time.Now.
+	Now func() time.Time
+}
+
+// Topic returns the topic name is published to.
+func (r *Relay) Topic(name string) string {
+	return path.Join(r.Prefix, name)
+}
+
+// Will returns the retained status the broker publishes should the relay go
+// away without saying so.
+func (r *Relay) Will() *Message {
+	status := r.Status
+	status.Online = false
+
+	msg := r.statusMessage(status)
+	return &msg
+}
+
+func (r *Relay) statusMessage(status Status) Message {
+	payload, _ := json.Marshal(status)
+
+	return Message{Topic: r.Topic(StatusTopic), Payload: payload, Retained: true}
+}
+
+// Heartbeat publishes the status of the receiver every HeartbeatInterval
+// until ctx is done, and then that it is offline.
+func (r *Relay) Heartbeat(ctx context.Context) {
+	interval := r.HeartbeatInterval
+	if interval <= 0 {
+		interval = DefaultHeartbeatInterval
+	}
+
+	started := r.now()
+	publish := func(ctx context.Context, online bool) {
+		status := r.Status
+		status.Time = r.now().UTC()
+		status.Online = online
+		status.Uptime = int64(status.Time.Sub(started).Seconds())
+
+		if err := r.Publisher.Publish(ctx, r.statusMessage(status)); err != nil && ctx.Err() == nil {
+			slog.Error("Failed publishing heartbeat", slog.Any("error", err))
+		}
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		publish(ctx, true)
+
+		select {
+		case <-ctx.Done():
+			offlineCtx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+			publish(offlineCtx, false)
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Poll fetches the JSON served at url every interval until ctx is done, and
+// publishes it to topic below the prefix. Failures are logged and retried at
+// the next interval.
+func (r *Relay) Poll(ctx context.Context, topic, url string, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		payload, err := fetch(ctx, url, interval)
+		if err == nil {
+			err = r.Publisher.Publish(ctx, Message{Topic: r.Topic(topic), Payload: payload})
+		}
+		if err != nil && ctx.Err() == nil {
+			slog.Info("Failed relaying", slog.String("topic", topic), slog.String("url", url), slog.Any("error", err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// fetch returns the JSON served at url.
+func fetch(ctx context.Context, url string, timeout time.Duration) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	payload, err := io.ReadAll(io.LimitReader(resp.Body, maxBody))
+	if err != nil {
+		return nil, err
+	}
+	if !json.Valid(payload) {
+		return nil, fmt.Errorf("mqttpub: %s did not serve JSON", url)
+	}
+
+	return payload, nil
+}
+
+// Handler publishes the JSON posted to /publish/{topic} to topic below the
+// prefix, so that containers of the pod can publish without a connection of
+// their own.
+func (r *Relay) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /publish/{topic}", func(w http.ResponseWriter, req *http.Request) {
+		payload, err := io.ReadAll(http.MaxBytesReader(w, req.Body, maxBody))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+			return
+		}
+		if !json.Valid(payload) {
+			http.Error(w, "body is not JSON", http.StatusBadRequest)
+			return
+		}
+
+		msg := Message{Topic: r.Topic(req.PathValue("topic")), Payload: payload}
+		if err := r.Publisher.Publish(req.Context(), msg); err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	return mux
+}
+
+func (r *Relay) now() time.Time {
+	if r.Now != nil {
+		return r.Now()
+	}
+
+	return time.Now()
+}
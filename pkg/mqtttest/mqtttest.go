@@ -0,0 +1,320 @@
+// Package mqtttest provides an in-process MQTT broker for tests. It speaks
+// just enough of MQTT 3.1.1 for clients to connect and publish at any QoS,
+// and records what they publish.
+package mqtttest
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+)
+
+// Packet types of MQTT 3.1.1.
+const (
+	connect    = 1
+	connack    = 2
+	publish    = 3
+	puback     = 4
+	pubrec     = 5
+	pubrel     = 6
+	pubcomp    = 7
+	subscribe  = 8
+	suback     = 9
+	pingreq    = 12
+	pingresp   = 13
+	disconnect = 14
+)
+
+// Message is a message published to the broker.
+type Message struct {
+	Topic    string
+	Payload  []byte
+	QoS      byte
+	Retained bool
+}
+
+// Client is a client that connected to the broker.
+type Client struct {
+	ID       string
+	Username string
+	Password string
+	// Will is the message the client asked the broker to publish when it
+	// goes away, if any.
+	Will *Message
+}
+
+// Server is an MQTT broker listening on a local port.
+type Server struct {
+	// URL is the address of the broker, e.g. tcp://127.0.0.1:1883.
+	URL string
+
+	listener net.Listener
+	wg       sync.WaitGroup
+
+	mu       sync.Mutex
+	messages []Message
+	clients  []Client
+	conns    map[net.Conn]struct{}
+}
+
+// NewServer starts a broker on a random local port.
+func NewServer() (*Server, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Server{
+		URL:      "tcp://" + l.Addr().String(),
+		listener: l,
+		conns:    map[net.Conn]struct{}{},
+	}
+
+	s.wg.Add(1)
+	go s.serve()
+
+	return s, nil
+}
+
+// Close stops the broker and disconnects its clients.
+func (s *Server) Close() {
+	_ = s.listener.Close()
+
+	s.mu.Lock()
+	for conn := range s.conns {
+		_ = conn.Close()
+	}
+	s.mu.Unlock()
+
+	s.wg.Wait()
+}
+
+// Messages returns the messages published so far.
+func (s *Server) Messages() []Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Message(nil), s.messages...)
+}
+
+// Clients returns the clients that have connected so far.
+func (s *Server) Clients() []Client {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Client(nil), s.clients...)
+}
+
+func (s *Server) serve() {
+	defer s.wg.Done()
+
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		s.mu.Lock()
+		s.conns[conn] = struct{}{}
+		s.mu.Unlock()
+
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			_ = s.handle(conn)
+
+			s.mu.Lock()
+			delete(s.conns, conn)
+			s.mu.Unlock()
+			_ = conn.Close()
+		}()
+	}
+}
+
+// handle serves the packets of a connection until it is closed.
+func (s *Server) handle(conn net.Conn) error {
+	r := bufio.NewReader(conn)
+	for {
+		header, body, err := readPacket(r)
+		if err != nil {
+			return err
+		}
+
+		switch header >> 4 {
+		case connect:
+			client, err := parseConnect(body)
+			if err != nil {
+				return err
+			}
+			s.mu.Lock()
+			s.clients = append(s.clients, client)
+			s.mu.Unlock()
+
+			_, err = conn.Write([]byte{connack << 4, 2, 0, 0})
+			if err != nil {
+				return err
+			}
+		case publish:
+			msg, id, err := parsePublish(header, body)
+			if err != nil {
+				return err
+			}
+			s.mu.Lock()
+			s.messages = append(s.messages, msg)
+			s.mu.Unlock()
+
+			switch msg.QoS {
+			case 1:
+				if _, err := conn.Write([]byte{puback << 4, 2, byte(id >> 8), byte(id)}); err != nil {
+					return err
+				}
+			case 2:
+				if _, err := conn.Write([]byte{pubrec << 4, 2, byte(id >> 8), byte(id)}); err != nil {
+					return err
+				}
+			}
+		case pubrel:
+			if len(body) < 2 {
+				return errors.New("mqtttest: short pubrel")
+			}
+			if _, err := conn.Write([]byte{pubcomp << 4, 2, body[0], body[1]}); err != nil {
+				return err
+			}
+		case subscribe:
+			// Grant the requested topics at QoS 0 without delivering
+			// anything.
+			if len(body) < 2 {
+				return errors.New("mqtttest: short subscribe")
+			}
+			if _, err := conn.Write([]byte{suback << 4, 3, body[0], body[1], 0}); err != nil {
+				return err
+			}
+		case pingreq:
+			if _, err := conn.Write([]byte{pingresp << 4, 0}); err != nil {
+				return err
+			}
+		case disconnect:
+			return nil
+		}
+	}
+}
+
+// readPacket reads the first byte of the fixed header and the rest of a
+// packet.
+func readPacket(r *bufio.Reader) (byte, []byte, error) {
+	header, err := r.ReadByte()
+	if err != nil {
+		return 0, nil, err
+	}
+
+	// The remaining length is a variable length integer of up to four
+	// bytes.
+	length, shift := 0, 0
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			return 0, nil, err
+		}
+		length |= int(b&0x7f) << shift
+		if b&0x80 == 0 {
+			break
+		}
+		if shift += 7; shift > 21 {
+			return 0, nil, errors.New("mqtttest: malformed remaining length")
+		}
+	}
+
+	body := make([]byte, length)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return 0, nil, err
+	}
+
+	return header, body, nil
+}
+
+func parseConnect(body []byte) (Client, error) {
+	p := &parser{buf: body}
+	p.string() // Protocol name.
+	p.bytes(1) // Protocol level.
+	flags := p.bytes(1)
+	p.bytes(2) // Keep alive.
+	if p.err != nil || len(flags) == 0 {
+		return Client{}, fmt.Errorf("mqtttest: malformed connect: %w", p.err)
+	}
+
+	client := Client{ID: p.string()}
+	if flags[0]&0x04 != 0 {
+		client.Will = &Message{
+			Topic:    p.string(),
+			Payload:  []byte(p.string()),
+			QoS:      (flags[0] >> 3) & 0x03,
+			Retained: flags[0]&0x20 != 0,
+		}
+	}
+	if flags[0]&0x80 != 0 {
+		client.Username = p.string()
+	}
+	if flags[0]&0x40 != 0 {
+		client.Password = p.string()
+	}
+
+	return client, p.err
+}
+
+func parsePublish(header byte, body []byte) (Message, uint16, error) {
+	msg := Message{
+		QoS:      (header >> 1) & 0x03,
+		Retained: header&0x01 != 0,
+	}
+
+	p := &parser{buf: body}
+	msg.Topic = p.string()
+
+	var id uint16
+	if msg.QoS > 0 {
+		if b := p.bytes(2); len(b) == 2 {
+			id = binary.BigEndian.Uint16(b)
+		}
+	}
+	if p.err != nil {
+		return Message{}, 0, fmt.Errorf("mqtttest: malformed publish: %w", p.err)
+	}
+	msg.Payload = append([]byte(nil), p.buf...)
+
+	return msg, id, nil
+}
+
+// parser reads the fields of a packet, remembering the first error.
+type parser struct {
+	buf []byte
+	err error
+}
+
+func (p *parser) bytes(n int) []byte {
+	if p.err != nil {
+		return nil
+	}
+	if len(p.buf) < n {
+		p.err = io.ErrUnexpectedEOF
+		return nil
+	}
+
+	b := p.buf[:n]
+	p.buf = p.buf[n:]
+
+	return b
+}
+
+// string reads a string prefixed by its two byte length.
+func (p *parser) string() string {
+	n := p.bytes(2)
+	if n == nil {
+		return ""
+	}
+
+	return string(p.bytes(int(binary.BigEndian.Uint16(n))))
+}
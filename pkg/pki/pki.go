@@ -0,0 +1,180 @@
+// Package pki issues the certificates guarding the streams of receivers: a
+// CA of each receiver, and the server and client certificates it signs.
+package pki
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"slices"
+	"time"
+)
+
+const (
+	// CAValidity is the time a CA is valid for.
+	CAValidity = 10 * 365 * 24 * time.Hour
+	// CertificateValidity is the time a server or client certificate is
+	// valid for.
+	CertificateValidity = 365 * 24 * time.Hour
+
+	// clockSkew backdates certificates so they are valid on machines whose
+	// clocks are slightly behind.
+	clockSkew = 5 * time.Minute
+)
+
+// KeyPair is a certificate and its private key.
+type KeyPair struct {
+	Cert *x509.Certificate
+	Key  crypto.Signer
+
+	// CertPEM and KeyPEM are the PEM encoded certificate and PKCS #8
+	// private key.
+	CertPEM []byte
+	KeyPEM  []byte
+}
+
+// Request describes a certificate to issue.
+type Request struct {
+	// CommonName is the subject of the certificate.
+	CommonName string
+	// Hosts are the DNS names and IP addresses a server certificate is
+	// valid for.
+	Hosts []string
+	// Client issues a certificate authenticating a client instead of a
+	// server.
+	Client bool
+}
+
+// NewCA returns a self-signed CA valid from now for validity.
+func NewCA(commonName string, now time.Time, validity time.Duration) (*KeyPair, error) {
+	template := &x509.Certificate{
+		Subject:               pkix.Name{CommonName: commonName},
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	return create(template, nil, now, validity)
+}
+
+// Issue returns a certificate signed by the CA valid from now for validity.
+func (ca *KeyPair) Issue(req Request, now time.Time, validity time.Duration) (*KeyPair, error) {
+	template := &x509.Certificate{
+		Subject:     pkix.Name{CommonName: req.CommonName},
+		KeyUsage:    x509.KeyUsageDigitalSignature,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	if req.Client {
+		template.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}
+	}
+	for _, host := range req.Hosts {
+		if ip := net.ParseIP(host); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else {
+			template.DNSNames = append(template.DNSNames, host)
+		}
+	}
+
+	return create(template, ca, now, validity)
+}
+
+// Parse decodes a PEM encoded certificate and private key.
+func Parse(certPEM, keyPEM []byte) (*KeyPair, error) {
+	block, _ := pem.Decode(certPEM)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, errors.New("pki: no certificate found")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("pki: %w", err)
+	}
+
+	block, _ = pem.Decode(keyPEM)
+	if block == nil || block.Type != "PRIVATE KEY" {
+		return nil, errors.New("pki: no private key found")
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("pki: %w", err)
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("pki: unsupported private key %T", key)
+	}
+
+	return &KeyPair{Cert: cert, Key: signer, CertPEM: certPEM, KeyPEM: keyPEM}, nil
+}
+
+// RenewalTime returns when a certificate should be replaced, once two thirds
+// of its validity have passed.
+func RenewalTime(cert *x509.Certificate) time.Time {
+	return cert.NotBefore.Add(cert.NotAfter.Sub(cert.NotBefore) * 2 / 3)
+}
+
+// Valid reports whether a certificate was signed by the CA, is valid for
+// exactly the hosts, and is not yet due for renewal at now.
+func Valid(cert *x509.Certificate, ca *KeyPair, hosts []string, now time.Time) bool {
+	if cert.CheckSignatureFrom(ca.Cert) != nil || !now.Before(RenewalTime(cert)) {
+		return false
+	}
+
+	var names []string
+	names = append(names, cert.DNSNames...)
+	for _, ip := range cert.IPAddresses {
+		names = append(names, ip.String())
+	}
+
+	want := slices.Clone(hosts)
+	slices.Sort(names)
+	slices.Sort(want)
+
+	return slices.Equal(names, want)
+}
+
+// create signs template with parent, or itself without one.
+func create(template *x509.Certificate, parent *KeyPair, now time.Time, validity time.Duration) (*KeyPair, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("pki: %w", err)
+	}
+
+	template.SerialNumber, err = rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, fmt.Errorf("pki: %w", err)
+	}
+	template.NotBefore = now.Add(-clockSkew)
+	template.NotAfter = now.Add(validity)
+
+	signer, issuer := crypto.Signer(key), template
+	if parent != nil {
+		signer, issuer = parent.Key, parent.Cert
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, issuer, key.Public(), signer)
+	if err != nil {
+		return nil, fmt.Errorf("pki: %w", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, fmt.Errorf("pki: %w", err)
+	}
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("pki: %w", err)
+	}
+
+	return &KeyPair{
+		Cert:    cert,
+		Key:     key,
+		CertPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		KeyPEM:  pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}),
+	}, nil
+}
@@ -0,0 +1,84 @@
+package pki
+
+import (
+	"crypto/x509"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestPKI(t *testing.T) {
+	RegisterFailHandler(Fail)
+
+	RunSpecs(t, "PKI Suite")
+}
+
+var now = time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+
+var _ = Describe("CA", func() {
+	var ca *KeyPair
+
+	BeforeEach(func() {
+		var err error
+		ca, err = NewCA("test-ca", now, CAValidity)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("issues server certificates valid for their hosts", func() {
+		hosts := []string{"receiver.default.svc", "10.0.0.1"}
+		server, err := ca.Issue(Request{CommonName: "receiver", Hosts: hosts}, now, CertificateValidity)
+		Expect(err).ToNot(HaveOccurred())
+
+		roots := x509.NewCertPool()
+		roots.AddCert(ca.Cert)
+		for _, host := range hosts {
+			_, err = server.Cert.Verify(x509.VerifyOptions{
+				DNSName:     host,
+				Roots:       roots,
+				CurrentTime: now,
+			})
+			Expect(err).ToNot(HaveOccurred(), host)
+		}
+
+		Expect(Valid(server.Cert, ca, []string{"10.0.0.1", "receiver.default.svc"}, now)).To(BeTrue())
+		Expect(Valid(server.Cert, ca, []string{"receiver.default.svc"}, now)).To(BeFalse())
+	})
+
+	It("issues client certificates", func() {
+		client, err := ca.Issue(Request{CommonName: "client", Client: true}, now, CertificateValidity)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(client.Cert.ExtKeyUsage).To(Equal([]x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}))
+		Expect(Valid(client.Cert, ca, nil, now)).To(BeTrue())
+	})
+
+	It("parses what it issues", func() {
+		parsed, err := Parse(ca.CertPEM, ca.KeyPEM)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(parsed.Cert.Equal(ca.Cert)).To(BeTrue())
+		Expect(parsed.Cert.IsCA).To(BeTrue())
+
+		_, err = Parse(ca.CertPEM, []byte("garbage"))
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("renews certificates after two thirds of their validity", func() {
+		server, err := ca.Issue(Request{CommonName: "receiver"}, now, 90*24*time.Hour)
+		Expect(err).ToNot(HaveOccurred())
+
+		renewal := RenewalTime(server.Cert)
+		Expect(renewal).To(BeTemporally("~", now.Add(60*24*time.Hour), 5*time.Minute))
+		Expect(Valid(server.Cert, ca, nil, renewal.Add(-time.Minute))).To(BeTrue())
+		Expect(Valid(server.Cert, ca, nil, renewal)).To(BeFalse())
+	})
+
+	It("rejects certificates of another CA", func() {
+		other, err := NewCA("other-ca", now, CAValidity)
+		Expect(err).ToNot(HaveOccurred())
+		server, err := other.Issue(Request{CommonName: "receiver"}, now, CertificateValidity)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(Valid(server.Cert, ca, nil, now)).To(BeFalse())
+	})
+})
@@ -0,0 +1,173 @@
+package radioctl
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	radiov1beta1 "github.com/frelon/k8s-radio/api/v1beta1"
+	rtlsdr "github.com/frelon/k8s-radio/device-plugin/rtl-sdr"
+)
+
+// nodeDevices lists the dongles the device plugin published for each node.
+// The dongles are unknown when the nodes may not be listed.
+func (r *Radio) nodeDevices(ctx context.Context) (*corev1.NodeList, map[string][]rtlsdr.DeviceInfo, error) {
+	nodes := &corev1.NodeList{}
+	if err := r.Client.List(ctx, nodes); err != nil {
+		return nil, nil, err
+	}
+
+	devices := map[string][]rtlsdr.DeviceInfo{}
+	for _, node := range nodes.Items {
+		infos, err := rtlsdr.ParseDevices(node.Annotations)
+		if err != nil {
+			return nil, nil, fmt.Errorf("node %s: %w", node.Name, err)
+		}
+		devices[node.Name] = infos
+	}
+
+	return nodes, devices, nil
+}
+
+// List writes a table of the receivers in the namespace, or in all
+// namespaces, with the node and dongle they run on.
+func (r *Radio) List(ctx context.Context, allNamespaces bool) error {
+	var opts []client.ListOption
+	if !allNamespaces {
+		opts = append(opts, client.InNamespace(r.Namespace))
+	}
+
+	receivers := &radiov1beta1.RtlSdrReceiverList{}
+	if err := r.Client.List(ctx, receivers, opts...); err != nil {
+		return err
+	}
+	pods := &corev1.PodList{}
+	if err := r.Client.List(ctx, pods, append(opts, client.MatchingLabels{"app.kubernetes.io/name": "rtlsdrreceiver"})...); err != nil {
+		return err
+	}
+
+	serials := map[string]string{}
+	if _, devices, err := r.nodeDevices(ctx); err == nil {
+		for _, infos := range devices {
+			for _, info := range infos {
+				if info.Pod != "" {
+					serials[info.Pod] = info.Serial
+				}
+			}
+		}
+	}
+
+	w := tabwriter.NewWriter(r.Out, 0, 8, 3, ' ', 0)
+	header := []string{"NAME", "MODE", "FREQUENCY", "GAIN", "STATE", "NODE", "SERIAL"}
+	if allNamespaces {
+		header = append([]string{"NAMESPACE"}, header...)
+	}
+	writeRow(w, header...)
+
+	for _, receiver := range receivers.Items {
+		node, serial := none, none
+		for _, pod := range pods.Items {
+			if pod.Namespace != receiver.Namespace || pod.Labels["app.kubernetes.io/instance"] != receiver.Name {
+				continue
+			}
+			if pod.Spec.NodeName != "" {
+				node = pod.Spec.NodeName
+			}
+			if s, ok := serials[pod.Namespace+"/"+pod.Name]; ok {
+				serial = s
+			}
+		}
+
+		row := []string{receiver.Name, mode(&receiver), frequency(&receiver), gain(&receiver), state(&receiver), node, serial}
+		if allNamespaces {
+			row = append([]string{receiver.Namespace}, row...)
+		}
+		writeRow(w, row...)
+	}
+
+	return w.Flush()
+}
+
+// Devices writes a table of the dongles plugged into each node and the pods
+// they are allocated to. Dongles of nodes the device plugin publishes nothing
+// for are counted from the allocatable resources of the node.
+func (r *Radio) Devices(ctx context.Context) error {
+	nodes, devices, err := r.nodeDevices(ctx)
+	if err != nil {
+		return err
+	}
+	slices.SortFunc(nodes.Items, func(a, b corev1.Node) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	w := tabwriter.NewWriter(r.Out, 0, 8, 3, ' ', 0)
+	writeRow(w, "NODE", "SERIAL", "PRODUCT", "POD")
+
+	for _, node := range nodes.Items {
+		if infos := devices[node.Name]; infos != nil {
+			for _, info := range infos {
+				pod := info.Pod
+				if pod == "" {
+					pod = none
+				}
+				writeRow(w, node.Name, info.Serial, info.Product, pod)
+			}
+			continue
+		}
+
+		allocatable := node.Status.Allocatable[corev1.ResourceName(rtlsdr.ResourceFullName)]
+		for range allocatable.Value() {
+			writeRow(w, node.Name, "<unknown>", "<unknown>", "<unknown>")
+		}
+	}
+
+	return w.Flush()
+}
+
+// writeRow writes the tab separated columns of a table row.
+func writeRow(w io.Writer, columns ...string) {
+	_, _ = fmt.Fprintln(w, strings.Join(columns, "\t"))
+}
+
+// mode returns the mode of the receiver, IQ unless set.
+func mode(receiver *radiov1beta1.RtlSdrReceiver) string {
+	if receiver.Spec.Mode == "" {
+		return string(radiov1beta1.ModeIQ)
+	}
+
+	return string(receiver.Spec.Mode)
+}
+
+// frequency returns the frequency the receiver is tuned to.
+func frequency(receiver *radiov1beta1.RtlSdrReceiver) string {
+	if receiver.Spec.Frequency == nil {
+		return none
+	}
+
+	return receiver.Spec.Frequency.String()
+}
+
+// gain returns the tuner gain of the receiver in dB.
+func gain(receiver *radiov1beta1.RtlSdrReceiver) string {
+	if receiver.Spec.Gain == nil {
+		return "auto"
+	}
+
+	return strconv.FormatFloat(receiver.Spec.Gain.AsApproximateFloat64(), 'f', -1, 64)
+}
+
+// state returns the state of the receiver.
+func state(receiver *radiov1beta1.RtlSdrReceiver) string {
+	if receiver.Status.State == "" {
+		return none
+	}
+
+	return string(receiver.Status.State)
+}
@@ -0,0 +1,91 @@
+package radioctl
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/portforward"
+	"k8s.io/client-go/transport/spdy"
+)
+
+// PortForwarder connects to ports of pods through the API server, like
+// kubectl port-forward.
+type PortForwarder struct {
+	Config *rest.Config
+}
+
+// Dial forwards a local port to port of the pod and connects to it. The
+// forwarding stops once the connection is closed.
+func (f PortForwarder) Dial(ctx context.Context, pod *corev1.Pod, port int) (net.Conn, error) {
+	clientset, err := kubernetes.NewForConfig(f.Config)
+	if err != nil {
+		return nil, err
+	}
+	transport, upgrader, err := spdy.RoundTripperFor(f.Config)
+	if err != nil {
+		return nil, err
+	}
+	url := clientset.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(pod.Namespace).
+		Name(pod.Name).
+		SubResource("portforward").
+		URL()
+	dialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, http.MethodPost, url)
+
+	stop, ready := make(chan struct{}), make(chan struct{})
+	fw, err := portforward.NewOnAddresses(dialer, []string{"127.0.0.1"}, []string{"0:" + strconv.Itoa(port)}, stop, ready, io.Discard, io.Discard)
+	if err != nil {
+		return nil, err
+	}
+
+	errs := make(chan error, 1)
+	go func() { errs <- fw.ForwardPorts() }()
+
+	select {
+	case <-ready:
+	case err := <-errs:
+		return nil, fmt.Errorf("failed forwarding port %d of pod %s: %w", port, pod.Name, err)
+	case <-ctx.Done():
+		close(stop)
+		return nil, ctx.Err()
+	}
+
+	ports, err := fw.GetPorts()
+	if err != nil {
+		close(stop)
+		return nil, err
+	}
+
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(int(ports[0].Local))))
+	if err != nil {
+		close(stop)
+		return nil, err
+	}
+
+	return &forwardedConn{Conn: conn, stop: stop}, nil
+}
+
+// forwardedConn stops forwarding the port once closed.
+type forwardedConn struct {
+	net.Conn
+
+	once sync.Once
+	stop chan struct{}
+}
+
+func (c *forwardedConn) Close() error {
+	err := c.Conn.Close()
+	c.once.Do(func() { close(c.stop) })
+
+	return err
+}
@@ -0,0 +1,138 @@
+// Package radioctl implements the day-to-day operations on receivers of the
+// kubectl radio plugin: tuning them, listing them and the dongles of the
+// cluster, listening to them and recording them.
+package radioctl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	radiov1beta1 "github.com/frelon/k8s-radio/api/v1beta1"
+)
+
+// none is shown for unset columns, as kubectl does.
+const none = "<none>"
+
+// ErrNotRunning is returned when a receiver has no running pod to connect to.
+var ErrNotRunning = errors.New("receiver is not running")
+
+// Forwarder connects to ports of pods, such as through the port forwarding
+// of the API server.
+type Forwarder interface {
+	Dial(ctx context.Context, pod *corev1.Pod, port int) (net.Conn, error)
+}
+
+// Radio runs the commands of the plugin against a cluster.
+type Radio struct {
+	Client client.Client
+	// Namespace is the namespace receivers are looked up in.
+	Namespace string
+	// Forwarder connects to the streams of receivers.
+	Forwarder Forwarder
+	// Out is where tables and messages are written.
+	Out io.Writer
+}
+
+// receiverLabels returns the labels the controller sets on the pods of a
+// receiver.
+func receiverLabels(name string) client.MatchingLabels {
+	return client.MatchingLabels{
+		"app.kubernetes.io/name":     "rtlsdrreceiver",
+		"app.kubernetes.io/instance": name,
+	}
+}
+
+// receiver returns the receiver named name.
+func (r *Radio) receiver(ctx context.Context, name string) (*radiov1beta1.RtlSdrReceiver, error) {
+	receiver := &radiov1beta1.RtlSdrReceiver{}
+	if err := r.Client.Get(ctx, client.ObjectKey{Namespace: r.Namespace, Name: name}, receiver); err != nil {
+		return nil, err
+	}
+
+	return receiver, nil
+}
+
+// runningPod returns the running pod of the receiver.
+func (r *Radio) runningPod(ctx context.Context, receiver *radiov1beta1.RtlSdrReceiver) (*corev1.Pod, error) {
+	pods := &corev1.PodList{}
+	if err := r.Client.List(ctx, pods, client.InNamespace(receiver.Namespace), receiverLabels(receiver.Name)); err != nil {
+		return nil, err
+	}
+
+	for i := range pods.Items {
+		if pod := &pods.Items[i]; pod.Status.Phase == corev1.PodRunning {
+			return pod, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrNotRunning, receiver.Name)
+}
+
+// streamEndpoint returns the in-cluster URL of the stream of the receiver,
+// telling the scheme, port and path to connect with.
+func streamEndpoint(receiver *radiov1beta1.RtlSdrReceiver) (*url.URL, int, error) {
+	if receiver.Status.Endpoint == "" {
+		return nil, 0, fmt.Errorf("%w: %s has no endpoint yet", ErrNotRunning, receiver.Name)
+	}
+
+	u, err := url.Parse(receiver.Status.Endpoint)
+	if err != nil {
+		return nil, 0, fmt.Errorf("invalid endpoint %q: %w", receiver.Status.Endpoint, err)
+	}
+	port, err := strconv.Atoi(u.Port())
+	if err != nil {
+		return nil, 0, fmt.Errorf("invalid endpoint %q: %w", receiver.Status.Endpoint, err)
+	}
+
+	return u, port, nil
+}
+
+// Tune sets the frequency of the receiver.
+func (r *Radio) Tune(ctx context.Context, name string, frequency resource.Quantity) error {
+	receiver, err := r.receiver(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	patch := client.MergeFrom(receiver.DeepCopy())
+	receiver.Spec.Frequency = &frequency
+	if err := r.Client.Patch(ctx, receiver, patch); err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(r.Out, "rtlsdrreceiver/%s tuned to %s\n", name, frequency.String())
+
+	return err
+}
+
+// Gain sets the tuner gain of the receiver in dB, or automatic gain when
+// decibels is nil.
+func (r *Radio) Gain(ctx context.Context, name string, decibels *resource.Quantity) error {
+	receiver, err := r.receiver(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	patch := client.MergeFrom(receiver.DeepCopy())
+	receiver.Spec.Gain = decibels
+	if err := r.Client.Patch(ctx, receiver, patch); err != nil {
+		return err
+	}
+
+	if receiver.Spec.Gain == nil {
+		_, err = fmt.Fprintf(r.Out, "rtlsdrreceiver/%s set to automatic gain\n", name)
+	} else {
+		_, err = fmt.Fprintf(r.Out, "rtlsdrreceiver/%s gain set to %s dB\n", name, gain(receiver))
+	}
+
+	return err
+}
@@ -0,0 +1,263 @@
+package radioctl
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	radiov1beta1 "github.com/frelon/k8s-radio/api/v1beta1"
+	rtlsdr "github.com/frelon/k8s-radio/device-plugin/rtl-sdr"
+	"github.com/frelon/k8s-radio/pkg/rtltcp"
+	"github.com/frelon/k8s-radio/pkg/sigmf"
+)
+
+func TestRadioctl(t *testing.T) {
+	RegisterFailHandler(Fail)
+
+	RunSpecs(t, "Radioctl Suite")
+}
+
+// dialer is a Forwarder calling a function.
+type dialer func(pod *corev1.Pod, port int) (net.Conn, error)
+
+func (d dialer) Dial(_ context.Context, pod *corev1.Pod, port int) (net.Conn, error) {
+	return d(pod, port)
+}
+
+func newReceiver(name string, spec radiov1beta1.RtlSdrReceiverSpec, endpoint string) *radiov1beta1.RtlSdrReceiver {
+	return &radiov1beta1.RtlSdrReceiver{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec:       spec,
+		Status:     radiov1beta1.RtlSdrReceiverStatus{State: radiov1beta1.StateRunning, Endpoint: endpoint},
+	}
+}
+
+func newPod(receiver, node string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      receiver + "-pod",
+			Namespace: "default",
+			Labels:    receiverLabels(receiver),
+		},
+		Spec:   corev1.PodSpec{NodeName: node},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+}
+
+var _ = Describe("Radio", func() {
+	var (
+		out   *bytes.Buffer
+		radio *Radio
+		ctx   context.Context
+	)
+
+	setup := func(forwarder Forwarder, objs ...client.Object) {
+		scheme := runtime.NewScheme()
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+		Expect(radiov1beta1.AddToScheme(scheme)).To(Succeed())
+
+		out = &bytes.Buffer{}
+		radio = &Radio{
+			Client:    fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build(),
+			Namespace: "default",
+			Forwarder: forwarder,
+			Out:       out,
+		}
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+	})
+
+	Context("When tuning a receiver", func() {
+		It("Should patch the frequency and gain", func() {
+			setup(nil, newReceiver("noaa", radiov1beta1.RtlSdrReceiverSpec{Frequency: ptr.To(resource.MustParse("137M"))}, ""))
+
+			Expect(radio.Tune(ctx, "noaa", resource.MustParse("145.8M"))).To(Succeed())
+			Expect(radio.Gain(ctx, "noaa", ptr.To(resource.MustParse("40.2")))).To(Succeed())
+
+			receiver, err := radio.receiver(ctx, "noaa")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(receiver.Spec.Frequency.Value()).To(Equal(int64(145_800_000)))
+			Expect(receiver.Spec.Gain.AsApproximateFloat64()).To(Equal(40.2))
+
+			Expect(radio.Gain(ctx, "noaa", nil)).To(Succeed())
+			receiver, err = radio.receiver(ctx, "noaa")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(receiver.Spec.Gain).To(BeNil())
+
+			Expect(out.String()).To(Equal("rtlsdrreceiver/noaa tuned to 145800k\n" +
+				"rtlsdrreceiver/noaa gain set to 40.2 dB\n" +
+				"rtlsdrreceiver/noaa set to automatic gain\n"))
+		})
+
+		It("Should fail for missing receivers", func() {
+			setup(nil)
+
+			Expect(radio.Tune(ctx, "missing", resource.MustParse("145.8M"))).ToNot(Succeed())
+		})
+	})
+
+	Context("When listing receivers and devices", func() {
+		BeforeEach(func() {
+			pi := &corev1.Node{ObjectMeta: metav1.ObjectMeta{
+				Name: "pi",
+				Annotations: map[string]string{
+					rtlsdr.DevicesAnnotation: `[{"serial":"00000001","product":"0bda:2838","pod":"default/noaa-pod"},{"serial":"00000002","product":"0bda:2838"}]`,
+				},
+			}}
+			old := &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{Name: "old"},
+				Status: corev1.NodeStatus{Allocatable: corev1.ResourceList{
+					rtlsdr.ResourceFullName: resource.MustParse("1"),
+				}},
+			}
+
+			setup(nil,
+				newReceiver("noaa", radiov1beta1.RtlSdrReceiverSpec{Frequency: ptr.To(resource.MustParse("137M")), Gain: ptr.To(resource.MustParse("40.2"))}, ""),
+				newReceiver("idle", radiov1beta1.RtlSdrReceiverSpec{Mode: radiov1beta1.ModeFM}, ""),
+				newPod("noaa", "pi"),
+				pi, old,
+			)
+		})
+
+		It("Should list receivers with their node and serial", func() {
+			Expect(radio.List(ctx, false)).To(Succeed())
+
+			Expect(out.String()).To(Equal("" +
+				"NAME   MODE   FREQUENCY   GAIN   STATE     NODE     SERIAL\n" +
+				"idle   FM     <none>      auto   Running   <none>   <none>\n" +
+				"noaa   IQ     137M        40.2   Running   pi       00000001\n"))
+		})
+
+		It("Should list the namespaces of receivers in all namespaces", func() {
+			Expect(radio.List(ctx, true)).To(Succeed())
+
+			Expect(out.String()).To(HavePrefix("NAMESPACE   NAME"))
+			Expect(out.String()).To(ContainSubstring("default     noaa"))
+		})
+
+		It("Should list the dongles of each node", func() {
+			Expect(radio.Devices(ctx)).To(Succeed())
+
+			Expect(out.String()).To(Equal("" +
+				"NODE   SERIAL      PRODUCT     POD\n" +
+				"old    <unknown>   <unknown>   <unknown>\n" +
+				"pi     00000001    0bda:2838   default/noaa-pod\n" +
+				"pi     00000002    0bda:2838   <none>\n"))
+		})
+	})
+
+	Context("When recording a receiver", func() {
+		It("Should write the I/Q stream to a SigMF dataset", func() {
+			samples := bytes.Repeat([]byte{127, 128}, 4096)
+			var dialed int
+			forwarder := dialer(func(pod *corev1.Pod, port int) (net.Conn, error) {
+				Expect(pod.Name).To(Equal("noaa-pod"))
+				dialed = port
+				server, conn := net.Pipe()
+				go func() {
+					defer GinkgoRecover()
+					defer func() { _ = server.Close() }()
+					Expect(rtltcp.WriteDongleInfo(server, rtltcp.DongleInfo{Tuner: rtltcp.TunerR820T, GainCount: 29})).To(Succeed())
+					_, _ = server.Write(samples)
+				}()
+
+				return conn, nil
+			})
+			setup(forwarder,
+				newReceiver("noaa", radiov1beta1.RtlSdrReceiverSpec{
+					Frequency:  ptr.To(resource.MustParse("137.1M")),
+					SampleRate: ptr.To(resource.MustParse("1024k")),
+					Gain:       ptr.To(resource.MustParse("40.2")),
+				}, "tcp://noaa.default.svc:1234"),
+				newPod("noaa", "pi"),
+			)
+
+			base := filepath.Join(GinkgoT().TempDir(), "noaa")
+			n, err := radio.Record(ctx, "noaa", base, 0)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(dialed).To(Equal(1234))
+			Expect(n).To(Equal(uint64(len(samples) / 2)))
+
+			data, err := os.ReadFile(base + sigmf.DataSuffix)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(data).To(Equal(samples))
+
+			meta, err := sigmf.ReadMeta(base + sigmf.MetaSuffix)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(meta.Global.SampleRate).To(Equal(1_024_000.0))
+			Expect(meta.Global.Recorder).To(Equal("kubectl-radio"))
+			Expect(meta.Global.Receiver).To(Equal("default/noaa"))
+			Expect(meta.Global.Node).To(Equal("pi"))
+			Expect(meta.Captures).To(HaveLen(1))
+			Expect(meta.Captures[0].Frequency).To(Equal(137_100_000.0))
+			Expect(*meta.Captures[0].Gain).To(Equal(40.2))
+		})
+
+		It("Should refuse receivers without an I/Q stream", func() {
+			setup(nil, newReceiver("fm", radiov1beta1.RtlSdrReceiverSpec{Mode: radiov1beta1.ModeFM}, "http://fm.default.svc:8000/audio.wav"), newPod("fm", "pi"))
+
+			_, err := radio.Record(ctx, "fm", filepath.Join(GinkgoT().TempDir(), "fm"), 0)
+			Expect(err).To(MatchError(ContainSubstring("only IQ receivers")))
+		})
+
+		It("Should refuse guarded receivers", func() {
+			setup(nil, newReceiver("noaa", radiov1beta1.RtlSdrReceiverSpec{}, "tls://noaa.default.svc:1234"), newPod("noaa", "pi"))
+
+			_, err := radio.Record(ctx, "noaa", filepath.Join(GinkgoT().TempDir(), "noaa"), 0)
+			Expect(err).To(MatchError(ErrGuarded))
+		})
+
+		It("Should refuse receivers that are not running", func() {
+			setup(nil, newReceiver("noaa", radiov1beta1.RtlSdrReceiverSpec{}, "tcp://noaa.default.svc:1234"))
+
+			_, err := radio.Record(ctx, "noaa", filepath.Join(GinkgoT().TempDir(), "noaa"), 0)
+			Expect(err).To(MatchError(ErrNotRunning))
+		})
+	})
+
+	Context("When listening to a receiver", func() {
+		It("Should copy the audio of FM receivers", func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				Expect(r.URL.Path).To(Equal("/audio.wav"))
+				_, _ = io.WriteString(w, "RIFF")
+			}))
+			DeferCleanup(server.Close)
+
+			var dialed int
+			forwarder := dialer(func(_ *corev1.Pod, port int) (net.Conn, error) {
+				dialed = port
+				return net.Dial("tcp", server.Listener.Addr().String())
+			})
+			setup(forwarder, newReceiver("fm", radiov1beta1.RtlSdrReceiverSpec{Mode: radiov1beta1.ModeFM}, "http://fm.default.svc:8000/audio.wav"), newPod("fm", "pi"))
+
+			audio := &bytes.Buffer{}
+			Expect(radio.Listen(ctx, "fm", audio)).To(Succeed())
+			Expect(dialed).To(Equal(8000))
+			Expect(audio.String()).To(Equal("RIFF"))
+		})
+
+		It("Should refuse receivers without audio", func() {
+			setup(nil, newReceiver("noaa", radiov1beta1.RtlSdrReceiverSpec{}, "tcp://noaa.default.svc:1234"), newPod("noaa", "pi"))
+
+			Expect(radio.Listen(ctx, "noaa", io.Discard)).To(MatchError(ContainSubstring("only FM receivers")))
+		})
+	})
+})
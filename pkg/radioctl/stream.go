@@ -0,0 +1,185 @@
+package radioctl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	radiov1beta1 "github.com/frelon/k8s-radio/api/v1beta1"
+	"github.com/frelon/k8s-radio/pkg/rtltcp"
+	"github.com/frelon/k8s-radio/pkg/sigmf"
+)
+
+const (
+	// defaultSampleRate is the sample rate rtl_tcp starts with unless told
+	// otherwise.
+	defaultSampleRate = 2_048_000
+
+	// recorder is the recorder written to the metadata of recordings.
+	recorder = "kubectl-radio"
+)
+
+// ErrGuarded is returned when connecting to a receiver whose stream is only
+// served over TLS to authenticated clients.
+var ErrGuarded = errors.New("connecting to guarded receivers is not supported")
+
+// Listen copies the audio of an FM receiver to w until ctx is done or the
+// stream ends.
+func (r *Radio) Listen(ctx context.Context, name string, w io.Writer) error {
+	receiver, err := r.receiver(ctx, name)
+	if err != nil {
+		return err
+	}
+	if receiver.Spec.Mode != radiov1beta1.ModeFM {
+		return fmt.Errorf("receiver %s is in %s mode, only FM receivers have audio", name, mode(receiver))
+	}
+
+	endpoint, port, err := streamEndpoint(receiver)
+	if err != nil {
+		return err
+	}
+	if endpoint.Scheme != "http" {
+		return ErrGuarded
+	}
+	pod, err := r.runningPod(ctx, receiver)
+	if err != nil {
+		return err
+	}
+
+	httpClient := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return r.Forwarder.Dial(ctx, pod, port)
+		},
+	}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint.String(), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed fetching the audio: %s", resp.Status)
+	}
+
+	_, err = io.Copy(w, resp.Body)
+	if ctx.Err() != nil {
+		return nil
+	}
+
+	return err
+}
+
+// Record writes the I/Q stream of a receiver to the SigMF dataset at base,
+// until duration has passed, ctx is done or the stream ends. A duration of
+// zero records until ctx is done. It returns the number of samples recorded.
+func (r *Radio) Record(ctx context.Context, name, base string, duration time.Duration) (uint64, error) {
+	receiver, err := r.receiver(ctx, name)
+	if err != nil {
+		return 0, err
+	}
+	if mode(receiver) != string(radiov1beta1.ModeIQ) {
+		return 0, fmt.Errorf("receiver %s is in %s mode, only IQ receivers have an I/Q stream", name, mode(receiver))
+	}
+
+	endpoint, port, err := streamEndpoint(receiver)
+	if err != nil {
+		return 0, err
+	}
+	if endpoint.Scheme != "tcp" {
+		return 0, ErrGuarded
+	}
+	pod, err := r.runningPod(ctx, receiver)
+	if err != nil {
+		return 0, err
+	}
+
+	if duration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, duration)
+		defer cancel()
+	}
+
+	conn, err := r.Forwarder.Dial(ctx, pod, port)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = conn.Close() }()
+	stop := context.AfterFunc(ctx, func() { _ = conn.Close() })
+	defer stop()
+
+	c, err := rtltcp.NewClient(conn)
+	if err != nil {
+		return 0, err
+	}
+
+	data, err := os.Create(base + sigmf.DataSuffix)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = data.Close() }()
+
+	start := time.Now()
+	n, err := io.Copy(data, c)
+	if ctx.Err() != nil || errors.Is(err, io.EOF) {
+		err = nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	if err := data.Close(); err != nil {
+		return 0, err
+	}
+
+	samples := uint64(n / 2)
+
+	return samples, sigmf.WriteMeta(base+sigmf.MetaSuffix, r.recordingMeta(ctx, receiver, pod.Spec.NodeName, pod.Namespace+"/"+pod.Name, start))
+}
+
+// recordingMeta returns the metadata of a recording of the receiver started
+// at start.
+func (r *Radio) recordingMeta(ctx context.Context, receiver *radiov1beta1.RtlSdrReceiver, node, pod string, start time.Time) *sigmf.Meta {
+	sampleRate := float64(defaultSampleRate)
+	if receiver.Spec.SampleRate != nil {
+		sampleRate = receiver.Spec.SampleRate.AsApproximateFloat64()
+	}
+
+	meta := &sigmf.Meta{
+		Global: sigmf.Global{
+			Datatype:   sigmf.DatatypeCU8,
+			Version:    sigmf.Version,
+			SampleRate: sampleRate,
+			Hardware:   "RTL-SDR",
+			Recorder:   recorder,
+			Extensions: []sigmf.ExtensionInfo{{Name: sigmf.Extension, Version: sigmf.Version, Optional: true}},
+			Node:       node,
+			Receiver:   receiver.Namespace + "/" + receiver.Name,
+		},
+		Captures: []sigmf.Capture{{Datetime: sigmf.Datetime(start)}},
+	}
+	if receiver.Spec.Frequency != nil {
+		meta.Captures[0].Frequency = receiver.Spec.Frequency.AsApproximateFloat64()
+	}
+	if receiver.Spec.Gain != nil {
+		gain := receiver.Spec.Gain.AsApproximateFloat64()
+		meta.Captures[0].Gain = &gain
+	}
+
+	// The serial is only known when the device plugin published it.
+	if _, devices, err := r.nodeDevices(context.WithoutCancel(ctx)); err == nil {
+		for _, info := range devices[node] {
+			if info.Pod == pod {
+				meta.Global.Serial = info.Serial
+			}
+		}
+	}
+
+	return meta
+}
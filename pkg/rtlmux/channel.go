@@ -0,0 +1,243 @@
+package rtlmux
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/frelon/k8s-radio/pkg/dsp"
+	"github.com/frelon/k8s-radio/pkg/rtltcp"
+)
+
+// ErrReadOnly is returned when tuning a channel.
+var ErrReadOnly = errors.New("rtlmux: channels cannot be tuned")
+
+// Channel is an upstream serving a narrowband channel down-converted from
+// the stream of a mux. Serve it with another mux to share it with clients.
+type Channel struct {
+	name   string
+	parent *Mux
+	ddc    *dsp.DDC
+
+	errMu sync.Mutex
+	err   error
+}
+
+var _ Upstream = (*Channel)(nil)
+
+// NewChannel returns the channel named name, produced by ddc from the
+// stream of parent.
+func NewChannel(name string, parent *Mux, ddc *dsp.DDC) *Channel {
+	return &Channel{
+		name:   name,
+		parent: parent,
+		ddc:    ddc,
+	}
+}
+
+// Info returns the dongle info of the parent stream.
+func (c *Channel) Info() rtltcp.DongleInfo {
+	return c.parent.upstream.Info()
+}
+
+// Stream down-converts the parent stream into chunks of size bytes and sends
+// them on the returned channel, which buffers at most depth chunks. The
+// channel is closed when ctx is done or the parent drops the subscription,
+// after which Err reports the cause.
+func (c *Channel) Stream(ctx context.Context, size, depth int) <-chan []byte {
+	ch := make(chan []byte, depth)
+
+	in, unsubscribe, err := c.parent.Subscribe("channel/" + c.name)
+	if err != nil {
+		c.setErr(err)
+		close(ch)
+		return ch
+	}
+
+	go func() {
+		defer close(ch)
+		defer unsubscribe()
+
+		var pending []byte
+		for {
+			select {
+			case <-ctx.Done():
+				c.setErr(ctx.Err())
+				return
+			case chunk, ok := <-in:
+				if !ok {
+					c.setErr(ErrClosed)
+					return
+				}
+
+				pending = c.ddc.Process(pending, chunk)
+			}
+
+			for len(pending) >= size {
+				out := make([]byte, size)
+				copy(out, pending)
+				pending = pending[:copy(pending, pending[size:])]
+
+				select {
+				case ch <- out:
+				case <-ctx.Done():
+					c.setErr(ctx.Err())
+					return
+				}
+			}
+		}
+	}()
+
+	return ch
+}
+
+// Err returns the error that ended the stream.
+func (c *Channel) Err() error {
+	c.errMu.Lock()
+	defer c.errMu.Unlock()
+
+	return c.err
+}
+
+func (c *Channel) setErr(err error) {
+	c.errMu.Lock()
+	defer c.errMu.Unlock()
+
+	c.err = err
+}
+
+// SendCommand always fails, the tuning of a channel is fixed.
+func (c *Channel) SendCommand(rtltcp.Command, uint32) error {
+	return ErrReadOnly
+}
+
+// restartInterval is the time between attempts to restart a failed channel.
+const restartInterval = time.Second
+
+// Config lists the channels split from the stream of a mux. It is written
+// by the controller and read by the sidecar.
+type Config struct {
+	// SampleRate is the sample rate of the parent stream in Hz.
+	SampleRate float64 `json:"sampleRate"`
+	// Channels are the channels to serve.
+	Channels []ChannelConfig `json:"channels,omitempty"`
+}
+
+// ChannelConfig describes a channel and the port it is served on.
+type ChannelConfig struct {
+	Name string `json:"name"`
+	Port int32  `json:"port"`
+	// Offset is the center of the channel relative to the parent stream.
+	Offset float64 `json:"offset"`
+	// Bandwidth is the width of the channel filter in Hz.
+	Bandwidth float64 `json:"bandwidth"`
+	// Decimation is the ratio of the parent and channel sample rates.
+	Decimation int `json:"decimation"`
+}
+
+// Channels serves the channels of a mux, each on its own port with its own
+// read-only mux.
+type Channels struct {
+	// Host is the host the channels listen on, all interfaces if empty.
+	Host string
+
+	parent *Mux
+
+	mu      sync.Mutex
+	running map[string]*runningChannel
+}
+
+type runningChannel struct {
+	config ChannelConfig
+	cancel context.CancelFunc
+}
+
+// NewChannels returns the channels of parent.
+func NewChannels(parent *Mux) *Channels {
+	return &Channels{
+		parent:  parent,
+		running: make(map[string]*runningChannel),
+	}
+}
+
+// Update starts the channels in config and stops the running channels that
+// were removed or changed. Channels run until ctx is done.
+func (c *Channels) Update(ctx context.Context, config Config) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	wanted := make(map[string]ChannelConfig, len(config.Channels))
+	for _, ch := range config.Channels {
+		wanted[ch.Name] = ch
+	}
+
+	for name, r := range c.running {
+		if cfg, ok := wanted[name]; ok && cfg == r.config {
+			continue
+		}
+
+		slog.Info("Stopping channel", slog.String("channel", name))
+		r.cancel()
+		delete(c.running, name)
+	}
+
+	for name, cfg := range wanted {
+		if _, ok := c.running[name]; ok {
+			continue
+		}
+
+		slog.Info("Starting channel",
+			slog.String("channel", name),
+			slog.Int("port", int(cfg.Port)),
+			slog.Float64("offset", cfg.Offset),
+			slog.Int("decimation", cfg.Decimation))
+
+		runCtx, cancel := context.WithCancel(ctx)
+		c.running[name] = &runningChannel{config: cfg, cancel: cancel}
+		go c.run(runCtx, config.SampleRate, cfg)
+	}
+}
+
+// run serves the channel until ctx is done, restarting it when it fails.
+func (c *Channels) run(ctx context.Context, sampleRate float64, cfg ChannelConfig) {
+	for {
+		err := c.serve(ctx, sampleRate, cfg)
+		if ctx.Err() != nil {
+			return
+		}
+
+		slog.Error("Channel failed, restarting", slog.String("channel", cfg.Name), slog.Any("error", err))
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(restartInterval):
+		}
+	}
+}
+
+func (c *Channels) serve(ctx context.Context, sampleRate float64, cfg ChannelConfig) error {
+	l, err := net.Listen("tcp", net.JoinHostPort(c.Host, strconv.Itoa(int(cfg.Port))))
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	ddc := dsp.NewDDC(sampleRate, cfg.Offset, cfg.Bandwidth, cfg.Decimation)
+	m := New(NewChannel(cfg.Name, c.parent, ddc), PolicyReadOnly)
+
+	// Keep the latency of the channel near that of the parent stream.
+	m.ChunkSize = max(chunkSize/ddc.Decimation(), 512) &^ 1
+
+	go func() {
+		_ = m.Serve(ctx, l)
+	}()
+
+	return m.Run(ctx)
+}
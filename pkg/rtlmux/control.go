@@ -0,0 +1,139 @@
+package rtlmux
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/frelon/k8s-radio/pkg/rtltcp"
+)
+
+// Setting is the tuning of the upstream and the generation of the receiver
+// it was last set from, exchanged with the controller over the control API.
+type Setting struct {
+	// Generation is the generation of the receiver the tuning was last set
+	// from, zero until the controller set it.
+	Generation int64 `json:"generation"`
+	// Tuning is the tuning of the upstream. It is the one last set from the
+	// receiver unless a client retuned the upstream since.
+	Tuning rtltcp.Tuning `json:"tuning"`
+}
+
+// Control retunes the upstream of a mux on behalf of the controller, so that
+// changing the tuning of a receiver does not restart rtl_tcp. It remembers
+// the generation of the receiver the tuning was set from, which tells the
+// controller whether the upstream is still tuned as the receiver specifies,
+// also after a restart.
+type Control struct {
+	// TokenFile holds the bearer token the controller authenticates with.
+	// It is read on every request, so a replaced token is accepted without
+	// a restart. Without it every request is rejected.
+	TokenFile string
+
+	mux *Mux
+
+	mu         sync.Mutex
+	generation int64
+}
+
+// NewControl returns the control of the upstream of the mux.
+func NewControl(m *Mux) *Control {
+	return &Control{mux: m}
+}
+
+// Setting returns the current setting of the upstream.
+func (c *Control) Setting() Setting {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return Setting{Generation: c.generation, Tuning: c.mux.Tuning()}
+}
+
+// Set tunes the upstream to the setting and returns the resulting one. The
+// generation is kept when the upstream fails to take the tuning.
+func (c *Control) Set(s Setting) (Setting, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.mux.Tune(s.Tuning); err != nil {
+		return Setting{Generation: c.generation, Tuning: c.mux.Tuning()}, err
+	}
+	c.generation = s.Generation
+
+	return Setting{Generation: c.generation, Tuning: c.mux.Tuning()}, nil
+}
+
+// errUnauthenticated is returned for requests without the token of the
+// controller.
+var errUnauthenticated = errors.New("rtlmux: unauthenticated")
+
+// ServeHTTP serves the setting of the upstream on GET and tunes it to the
+// one sent on PUT, only to the controller, as retuning bypasses the lock
+// policy and whatever guards the stream.
+func (c *Control) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := c.authenticate(r); err != nil {
+		slog.Info("Rejected control request", slog.String("remote", r.RemoteAddr), slog.Any("error", err))
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		http.Error(w, errUnauthenticated.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeSetting(w, c.Setting())
+	case http.MethodPut:
+		var setting Setting
+		if err := json.NewDecoder(r.Body).Decode(&setting); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		setting, err := c.Set(setting)
+		if err != nil {
+			slog.Error("Failed retuning rtl_tcp", slog.Any("error", err))
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+
+		slog.Info("Retuned rtl_tcp",
+			slog.Int64("generation", setting.Generation),
+			slog.Any("frequency", setting.Tuning.Frequency))
+		writeSetting(w, setting)
+	default:
+		w.Header().Set("Allow", "GET, PUT")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+	}
+}
+
+// authenticate checks the request carries the bearer token of the
+// controller.
+func (c *Control) authenticate(r *http.Request) error {
+	if c.TokenFile == "" {
+		return errUnauthenticated
+	}
+
+	want, err := os.ReadFile(c.TokenFile)
+	if err != nil {
+		return err
+	}
+	want = bytes.TrimSpace(want)
+
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || len(want) == 0 || subtle.ConstantTimeCompare(want, []byte(strings.TrimSpace(token))) != 1 {
+		return errUnauthenticated
+	}
+
+	return nil
+}
+
+// writeSetting serves the setting as JSON.
+func writeSetting(w http.ResponseWriter, setting Setting) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(setting)
+}
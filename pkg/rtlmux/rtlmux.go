@@ -0,0 +1,426 @@
+// Package rtlmux shares one rtl_tcp connection among many clients.
+package rtlmux
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/frelon/k8s-radio/pkg/loss"
+	"github.com/frelon/k8s-radio/pkg/rtltcp"
+)
+
+const (
+	// chunkSize is the size of the chunks of I/Q samples sent to clients.
+	chunkSize = 32 * 1024
+	// clientBuffer is the number of chunks buffered per client before it is
+	// considered too slow and dropped.
+	clientBuffer = 128
+	// headerTimeout bounds how long sending the header to a client may take.
+	headerTimeout = 5 * time.Second
+)
+
+// Policy decides which clients may send tuning commands.
+type Policy string
+
+const (
+	// PolicyFirstClient lets the longest connected client tune. The others
+	// are read-only until it disconnects.
+	PolicyFirstClient Policy = "FirstClient"
+	// PolicyOpen lets every client tune, the last command wins.
+	PolicyOpen Policy = "Open"
+	// PolicyReadOnly ignores tuning commands from all clients.
+	PolicyReadOnly Policy = "ReadOnly"
+)
+
+// ParsePolicy returns the policy named s.
+func ParsePolicy(s string) (Policy, error) {
+	switch p := Policy(s); p {
+	case PolicyFirstClient, PolicyOpen, PolicyReadOnly:
+		return p, nil
+	default:
+		return "", fmt.Errorf("unknown lock policy %q", s)
+	}
+}
+
+// ErrClosed is returned by Serve once the upstream stream has ended.
+var ErrClosed = errors.New("rtlmux: upstream closed")
+
+// Upstream is the source of the samples shared by a mux. It is implemented
+// by *rtltcp.Client and *Channel.
+type Upstream interface {
+	Info() rtltcp.DongleInfo
+	Stream(ctx context.Context, size, depth int) <-chan []byte
+	Err() error
+	SendCommand(cmd rtltcp.Command, param uint32) error
+}
+
+// Mux fans out the I/Q stream of an upstream rtl_tcp server to any number of
+// downstream clients and forwards the tuning commands the policy allows.
+// Clients that cannot keep up are dropped instead of stalling the others.
+type Mux struct {
+	// Policy decides which clients may tune, defaults to PolicyFirstClient.
+	Policy Policy
+	// MaxClients limits the number of connected clients when positive.
+	MaxClients int
+	// ChunkSize is the size of the chunks read from the upstream, defaults
+	// to 32 KiB. Smaller chunks lower the latency of slow streams.
+	ChunkSize int
+	// Loss counts the samples the upstream drops against the sample rate
+	// of the tuning, when set.
+	Loss *loss.Counter
+
+	upstream Upstream
+
+	mu      sync.Mutex
+	clients []*client
+	closed  bool
+	tuning  rtltcp.Tuning
+}
+
+// client is a consumer of the stream. Internal consumers, like channels,
+// have no connection. Taps are connected but, like internal consumers, are
+// not counted as clients and never tune.
+type client struct {
+	name string
+	conn net.Conn
+	tap  bool
+	ch   chan []byte
+}
+
+// New returns a mux sharing the upstream connection.
+func New(upstream Upstream, policy Policy) *Mux {
+	return &Mux{
+		Policy:   policy,
+		upstream: upstream,
+	}
+}
+
+// SetTuning sets the tuning the upstream was started with. rtl_tcp does not
+// report it, so the mux only learns about later changes from the commands it
+// forwards.
+func (m *Mux) SetTuning(t rtltcp.Tuning) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.tuning = t
+}
+
+// Tuning returns the current tuning of the upstream.
+func (m *Mux) Tuning() rtltcp.Tuning {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.tuning
+}
+
+// Clients returns the number of connected clients, not counting internal
+// subscribers and taps.
+func (m *Mux) Clients() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.connected()
+}
+
+// connected returns the number of connected clients. m.mu must be held.
+func (m *Mux) connected() int {
+	n := 0
+	for _, c := range m.clients {
+		if c.conn != nil && !c.tap {
+			n++
+		}
+	}
+
+	return n
+}
+
+// Run copies samples from the upstream to all clients until ctx is done or
+// the upstream fails. All clients are disconnected when it returns.
+func (m *Mux) Run(ctx context.Context) error {
+	defer m.close()
+
+	size := m.ChunkSize
+	if size <= 0 {
+		size = chunkSize
+	}
+
+	for chunk := range m.upstream.Stream(ctx, size, 1) {
+		if m.Loss != nil {
+			m.Loss.Add(time.Now(), len(chunk)/2, m.Tuning().SampleRate)
+		}
+		m.broadcast(chunk)
+	}
+
+	return m.upstream.Err()
+}
+
+// Serve accepts clients on l until ctx is done or the upstream has ended.
+func (m *Mux) Serve(ctx context.Context, l net.Listener) error {
+	return m.serve(ctx, l, false)
+}
+
+// ServeTap accepts taps on l until ctx is done or the upstream has ended.
+// Taps receive the stream like clients but do not count towards MaxClients
+// and their commands are ignored, so that monitoring the stream never takes
+// it from a client.
+func (m *Mux) ServeTap(ctx context.Context, l net.Listener) error {
+	return m.serve(ctx, l, true)
+}
+
+func (m *Mux) serve(ctx context.Context, l net.Listener, tap bool) error {
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	stop := context.AfterFunc(ctx, func() {
+		_ = l.Close()
+	})
+	defer stop()
+
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				if cause := context.Cause(ctx); errors.Is(cause, ErrClosed) {
+					return cause
+				}
+				return nil
+			}
+			return err
+		}
+
+		// Clients are accepted concurrently, so that one that is slow to
+		// take the header does not hold up the others.
+		go func() {
+			if err := m.accept(conn, tap); err != nil {
+				slog.Info("Rejecting client", slog.String("client", conn.RemoteAddr().String()), slog.Any("error", err))
+				_ = conn.Close()
+
+				if errors.Is(err, ErrClosed) {
+					cancel(err)
+				}
+			}
+		}()
+	}
+}
+
+// accept sends the dongle info to conn and starts streaming to it.
+func (m *Mux) accept(conn net.Conn, tap bool) error {
+	_ = conn.SetWriteDeadline(time.Now().Add(headerTimeout))
+	if err := rtltcp.WriteDongleInfo(conn, m.upstream.Info()); err != nil {
+		return err
+	}
+	_ = conn.SetWriteDeadline(time.Time{})
+
+	c, err := m.subscribe(conn.RemoteAddr().String(), conn, tap)
+	if err != nil {
+		return err
+	}
+
+	slog.Info("Client connected", slog.String("client", c.name), slog.Int("clients", m.Clients()))
+
+	go m.write(c)
+	go m.readCommands(c)
+
+	return nil
+}
+
+// write sends chunks to the client until it is unsubscribed.
+func (m *Mux) write(c *client) {
+	defer m.unsubscribe(c)
+
+	for chunk := range c.ch {
+		if _, err := c.conn.Write(chunk); err != nil {
+			return
+		}
+	}
+}
+
+// readCommands forwards the commands of the client the policy allows.
+func (m *Mux) readCommands(c *client) {
+	defer m.unsubscribe(c)
+
+	for {
+		cmd, param, err := rtltcp.ReadCommand(c.conn)
+		if err != nil {
+			return
+		}
+
+		if c.tap {
+			continue
+		}
+
+		if !m.mayTune(c) {
+			slog.Info("Ignoring command from read-only client",
+				slog.String("client", c.name),
+				slog.String("command", cmd.String()))
+			continue
+		}
+
+		if err := m.SendCommand(cmd, param); err != nil {
+			slog.Error("Failed forwarding command", slog.Any("error", err))
+			return
+		}
+	}
+}
+
+// SendCommand sends a command to the upstream regardless of the policy, for
+// internal consumers like the scanner, and tracks the tuning it sets.
+func (m *Mux) SendCommand(cmd rtltcp.Command, param uint32) error {
+	if err := m.upstream.SendCommand(cmd, param); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.tuning.Apply(cmd, param)
+
+	return nil
+}
+
+// Tune sends the commands setting the upstream to the tuning t regardless of
+// the policy, skipping those it is already set to. A zero frequency or sample
+// rate leaves it as it is.
+func (m *Mux) Tune(t rtltcp.Tuning) error {
+	current := m.Tuning()
+
+	type command struct {
+		cmd   rtltcp.Command
+		param uint32
+	}
+	var commands []command
+	if t.SampleRate != 0 && t.SampleRate != current.SampleRate {
+		commands = append(commands, command{rtltcp.SetSampleRate, t.SampleRate})
+	}
+	if t.Frequency != 0 && t.Frequency != current.Frequency {
+		commands = append(commands, command{rtltcp.SetFrequency, t.Frequency})
+	}
+	if t.ManualGain != current.ManualGain {
+		commands = append(commands, command{rtltcp.SetGainMode, boolParam(t.ManualGain)})
+	}
+	if t.ManualGain && (t.Gain != current.Gain || !current.ManualGain) {
+		commands = append(commands, command{rtltcp.SetGain, uint32(t.Gain)})
+	}
+
+	for _, c := range commands {
+		if err := m.SendCommand(c.cmd, c.param); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// boolParam encodes a boolean command parameter.
+func boolParam(b bool) uint32 {
+	if b {
+		return 1
+	}
+
+	return 0
+}
+
+// mayTune reports whether the policy allows the client to send commands.
+func (m *Mux) mayTune(c *client) bool {
+	switch m.Policy {
+	case PolicyOpen:
+		return true
+	case PolicyReadOnly:
+		return false
+	default:
+		m.mu.Lock()
+		defer m.mu.Unlock()
+
+		i := slices.IndexFunc(m.clients, func(c *client) bool { return c.conn != nil && !c.tap })
+		return i >= 0 && m.clients[i] == c
+	}
+}
+
+func (m *Mux) broadcast(chunk []byte) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.clients = slices.DeleteFunc(m.clients, func(c *client) bool {
+		select {
+		case c.ch <- chunk:
+			return false
+		default:
+			slog.Info("Dropping slow client", slog.String("client", c.name))
+			c.disconnect()
+			return true
+		}
+	})
+}
+
+// Subscribe returns a channel receiving the chunks of the stream, and a
+// function ending the subscription. Like clients, a subscriber that cannot
+// keep up is dropped and its channel closed.
+func (m *Mux) Subscribe(name string) (<-chan []byte, func(), error) {
+	c, err := m.subscribe(name, nil, false)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return c.ch, func() { m.unsubscribe(c) }, nil
+}
+
+func (m *Mux) subscribe(name string, conn net.Conn, tap bool) (*client, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.closed {
+		return nil, ErrClosed
+	}
+
+	if conn != nil && !tap && m.MaxClients > 0 && m.connected() >= m.MaxClients {
+		return nil, fmt.Errorf("limit of %d clients reached", m.MaxClients)
+	}
+
+	c := &client{
+		name: name,
+		conn: conn,
+		tap:  tap,
+		ch:   make(chan []byte, clientBuffer),
+	}
+	m.clients = append(m.clients, c)
+
+	return c, nil
+}
+
+func (m *Mux) unsubscribe(c *client) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if i := slices.Index(m.clients, c); i >= 0 {
+		m.clients = slices.Delete(m.clients, i, i+1)
+		c.disconnect()
+
+		slog.Info("Client disconnected", slog.String("client", c.name), slog.Int("clients", m.connected()))
+	}
+}
+
+func (m *Mux) close() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.closed = true
+	for _, c := range m.clients {
+		c.disconnect()
+	}
+	m.clients = nil
+}
+
+// disconnect stops streaming to the client and closes its connection. It
+// must only be called once, by whoever removes the client from the mux.
+func (c *client) disconnect() {
+	close(c.ch)
+	if c.conn != nil {
+		_ = c.conn.Close()
+	}
+}
@@ -0,0 +1,404 @@
+package rtlmux
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"math"
+	"math/cmplx"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/frelon/k8s-radio/pkg/fakesdr"
+	"github.com/frelon/k8s-radio/pkg/loss"
+	"github.com/frelon/k8s-radio/pkg/rtltcp"
+)
+
+func TestRtlMux(t *testing.T) {
+	RegisterFailHandler(Fail)
+
+	RunSpecs(t, "rtl_tcp Mux Suite")
+}
+
+// listen returns a listener on a random local port.
+func listen() net.Listener {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	Expect(err).ToNot(HaveOccurred())
+	DeferCleanup(func() { _ = l.Close() })
+
+	return l
+}
+
+// startMux starts a fake rtl_tcp server and a mux in front of it.
+func startMux(ctx context.Context, policy Policy, maxClients int, carriers ...*fakesdr.Carrier) (*fakesdr.Server, *Mux, string) {
+	server := fakesdr.NewServer(fakesdr.NewGenerator(0.01, carriers...), fakesdr.Tuning{
+		Frequency:  100_000_000,
+		SampleRate: 2_400_000,
+	})
+	upstreamListener := listen()
+	go func() {
+		defer GinkgoRecover()
+		Expect(server.Serve(ctx, upstreamListener)).To(Succeed())
+	}()
+
+	upstream, err := rtltcp.Dial(ctx, upstreamListener.Addr().String())
+	Expect(err).ToNot(HaveOccurred())
+
+	m := New(upstream, policy)
+	m.MaxClients = maxClients
+
+	l := listen()
+	go func() { _ = m.Run(ctx) }()
+	go func() { _ = m.Serve(ctx, l) }()
+
+	return server, m, l.Addr().String()
+}
+
+// dial connects a client to the mux and reads some samples.
+func dial(ctx context.Context, addr string) *rtltcp.Client {
+	c, err := rtltcp.Dial(ctx, addr)
+	Expect(err).ToNot(HaveOccurred())
+	DeferCleanup(func() { _ = c.Close() })
+
+	buf := make([]byte, 1024)
+	_, err = io.ReadFull(c, buf)
+	Expect(err).ToNot(HaveOccurred())
+
+	return c
+}
+
+var _ = Describe("Mux", func() {
+	It("counts the samples the upstream drops", func(ctx SpecContext) {
+		server := fakesdr.NewServer(fakesdr.NewGenerator(0.01), fakesdr.Tuning{SampleRate: 240_000})
+		upstreamListener := listen()
+		go func() { _ = server.Serve(ctx, upstreamListener) }()
+
+		upstream, err := rtltcp.Dial(ctx, upstreamListener.Addr().String())
+		Expect(err).ToNot(HaveOccurred())
+
+		m := New(upstream, PolicyFirstClient)
+		m.ChunkSize = 4096
+		m.Loss = &loss.Counter{Tolerance: 100 * time.Millisecond}
+		go func() { _ = m.Run(ctx) }()
+
+		By("keeping up with the sample rate of the server")
+		m.SetTuning(rtltcp.Tuning{SampleRate: 240_000})
+		Eventually(func() uint64 { return m.Loss.Stats(time.Now()).Samples }).Should(BeNumerically(">", 120_000))
+		Expect(m.Loss.Stats(time.Now()).DroppedSamples).To(BeZero())
+
+		By("falling behind a sample rate ten times higher")
+		m.SetTuning(rtltcp.Tuning{SampleRate: 2_400_000})
+		Eventually(func() uint64 { return m.Loss.Stats(time.Now()).DroppedBuffers }).Should(BeNumerically(">", 1))
+	})
+
+	It("streams to several clients at once", func(ctx SpecContext) {
+		_, m, addr := startMux(ctx, PolicyFirstClient, 0)
+
+		a := dial(ctx, addr)
+		b := dial(ctx, addr)
+		Expect(a.Info().Tuner).To(Equal(rtltcp.TunerR820T))
+		Expect(b.Info().Tuner).To(Equal(rtltcp.TunerR820T))
+		Eventually(m.Clients).Should(Equal(2))
+	})
+
+	It("only lets the first client tune", func(ctx SpecContext) {
+		server, _, addr := startMux(ctx, PolicyFirstClient, 0)
+
+		owner := dial(ctx, addr)
+		other := dial(ctx, addr)
+
+		Expect(other.SetFrequency(105_000_000)).To(Succeed())
+		Consistently(func() uint32 { return server.Tuning().Frequency }, "200ms").Should(Equal(uint32(100_000_000)))
+
+		Expect(owner.SetFrequency(101_900_000)).To(Succeed())
+		Eventually(func() uint32 { return server.Tuning().Frequency }).Should(Equal(uint32(101_900_000)))
+
+		By("handing over ownership when the owner disconnects")
+		Expect(owner.Close()).To(Succeed())
+		Eventually(func() error {
+			if err := other.SetFrequency(103_000_000); err != nil {
+				return err
+			}
+			if f := server.Tuning().Frequency; f != 103_000_000 {
+				return io.ErrNoProgress
+			}
+			return nil
+		}).Should(Succeed())
+	})
+
+	It("ignores all commands when read-only", func(ctx SpecContext) {
+		server, _, addr := startMux(ctx, PolicyReadOnly, 0)
+
+		c := dial(ctx, addr)
+		Expect(c.SetFrequency(105_000_000)).To(Succeed())
+		Consistently(func() uint32 { return server.Tuning().Frequency }, "200ms").Should(Equal(uint32(100_000_000)))
+	})
+
+	It("lets any client tune when open", func(ctx SpecContext) {
+		server, _, addr := startMux(ctx, PolicyOpen, 0)
+
+		_ = dial(ctx, addr)
+		c := dial(ctx, addr)
+		Expect(c.SetFrequency(105_000_000)).To(Succeed())
+		Eventually(func() uint32 { return server.Tuning().Frequency }).Should(Equal(uint32(105_000_000)))
+	})
+
+	It("tracks the tuning set by clients", func(ctx SpecContext) {
+		_, m, addr := startMux(ctx, PolicyFirstClient, 0)
+		m.SetTuning(rtltcp.Tuning{Frequency: 100_000_000, SampleRate: 2_400_000})
+
+		c := dial(ctx, addr)
+		Expect(c.SetFrequency(105_000_000)).To(Succeed())
+		Expect(c.SetGainMode(true)).To(Succeed())
+		Expect(c.SetGain(297)).To(Succeed())
+		Eventually(m.Tuning).Should(Equal(rtltcp.Tuning{
+			Frequency:  105_000_000,
+			SampleRate: 2_400_000,
+			Gain:       297,
+			ManualGain: true,
+		}))
+	})
+
+	It("retunes the upstream regardless of the policy", func(ctx SpecContext) {
+		server, m, _ := startMux(ctx, PolicyReadOnly, 0)
+		m.SetTuning(rtltcp.Tuning{Frequency: 100_000_000, SampleRate: 2_400_000})
+
+		want := rtltcp.Tuning{Frequency: 145_800_000, SampleRate: 1_024_000, Gain: 402, ManualGain: true}
+		Expect(m.Tune(want)).To(Succeed())
+		Expect(m.Tuning()).To(Equal(want))
+		Eventually(server.Tuning).Should(Equal(want))
+
+		By("going back to automatic gain")
+		Expect(m.Tune(rtltcp.Tuning{Frequency: 145_800_000, SampleRate: 1_024_000})).To(Succeed())
+		Eventually(func() bool { return server.Tuning().ManualGain }).Should(BeFalse())
+	})
+
+	It("remembers the generation the upstream was tuned from", func(ctx SpecContext) {
+		server, m, _ := startMux(ctx, PolicyFirstClient, 0)
+		m.SetTuning(rtltcp.Tuning{Frequency: 100_000_000, SampleRate: 2_400_000})
+		control := NewControl(m)
+
+		Expect(control.Setting()).To(Equal(Setting{Tuning: rtltcp.Tuning{Frequency: 100_000_000, SampleRate: 2_400_000}}))
+
+		setting, err := control.Set(Setting{Generation: 3, Tuning: rtltcp.Tuning{Frequency: 101_900_000, SampleRate: 2_400_000}})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(setting).To(Equal(Setting{Generation: 3, Tuning: rtltcp.Tuning{Frequency: 101_900_000, SampleRate: 2_400_000}}))
+		Expect(control.Setting()).To(Equal(setting))
+		Eventually(func() uint32 { return server.Tuning().Frequency }).Should(Equal(uint32(101_900_000)))
+	})
+
+	It("only lets the controller use the control API", func(ctx SpecContext) {
+		server, m, _ := startMux(ctx, PolicyFirstClient, 0)
+		m.SetTuning(rtltcp.Tuning{Frequency: 100_000_000, SampleRate: 2_400_000})
+
+		tokenFile := filepath.Join(GinkgoT().TempDir(), "token")
+		Expect(os.WriteFile(tokenFile, []byte("secret\n"), 0o600)).To(Succeed())
+		control := NewControl(m)
+		control.TokenFile = tokenFile
+		api := httptest.NewServer(control)
+		DeferCleanup(api.Close)
+
+		put := func(token string) *http.Response {
+			body := `{"generation":2,"tuning":{"frequency":145800000,"sampleRate":2400000}}`
+			req, err := http.NewRequestWithContext(ctx, http.MethodPut, api.URL+"/control", strings.NewReader(body))
+			Expect(err).ToNot(HaveOccurred())
+			if token != "" {
+				req.Header.Set("Authorization", "Bearer "+token)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			Expect(err).ToNot(HaveOccurred())
+			DeferCleanup(resp.Body.Close)
+			return resp
+		}
+
+		By("rejecting requests without the token")
+		Expect(put("").StatusCode).To(Equal(http.StatusUnauthorized))
+		Expect(put("guess").StatusCode).To(Equal(http.StatusUnauthorized))
+		Expect(control.Setting().Generation).To(BeZero())
+		Consistently(func() uint32 { return server.Tuning().Frequency }, 100*time.Millisecond).Should(Equal(uint32(100_000_000)))
+
+		By("retuning for the controller")
+		resp := put("secret")
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		var setting Setting
+		Expect(json.NewDecoder(resp.Body).Decode(&setting)).To(Succeed())
+		Expect(setting.Generation).To(Equal(int64(2)))
+		Eventually(func() uint32 { return server.Tuning().Frequency }).Should(Equal(uint32(145_800_000)))
+
+		By("rejecting every request without a token file")
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/control", nil)
+		req.Header.Set("Authorization", "Bearer secret")
+		NewControl(m).ServeHTTP(rec, req)
+		Expect(rec.Code).To(Equal(http.StatusUnauthorized))
+	})
+
+	It("rejects clients over the limit", func(ctx SpecContext) {
+		_, m, addr := startMux(ctx, PolicyFirstClient, 1)
+
+		_ = dial(ctx, addr)
+		Eventually(m.Clients).Should(Equal(1))
+
+		conn, err := net.Dial("tcp", addr)
+		Expect(err).ToNot(HaveOccurred())
+		defer conn.Close()
+
+		_, err = io.ReadAll(conn)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(m.Clients()).To(Equal(1))
+	})
+
+	It("streams to taps without counting them or letting them tune", func(ctx SpecContext) {
+		server, m, addr := startMux(ctx, PolicyFirstClient, 1)
+
+		tapListener := listen()
+		go func() { _ = m.ServeTap(ctx, tapListener) }()
+
+		tap := dial(ctx, tapListener.Addr().String())
+		Expect(tap.SetFrequency(105_000_000)).To(Succeed())
+		Consistently(func() uint32 { return server.Tuning().Frequency }, "200ms").Should(Equal(uint32(100_000_000)))
+		Expect(m.Clients()).To(BeZero())
+
+		By("still letting the first client connect and tune")
+		c := dial(ctx, addr)
+		Expect(c.SetFrequency(101_900_000)).To(Succeed())
+		Eventually(func() uint32 { return server.Tuning().Frequency }).Should(Equal(uint32(101_900_000)))
+		Expect(m.Clients()).To(Equal(1))
+	})
+
+	It("drops slow clients without stalling the others", func(ctx SpecContext) {
+		_, m, addr := startMux(ctx, PolicyFirstClient, 0)
+
+		By("connecting a client that never reads")
+		slow, err := net.Dial("tcp", addr)
+		Expect(err).ToNot(HaveOccurred())
+		defer slow.Close()
+		Expect(slow.(*net.TCPConn).SetReadBuffer(4096)).To(Succeed())
+
+		fast := dial(ctx, addr)
+		Eventually(m.Clients).Should(Equal(2))
+
+		By("reading from the other client")
+		buf := make([]byte, 64*1024)
+		Eventually(func() int {
+			_, err := io.ReadFull(fast, buf)
+			Expect(err).ToNot(HaveOccurred())
+			return m.Clients()
+		}, "30s").Should(Equal(1))
+	})
+
+	It("accepts clients while another is slow to take the header", func(ctx SpecContext) {
+		_, m, _ := startMux(ctx, PolicyOpen, 0)
+
+		By("accepting a client that never reads the header first")
+		stalled, peer := net.Pipe()
+		DeferCleanup(func() { _ = peer.Close() })
+		l := &stallingListener{Listener: listen(), stalled: stalled}
+		go func() { _ = m.Serve(ctx, l) }()
+
+		By("streaming to the next client well before the header times out")
+		start := time.Now()
+		_ = dial(ctx, l.Addr().String())
+		Expect(time.Since(start)).To(BeNumerically("<", headerTimeout/2))
+	})
+})
+
+// stallingListener hands out a stalled connection before accepting any
+// other.
+type stallingListener struct {
+	net.Listener
+	stalled net.Conn
+	once    sync.Once
+}
+
+func (l *stallingListener) Accept() (net.Conn, error) {
+	var conn net.Conn
+	l.once.Do(func() { conn = l.stalled })
+	if conn != nil {
+		return conn, nil
+	}
+
+	return l.Listener.Accept()
+}
+
+// freePort returns a local port that is not in use.
+func freePort() int32 {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	Expect(err).ToNot(HaveOccurred())
+	defer l.Close()
+
+	return int32(l.Addr().(*net.TCPAddr).Port)
+}
+
+// meanPower returns the mean power of unsigned 8-bit I/Q samples.
+func meanPower(iq []byte) float64 {
+	samples := make([]complex64, len(iq)/2)
+	rtltcp.ToComplex(samples, iq)
+
+	var sum float64
+	for _, s := range samples {
+		sum += math.Pow(cmplx.Abs(complex128(s)), 2)
+	}
+
+	return sum / float64(len(samples))
+}
+
+var _ = Describe("Channels", func() {
+	It("serves narrowband channels split from the stream", func(ctx SpecContext) {
+		_, m, _ := startMux(ctx, PolicyReadOnly, 0, &fakesdr.Carrier{
+			Frequency: 100_200_000,
+			Amplitude: 0.5,
+		})
+
+		on, off := freePort(), freePort()
+
+		channels := NewChannels(m)
+		channels.Host = "127.0.0.1"
+		channels.Update(ctx, Config{
+			SampleRate: 2_400_000,
+			Channels: []ChannelConfig{
+				{Name: "on", Port: on, Offset: 200_000, Bandwidth: 50_000, Decimation: 48},
+				{Name: "off", Port: off, Offset: -200_000, Bandwidth: 50_000, Decimation: 48},
+			},
+		})
+
+		read := func(port int32) []byte {
+			var c *rtltcp.Client
+			Eventually(func() (err error) {
+				c, err = rtltcp.Dial(ctx, net.JoinHostPort("127.0.0.1", strconv.Itoa(int(port))))
+				return err
+			}).Should(Succeed())
+			defer c.Close()
+
+			buf := make([]byte, 2*2000)
+			_, err := io.ReadFull(c, buf)
+			Expect(err).ToNot(HaveOccurred())
+
+			return buf
+		}
+
+		Expect(meanPower(read(on))).To(BeNumerically("~", 0.25, 0.05))
+		Expect(meanPower(read(off))).To(BeNumerically("<", 0.01))
+
+		By("stopping removed channels")
+		channels.Update(ctx, Config{SampleRate: 2_400_000})
+		Eventually(func() error {
+			c, err := net.Dial("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(int(on))))
+			if err == nil {
+				c.Close()
+			}
+			return err
+		}).ShouldNot(Succeed())
+	})
+})
@@ -0,0 +1,226 @@
+package rtltcp
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+)
+
+// Client is a connection to an rtl_tcp server.
+type Client struct {
+	conn net.Conn
+	info DongleInfo
+
+	// wmu serializes commands, so they are never interleaved on the wire.
+	wmu sync.Mutex
+
+	// odd holds the I half of a sample split across two reads.
+	odd    byte
+	hasOdd bool
+
+	errMu sync.Mutex
+	err   error
+}
+
+// Dial connects to the rtl_tcp server at addr and reads the dongle info.
+func Dial(ctx context.Context, addr string) (*Client, error) {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("rtltcp: failed dialing %s: %w", addr, err)
+	}
+
+	c, err := NewClient(conn)
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// NewClient reads the dongle info from an established connection.
+func NewClient(conn net.Conn) (*Client, error) {
+	info, err := ReadDongleInfo(conn)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Client{
+		conn: conn,
+		info: info,
+	}, nil
+}
+
+// Info returns the dongle info sent by the server.
+func (c *Client) Info() DongleInfo {
+	return c.info
+}
+
+// Close closes the connection.
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// Read reads interleaved unsigned 8-bit I/Q samples into p. It only returns
+// whole samples, so n is always even; p must hold at least one sample.
+func (c *Client) Read(p []byte) (int, error) {
+	if len(p) < 2 {
+		return 0, io.ErrShortBuffer
+	}
+
+	p = p[:len(p)&^1]
+
+	off := 0
+	if c.hasOdd {
+		p[0] = c.odd
+		off = 1
+	}
+
+	n, err := c.conn.Read(p[off:])
+	n += off
+	c.hasOdd = false
+
+	if n%2 == 1 {
+		n--
+		c.odd = p[n]
+		c.hasOdd = true
+	}
+
+	return n, err
+}
+
+// Stream reads chunks of size bytes of I/Q samples and sends them on the
+// returned channel, which buffers at most depth chunks. When the consumer
+// falls behind, reading stops, so the server is slowed down by TCP flow
+// control instead of samples being dropped here. The channel is closed when
+// ctx is done or reading fails, after which Err reports the cause. Chunks hold
+// whole samples, so an odd size is rounded down, to one sample at least.
+func (c *Client) Stream(ctx context.Context, size, depth int) <-chan []byte {
+	size = max(size&^1, 2)
+	ch := make(chan []byte, depth)
+
+	go func() {
+		defer close(ch)
+
+		stop := context.AfterFunc(ctx, func() {
+			_ = c.conn.Close()
+		})
+		defer stop()
+
+		for {
+			buf := make([]byte, size)
+			if _, err := io.ReadFull(c, buf); err != nil {
+				if ctx.Err() != nil {
+					err = ctx.Err()
+				}
+				c.setErr(err)
+				return
+			}
+
+			select {
+			case ch <- buf:
+			case <-ctx.Done():
+				c.setErr(ctx.Err())
+				return
+			}
+		}
+	}()
+
+	return ch
+}
+
+// Err returns the error that ended the stream.
+func (c *Client) Err() error {
+	c.errMu.Lock()
+	defer c.errMu.Unlock()
+
+	return c.err
+}
+
+func (c *Client) setErr(err error) {
+	c.errMu.Lock()
+	defer c.errMu.Unlock()
+
+	c.err = err
+}
+
+// SendCommand sends a raw command to the server.
+func (c *Client) SendCommand(cmd Command, param uint32) error {
+	c.wmu.Lock()
+	defer c.wmu.Unlock()
+
+	b := EncodeCommand(cmd, param)
+	if _, err := c.conn.Write(b[:]); err != nil {
+		return fmt.Errorf("rtltcp: failed sending %s: %w", cmd, err)
+	}
+
+	return nil
+}
+
+// SetFrequency tunes the dongle to the frequency in Hz.
+func (c *Client) SetFrequency(hz uint32) error {
+	return c.SendCommand(SetFrequency, hz)
+}
+
+// SetSampleRate sets the sample rate in Hz.
+func (c *Client) SetSampleRate(hz uint32) error {
+	return c.SendCommand(SetSampleRate, hz)
+}
+
+// SetGainMode selects manual gain when manual is true, or automatic gain.
+func (c *Client) SetGainMode(manual bool) error {
+	return c.SendCommand(SetGainMode, boolParam(manual))
+}
+
+// SetGain sets the tuner gain in tenths of a dB. Manual gain mode must be
+// enabled for it to take effect.
+func (c *Client) SetGain(tenthsDB int32) error {
+	return c.SendCommand(SetGain, uint32(tenthsDB))
+}
+
+// SetGainByIndex sets the tuner gain to the index in the tuner's gain table.
+func (c *Client) SetGainByIndex(index uint32) error {
+	return c.SendCommand(SetGainByIndex, index)
+}
+
+// SetFrequencyCorrection sets the frequency correction in ppm.
+func (c *Client) SetFrequencyCorrection(ppm int32) error {
+	return c.SendCommand(SetFrequencyCorrection, uint32(ppm))
+}
+
+// SetIFGain sets the gain of an intermediate frequency stage in tenths of a
+// dB.
+func (c *Client) SetIFGain(stage uint16, tenthsDB int16) error {
+	return c.SendCommand(SetIFGain, uint32(stage)<<16|uint32(uint16(tenthsDB)))
+}
+
+// SetAGC enables or disables the automatic gain control of the RTL2832.
+func (c *Client) SetAGC(enabled bool) error {
+	return c.SendCommand(SetAGCMode, boolParam(enabled))
+}
+
+// SetDirectSampling selects direct sampling, used to receive HF.
+func (c *Client) SetDirectSampling(mode DirectSampling) error {
+	return c.SendCommand(SetDirectSampling, uint32(mode))
+}
+
+// SetOffsetTuning enables or disables offset tuning.
+func (c *Client) SetOffsetTuning(enabled bool) error {
+	return c.SendCommand(SetOffsetTuning, boolParam(enabled))
+}
+
+// SetBiasTee enables or disables the bias tee powering an active antenna.
+func (c *Client) SetBiasTee(enabled bool) error {
+	return c.SendCommand(SetBiasTee, boolParam(enabled))
+}
+
+func boolParam(b bool) uint32 {
+	if b {
+		return 1
+	}
+
+	return 0
+}
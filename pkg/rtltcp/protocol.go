@@ -0,0 +1,191 @@
+// Package rtltcp implements the rtl_tcp wire protocol.
+//
+// On connect the server sends a 12 byte header describing the dongle,
+// followed by a continuous stream of interleaved unsigned 8-bit I/Q samples.
+// The client controls the dongle by sending 5 byte commands: a command byte
+// followed by a big-endian 32-bit parameter.
+package rtltcp
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Magic is the first four bytes of the header sent by the server.
+const Magic = "RTL0"
+
+const (
+	// HeaderSize is the size of the dongle info header.
+	HeaderSize = 12
+	// CommandSize is the size of a command sent to the server.
+	CommandSize = 5
+)
+
+// ErrBadMagic is returned when the header does not start with Magic.
+var ErrBadMagic = errors.New("rtltcp: bad magic")
+
+// TunerType is the tuner chip of the dongle.
+type TunerType uint32
+
+const (
+	TunerUnknown TunerType = iota
+	TunerE4000
+	TunerFC0012
+	TunerFC0013
+	TunerFC2580
+	TunerR820T
+	TunerR828D
+)
+
+func (t TunerType) String() string {
+	switch t {
+	case TunerE4000:
+		return "E4000"
+	case TunerFC0012:
+		return "FC0012"
+	case TunerFC0013:
+		return "FC0013"
+	case TunerFC2580:
+		return "FC2580"
+	case TunerR820T:
+		return "R820T"
+	case TunerR828D:
+		return "R828D"
+	default:
+		return "Unknown"
+	}
+}
+
+// DongleInfo is the header sent by the server on connect.
+type DongleInfo struct {
+	Tuner     TunerType
+	GainCount uint32
+}
+
+// ReadDongleInfo reads the dongle info header from r.
+func ReadDongleInfo(r io.Reader) (DongleInfo, error) {
+	var header [HeaderSize]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return DongleInfo{}, fmt.Errorf("rtltcp: failed reading header: %w", err)
+	}
+
+	if string(header[:4]) != Magic {
+		return DongleInfo{}, ErrBadMagic
+	}
+
+	return DongleInfo{
+		Tuner:     TunerType(binary.BigEndian.Uint32(header[4:])),
+		GainCount: binary.BigEndian.Uint32(header[8:]),
+	}, nil
+}
+
+// WriteDongleInfo writes the dongle info header to w.
+func WriteDongleInfo(w io.Writer, info DongleInfo) error {
+	var header [HeaderSize]byte
+	copy(header[:], Magic)
+	binary.BigEndian.PutUint32(header[4:], uint32(info.Tuner))
+	binary.BigEndian.PutUint32(header[8:], info.GainCount)
+
+	_, err := w.Write(header[:])
+	return err
+}
+
+// Command is a command sent from the client to the server.
+type Command byte
+
+const (
+	SetFrequency           Command = 0x01
+	SetSampleRate          Command = 0x02
+	SetGainMode            Command = 0x03
+	SetGain                Command = 0x04
+	SetFrequencyCorrection Command = 0x05
+	SetIFGain              Command = 0x06
+	SetTestMode            Command = 0x07
+	SetAGCMode             Command = 0x08
+	SetDirectSampling      Command = 0x09
+	SetOffsetTuning        Command = 0x0a
+	SetRTLXtal             Command = 0x0b
+	SetTunerXtal           Command = 0x0c
+	SetGainByIndex         Command = 0x0d
+	SetBiasTee             Command = 0x0e
+)
+
+func (c Command) String() string {
+	switch c {
+	case SetFrequency:
+		return "SetFrequency"
+	case SetSampleRate:
+		return "SetSampleRate"
+	case SetGainMode:
+		return "SetGainMode"
+	case SetGain:
+		return "SetGain"
+	case SetFrequencyCorrection:
+		return "SetFrequencyCorrection"
+	case SetIFGain:
+		return "SetIFGain"
+	case SetTestMode:
+		return "SetTestMode"
+	case SetAGCMode:
+		return "SetAGCMode"
+	case SetDirectSampling:
+		return "SetDirectSampling"
+	case SetOffsetTuning:
+		return "SetOffsetTuning"
+	case SetRTLXtal:
+		return "SetRTLXtal"
+	case SetTunerXtal:
+		return "SetTunerXtal"
+	case SetGainByIndex:
+		return "SetGainByIndex"
+	case SetBiasTee:
+		return "SetBiasTee"
+	default:
+		return fmt.Sprintf("Command(0x%02x)", byte(c))
+	}
+}
+
+// DirectSampling selects the ADC branch used for direct sampling.
+type DirectSampling uint32
+
+const (
+	DirectSamplingOff DirectSampling = iota
+	DirectSamplingI
+	DirectSamplingQ
+)
+
+// EncodeCommand returns the wire representation of a command.
+func EncodeCommand(cmd Command, param uint32) [CommandSize]byte {
+	var b [CommandSize]byte
+	b[0] = byte(cmd)
+	binary.BigEndian.PutUint32(b[1:], param)
+
+	return b
+}
+
+// ReadCommand reads a single command from r.
+func ReadCommand(r io.Reader) (Command, uint32, error) {
+	var b [CommandSize]byte
+	if _, err := io.ReadFull(r, b[:]); err != nil {
+		return 0, 0, err
+	}
+
+	return Command(b[0]), binary.BigEndian.Uint32(b[1:]), nil
+}
+
+// ToComplex converts interleaved unsigned 8-bit I/Q samples to complex
+// samples in the range [-1, 1]. It returns the number of samples written,
+// which is limited by the length of both buffers.
+func ToComplex(dst []complex64, src []byte) int {
+	n := min(len(dst), len(src)/2)
+	for i := range n {
+		dst[i] = complex(
+			(float32(src[2*i])-127.5)/127.5,
+			(float32(src[2*i+1])-127.5)/127.5,
+		)
+	}
+
+	return n
+}
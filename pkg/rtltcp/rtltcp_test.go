@@ -0,0 +1,202 @@
+package rtltcp
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestRtlTcp(t *testing.T) {
+	RegisterFailHandler(Fail)
+
+	RunSpecs(t, "rtl_tcp Suite")
+}
+
+type command struct {
+	cmd   Command
+	param uint32
+}
+
+// fakeServer accepts a single client, sends the header and the samples, and
+// records the commands it receives until the client goes away.
+func fakeServer(info DongleInfo, samples []byte) (string, <-chan command) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	Expect(err).ToNot(HaveOccurred())
+	DeferCleanup(l.Close)
+
+	commands := make(chan command, 16)
+	go func() {
+		defer GinkgoRecover()
+
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		Expect(WriteDongleInfo(conn, info)).To(Succeed())
+
+		go func() {
+			// Write in odd-sized pieces to exercise sample alignment.
+			for len(samples) > 0 {
+				n := min(3, len(samples))
+				if _, err := conn.Write(samples[:n]); err != nil {
+					return
+				}
+				samples = samples[n:]
+			}
+		}()
+
+		defer close(commands)
+		for {
+			cmd, param, err := ReadCommand(conn)
+			if err != nil {
+				return
+			}
+			commands <- command{cmd, param}
+		}
+	}()
+
+	return l.Addr().String(), commands
+}
+
+var _ = Describe("rtl_tcp", func() {
+	It("reads the dongle info", func(ctx SpecContext) {
+		addr, _ := fakeServer(DongleInfo{Tuner: TunerR820T, GainCount: 29}, nil)
+
+		c, err := Dial(ctx, addr)
+		Expect(err).ToNot(HaveOccurred())
+		defer c.Close()
+
+		Expect(c.Info().Tuner).To(Equal(TunerR820T))
+		Expect(c.Info().Tuner.String()).To(Equal("R820T"))
+		Expect(c.Info().GainCount).To(Equal(uint32(29)))
+	})
+
+	It("rejects a bad header", func() {
+		_, err := ReadDongleInfo(bytes.NewReader([]byte("RTL1\x00\x00\x00\x05\x00\x00\x00\x1d")))
+		Expect(err).To(MatchError(ErrBadMagic))
+	})
+
+	It("sends commands", func(ctx SpecContext) {
+		addr, commands := fakeServer(DongleInfo{Tuner: TunerR820T}, nil)
+
+		c, err := Dial(ctx, addr)
+		Expect(err).ToNot(HaveOccurred())
+		defer c.Close()
+
+		Expect(c.SetFrequency(101_900_000)).To(Succeed())
+		Expect(c.SetSampleRate(2_048_000)).To(Succeed())
+		Expect(c.SetGainMode(true)).To(Succeed())
+		Expect(c.SetGain(-10)).To(Succeed())
+		Expect(c.SetFrequencyCorrection(-3)).To(Succeed())
+		Expect(c.SetAGC(true)).To(Succeed())
+		Expect(c.SetDirectSampling(DirectSamplingQ)).To(Succeed())
+		Expect(c.SetOffsetTuning(true)).To(Succeed())
+		Expect(c.SetBiasTee(true)).To(Succeed())
+		Expect(c.SetIFGain(2, -5)).To(Succeed())
+
+		expected := []command{
+			{SetFrequency, 101_900_000},
+			{SetSampleRate, 2_048_000},
+			{SetGainMode, 1},
+			{SetGain, 0xfffffff6},
+			{SetFrequencyCorrection, 0xfffffffd},
+			{SetAGCMode, 1},
+			{SetDirectSampling, 2},
+			{SetOffsetTuning, 1},
+			{SetBiasTee, 1},
+			{SetIFGain, 0x0002fffb},
+		}
+		for _, e := range expected {
+			Eventually(commands).Should(Receive(Equal(e)))
+		}
+	})
+
+	It("encodes commands big-endian", func() {
+		b := EncodeCommand(SetFrequency, 0x01020304)
+		Expect(b).To(Equal([CommandSize]byte{0x01, 0x01, 0x02, 0x03, 0x04}))
+	})
+
+	It("reads whole samples", func(ctx SpecContext) {
+		addr, _ := fakeServer(DongleInfo{}, []byte{1, 2, 3, 4, 5, 6, 7, 8})
+
+		c, err := Dial(ctx, addr)
+		Expect(err).ToNot(HaveOccurred())
+		defer c.Close()
+
+		var got []byte
+		buf := make([]byte, 5)
+		for len(got) < 8 {
+			n, err := c.Read(buf)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(n % 2).To(BeZero())
+			got = append(got, buf[:n]...)
+		}
+		Expect(got).To(Equal([]byte{1, 2, 3, 4, 5, 6, 7, 8}))
+	})
+
+	It("streams samples with backpressure", func(ctx SpecContext) {
+		samples := make([]byte, 64)
+		for i := range samples {
+			samples[i] = byte(i)
+		}
+		addr, _ := fakeServer(DongleInfo{}, samples)
+
+		c, err := Dial(ctx, addr)
+		Expect(err).ToNot(HaveOccurred())
+		defer c.Close()
+
+		streamCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		ch := c.Stream(streamCtx, 8, 2)
+
+		// The stream stops reading once the buffer is full.
+		Eventually(func() int { return len(ch) }).Should(Equal(2))
+		Consistently(func() int { return len(ch) }, 100*time.Millisecond).Should(Equal(2))
+
+		var got []byte
+		for range 8 {
+			var chunk []byte
+			Eventually(ch).Should(Receive(&chunk))
+			got = append(got, chunk...)
+		}
+		Expect(got).To(Equal(samples))
+
+		cancel()
+		Eventually(ch).Should(BeClosed())
+		Expect(c.Err()).To(MatchError(context.Canceled))
+	})
+
+	It("streams whole samples for odd chunk sizes", func(ctx SpecContext) {
+		samples := []byte{1, 2, 3, 4, 5, 6, 7, 8}
+		addr, _ := fakeServer(DongleInfo{}, samples)
+
+		c, err := Dial(ctx, addr)
+		Expect(err).ToNot(HaveOccurred())
+		defer c.Close()
+
+		ch := c.Stream(ctx, 5, 2)
+
+		var chunk []byte
+		Eventually(ch).Should(Receive(&chunk))
+		Expect(chunk).To(Equal([]byte{1, 2, 3, 4}))
+		Eventually(ch).Should(Receive(&chunk))
+		Expect(chunk).To(Equal([]byte{5, 6, 7, 8}))
+	})
+
+	It("converts samples to complex", func() {
+		dst := make([]complex64, 4)
+		n := ToComplex(dst, []byte{255, 0, 0, 255, 127})
+		Expect(n).To(Equal(2))
+		Expect(real(dst[0])).To(BeNumerically("==", 1))
+		Expect(imag(dst[0])).To(BeNumerically("==", -1))
+		Expect(real(dst[1])).To(BeNumerically("==", -1))
+	})
+})
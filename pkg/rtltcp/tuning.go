@@ -0,0 +1,36 @@
+package rtltcp
+
+// Tuning is the state of a dongle as set by the commands of its client.
+type Tuning struct {
+	// Frequency is the centre frequency in Hz.
+	Frequency uint32
+	// SampleRate is the sample rate in Hz.
+	SampleRate uint32
+	// Gain is the manual gain in tenths of a dB. It is ignored unless
+	// ManualGain is set.
+	Gain int32
+	// ManualGain is set when the client selected manual gain mode.
+	ManualGain bool
+}
+
+// Apply updates the tuning with a command and reports whether the command
+// is one that changes it. A zero sample rate is invalid and ignored.
+func (t *Tuning) Apply(cmd Command, param uint32) bool {
+	switch cmd {
+	case SetFrequency:
+		t.Frequency = param
+	case SetSampleRate:
+		if param == 0 {
+			return false
+		}
+		t.SampleRate = param
+	case SetGainMode:
+		t.ManualGain = param != 0
+	case SetGain:
+		t.Gain = int32(param)
+	default:
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,259 @@
+// Package scanner tunes a receiver through a list of channels looking for
+// activity, like the scan function of a handheld radio.
+package scanner
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/frelon/k8s-radio/pkg/dsp"
+)
+
+const (
+	// DefaultDwell is the default time spent measuring each channel.
+	DefaultDwell = 100 * time.Millisecond
+	// DefaultSettle is the default time discarded after retuning, while
+	// samples captured at the previous frequency are still buffered.
+	DefaultSettle = 50 * time.Millisecond
+	// DefaultHold is the default time the scanner stays on a channel after
+	// its activity ends.
+	DefaultHold = 2 * time.Second
+	// DefaultMaxHits is the default number of hits kept.
+	DefaultMaxHits = 10
+)
+
+// ErrStreamEnded is returned by Run when the sample stream ends.
+var ErrStreamEnded = errors.New("scanner: stream ended")
+
+// Hit is a period of activity on a channel.
+type Hit struct {
+	// Frequency is the frequency of the channel in Hz.
+	Frequency uint32 `json:"frequency"`
+	// Power is the peak power of the channel in dBFS.
+	Power float64 `json:"power"`
+	// FirstSeen is when the activity was detected.
+	FirstSeen time.Time `json:"firstSeen"`
+	// LastSeen is when the activity was last detected.
+	LastSeen time.Time `json:"lastSeen"`
+}
+
+// Status describes what the scanner is doing.
+type Status struct {
+	// Frequency is the frequency of the channel being measured in Hz.
+	Frequency uint32 `json:"frequency"`
+	// Hits are the most recent hits, newest first.
+	Hits []Hit `json:"hits"`
+}
+
+// Scanner measures the power of each channel in turn and pauses on channels
+// above the squelch threshold until they have been quiet for Hold.
+type Scanner struct {
+	// Frequencies are the centre frequencies of the channels in Hz.
+	Frequencies []uint32
+	// Bandwidth is the bandwidth of the channels in Hz.
+	Bandwidth float64
+	// Squelch is the power in dBFS a channel must reach to count as
+	// active.
+	Squelch float64
+
+	// Dwell is the time spent measuring each channel, defaults to
+	// DefaultDwell.
+	Dwell time.Duration
+	// Settle is the time discarded after retuning, defaults to
+	// DefaultSettle.
+	Settle time.Duration
+	// Hold is the time to stay on a channel after its activity ends,
+	// defaults to DefaultHold.
+	Hold time.Duration
+	// MaxHits is the number of hits kept, defaults to DefaultMaxHits.
+	MaxHits int
+
+	// Now returns the current time, defaults to time.Now.
+	Now func() time.Time
+
+	mu     sync.Mutex
+	status Status
+}
+
+// Status returns the status of the scanner.
+func (s *Scanner) Status() Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	status := s.status
+	status.Hits = append([]Hit(nil), s.status.Hits...)
+
+	return status
+}
+
+// Run scans the channels until ctx is done or the stream ends. tune sets the
+// frequency of the receiver, and sampleRate returns its sample rate. Time on
+// a channel is counted in samples, so the scanner keeps pace with the stream
+// however fast it is read.
+func (s *Scanner) Run(ctx context.Context, samples <-chan []byte, tune func(hz uint32) error, sampleRate func() uint32) error {
+	if len(s.Frequencies) == 0 {
+		return errors.New("scanner: no frequencies to scan")
+	}
+
+	st := &stream{ctx: ctx, ch: samples}
+
+	for i := 0; ; i = (i + 1) % len(s.Frequencies) {
+		freq := s.Frequencies[i]
+		if err := tune(freq); err != nil {
+			return err
+		}
+		s.tuned(freq)
+
+		rate := float64(sampleRate())
+		if err := st.discard(sampleBytes(or(s.Settle, DefaultSettle), rate)); err != nil {
+			return err
+		}
+
+		dwell := sampleBytes(or(s.Dwell, DefaultDwell), rate)
+		power, err := s.measure(st, dwell, rate)
+		if err != nil {
+			return err
+		}
+		if power < s.Squelch {
+			continue
+		}
+
+		// Stay on the channel until it has been quiet for the hold time.
+		s.hit(freq, power, true)
+		for quiet, hold := 0, sampleBytes(or(s.Hold, DefaultHold), rate); quiet < hold; {
+			power, err := s.measure(st, dwell, rate)
+			if err != nil {
+				return err
+			}
+
+			if power >= s.Squelch {
+				s.hit(freq, power, false)
+				quiet = 0
+			} else {
+				quiet += dwell
+			}
+		}
+	}
+}
+
+// measure returns the power in dBFS of the next n bytes of samples,
+// filtered to the channel bandwidth.
+func (s *Scanner) measure(st *stream, n int, rate float64) (float64, error) {
+	bandwidth := min(s.Bandwidth, rate)
+	if bandwidth <= 0 {
+		bandwidth = rate
+	}
+	ddc := dsp.NewDDC(rate, 0, bandwidth, int(max(rate/(2*bandwidth), 1)))
+
+	var sum float64
+	var count int
+	var out []byte
+	for n > 0 {
+		chunk, err := st.next(n)
+		if err != nil {
+			return 0, err
+		}
+		n -= len(chunk)
+
+		out = ddc.Process(out[:0], chunk)
+		sum += dsp.Power(out) * float64(len(out)/2)
+		count += len(out) / 2
+	}
+
+	if count == 0 {
+		return dsp.MinDBFS, nil
+	}
+
+	return dsp.DBFS(sum / float64(count)), nil
+}
+
+func (s *Scanner) tuned(freq uint32) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.status.Frequency = freq
+}
+
+// hit records activity on the channel, as a new hit or as the continuation
+// of the most recent one.
+func (s *Scanner) hit(freq uint32, power float64, first bool) {
+	now := time.Now()
+	if s.Now != nil {
+		now = s.Now()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !first && len(s.status.Hits) > 0 && s.status.Hits[0].Frequency == freq {
+		latest := &s.status.Hits[0]
+		latest.Power = max(latest.Power, power)
+		latest.LastSeen = now
+		return
+	}
+
+	hits := append([]Hit{{Frequency: freq, Power: power, FirstSeen: now, LastSeen: now}}, s.status.Hits...)
+	s.status.Hits = hits[:min(len(hits), or(s.MaxHits, DefaultMaxHits))]
+}
+
+// stream reads the chunks of a sample stream in pieces of any size.
+type stream struct {
+	ctx  context.Context
+	ch   <-chan []byte
+	rest []byte
+}
+
+// next returns at most n bytes of samples, an even number.
+func (st *stream) next(n int) ([]byte, error) {
+	for len(st.rest) == 0 {
+		select {
+		case <-st.ctx.Done():
+			return nil, st.ctx.Err()
+		case chunk, ok := <-st.ch:
+			if !ok {
+				// The stream may be closed because ctx is done.
+				if err := st.ctx.Err(); err != nil {
+					return nil, err
+				}
+				return nil, ErrStreamEnded
+			}
+			st.rest = chunk[:len(chunk)&^1]
+		}
+	}
+
+	n = min(n, len(st.rest))
+	chunk := st.rest[:n]
+	st.rest = st.rest[n:]
+
+	return chunk, nil
+}
+
+// discard skips n bytes of samples.
+func (st *stream) discard(n int) error {
+	for n > 0 {
+		chunk, err := st.next(n)
+		if err != nil {
+			return err
+		}
+		n -= len(chunk)
+	}
+
+	return nil
+}
+
+// sampleBytes returns the number of bytes of samples in d at rate, an even
+// number and at least one sample.
+func sampleBytes(d time.Duration, rate float64) int {
+	return max(2*int(d.Seconds()*rate), 2)
+}
+
+// or returns v, or def if v is not positive.
+func or[T int | time.Duration](v, def T) T {
+	if v > 0 {
+		return v
+	}
+
+	return def
+}
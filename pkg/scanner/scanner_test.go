@@ -0,0 +1,151 @@
+package scanner
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/frelon/k8s-radio/pkg/fakesdr"
+)
+
+func TestScanner(t *testing.T) {
+	RegisterFailHandler(Fail)
+
+	RunSpecs(t, "Scanner Suite")
+}
+
+const sampleRate = 240_000
+
+// radio is a fake receiver with a carrier at 101 MHz that can be switched
+// off.
+type radio struct {
+	frequency atomic.Uint32
+	on        atomic.Bool
+}
+
+// start streams the samples of the radio until ctx is done.
+func (r *radio) start(ctx context.Context) <-chan []byte {
+	carrier := fakesdr.NewGenerator(0.01, &fakesdr.Carrier{Frequency: 101_000_000, Amplitude: 0.5})
+	quiet := fakesdr.NewGenerator(0.01)
+
+	// Unbuffered, so that less than the settle time of samples captured at
+	// the previous frequency is still on its way after retuning.
+	ch := make(chan []byte)
+	go func() {
+		defer close(ch)
+
+		for {
+			source := quiet
+			if r.on.Load() {
+				source = carrier
+			}
+
+			buf := make([]byte, 8192)
+			tuning := fakesdr.Tuning{Frequency: r.frequency.Load(), SampleRate: sampleRate}
+			if err := source.ReadIQ(buf, tuning); err != nil {
+				return
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case ch <- buf:
+			}
+		}
+	}()
+
+	return ch
+}
+
+func (r *radio) tune(hz uint32) error {
+	r.frequency.Store(hz)
+	return nil
+}
+
+func rate() uint32 {
+	return sampleRate
+}
+
+// scan runs the scanner against the radio until the spec ends.
+func scan(ctx context.Context, s *Scanner, r *radio) {
+	ctx, cancel := context.WithCancel(ctx)
+	DeferCleanup(cancel)
+
+	samples := r.start(ctx)
+	go func() {
+		defer GinkgoRecover()
+		Expect(s.Run(ctx, samples, r.tune, rate)).To(MatchError(context.Canceled))
+	}()
+}
+
+func newScanner() *Scanner {
+	return &Scanner{
+		Frequencies: []uint32{100_000_000, 100_500_000, 101_000_000, 101_500_000},
+		Bandwidth:   25_000,
+		Squelch:     -30,
+		Hold:        200 * time.Millisecond,
+	}
+}
+
+var _ = Describe("Scanner", func() {
+	It("stops on active channels", func(ctx SpecContext) {
+		r := &radio{}
+		r.on.Store(true)
+
+		s := newScanner()
+		scan(ctx, s, r)
+
+		Eventually(func() []Hit { return s.Status().Hits }).Should(HaveLen(1))
+
+		hit := s.Status().Hits[0]
+		Expect(hit.Frequency).To(Equal(uint32(101_000_000)))
+		Expect(hit.Power).To(BeNumerically("~", -6, 1))
+		Consistently(func() uint32 { return s.Status().Frequency }, "500ms").Should(Equal(uint32(101_000_000)))
+		Expect(s.Status().Hits).To(HaveLen(1))
+	})
+
+	It("moves on once the channel is quiet", func(ctx SpecContext) {
+		r := &radio{}
+		r.on.Store(true)
+
+		s := newScanner()
+		scan(ctx, s, r)
+
+		Eventually(func() []Hit { return s.Status().Hits }).Should(HaveLen(1))
+		r.on.Store(false)
+
+		Eventually(func() uint32 { return s.Status().Frequency }).ShouldNot(Equal(uint32(101_000_000)))
+		hit := s.Status().Hits[0]
+		Expect(hit.LastSeen).ToNot(BeTemporally("<", hit.FirstSeen))
+	})
+
+	It("ignores quiet channels", func(ctx SpecContext) {
+		s := newScanner()
+		scan(ctx, s, &radio{})
+
+		Consistently(func() []Hit { return s.Status().Hits }, "500ms").Should(BeEmpty())
+	})
+
+	It("keeps the most recent hits", func() {
+		s := &Scanner{MaxHits: 2}
+		s.hit(100_000_000, -20, true)
+		s.hit(100_000_000, -10, false)
+		s.hit(101_000_000, -20, true)
+		s.hit(102_000_000, -20, true)
+
+		hits := s.Status().Hits
+		Expect(hits).To(HaveLen(2))
+		Expect(hits[0].Frequency).To(Equal(uint32(102_000_000)))
+		Expect(hits[1].Frequency).To(Equal(uint32(101_000_000)))
+
+		s = &Scanner{}
+		s.hit(100_000_000, -20, true)
+		s.hit(100_000_000, -10, false)
+		Expect(s.Status().Hits).To(HaveLen(1))
+		Expect(s.Status().Hits[0].Power).To(Equal(-10.0))
+	})
+})
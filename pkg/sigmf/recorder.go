@@ -0,0 +1,357 @@
+package sigmf
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/frelon/k8s-radio/pkg/rtltcp"
+)
+
+// nameFormat is the timestamp in dataset names. Names sort in the order
+// the datasets were started.
+const nameFormat = "20060102T150405.000Z"
+
+// maxStatusFiles bounds the number of datasets listed in the status.
+const maxStatusFiles = 100
+
+// ErrStreamEnded is returned by Record when the sample stream ends before
+// the recording is finished.
+var ErrStreamEnded = errors.New("sigmf: stream ended")
+
+// Status describes the datasets of a recorder.
+type Status struct {
+	// Files are the names of the datasets in the directory, oldest first,
+	// without suffix. Only the most recent are listed.
+	Files []string `json:"files"`
+	// Bytes is the size of the data files of all datasets in the
+	// directory.
+	Bytes int64 `json:"bytes"`
+	// Finished is set once the duration or size limit has been reached.
+	Finished bool `json:"finished"`
+}
+
+// Recorder writes an I/Q stream to a directory as a series of SigMF
+// datasets, starting a new dataset when the current one gets too old or too
+// large, or when the sample rate changes.
+type Recorder struct {
+	// Dir is the directory the datasets are written to.
+	Dir string
+	// Name prefixes the name of every dataset.
+	Name string
+	// Global is copied into the metadata of every dataset.
+	Global Global
+
+	// Interval rotates datasets after this long when positive.
+	Interval time.Duration
+	// MaxFileSize rotates datasets once their data file has this many
+	// bytes when positive.
+	MaxFileSize int64
+	// MaxFiles is the number of datasets kept when positive. The oldest
+	// are deleted when a new dataset is started.
+	MaxFiles int
+
+	// Duration stops the recording after this long when positive.
+	Duration time.Duration
+	// MaxSize stops the recording after this many bytes of samples when
+	// positive.
+	MaxSize int64
+
+	// Now returns the current time, defaults to time.Now.
+	Now func() time.Time
+
+	mu     sync.Mutex
+	status Status
+
+	// recorded is the number of bytes written by Record, and started the
+	// time it was first called.
+	recorded int64
+	started  time.Time
+}
+
+// dataset is the dataset being written.
+type dataset struct {
+	name   string
+	data   *os.File
+	meta   Meta
+	opened time.Time
+	size   int64
+	tuning rtltcp.Tuning
+}
+
+// Status returns the status of the recorder.
+func (r *Recorder) Status() Status {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	status := r.status
+	status.Files = slices.Clone(r.status.Files)
+
+	return status
+}
+
+// Record writes the chunks of samples to datasets until the stream ends,
+// ctx is done or a limit is reached. tuning returns the tuning the samples
+// were captured with. Record may be called again after the stream ended to
+// continue the recording with a new dataset; the limits apply to all calls.
+// Record must not be called concurrently.
+func (r *Recorder) Record(ctx context.Context, samples <-chan []byte, tuning func() rtltcp.Tuning) error {
+	if r.Status().Finished {
+		return nil
+	}
+
+	if err := os.MkdirAll(r.Dir, 0o755); err != nil {
+		return err
+	}
+
+	if r.started.IsZero() {
+		r.started = r.now()
+	}
+
+	var d *dataset
+	defer func() {
+		if d != nil {
+			if err := d.close(); err != nil {
+				slog.Error("Failed closing dataset", slog.String("dataset", d.name), slog.Any("error", err))
+			}
+		}
+		r.refresh()
+	}()
+
+	for {
+		var chunk []byte
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case c, ok := <-samples:
+			if !ok {
+				return ErrStreamEnded
+			}
+			chunk = c
+		}
+
+		now := r.now()
+		if r.Duration > 0 && now.Sub(r.started) >= r.Duration {
+			r.finish()
+			return nil
+		}
+
+		t := tuning()
+		if d != nil && r.rotate(d, now, t) {
+			if err := d.close(); err != nil {
+				return err
+			}
+			d = nil
+		}
+
+		if d == nil {
+			var err error
+			if d, err = r.open(now, t); err != nil {
+				return err
+			}
+			if err := r.prune(); err != nil {
+				slog.Error("Failed deleting old datasets", slog.Any("error", err))
+			}
+			r.refresh()
+		} else if t != d.tuning {
+			if err := d.capture(r.Dir, now, t); err != nil {
+				return err
+			}
+		}
+
+		if r.MaxSize > 0 {
+			// Keep whole samples.
+			remaining := (r.MaxSize - r.recorded) &^ 1
+			if int64(len(chunk)) > remaining {
+				chunk = chunk[:remaining]
+			}
+		}
+
+		n, err := d.data.Write(chunk)
+		d.size += int64(n)
+		r.recorded += int64(n)
+		r.written(int64(n))
+		if err != nil {
+			return err
+		}
+
+		if r.MaxSize > 0 && r.recorded >= r.MaxSize&^1 {
+			r.finish()
+			return nil
+		}
+	}
+}
+
+func (r *Recorder) now() time.Time {
+	if r.Now != nil {
+		return r.Now()
+	}
+
+	return time.Now()
+}
+
+// rotate reports whether a new dataset must be started.
+func (r *Recorder) rotate(d *dataset, now time.Time, t rtltcp.Tuning) bool {
+	switch {
+	case t.SampleRate != d.tuning.SampleRate:
+		// The sample rate is global to a dataset.
+		return true
+	case r.Interval > 0 && now.Sub(d.opened) >= r.Interval:
+		return true
+	case r.MaxFileSize > 0 && d.size >= r.MaxFileSize:
+		return true
+	default:
+		return false
+	}
+}
+
+// open starts a new dataset.
+func (r *Recorder) open(now time.Time, t rtltcp.Tuning) (*dataset, error) {
+	name := fmt.Sprintf("%s-%s", r.Name, now.UTC().Format(nameFormat))
+
+	data, err := os.OpenFile(filepath.Join(r.Dir, name+DataSuffix), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, err
+	}
+
+	global := r.Global
+	global.Datatype = DatatypeCU8
+	global.Version = Version
+	global.SampleRate = float64(t.SampleRate)
+	global.Extensions = []ExtensionInfo{{Name: Extension, Version: Version, Optional: true}}
+
+	d := &dataset{
+		name:   name,
+		data:   data,
+		meta:   Meta{Global: global},
+		opened: now,
+	}
+	if err := d.capture(r.Dir, now, t); err != nil {
+		_ = data.Close()
+		return nil, err
+	}
+
+	slog.Info("Recording dataset", slog.String("dataset", name), slog.Any("frequency", t.Frequency))
+
+	return d, nil
+}
+
+// capture starts a new capture segment at the next sample and writes the
+// metadata, so that a dataset always has valid metadata.
+func (d *dataset) capture(dir string, now time.Time, t rtltcp.Tuning) error {
+	capture := Capture{
+		SampleStart: uint64(d.size / 2),
+		Frequency:   float64(t.Frequency),
+		Datetime:    Datetime(now),
+	}
+	if t.ManualGain {
+		gain := float64(t.Gain) / 10
+		capture.Gain = &gain
+	}
+
+	// A capture replaces one at the same sample.
+	if n := len(d.meta.Captures); n > 0 && d.meta.Captures[n-1].SampleStart == capture.SampleStart {
+		d.meta.Captures = d.meta.Captures[:n-1]
+	}
+	d.meta.Captures = append(d.meta.Captures, capture)
+	d.tuning = t
+
+	return WriteMeta(filepath.Join(dir, d.name+MetaSuffix), &d.meta)
+}
+
+func (d *dataset) close() error {
+	if err := d.data.Sync(); err != nil {
+		_ = d.data.Close()
+		return err
+	}
+
+	return d.data.Close()
+}
+
+// finish marks the recording as finished.
+func (r *Recorder) finish() {
+	slog.Info("Recording finished", slog.Int64("bytes", r.recorded))
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.status.Finished = true
+}
+
+// datasets returns the names of the datasets in the directory, oldest first.
+func (r *Recorder) datasets() ([]string, error) {
+	matches, err := filepath.Glob(filepath.Join(r.Dir, r.Name+"-*"+MetaSuffix))
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(matches))
+	for _, m := range matches {
+		names = append(names, strings.TrimSuffix(filepath.Base(m), MetaSuffix))
+	}
+	slices.Sort(names)
+
+	return names, nil
+}
+
+// prune deletes the oldest datasets over MaxFiles.
+func (r *Recorder) prune() error {
+	if r.MaxFiles <= 0 {
+		return nil
+	}
+
+	names, err := r.datasets()
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, name := range names[:max(len(names)-r.MaxFiles, 0)] {
+		slog.Info("Deleting old dataset", slog.String("dataset", name))
+		for _, suffix := range []string{DataSuffix, MetaSuffix} {
+			if err := os.Remove(filepath.Join(r.Dir, name+suffix)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// written adds n bytes written to the current dataset to the status.
+func (r *Recorder) written(n int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.status.Bytes += n
+}
+
+// refresh updates the status from the directory.
+func (r *Recorder) refresh() {
+	names, err := r.datasets()
+	if err != nil {
+		slog.Error("Failed listing datasets", slog.Any("error", err))
+		return
+	}
+
+	var size int64
+	for _, name := range names {
+		if info, err := os.Stat(filepath.Join(r.Dir, name+DataSuffix)); err == nil {
+			size += info.Size()
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.status.Files = names[max(len(names)-maxStatusFiles, 0):]
+	r.status.Bytes = size
+}
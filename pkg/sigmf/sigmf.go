@@ -0,0 +1,133 @@
+// Package sigmf records I/Q streams as SigMF datasets: a data file holding
+// the raw samples and a JSON metadata file describing how they were captured.
+//
+// See https://sigmf.org for the specification.
+package sigmf
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	// Version is the version of the SigMF specification written.
+	Version = "1.0.0"
+	// DatatypeCU8 is the datatype of the interleaved unsigned 8-bit I/Q
+	// samples rtl_tcp produces.
+	DatatypeCU8 = "cu8"
+
+	// DataSuffix is the file suffix of the data file of a dataset.
+	DataSuffix = ".sigmf-data"
+	// MetaSuffix is the file suffix of the metadata file of a dataset.
+	MetaSuffix = ".sigmf-meta"
+
+	// Extension is the namespace of the non-core fields written.
+	Extension = "k8s-radio"
+
+	// datetimeFormat is the ISO 8601 format of core:datetime.
+	datetimeFormat = "2006-01-02T15:04:05.000Z"
+)
+
+// Meta is the content of a metadata file.
+type Meta struct {
+	Global      Global       `json:"global"`
+	Captures    []Capture    `json:"captures"`
+	Annotations []Annotation `json:"annotations"`
+}
+
+// Global describes the whole dataset.
+type Global struct {
+	Datatype    string          `json:"core:datatype"`
+	Version     string          `json:"core:version"`
+	SampleRate  float64         `json:"core:sample_rate,omitempty"`
+	Hardware    string          `json:"core:hw,omitempty"`
+	Recorder    string          `json:"core:recorder,omitempty"`
+	Description string          `json:"core:description,omitempty"`
+	Extensions  []ExtensionInfo `json:"core:extensions,omitempty"`
+
+	// Serial is the serial number of the dongle.
+	Serial string `json:"k8s-radio:serial,omitempty"`
+	// Node is the Kubernetes node the dongle is plugged into.
+	Node string `json:"k8s-radio:node,omitempty"`
+	// Receiver is the namespaced name of the RtlSdrReceiver recorded.
+	Receiver string `json:"k8s-radio:receiver,omitempty"`
+}
+
+// ExtensionInfo declares an extension namespace used by the dataset.
+type ExtensionInfo struct {
+	Name     string `json:"name"`
+	Version  string `json:"version"`
+	Optional bool   `json:"optional"`
+}
+
+// Capture describes the samples from SampleStart until the next capture.
+type Capture struct {
+	SampleStart uint64  `json:"core:sample_start"`
+	Frequency   float64 `json:"core:frequency,omitempty"`
+	Datetime    string  `json:"core:datetime,omitempty"`
+
+	// Gain is the manual tuner gain in dB, unset when the gain is automatic.
+	Gain *float64 `json:"k8s-radio:gain,omitempty"`
+}
+
+// Annotation describes a range of samples.
+type Annotation struct {
+	SampleStart uint64 `json:"core:sample_start"`
+	SampleCount uint64 `json:"core:sample_count,omitempty"`
+	Comment     string `json:"core:comment,omitempty"`
+}
+
+// Datetime formats t the way core:datetime expects.
+func Datetime(t time.Time) string {
+	return t.UTC().Format(datetimeFormat)
+}
+
+// ReadMeta reads the metadata file at path.
+func ReadMeta(path string) (*Meta, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	meta := &Meta{}
+	if err := json.Unmarshal(data, meta); err != nil {
+		return nil, err
+	}
+
+	return meta, nil
+}
+
+// WriteMeta writes the metadata file at path. The file is replaced
+// atomically so readers never see a partial file.
+func WriteMeta(path string, meta *Meta) error {
+	if meta.Annotations == nil {
+		meta.Annotations = []Annotation{}
+	}
+
+	data, err := json.MarshalIndent(meta, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".sigmf-meta-*")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if err := tmp.Chmod(0o644); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
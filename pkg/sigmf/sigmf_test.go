@@ -0,0 +1,185 @@
+package sigmf
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/frelon/k8s-radio/pkg/rtltcp"
+)
+
+func TestSigMF(t *testing.T) {
+	RegisterFailHandler(Fail)
+
+	RunSpecs(t, "SigMF Suite")
+}
+
+// clock is a fake clock advancing by step every time it is read.
+type clock struct {
+	now  time.Time
+	step time.Duration
+}
+
+func (c *clock) Now() time.Time {
+	now := c.now
+	c.now = c.now.Add(c.step)
+	return now
+}
+
+// stream returns a closed channel holding n chunks of size bytes.
+func stream(n, size int) <-chan []byte {
+	ch := make(chan []byte, n)
+	for range n {
+		ch <- make([]byte, size)
+	}
+	close(ch)
+
+	return ch
+}
+
+// fixed returns a tuning function always returning t.
+func fixed(t rtltcp.Tuning) func() rtltcp.Tuning {
+	return func() rtltcp.Tuning { return t }
+}
+
+var tuning = rtltcp.Tuning{Frequency: 100_000_000, SampleRate: 2_048_000}
+
+// newRecorder returns a recorder writing to a temporary directory.
+func newRecorder() *Recorder {
+	return &Recorder{
+		Dir:  GinkgoT().TempDir(),
+		Name: "test",
+		Now:  (&clock{now: time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC), step: time.Millisecond}).Now,
+	}
+}
+
+// readMeta reads the metadata of a dataset of the recorder.
+func readMeta(r *Recorder, name string) *Meta {
+	meta, err := ReadMeta(filepath.Join(r.Dir, name+MetaSuffix))
+	Expect(err).ToNot(HaveOccurred())
+
+	return meta
+}
+
+var _ = Describe("Recorder", func() {
+	It("writes a SigMF dataset", func(ctx SpecContext) {
+		r := newRecorder()
+		r.Global = Global{Hardware: "RTL-SDR v4", Serial: "00000001", Node: "node-a", Receiver: "default/fm"}
+
+		gained := tuning
+		gained.ManualGain = true
+		gained.Gain = 297
+
+		Expect(r.Record(ctx, stream(4, 1024), fixed(gained))).To(MatchError(ErrStreamEnded))
+
+		status := r.Status()
+		Expect(status.Files).To(Equal([]string{"test-20261019T120000.001Z"}))
+		Expect(status.Bytes).To(Equal(int64(4096)))
+		Expect(status.Finished).To(BeFalse())
+
+		info, err := os.Stat(filepath.Join(r.Dir, status.Files[0]+DataSuffix))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(info.Size()).To(Equal(int64(4096)))
+
+		meta := readMeta(r, status.Files[0])
+		Expect(meta.Global.Datatype).To(Equal(DatatypeCU8))
+		Expect(meta.Global.Version).To(Equal(Version))
+		Expect(meta.Global.SampleRate).To(Equal(2_048_000.0))
+		Expect(meta.Global.Hardware).To(Equal("RTL-SDR v4"))
+		Expect(meta.Global.Serial).To(Equal("00000001"))
+		Expect(meta.Global.Node).To(Equal("node-a"))
+		Expect(meta.Global.Receiver).To(Equal("default/fm"))
+		Expect(meta.Captures).To(HaveLen(1))
+		Expect(meta.Captures[0].Frequency).To(Equal(100_000_000.0))
+		Expect(meta.Captures[0].Datetime).To(Equal("2026-10-19T12:00:00.001Z"))
+		Expect(meta.Captures[0].Gain).To(HaveValue(BeNumerically("~", 29.7)))
+		Expect(meta.Annotations).ToNot(BeNil())
+	})
+
+	It("starts a capture when retuned and a dataset when the sample rate changes", func(ctx SpecContext) {
+		r := newRecorder()
+
+		tunings := []rtltcp.Tuning{tuning, tuning, tuning, tuning}
+		tunings[2].Frequency = 101_900_000
+		tunings[3].Frequency = 101_900_000
+		tunings[3].SampleRate = 1_024_000
+
+		i := 0
+		next := func() rtltcp.Tuning {
+			t := tunings[i]
+			i++
+			return t
+		}
+
+		Expect(r.Record(ctx, stream(4, 1000), next)).To(MatchError(ErrStreamEnded))
+
+		files := r.Status().Files
+		Expect(files).To(HaveLen(2))
+
+		first := readMeta(r, files[0])
+		Expect(first.Captures).To(HaveLen(2))
+		Expect(first.Captures[1].SampleStart).To(Equal(uint64(1000)))
+		Expect(first.Captures[1].Frequency).To(Equal(101_900_000.0))
+
+		second := readMeta(r, files[1])
+		Expect(second.Global.SampleRate).To(Equal(1_024_000.0))
+	})
+
+	It("rotates datasets and keeps the newest", func(ctx SpecContext) {
+		r := newRecorder()
+		r.MaxFileSize = 2048
+		r.MaxFiles = 2
+
+		Expect(r.Record(ctx, stream(10, 1024), fixed(tuning))).To(MatchError(ErrStreamEnded))
+
+		status := r.Status()
+		Expect(status.Files).To(HaveLen(2))
+		Expect(status.Bytes).To(Equal(int64(4096)))
+
+		matches, err := filepath.Glob(filepath.Join(r.Dir, "*"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(matches).To(HaveLen(4))
+	})
+
+	It("stops at the size limit", func(ctx SpecContext) {
+		r := newRecorder()
+		r.MaxSize = 2500
+
+		Expect(r.Record(ctx, stream(10, 1024), fixed(tuning))).To(Succeed())
+
+		status := r.Status()
+		Expect(status.Finished).To(BeTrue())
+		Expect(status.Bytes).To(Equal(int64(2500)))
+
+		By("not recording again once finished")
+		Expect(r.Record(ctx, stream(1, 1024), fixed(tuning))).To(Succeed())
+		Expect(r.Status().Bytes).To(Equal(int64(2500)))
+	})
+
+	It("stops after the duration", func(ctx SpecContext) {
+		r := newRecorder()
+		r.Now = (&clock{now: time.Now(), step: time.Second}).Now
+		r.Duration = 3 * time.Second
+
+		Expect(r.Record(ctx, stream(10, 1024), fixed(tuning))).To(Succeed())
+
+		status := r.Status()
+		Expect(status.Finished).To(BeTrue())
+		Expect(status.Bytes).To(Equal(int64(2048)))
+	})
+
+	It("stops when the context is done", func() {
+		r := newRecorder()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		Expect(r.Record(ctx, make(chan []byte), fixed(tuning))).To(MatchError(context.Canceled))
+		Expect(r.Status().Files).To(BeEmpty())
+	})
+})
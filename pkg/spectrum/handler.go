@@ -0,0 +1,83 @@
+package spectrum
+
+import (
+	_ "embed"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// writeTimeout bounds how long sending a frame to a WebSocket client may
+// take before the client is dropped.
+const writeTimeout = 5 * time.Second
+
+//go:embed index.html
+var index []byte
+
+var upgrader = websocket.Upgrader{}
+
+// Handler serves the waterfall at /, the latest frame as JSON at /spectrum and
+// the frames as they are computed over a WebSocket at /ws.
+func (a *Analyzer) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = w.Write(index)
+	})
+	mux.HandleFunc("GET /spectrum", a.serveLatest)
+	mux.HandleFunc("GET /ws", a.serveWebSocket)
+
+	return mux
+}
+
+// serveLatest serves the latest frame, or 503 until the first one is ready.
+func (a *Analyzer) serveLatest(w http.ResponseWriter, _ *http.Request) {
+	frame, ok := a.Latest()
+	if !ok {
+		http.Error(w, "no spectrum yet", http.StatusServiceUnavailable)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(frame)
+}
+
+// serveWebSocket sends the frames to the client as JSON messages until it
+// disconnects.
+func (a *Analyzer) serveWebSocket(w http.ResponseWriter, r *http.Request) {
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer func() { _ = conn.Close() }()
+
+	frames, unsubscribe := a.Subscribe()
+	defer unsubscribe()
+
+	// The client sends nothing, reading only notices when it goes away.
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		case <-closed:
+			return
+		case frame := <-frames:
+			_ = conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+			if err := conn.WriteJSON(frame); err != nil {
+				slog.Info("Dropped spectrum client", slog.String("remote", r.RemoteAddr), slog.Any("error", err))
+				return
+			}
+		}
+	}
+}
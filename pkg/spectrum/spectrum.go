@@ -0,0 +1,244 @@
+// Package spectrum computes the live power spectrum of an I/Q stream and
+// serves it as JSON, over a WebSocket and as a waterfall in the browser.
+package spectrum
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/frelon/k8s-radio/pkg/dsp"
+	"github.com/frelon/k8s-radio/pkg/rtltcp"
+)
+
+const (
+	// DefaultFFTSize is the default number of bins of a frame.
+	DefaultFFTSize = 1024
+	// DefaultAveraging is the default number of FFTs averaged into a frame.
+	DefaultAveraging = 4
+	// DefaultFrameRate is the default number of frames per second.
+	DefaultFrameRate = 10
+
+	// MaxFFTSize bounds the number of bins of a frame.
+	MaxFFTSize = 16384
+)
+
+// ErrStreamEnded is returned by Run when the sample stream ends.
+var ErrStreamEnded = errors.New("spectrum: stream ended")
+
+// Frame is the power spectrum of the stream at one point in time.
+type Frame struct {
+	// Time is when the frame was computed.
+	Time time.Time `json:"time"`
+	// Frequency is the centre frequency of the receiver in Hz.
+	Frequency uint32 `json:"frequency"`
+	// SampleRate is the sample rate of the receiver in Hz, which is also the
+	// span of the frame.
+	SampleRate uint32 `json:"sampleRate"`
+	// Power is the power of each bin in dBFS, from the lowest to the highest
+	// frequency.
+	Power []float32 `json:"power"`
+}
+
+// Analyzer turns the stream into frames of FFTSize bins, each the average of
+// Averaging FFTs, at no more than FrameRate frames per second. The samples
+// between frames are skipped, so the analyzer keeps pace with the stream
+// however slow the FFTs are.
+type Analyzer struct {
+	// FFTSize is the number of bins of a frame, a power of two. Defaults to
+	// DefaultFFTSize.
+	FFTSize int
+	// Averaging is the number of FFTs averaged into a frame, defaults to
+	// DefaultAveraging.
+	Averaging int
+	// FrameRate is the number of frames per second, defaults to
+	// DefaultFrameRate.
+	FrameRate float64
+
+	// Now returns the current time, defaults to time.Now.
+	Now func() time.Time
+
+	mu          sync.Mutex
+	latest      *Frame
+	subscribers map[chan Frame]struct{}
+}
+
+// Validate checks the settings of the analyzer.
+func (a *Analyzer) Validate() error {
+	size := or(a.FFTSize, DefaultFFTSize)
+	if size < 2 || size > MaxFFTSize || size&(size-1) != 0 {
+		return fmt.Errorf("spectrum: FFT size %d is not a power of two up to %d", size, MaxFFTSize)
+	}
+	if a.Averaging < 0 || a.FrameRate < 0 {
+		return errors.New("spectrum: averaging and frame rate may not be negative")
+	}
+
+	return nil
+}
+
+// Latest returns the most recent frame, if any.
+func (a *Analyzer) Latest() (Frame, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.latest == nil {
+		return Frame{}, false
+	}
+
+	return *a.latest, true
+}
+
+// Subscribe returns a channel receiving the frames as they are computed, and
+// a function to stop receiving them. A subscriber that falls behind only
+// receives the most recent frame.
+func (a *Analyzer) Subscribe() (<-chan Frame, func()) {
+	ch := make(chan Frame, 1)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.subscribers == nil {
+		a.subscribers = map[chan Frame]struct{}{}
+	}
+	a.subscribers[ch] = struct{}{}
+
+	return ch, func() {
+		a.mu.Lock()
+		defer a.mu.Unlock()
+
+		delete(a.subscribers, ch)
+	}
+}
+
+// Run computes frames until ctx is done or the stream ends. tuning returns
+// the frequency and sample rate of the receiver.
+func (a *Analyzer) Run(ctx context.Context, samples <-chan []byte, tuning func() rtltcp.Tuning) error {
+	if err := a.Validate(); err != nil {
+		return err
+	}
+
+	size := or(a.FFTSize, DefaultFFTSize)
+	averaging := or(a.Averaging, DefaultAveraging)
+	rate := a.FrameRate
+	if rate <= 0 {
+		rate = DefaultFrameRate
+	}
+
+	window := dsp.Hann(size)
+	var gain float64
+	for _, w := range window {
+		gain += w
+	}
+
+	power := make([]float64, size)
+	block := make([]complex128, 0, size)
+	buf := make([]byte, 0, 2*size*averaging)
+	var rest []byte
+
+	next := func() error {
+		for len(rest) == 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case chunk, ok := <-samples:
+				if !ok {
+					return ErrStreamEnded
+				}
+				rest = chunk[:len(chunk)&^1]
+			}
+		}
+
+		return nil
+	}
+
+	for {
+		t := tuning()
+
+		// Collect the samples of the FFTs.
+		for buf = buf[:0]; len(buf) < cap(buf); {
+			if err := next(); err != nil {
+				return err
+			}
+			n := min(cap(buf)-len(buf), len(rest))
+			buf, rest = append(buf, rest[:n]...), rest[n:]
+		}
+
+		clear(power)
+		for i := range averaging {
+			block = dsp.Complex(block[:0], buf[2*size*i:2*size*(i+1)])
+			for j, w := range window {
+				block[j] *= complex(w, 0)
+			}
+			dsp.FFT(block)
+			for j, v := range block {
+				power[j] += real(v)*real(v) + imag(v)*imag(v)
+			}
+		}
+
+		a.publish(Frame{
+			Time:       a.now(),
+			Frequency:  t.Frequency,
+			SampleRate: t.SampleRate,
+			Power:      decibels(power, float64(averaging)*gain*gain),
+		})
+
+		// Skip the samples until the next frame is due.
+		for skip := 2*int(float64(t.SampleRate)/rate) - cap(buf); skip > 0; {
+			if err := next(); err != nil {
+				return err
+			}
+			n := min(skip, len(rest))
+			skip, rest = skip-n, rest[n:]
+		}
+	}
+}
+
+// decibels scales the power of the FFT bins by norm and converts it to dBFS,
+// rounded to a tenth of a dB, reordering the bins from the lowest to the
+// highest frequency.
+func decibels(power []float64, norm float64) []float32 {
+	half := len(power) / 2
+	out := make([]float32, len(power))
+	for i, p := range power {
+		db := math.Round(dsp.DBFS(p/norm)*10) / 10
+		out[(i+half)%len(power)] = float32(db)
+	}
+
+	return out
+}
+
+// publish makes the frame the latest and sends it to the subscribers,
+// replacing any frame they have not received yet.
+func (a *Analyzer) publish(frame Frame) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.latest = &frame
+	for ch := range a.subscribers {
+		select {
+		case <-ch:
+		default:
+		}
+		ch <- frame
+	}
+}
+
+func (a *Analyzer) now() time.Time {
+	if a.Now != nil {
+		return a.Now()
+	}
+
+	return time.Now()
+}
+
+// or returns v, or def if v is not positive.
+func or(v, def int) int {
+	if v > 0 {
+		return v
+	}
+
+	return def
+}
@@ -0,0 +1,193 @@
+package spectrum
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/frelon/k8s-radio/pkg/fakesdr"
+	"github.com/frelon/k8s-radio/pkg/rtltcp"
+)
+
+func TestSpectrum(t *testing.T) {
+	RegisterFailHandler(Fail)
+
+	RunSpecs(t, "Spectrum Suite")
+}
+
+// tuning is a receiver at 101 MHz with a carrier 60 kHz above it.
+var tuning = rtltcp.Tuning{Frequency: 101_000_000, SampleRate: 240_000}
+
+func tuned() rtltcp.Tuning {
+	return tuning
+}
+
+// stream returns n bytes of samples of the carrier in chunks, then ends.
+func stream(n int) <-chan []byte {
+	gen := fakesdr.NewGenerator(0.01, &fakesdr.Carrier{Frequency: 101_060_000, Amplitude: 0.5})
+
+	ch := make(chan []byte, 4)
+	go func() {
+		defer close(ch)
+
+		for ; n > 0; n -= 8192 {
+			buf := make([]byte, min(n, 8192))
+			if err := gen.ReadIQ(buf, tuning); err != nil {
+				return
+			}
+			ch <- buf
+		}
+	}()
+
+	return ch
+}
+
+// endless streams the carrier until the spec ends.
+func endless(ctx context.Context) <-chan []byte {
+	gen := fakesdr.NewGenerator(0.01, &fakesdr.Carrier{Frequency: 101_060_000, Amplitude: 0.5})
+
+	ch := make(chan []byte)
+	go func() {
+		defer close(ch)
+
+		for {
+			buf := make([]byte, 8192)
+			if err := gen.ReadIQ(buf, tuning); err != nil {
+				return
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case ch <- buf:
+			}
+		}
+	}()
+
+	return ch
+}
+
+// peak returns the bin with the highest power.
+func peak(power []float32) int {
+	highest := 0
+	for i, p := range power {
+		if p > power[highest] {
+			highest = i
+		}
+	}
+
+	return highest
+}
+
+var _ = Describe("Analyzer", func() {
+	It("computes the power of the carrier in its bin", func(ctx SpecContext) {
+		a := &Analyzer{FFTSize: 256, Averaging: 2}
+		Expect(a.Run(ctx, stream(2*256*2), tuned)).To(MatchError(ErrStreamEnded))
+
+		frame, ok := a.Latest()
+		Expect(ok).To(BeTrue())
+		Expect(frame.Frequency).To(Equal(tuning.Frequency))
+		Expect(frame.SampleRate).To(Equal(tuning.SampleRate))
+		Expect(frame.Power).To(HaveLen(256))
+
+		// The carrier is a quarter of the sample rate above the centre.
+		Expect(peak(frame.Power)).To(Equal(128 + 64))
+		Expect(frame.Power[128+64]).To(BeNumerically("~", -6, 1))
+		Expect(frame.Power[32]).To(BeNumerically("<", -40))
+	})
+
+	It("limits the frame rate by skipping samples", func(ctx SpecContext) {
+		var frames atomic.Int32
+		a := &Analyzer{FFTSize: 64, Averaging: 1, FrameRate: 10, Now: func() time.Time {
+			frames.Add(1)
+			return time.Now()
+		}}
+
+		// One second of samples.
+		Expect(a.Run(ctx, stream(2*int(tuning.SampleRate)), tuned)).To(MatchError(ErrStreamEnded))
+		Expect(frames.Load()).To(BeNumerically("~", 10, 1))
+	})
+
+	It("rejects FFT sizes that are not a power of two", func(ctx SpecContext) {
+		a := &Analyzer{FFTSize: 1000}
+		Expect(a.Validate()).To(MatchError(ContainSubstring("power of two")))
+		Expect(a.Run(ctx, stream(0), tuned)).To(MatchError(ContainSubstring("power of two")))
+	})
+})
+
+var _ = Describe("Handler", func() {
+	var a *Analyzer
+	var server *httptest.Server
+
+	BeforeEach(func() {
+		a = &Analyzer{FFTSize: 128, FrameRate: 20}
+		server = httptest.NewServer(a.Handler())
+		DeferCleanup(server.Close)
+	})
+
+	run := func(ctx context.Context) {
+		ctx, cancel := context.WithCancel(ctx)
+		DeferCleanup(cancel)
+
+		go func() {
+			defer GinkgoRecover()
+			Expect(a.Run(ctx, endless(ctx), tuned)).To(MatchError(context.Canceled))
+		}()
+	}
+
+	It("serves the waterfall", func() {
+		resp, err := http.Get(server.URL)
+		Expect(err).ToNot(HaveOccurred())
+		defer func() { _ = resp.Body.Close() }()
+
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		body, err := io.ReadAll(resp.Body)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(body)).To(ContainSubstring("<canvas"))
+	})
+
+	It("serves the latest frame as JSON", func(ctx SpecContext) {
+		resp, err := http.Get(server.URL + "/spectrum")
+		Expect(err).ToNot(HaveOccurred())
+		_ = resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusServiceUnavailable))
+
+		run(ctx)
+
+		Eventually(func(g Gomega) {
+			resp, err := http.Get(server.URL + "/spectrum")
+			g.Expect(err).ToNot(HaveOccurred())
+			defer func() { _ = resp.Body.Close() }()
+			g.Expect(resp.StatusCode).To(Equal(http.StatusOK))
+
+			var frame Frame
+			g.Expect(json.NewDecoder(resp.Body).Decode(&frame)).To(Succeed())
+			g.Expect(frame.Power).To(HaveLen(128))
+		}).Should(Succeed())
+	})
+
+	It("streams the frames over a WebSocket", func(ctx SpecContext) {
+		run(ctx)
+
+		conn, _, err := websocket.DefaultDialer.DialContext(ctx, "ws"+strings.TrimPrefix(server.URL, "http")+"/ws", nil)
+		Expect(err).ToNot(HaveOccurred())
+		defer func() { _ = conn.Close() }()
+
+		for range 3 {
+			var frame Frame
+			Expect(conn.ReadJSON(&frame)).To(Succeed())
+			Expect(frame.Frequency).To(Equal(tuning.Frequency))
+			Expect(peak(frame.Power)).To(Equal(64 + 32))
+		}
+	})
+})
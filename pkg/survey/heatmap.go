@@ -0,0 +1,95 @@
+package survey
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"math"
+	"slices"
+)
+
+// MaxHeatmapWidth bounds the width of heatmaps. Wider sweeps are reduced by
+// keeping the strongest of neighbouring bins.
+const MaxHeatmapWidth = 2000
+
+// palette is the colour scale of heatmaps, from the weakest to the
+// strongest power.
+var palette = []color.RGBA{
+	{0x00, 0x00, 0x00, 0xff},
+	{0x1f, 0x0c, 0x48, 0xff},
+	{0x7b, 0x1d, 0x6f, 0xff},
+	{0xd4, 0x4a, 0x42, 0xff},
+	{0xfb, 0xb0, 0x3b, 0xff},
+	{0xfc, 0xfd, 0xbf, 0xff},
+}
+
+// WriteHeatmap renders the sweeps as a PNG image with one row per sweep,
+// oldest at the top, and frequency increasing to the right. Colours are
+// scaled between the 5th and 99th percentile of the power.
+func WriteHeatmap(w io.Writer, sweeps []Sweep) error {
+	width := 0
+	for _, sweep := range sweeps {
+		width = max(width, len(sweep.Bins))
+	}
+	if width == 0 {
+		return errors.New("survey: no bins to render")
+	}
+
+	// Bins per pixel.
+	group := (width + MaxHeatmapWidth - 1) / MaxHeatmapWidth
+	img := image.NewRGBA(image.Rect(0, 0, (width+group-1)/group, len(sweeps)))
+
+	low, high := powerRange(sweeps)
+	for y, sweep := range sweeps {
+		for x := range img.Bounds().Dx() {
+			bins := sweep.Bins[min(x*group, len(sweep.Bins)):min((x+1)*group, len(sweep.Bins))]
+			if len(bins) == 0 {
+				continue
+			}
+
+			power := math.Inf(-1)
+			for _, bin := range bins {
+				power = max(power, bin.Power)
+			}
+			img.SetRGBA(x, y, colour((power-low)/(high-low)))
+		}
+	}
+
+	return png.Encode(w, img)
+}
+
+// powerRange returns the 5th and 99th percentile of the power of the
+// sweeps, so that a few outliers do not wash out the image.
+func powerRange(sweeps []Sweep) (float64, float64) {
+	var powers []float64
+	for _, sweep := range sweeps {
+		for _, bin := range sweep.Bins {
+			powers = append(powers, bin.Power)
+		}
+	}
+	slices.Sort(powers)
+
+	low := powers[len(powers)*5/100]
+	high := powers[len(powers)*99/100]
+	if high <= low {
+		high = low + 1
+	}
+
+	return low, high
+}
+
+// colour maps a value between 0 and 1 to the palette.
+func colour(v float64) color.RGBA {
+	v = min(max(v, 0), 1) * float64(len(palette)-1)
+	i := min(int(v), len(palette)-2)
+	f := v - float64(i)
+
+	a, b := palette[i], palette[i+1]
+	mix := func(x, y uint8) uint8 {
+		return uint8(math.Round(float64(x) + f*(float64(y)-float64(x))))
+	}
+
+	return color.RGBA{mix(a.R, b.R), mix(a.G, b.G), mix(a.B, b.B), 0xff}
+}
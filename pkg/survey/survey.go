@@ -0,0 +1,195 @@
+// Package survey summarises the power-vs-frequency CSV written by rtl_power:
+// it groups the rows into sweeps, finds the strongest peaks and renders a
+// heatmap of the sweeps.
+package survey
+
+import (
+	"cmp"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// timeFormat is the date and time columns of rtl_power joined by a space.
+const timeFormat = "2006-01-02 15:04:05"
+
+// Row is one line of rtl_power output: the power of the bins of one hop of
+// a sweep.
+type Row struct {
+	// Time is when the hop was measured.
+	Time time.Time
+	// Low and High are the frequencies covered by the hop in Hz.
+	Low, High float64
+	// Step is the width of the bins in Hz.
+	Step float64
+	// Samples is the number of samples integrated.
+	Samples int
+	// Power is the power of each bin in dB.
+	Power []float64
+}
+
+// Bin is the power of a frequency bin.
+type Bin struct {
+	// Frequency is the centre of the bin in Hz.
+	Frequency float64 `json:"frequency"`
+	// Power is the power of the bin in dB.
+	Power float64 `json:"power"`
+}
+
+// Sweep is one pass over the surveyed range.
+type Sweep struct {
+	// Time is when the sweep started.
+	Time time.Time
+	// Bins are the bins of the sweep, by frequency.
+	Bins []Bin
+}
+
+// Summary is the outcome of a survey, small enough to be the termination
+// message of a container.
+type Summary struct {
+	// Sweeps is the number of sweeps measured.
+	Sweeps int `json:"sweeps"`
+	// Peaks are the strongest peaks, strongest first.
+	Peaks []Bin `json:"peaks"`
+}
+
+// ReadCSV reads the rows written by rtl_power.
+func ReadCSV(r io.Reader) ([]Row, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	var rows []Row
+	for line := 1; ; line++ {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		row, err := parseRow(record)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		rows = append(rows, row)
+	}
+}
+
+func parseRow(record []string) (Row, error) {
+	if len(record) < 7 {
+		return Row{}, fmt.Errorf("expected at least 7 fields, got %d", len(record))
+	}
+
+	t, err := time.ParseInLocation(timeFormat, strings.TrimSpace(record[0])+" "+strings.TrimSpace(record[1]), time.UTC)
+	if err != nil {
+		return Row{}, err
+	}
+
+	values := make([]float64, 0, len(record)-2)
+	for _, field := range record[2:] {
+		v, err := strconv.ParseFloat(strings.TrimSpace(field), 64)
+		if err != nil {
+			return Row{}, err
+		}
+		values = append(values, v)
+	}
+
+	return Row{
+		Time:    t,
+		Low:     values[0],
+		High:    values[1],
+		Step:    values[2],
+		Samples: int(values[3]),
+		Power:   values[4:],
+	}, nil
+}
+
+// Sweeps groups the rows into sweeps. rtl_power hops up through the range,
+// so a row starting at or below the previous one starts a new sweep.
+func Sweeps(rows []Row) []Sweep {
+	var sweeps []Sweep
+	for i, row := range rows {
+		if i == 0 || row.Low <= rows[i-1].Low {
+			sweeps = append(sweeps, Sweep{Time: row.Time})
+		}
+
+		sweep := &sweeps[len(sweeps)-1]
+		for j, power := range row.Power {
+			sweep.Bins = append(sweep.Bins, Bin{Frequency: row.Low + (float64(j)+0.5)*row.Step, Power: power})
+		}
+	}
+
+	for _, sweep := range sweeps {
+		slices.SortStableFunc(sweep.Bins, byFrequency)
+	}
+
+	return sweeps
+}
+
+// MaxHold returns the highest power of every bin over all sweeps, by
+// frequency.
+func MaxHold(sweeps []Sweep) []Bin {
+	peak := map[float64]float64{}
+	for _, sweep := range sweeps {
+		for _, bin := range sweep.Bins {
+			if power, ok := peak[bin.Frequency]; !ok || bin.Power > power {
+				peak[bin.Frequency] = bin.Power
+			}
+		}
+	}
+
+	bins := make([]Bin, 0, len(peak))
+	for frequency, power := range peak {
+		bins = append(bins, Bin{Frequency: frequency, Power: power})
+	}
+	slices.SortFunc(bins, byFrequency)
+
+	return bins
+}
+
+// Peaks returns the n strongest local maxima of the bins standing at least
+// threshold dB above the median power, strongest first.
+func Peaks(bins []Bin, n int, threshold float64) []Bin {
+	if len(bins) == 0 || n <= 0 {
+		return nil
+	}
+
+	floor := median(bins) + threshold
+
+	var peaks []Bin
+	for i, bin := range bins {
+		// Plateaus count once, at their first bin.
+		if bin.Power < floor ||
+			(i > 0 && bins[i-1].Power >= bin.Power) ||
+			(i < len(bins)-1 && bins[i+1].Power > bin.Power) {
+			continue
+		}
+		peaks = append(peaks, bin)
+	}
+
+	slices.SortStableFunc(peaks, func(a, b Bin) int { return cmp.Compare(b.Power, a.Power) })
+
+	return peaks[:min(len(peaks), n)]
+}
+
+func byFrequency(a, b Bin) int {
+	return cmp.Compare(a.Frequency, b.Frequency)
+}
+
+// median returns the median power of the bins.
+func median(bins []Bin) float64 {
+	powers := make([]float64, 0, len(bins))
+	for _, bin := range bins {
+		powers = append(powers, bin.Power)
+	}
+	slices.Sort(powers)
+
+	return powers[len(powers)/2]
+}
@@ -0,0 +1,103 @@
+package survey
+
+import (
+	"bytes"
+	"image/png"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestSurvey(t *testing.T) {
+	RegisterFailHandler(Fail)
+
+	RunSpecs(t, "Survey Suite")
+}
+
+// output is two sweeps of 144-146 MHz in two hops of four 250 kHz bins, with
+// a carrier at 145.375 MHz that is stronger in the second sweep.
+const output = `2026-10-19, 12:00:00, 144000000, 145000000, 250000.00, 100, -50.00, -49.50, -30.00, -50.10
+2026-10-19, 12:00:01, 145000000, 146000000, 250000.00, 100, -50.20, -20.00, -49.80, -50.00
+2026-10-19, 12:00:10, 144000000, 145000000, 250000.00, 100, -50.00, -49.00, -35.00, -50.30
+2026-10-19, 12:00:11, 145000000, 146000000, 250000.00, 100, -50.10, -10.00, -49.90, -50.40
+`
+
+func readSweeps() []Sweep {
+	rows, err := ReadCSV(strings.NewReader(output))
+	Expect(err).ToNot(HaveOccurred())
+
+	return Sweeps(rows)
+}
+
+var _ = Describe("Survey", func() {
+	It("reads the rows written by rtl_power", func() {
+		rows, err := ReadCSV(strings.NewReader(output))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rows).To(HaveLen(4))
+
+		Expect(rows[1].Time).To(Equal(time.Date(2026, 10, 19, 12, 0, 1, 0, time.UTC)))
+		Expect(rows[1].Low).To(Equal(145_000_000.0))
+		Expect(rows[1].High).To(Equal(146_000_000.0))
+		Expect(rows[1].Step).To(Equal(250_000.0))
+		Expect(rows[1].Samples).To(Equal(100))
+		Expect(rows[1].Power).To(Equal([]float64{-50.2, -20, -49.8, -50}))
+	})
+
+	It("rejects malformed rows", func() {
+		_, err := ReadCSV(strings.NewReader("2026-10-19, 12:00:00, 144000000\n"))
+		Expect(err).To(MatchError(ContainSubstring("line 1")))
+	})
+
+	It("groups the rows into sweeps", func() {
+		sweeps := readSweeps()
+		Expect(sweeps).To(HaveLen(2))
+		Expect(sweeps[1].Time).To(Equal(time.Date(2026, 10, 19, 12, 0, 10, 0, time.UTC)))
+		Expect(sweeps[0].Bins).To(HaveLen(8))
+		Expect(sweeps[0].Bins[5]).To(Equal(Bin{Frequency: 145_375_000, Power: -20}))
+	})
+
+	It("finds the strongest peaks of the max hold", func() {
+		bins := MaxHold(readSweeps())
+		Expect(bins).To(HaveLen(8))
+		Expect(bins[5].Power).To(Equal(-10.0))
+
+		Expect(Peaks(bins, 10, 6)).To(Equal([]Bin{
+			{Frequency: 145_375_000, Power: -10},
+			{Frequency: 144_625_000, Power: -30},
+		}))
+		Expect(Peaks(bins, 1, 6)).To(HaveLen(1))
+		Expect(Peaks(bins, 10, 40)).To(BeEmpty())
+	})
+
+	It("renders a heatmap with a row per sweep", func() {
+		var buf bytes.Buffer
+		Expect(WriteHeatmap(&buf, readSweeps())).To(Succeed())
+
+		img, err := png.Decode(&buf)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(img.Bounds().Dx()).To(Equal(8))
+		Expect(img.Bounds().Dy()).To(Equal(2))
+
+		// The carrier is brighter than the noise.
+		carrier, _, _, _ := img.At(5, 1).RGBA()
+		noise, _, _, _ := img.At(0, 1).RGBA()
+		Expect(carrier).To(BeNumerically(">", noise))
+	})
+
+	It("narrows wide heatmaps", func() {
+		bins := make([]Bin, 3*MaxHeatmapWidth)
+		for i := range bins {
+			bins[i] = Bin{Frequency: float64(i), Power: -50}
+		}
+
+		var buf bytes.Buffer
+		Expect(WriteHeatmap(&buf, []Sweep{{Bins: bins}})).To(Succeed())
+
+		img, err := png.Decode(&buf)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(img.Bounds().Dx()).To(Equal(MaxHeatmapWidth))
+	})
+})
@@ -18,6 +18,8 @@ import (
 var (
 	// managerImage is the manager image to be built and loaded for testing.
 	managerImage = "example.com/k8s-radio:v0.0.1"
+	// simulatorImage is the fake rtl_tcp image used by simulated receivers.
+	simulatorImage = "fake-rtltcp:dev"
 	// shouldCleanupCertManager tracks whether CertManager was installed by this suite.
 	shouldCleanupCertManager = false
 )
@@ -46,6 +48,10 @@ var _ = BeforeSuite(func() {
 	err = utils.LoadImageToKindClusterWithName(managerImage)
 	ExpectWithOffset(1, err).NotTo(HaveOccurred(), "Failed to load the manager image into Kind")
 
+	By("loading the simulator image on Kind")
+	err = utils.LoadImageToKindClusterWithName(simulatorImage)
+	ExpectWithOffset(1, err).NotTo(HaveOccurred(), "Failed to load the simulator image into Kind")
+
 	configureKubectlKubeRC()
 	setupCertManager()
 })
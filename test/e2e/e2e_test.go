@@ -139,6 +139,40 @@ var _ = Describe("Manager", Ordered, func() {
 			Eventually(verifyControllerUp).Should(Succeed())
 		})
 
+		It("should stream from a simulated receiver", func() {
+			By("creating the simulated sample receiver")
+			sample := "config/samples/radio_v1beta1_rtlsdrreceiver_simulated.yaml"
+			cmd := exec.Command("kubectl", "apply", "-f", sample, "-n", namespace)
+			_, err := utils.Run(cmd)
+			Expect(err).NotTo(HaveOccurred(), "Failed to create the simulated receiver")
+			DeferCleanup(func() {
+				cmd := exec.Command("kubectl", "delete", "-f", sample, "-n", namespace, "--ignore-not-found")
+				_, _ = utils.Run(cmd)
+			})
+
+			By("waiting for the simulated sample receiver to run")
+			verifyReceiverRunning := func(g Gomega) {
+				cmd := exec.Command("kubectl", "get",
+					"rtlsdrreceivers", "rtlsdrreceiver-simulated",
+					"-o", "jsonpath={.status.state}",
+					"-n", namespace,
+				)
+				output, err := utils.Run(cmd)
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(output).To(Equal("Running"), "Incorrect simulated receiver state")
+			}
+			Eventually(verifyReceiverRunning).Should(Succeed())
+
+			By("reading the rtl_tcp header from the receiver service")
+			cmd = exec.Command("kubectl", "run", "rtltcp-check", "--rm", "-i",
+				"--restart=Never", "--image=busybox:1.36", "-n", namespace, "--",
+				"sh", "-c", fmt.Sprintf("nc -w 5 rtlsdrreceiver-simulated.%s.svc 1234 | head -c 4", namespace),
+			)
+			output, err := utils.Run(cmd)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(output).To(ContainSubstring("RTL0"))
+		})
+
 		// +kubebuilder:scaffold:e2e-webhooks-checks
 	})
 })